@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+type accessDb interface {
+	GetCampaignForUser(ctx context.Context, userID, campaignID string) (*models.Campaign, error)
+	SessionBelongsToCampaign(ctx context.Context, campaignID, sessionID string) (bool, error)
+	TranscriptBelongsToSession(ctx context.Context, campaignID, sessionID, jobID string) (bool, error)
+}
+
+// AccessManager checks that the caller in the context may touch a campaign and that the sessions and
+// transcripts addressed through it actually belong to that campaign. Players can read a campaign, changing it
+// takes its owner or GM.
+type AccessManager struct {
+	accessDb accessDb
+}
+
+func NewAccessManager(accessDb accessDb) *AccessManager {
+	return &AccessManager{
+		accessDb: accessDb,
+	}
+}
+
+// AuthorizeCampaign returns Forbidden unless the caller owns or plays in the campaign, and for ManageAccess
+// unless the caller is its owner or GM
+func (a *AccessManager) AuthorizeCampaign(ctx context.Context, campaignID string, access models.CampaignAccess) error {
+	identity, ok := models.IdentityFromContext(ctx)
+	if !ok {
+		return models.Unauthorized
+	}
+	campaign, err := a.accessDb.GetCampaignForUser(ctx, identity.UserID, campaignID)
+	if errors.Is(err, models.EntityNotFound) {
+		return fmt.Errorf("user %s has no access to campaign %s: %w", identity.UserID, campaignID, models.Forbidden)
+	}
+	if err != nil {
+		return err
+	}
+	if access == models.ManageAccess && campaign.Role != models.CampaignOwner && campaign.Role != models.CampaignGM {
+		return fmt.Errorf("user %s is a %s of campaign %s and can't change it: %w", identity.UserID, campaign.Role, campaignID, models.Forbidden)
+	}
+	return nil
+}
+
+func (a *AccessManager) AuthorizeSession(ctx context.Context, campaignID, sessionID string, access models.CampaignAccess) error {
+	if err := a.AuthorizeCampaign(ctx, campaignID, access); err != nil {
+		return err
+	}
+	belongs, err := a.accessDb.SessionBelongsToCampaign(ctx, campaignID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !belongs {
+		return fmt.Errorf("session %s: %w", sessionID, models.EntityNotFound)
+	}
+	return nil
+}
+
+func (a *AccessManager) AuthorizeTranscript(ctx context.Context, campaignID, sessionID, jobID string, access models.CampaignAccess) error {
+	if err := a.AuthorizeCampaign(ctx, campaignID, access); err != nil {
+		return err
+	}
+	belongs, err := a.accessDb.TranscriptBelongsToSession(ctx, campaignID, sessionID, jobID)
+	if err != nil {
+		return err
+	}
+	if !belongs {
+		return fmt.Errorf("transcript %s: %w", jobID, models.EntityNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAccessDb struct {
+	mock.Mock
+}
+
+func (m *MockAccessDb) GetCampaignForUser(ctx context.Context, userID, campaignID string) (*models.Campaign, error) {
+	args := m.Called(ctx, userID, campaignID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Campaign), nil
+}
+
+func (m *MockAccessDb) SessionBelongsToCampaign(ctx context.Context, campaignID, sessionID string) (bool, error) {
+	args := m.Called(ctx, campaignID, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAccessDb) TranscriptBelongsToSession(ctx context.Context, campaignID, sessionID, jobID string) (bool, error) {
+	args := m.Called(ctx, campaignID, sessionID, jobID)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestAuthorizeCampaign(t *testing.T) {
+	cases := []struct {
+		description   string
+		role          models.CampaignRole
+		access        models.CampaignAccess
+		expectedError error
+	}{
+		{
+			description: "player reads the campaign",
+			role:        models.CampaignPlayer,
+			access:      models.ReadAccess,
+		},
+		{
+			description:   "player tries to change the campaign, Forbidden returned",
+			role:          models.CampaignPlayer,
+			access:        models.ManageAccess,
+			expectedError: models.Forbidden,
+		},
+		{
+			description: "GM changes the campaign",
+			role:        models.CampaignGM,
+			access:      models.ManageAccess,
+		},
+		{
+			description: "owner changes the campaign",
+			role:        models.CampaignOwner,
+			access:      models.ManageAccess,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockAccessDb{}
+			mockDb.On("GetCampaignForUser", mock.Anything, "user-1", "cmp123").Return(&models.Campaign{ID: "cmp123", Role: c.role}, nil)
+			mockDb.On("SessionBelongsToCampaign", mock.Anything, "cmp123", "ses123").Return(true, nil)
+
+			ctx := models.ContextWithIdentity(context.Background(), models.Identity{UserID: "user-1"})
+			testManager := NewAccessManager(mockDb)
+			for _, err := range []error{
+				testManager.AuthorizeCampaign(ctx, "cmp123", c.access),
+				testManager.AuthorizeSession(ctx, "cmp123", "ses123", c.access),
+			} {
+				if c.expectedError == nil {
+					if err != nil {
+						t.Errorf("unexpected error returned: %s", err)
+					}
+				} else if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthorizeTranscript(t *testing.T) {
+	dbError := errors.New("db error")
+	cases := []struct {
+		description       string
+		identity          *models.Identity
+		hasAccess         bool
+		accessError       error
+		transcriptBelongs bool
+		expectedError     error
+	}{
+		{
+			description:       "caller has access and transcript belongs to the session",
+			identity:          &models.Identity{UserID: "user-1"},
+			hasAccess:         true,
+			transcriptBelongs: true,
+		},
+		{
+			description:   "no identity in the context, Unauthorized returned",
+			expectedError: models.Unauthorized,
+		},
+		{
+			description:   "caller does not own or play in the campaign, Forbidden returned",
+			identity:      &models.Identity{UserID: "user-2"},
+			expectedError: models.Forbidden,
+		},
+		{
+			description:   "transcript is in another session, EntityNotFound returned",
+			identity:      &models.Identity{UserID: "user-1"},
+			hasAccess:     true,
+			expectedError: models.EntityNotFound,
+		},
+		{
+			description:   "database returned an error, error is returned",
+			identity:      &models.Identity{UserID: "user-1"},
+			accessError:   dbError,
+			expectedError: dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockAccessDb{}
+			if c.accessError != nil {
+				mockDb.On("GetCampaignForUser", mock.Anything, mock.Anything, "cmp123").Return(nil, c.accessError)
+			} else if c.hasAccess {
+				mockDb.On("GetCampaignForUser", mock.Anything, mock.Anything, "cmp123").Return(&models.Campaign{ID: "cmp123", Role: models.CampaignPlayer}, nil)
+			} else {
+				mockDb.On("GetCampaignForUser", mock.Anything, mock.Anything, "cmp123").Return(nil, models.EntityNotFound)
+			}
+			mockDb.On("TranscriptBelongsToSession", mock.Anything, "cmp123", "ses123", "job123").Return(c.transcriptBelongs, nil)
+
+			ctx := context.Background()
+			if c.identity != nil {
+				ctx = models.ContextWithIdentity(ctx, *c.identity)
+			}
+			testManager := NewAccessManager(mockDb)
+			err := testManager.AuthorizeTranscript(ctx, "cmp123", "ses123", "job123", models.ReadAccess)
+			if c.expectedError == nil {
+				if err != nil {
+					t.Errorf("unexpected error returned: %s", err)
+				}
+				return
+			}
+			if !errors.Is(err, c.expectedError) {
+				t.Errorf("expected error: %s got %v", c.expectedError, err)
+			}
+		})
+	}
+}
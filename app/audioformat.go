@@ -0,0 +1,65 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// sniffLength is how much of a recording is read to recognise its container
+const sniffLength = 64
+
+// sniffAudioFormat recognises the container of a recording from its first bytes
+func sniffAudioFormat(header []byte) (models.AudioFormat, bool) {
+	switch {
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return models.MP3, true
+	case len(header) >= 2 && header[0] == 0xFF && header[1]&0xE0 == 0xE0 && header[1]&0x06 != 0:
+		// an MPEG audio frame sync with a layer set, ADTS AAC shares the sync but leaves the layer at zero
+		return models.MP3, true
+	case len(header) >= 8 && bytes.Equal(header[4:8], []byte("ftyp")):
+		return models.MP4, true
+	case len(header) >= 12 && bytes.HasPrefix(header, []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WAVE")):
+		return models.WAV, true
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return models.FLAC, true
+	case bytes.HasPrefix(header, []byte("#!AMR")):
+		return models.AMR, true
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return models.OGG, true
+	case bytes.HasPrefix(header, []byte{0x1A, 0x45, 0xDF, 0xA3}) && bytes.Contains(header, []byte("webm")):
+		// an EBML header, Matroska files share it but name a different doc type
+		return models.WebM, true
+	}
+	return 0, false
+}
+
+// checkAudioFormat returns InvalidEntity unless header starts a recording in the declared format
+func checkAudioFormat(header []byte, declared models.AudioFormat) error {
+	sniffed, ok := sniffAudioFormat(header)
+	if !ok {
+		return fmt.Errorf("audio is not in a supported container: %w", models.InvalidEntity)
+	}
+	if sniffed != declared {
+		return fmt.Errorf("audio was declared as %s but is %s: %w", declared, sniffed, models.InvalidEntity)
+	}
+	return nil
+}
+
+// sniffAudio checks the start of a recording matches the declared format and returns a reader over the whole
+// recording, including the bytes that were inspected
+func sniffAudio(body io.Reader, declared models.AudioFormat) (io.Reader, error) {
+	reader := bufio.NewReaderSize(body, sniffLength)
+	header, err := reader.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("audio could not be read (%s): %w", err, models.InvalidEntity)
+	}
+	if err = checkAudioFormat(header, declared); err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
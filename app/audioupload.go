@@ -0,0 +1,91 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// audioReader counts and hashes audio as it streams to the file store. It fails the upload once more than
+// maxBytes were read and remembers why reading stopped, since the file store may not return the reader's
+// error as is.
+type audioReader struct {
+	body     io.Reader
+	hash     hash.Hash
+	size     int64
+	maxBytes int64
+	err      error
+}
+
+func newAudioReader(body io.Reader, maxBytes int64) *audioReader {
+	return &audioReader{
+		body:     body,
+		hash:     sha256.New(),
+		maxBytes: maxBytes,
+	}
+}
+
+// resumeAudioReader continues hashing audio from the state saved by hashState after the previous chunk
+func resumeAudioReader(body io.Reader, maxBytes int64, hashState []byte) (*audioReader, error) {
+	reader := newAudioReader(body, maxBytes)
+	if len(hashState) == 0 {
+		return reader, nil
+	}
+	if err := reader.hash.(encoding.BinaryUnmarshaler).UnmarshalBinary(hashState); err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+func (a *audioReader) Read(p []byte) (int, error) {
+	if a.err != nil {
+		return 0, a.err
+	}
+	n, err := a.body.Read(p)
+	a.size += int64(n)
+	a.hash.Write(p[:n])
+	if a.size > a.maxBytes {
+		a.err = fmt.Errorf("audio is larger than %d bytes: %w", a.maxBytes, models.TooLarge)
+		return n, a.err
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		a.err = fmt.Errorf("audio upload was cut off after %d bytes (%s): %w", a.size, err, models.InvalidEntity)
+		return n, a.err
+	}
+	return n, err
+}
+
+// checkComplete returns why the upload failed, or InvalidEntity when fewer bytes arrived than the client declared
+func (a *audioReader) checkComplete(declaredSize int64) error {
+	if a.err != nil {
+		return a.err
+	}
+	if declaredSize >= 0 && a.size != declaredSize {
+		return fmt.Errorf("received %d of %d audio bytes: %w", a.size, declaredSize, models.InvalidEntity)
+	}
+	return nil
+}
+
+// hashState saves the hash of the audio read so far so a later chunk can resume it
+func (a *audioReader) hashState() ([]byte, error) {
+	return a.hash.(encoding.BinaryMarshaler).MarshalBinary()
+}
+
+func (a *audioReader) sha256() string {
+	return hex.EncodeToString(a.hash.Sum(nil))
+}
+
+// sha256FromState returns the SHA-256 of all the audio hashed into a state saved by hashState
+func sha256FromState(hashState []byte) (string, error) {
+	reader, err := resumeAudioReader(nil, 0, hashState)
+	if err != nil {
+		return "", err
+	}
+	return reader.sha256(), nil
+}
@@ -2,23 +2,52 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/EdgarH78/dragonspeak-service/models"
 )
 
+// inviteTTL is how long a campaign invite can be accepted after it was created
+const inviteTTL = 7 * 24 * time.Hour
+
 type campaignDb interface {
 	AddCampaign(ctx context.Context, ownerID string, campaign models.Campaign) (*models.Campaign, error)
-	GetCampaignsForUser(ctx context.Context, ownerID string) ([]models.Campaign, error)
+	GetCampaignsForUser(ctx context.Context, userID string, page models.PageRequest) (*models.Page[models.Campaign], error)
+	GetCampaignForUser(ctx context.Context, userID, campaignID string) (*models.Campaign, error)
+	GetPlayer(ctx context.Context, campaignID, playerID string) (*models.Player, error)
+	GetPlayersForCampaign(ctx context.Context, campaignID string) ([]models.Player, error)
+	GetUserByID(ctx context.Context, userID string) (*models.User, error)
+	AddCampaignInvite(ctx context.Context, invite models.CampaignInvite) (*models.CampaignInvite, error)
+	GetCampaignInvite(ctx context.Context, code string) (*models.CampaignInvite, error)
+	AcceptCampaignInvite(ctx context.Context, code, userID string, player models.Player) (*models.Player, error)
+	UpdateCampaign(ctx context.Context, campaign models.Campaign) (*models.Campaign, error)
+	DeleteCampaign(ctx context.Context, campaignID string) error
+	GetTranscriptsForCampaign(ctx context.Context, campaignID string) ([]models.Transcript, error)
+	GetVocabularyForCampaign(ctx context.Context, campaignID string) ([]models.VocabularyTerm, error)
+	GetVocabularyTerm(ctx context.Context, campaignID, termID string) (*models.VocabularyTerm, error)
+	AddVocabularyTerm(ctx context.Context, campaignID string, term models.VocabularyTerm) (*models.VocabularyTerm, error)
+	UpdateVocabularyTerm(ctx context.Context, campaignID string, term models.VocabularyTerm) (*models.VocabularyTerm, error)
+	DeleteVocabularyTerm(ctx context.Context, campaignID, termID string) error
+}
+
+// transcriptFileRemover deletes the stored files of transcripts removed along with their campaign or session
+type transcriptFileRemover interface {
+	DeleteTranscriptFiles(ctx context.Context, transcripts []models.Transcript)
 }
 
 type CampaignManager struct {
-	campaignDb campaignDb
+	campaignDb      campaignDb
+	transcriptFiles transcriptFileRemover
+	now             func() time.Time
 }
 
-func NewCampaignManager(campaignDb campaignDb) *CampaignManager {
+func NewCampaignManager(campaignDb campaignDb, transcriptFiles transcriptFileRemover) *CampaignManager {
 	return &CampaignManager{
-		campaignDb: campaignDb,
+		campaignDb:      campaignDb,
+		transcriptFiles: transcriptFiles,
+		now:             time.Now,
 	}
 }
 
@@ -26,9 +55,224 @@ func (c *CampaignManager) AddCampaign(ctx context.Context, ownerID string, campa
 	if campaign.Name == "" {
 		return nil, fmt.Errorf("missing field name %w", models.InvalidEntity)
 	}
+	language, err := checkDefaultLanguage(campaign.DefaultLanguage)
+	if err != nil {
+		return nil, err
+	}
+	campaign.DefaultLanguage = language
 	return c.campaignDb.AddCampaign(ctx, ownerID, campaign)
 }
 
-func (c *CampaignManager) GetCampaignsForUser(ctx context.Context, ownerID string) ([]models.Campaign, error) {
-	return c.campaignDb.GetCampaignsForUser(ctx, ownerID)
+// GetCampaign returns a campaign the user owns or plays in
+func (c *CampaignManager) GetCampaign(ctx context.Context, userID, campaignID string) (*models.Campaign, error) {
+	return c.campaignDb.GetCampaignForUser(ctx, userID, campaignID)
+}
+
+// GetCampaignsForUser returns a page of the campaigns the user owns or plays in
+func (c *CampaignManager) GetCampaignsForUser(ctx context.Context, userID string, page models.PageRequest) (*models.Page[models.Campaign], error) {
+	page, err := checkPageRequest(page)
+	if err != nil {
+		return nil, err
+	}
+	return c.campaignDb.GetCampaignsForUser(ctx, userID, page)
+}
+
+// UpdateCampaign applies the fields set in the update to a campaign, only the owner can change it
+func (c *CampaignManager) UpdateCampaign(ctx context.Context, campaignID string, update models.CampaignUpdate) (*models.Campaign, error) {
+	campaign, err := c.getOwnedCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Version != update.Version {
+		return nil, fmt.Errorf("campaign %s is at version %d: %w", campaignID, campaign.Version, models.Conflicted)
+	}
+	if update.Name != nil {
+		campaign.Name = *update.Name
+	}
+	if update.Link != nil {
+		campaign.Link = *update.Link
+	}
+	if update.DefaultLanguage != nil {
+		language, err := checkDefaultLanguage(*update.DefaultLanguage)
+		if err != nil {
+			return nil, err
+		}
+		campaign.DefaultLanguage = language
+	}
+	if campaign.Name == "" {
+		return nil, fmt.Errorf("missing field name %w", models.InvalidEntity)
+	}
+	updated, err := c.campaignDb.UpdateCampaign(ctx, *campaign)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteCampaign removes a campaign with everything recorded for it, only the owner can delete it
+func (c *CampaignManager) DeleteCampaign(ctx context.Context, campaignID string) error {
+	if _, err := c.getOwnedCampaign(ctx, campaignID); err != nil {
+		return err
+	}
+	transcripts, err := c.campaignDb.GetTranscriptsForCampaign(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	if err = c.campaignDb.DeleteCampaign(ctx, campaignID); err != nil {
+		return err
+	}
+	c.transcriptFiles.DeleteTranscriptFiles(ctx, transcripts)
+	return nil
+}
+
+// checkDefaultLanguage normalizes the default language of a campaign, empty leaves it to the service default
+func checkDefaultLanguage(language string) (string, error) {
+	if language == "" {
+		return "", nil
+	}
+	return models.LanguageFromString(language)
+}
+
+// getOwnedCampaign loads the campaign for the caller and returns Forbidden unless the caller owns it
+func (c *CampaignManager) getOwnedCampaign(ctx context.Context, campaignID string) (*models.Campaign, error) {
+	identity, ok := models.IdentityFromContext(ctx)
+	if !ok {
+		return nil, models.Unauthorized
+	}
+	campaign, err := c.campaignDb.GetCampaignForUser(ctx, identity.UserID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Role != models.CampaignOwner {
+		return nil, fmt.Errorf("only the campaign owner can do this: %w", models.Forbidden)
+	}
+	return campaign, nil
+}
+
+// CreateInvite creates an invite to the campaign, only the campaign owner can invite other users. Conflicted is
+// returned for an invite that would add a GM to a campaign that has one.
+func (c *CampaignManager) CreateInvite(ctx context.Context, campaignID string, invite models.CampaignInvite) (*models.CampaignInvite, error) {
+	if _, err := c.getOwnedCampaign(ctx, campaignID); err != nil {
+		return nil, err
+	}
+
+	if invite.PlayerID == "" && invite.PlayerType == models.GM {
+		players, err := c.campaignDb.GetPlayersForCampaign(ctx, campaignID)
+		if err != nil {
+			return nil, err
+		}
+		if hasOtherGM(players, "") {
+			return nil, fmt.Errorf("campaign already has a GM: %w", models.Conflicted)
+		}
+	}
+	if invite.PlayerID != "" {
+		player, err := c.campaignDb.GetPlayer(ctx, campaignID, invite.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+		if player.UserID != "" {
+			return nil, fmt.Errorf("player %s is already linked to a user: %w", player.ID, models.Conflicted)
+		}
+		invite.PlayerType = player.Type
+	}
+	invite.CampaignID = campaignID
+	invite.ExpiresAt = c.now().Add(inviteTTL)
+	invite.AcceptedAt = nil
+	return c.campaignDb.AddCampaignInvite(ctx, invite)
+}
+
+// AcceptInvite makes the user a player of the invite's campaign and returns the campaign with the user's role
+func (c *CampaignManager) AcceptInvite(ctx context.Context, userID, code string) (*models.Campaign, error) {
+	invite, err := c.campaignDb.GetCampaignInvite(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if invite.AcceptedAt != nil {
+		return nil, fmt.Errorf("invite was already accepted: %w", models.Conflicted)
+	}
+	if c.now().After(invite.ExpiresAt) {
+		return nil, fmt.Errorf("invite expired at %s: %w", invite.ExpiresAt, models.Conflicted)
+	}
+
+	_, err = c.campaignDb.GetCampaignForUser(ctx, userID, invite.CampaignID)
+	if err == nil {
+		return nil, fmt.Errorf("user %s is already part of campaign %s: %w", userID, invite.CampaignID, models.EntityAlreadyExists)
+	}
+	if !errors.Is(err, models.EntityNotFound) {
+		return nil, err
+	}
+
+	user, err := c.campaignDb.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	player := models.Player{
+		Name: user.Handle,
+		Type: invite.PlayerType,
+	}
+	if _, err = c.campaignDb.AcceptCampaignInvite(ctx, code, userID, player); err != nil {
+		return nil, err
+	}
+	return c.campaignDb.GetCampaignForUser(ctx, userID, invite.CampaignID)
+}
+
+// GetVocabulary returns the vocabulary transcripts of the campaign are made with, the custom terms followed by
+// the names of the campaign, its players and their characters
+func (c *CampaignManager) GetVocabulary(ctx context.Context, campaignID string) ([]models.VocabularyTerm, error) {
+	terms, err := c.campaignDb.GetVocabularyForCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	return buildVocabulary(terms), nil
+}
+
+func (c *CampaignManager) GetVocabularyTerm(ctx context.Context, campaignID, termID string) (*models.VocabularyTerm, error) {
+	return c.campaignDb.GetVocabularyTerm(ctx, campaignID, termID)
+}
+
+// AddVocabularyTerm adds a custom term to the vocabulary of a campaign, EntityAlreadyExists is returned when the
+// campaign has a custom term with the same phrase
+func (c *CampaignManager) AddVocabularyTerm(ctx context.Context, campaignID string, term models.VocabularyTerm) (*models.VocabularyTerm, error) {
+	term.Source = models.VocabularyCustom
+	term, err := normalizeVocabularyTerm(term)
+	if err != nil {
+		return nil, err
+	}
+	terms, err := c.campaignDb.GetVocabularyForCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	customTerms := 0
+	for _, existing := range terms {
+		if existing.Source == models.VocabularyCustom {
+			customTerms++
+		}
+	}
+	if customTerms >= maxCustomVocabularyTerms {
+		return nil, fmt.Errorf("campaign already has %d custom terms: %w", customTerms, models.Conflicted)
+	}
+	return c.campaignDb.AddVocabularyTerm(ctx, campaignID, term)
+}
+
+// UpdateVocabularyTerm applies the fields set in the update to a custom term of the campaign
+func (c *CampaignManager) UpdateVocabularyTerm(ctx context.Context, campaignID, termID string, update models.VocabularyTermUpdate) (*models.VocabularyTerm, error) {
+	term, err := c.campaignDb.GetVocabularyTerm(ctx, campaignID, termID)
+	if err != nil {
+		return nil, err
+	}
+	if update.Phrase != nil {
+		term.Phrase = *update.Phrase
+	}
+	if update.SoundsLike != nil {
+		term.SoundsLike = *update.SoundsLike
+	}
+	normalized, err := normalizeVocabularyTerm(*term)
+	if err != nil {
+		return nil, err
+	}
+	return c.campaignDb.UpdateVocabularyTerm(ctx, campaignID, normalized)
+}
+
+func (c *CampaignManager) DeleteVocabularyTerm(ctx context.Context, campaignID, termID string) error {
+	return c.campaignDb.DeleteVocabularyTerm(ctx, campaignID, termID)
 }
@@ -3,7 +3,9 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/EdgarH78/dragonspeak-service/models"
 	"github.com/stretchr/testify/assert"
@@ -22,15 +24,137 @@ func (m *MockCampaignDB) AddCampaign(ctx context.Context, ownerID string, campai
 	return args.Get(0).(*models.Campaign), nil
 }
 
-func (m *MockCampaignDB) GetCampaignsForUser(ctx context.Context, ownerID string) ([]models.Campaign, error) {
-	args := m.Called(ctx, ownerID)
+func (m *MockCampaignDB) GetCampaignsForUser(ctx context.Context, ownerID string, page models.PageRequest) (*models.Page[models.Campaign], error) {
+	args := m.Called(ctx, ownerID, page)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]models.Campaign), nil
+	return args.Get(0).(*models.Page[models.Campaign]), nil
 
 }
 
+func (m *MockCampaignDB) GetCampaignForUser(ctx context.Context, userID, campaignID string) (*models.Campaign, error) {
+	args := m.Called(ctx, userID, campaignID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Campaign), nil
+}
+
+func (m *MockCampaignDB) GetPlayer(ctx context.Context, campaignID, playerID string) (*models.Player, error) {
+	args := m.Called(ctx, campaignID, playerID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Player), nil
+}
+
+func (m *MockCampaignDB) GetPlayersForCampaign(ctx context.Context, campaignID string) ([]models.Player, error) {
+	args := m.Called(ctx, campaignID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Player), nil
+}
+
+func (m *MockCampaignDB) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	args := m.Called(ctx, userID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), nil
+}
+
+func (m *MockCampaignDB) AddCampaignInvite(ctx context.Context, invite models.CampaignInvite) (*models.CampaignInvite, error) {
+	args := m.Called(ctx, invite)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CampaignInvite), nil
+}
+
+func (m *MockCampaignDB) GetCampaignInvite(ctx context.Context, code string) (*models.CampaignInvite, error) {
+	args := m.Called(ctx, code)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CampaignInvite), nil
+}
+
+func (m *MockCampaignDB) AcceptCampaignInvite(ctx context.Context, code, userID string, player models.Player) (*models.Player, error) {
+	args := m.Called(ctx, code, userID, player)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Player), nil
+}
+
+func (m *MockCampaignDB) UpdateCampaign(ctx context.Context, campaign models.Campaign) (*models.Campaign, error) {
+	args := m.Called(ctx, campaign)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Campaign), nil
+}
+
+func (m *MockCampaignDB) DeleteCampaign(ctx context.Context, campaignID string) error {
+	args := m.Called(ctx, campaignID)
+	return args.Error(0)
+}
+
+func (m *MockCampaignDB) GetTranscriptsForCampaign(ctx context.Context, campaignID string) ([]models.Transcript, error) {
+	args := m.Called(ctx, campaignID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Transcript), nil
+}
+
+func (m *MockCampaignDB) GetVocabularyForCampaign(ctx context.Context, campaignID string) ([]models.VocabularyTerm, error) {
+	args := m.Called(ctx, campaignID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.VocabularyTerm), nil
+}
+
+func (m *MockCampaignDB) GetVocabularyTerm(ctx context.Context, campaignID, termID string) (*models.VocabularyTerm, error) {
+	args := m.Called(ctx, campaignID, termID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.VocabularyTerm), nil
+}
+
+func (m *MockCampaignDB) AddVocabularyTerm(ctx context.Context, campaignID string, term models.VocabularyTerm) (*models.VocabularyTerm, error) {
+	args := m.Called(ctx, campaignID, term)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.VocabularyTerm), nil
+}
+
+func (m *MockCampaignDB) UpdateVocabularyTerm(ctx context.Context, campaignID string, term models.VocabularyTerm) (*models.VocabularyTerm, error) {
+	args := m.Called(ctx, campaignID, term)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.VocabularyTerm), nil
+}
+
+func (m *MockCampaignDB) DeleteVocabularyTerm(ctx context.Context, campaignID, termID string) error {
+	args := m.Called(ctx, campaignID, termID)
+	return args.Error(0)
+}
+
+type MockTranscriptFileRemover struct {
+	mock.Mock
+}
+
+func (m *MockTranscriptFileRemover) DeleteTranscriptFiles(ctx context.Context, transcripts []models.Transcript) {
+	m.Called(ctx, transcripts)
+}
+
 func TestAddCampaign(t *testing.T) {
 	dbError := errors.New("db error")
 	cases := []struct {
@@ -68,6 +192,33 @@ func TestAddCampaign(t *testing.T) {
 			},
 			expectedError: models.InvalidEntity,
 		},
+		{
+			description: "campaign with a default language is added to the database",
+			userID:      "userId123",
+			campaignToAdd: models.Campaign{
+				Name:            "testAndDragons",
+				DefaultLanguage: "de-DE",
+			},
+			dbResult: &models.Campaign{
+				Name:            "testAndDragons",
+				DefaultLanguage: "de-DE",
+				ID:              "abc123",
+			},
+			expectedResult: &models.Campaign{
+				Name:            "testAndDragons",
+				DefaultLanguage: "de-DE",
+				ID:              "abc123",
+			},
+		},
+		{
+			description: "campaign default language is not supported, InvalidEntity returned",
+			userID:      "userId123",
+			campaignToAdd: models.Campaign{
+				Name:            "testAndDragons",
+				DefaultLanguage: "tlh-KL",
+			},
+			expectedError: models.InvalidEntity,
+		},
 		{
 			description: "database returned an error, error is returned",
 			userID:      "userId123",
@@ -90,7 +241,7 @@ func TestAddCampaign(t *testing.T) {
 			} else {
 				mockDb.On("AddCampaign", mock.Anything, c.userID, c.campaignToAdd).Return(c.dbResult, nil)
 			}
-			testManager := NewCampaignManager(mockDb)
+			testManager := NewCampaignManager(mockDb, &MockTranscriptFileRemover{})
 			result, err := testManager.AddCampaign(context.Background(), c.userID, c.campaignToAdd)
 			if err != nil && c.expectedError == nil {
 				t.Errorf("unexpected error returned: %s", err)
@@ -120,14 +271,17 @@ func TestCampaignsForUser(t *testing.T) {
 	cases := []struct {
 		description    string
 		userID         string
+		page           models.PageRequest
+		expectedPage   models.PageRequest
 		dbError        error
 		dbResult       []models.Campaign
 		expectedError  error
 		expectedResult []models.Campaign
 	}{
 		{
-			description: "user is retrieved",
-			userID:      "user123",
+			description:  "user is retrieved",
+			userID:       "user123",
+			expectedPage: models.PageRequest{Limit: models.DefaultPageLimit},
 			dbResult: []models.Campaign{
 				{
 					Name: "testAndDragons",
@@ -153,9 +307,30 @@ func TestCampaignsForUser(t *testing.T) {
 				},
 			},
 		},
+		{
+			description:    "next page requested by name descending",
+			userID:         "user123",
+			page:           models.PageRequest{Limit: 1, Cursor: &models.Cursor{Value: "testAndDragons2", ID: "abc456", Sort: models.Sort{Field: "name", Descending: true}}, Sort: models.Sort{Field: "name", Descending: true}},
+			expectedPage:   models.PageRequest{Limit: 1, Cursor: &models.Cursor{Value: "testAndDragons2", ID: "abc456", Sort: models.Sort{Field: "name", Descending: true}}, Sort: models.Sort{Field: "name", Descending: true}},
+			dbResult:       []models.Campaign{{Name: "testAndDragons", Link: "http://dnd.com", ID: "abc123"}},
+			expectedResult: []models.Campaign{{Name: "testAndDragons", Link: "http://dnd.com", ID: "abc123"}},
+		},
+		{
+			description:   "cursor taken from the list in another order, InvalidEntity returned",
+			userID:        "user123",
+			page:          models.PageRequest{Limit: 1, Cursor: &models.Cursor{Value: "testAndDragons2", ID: "abc456", Sort: models.Sort{Field: "name"}}, Sort: models.Sort{Field: "name", Descending: true}},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "limit above the maximum, InvalidEntity returned",
+			userID:        "user123",
+			page:          models.PageRequest{Limit: models.MaxPageLimit + 1},
+			expectedError: models.InvalidEntity,
+		},
 		{
 			description:   "database returns error",
 			userID:        "abc123",
+			expectedPage:  models.PageRequest{Limit: models.DefaultPageLimit},
 			dbError:       dbError,
 			expectedError: dbError,
 		},
@@ -167,24 +342,24 @@ func TestCampaignsForUser(t *testing.T) {
 
 			mockDb := &MockCampaignDB{}
 			if c.dbError != nil {
-				mockDb.On("GetCampaignsForUser", mock.Anything, c.userID).Return(nil, c.dbError)
+				mockDb.On("GetCampaignsForUser", mock.Anything, c.userID, c.expectedPage).Return(nil, c.dbError)
 			} else {
-				mockDb.On("GetCampaignsForUser", mock.Anything, c.userID).Return(c.dbResult, nil)
+				mockDb.On("GetCampaignsForUser", mock.Anything, c.userID, c.expectedPage).Return(&models.Page[models.Campaign]{Items: c.dbResult}, nil)
 			}
-			testManager := NewCampaignManager(mockDb)
-			result, err := testManager.GetCampaignsForUser(context.Background(), c.userID)
+			testManager := NewCampaignManager(mockDb, &MockTranscriptFileRemover{})
+			result, err := testManager.GetCampaignsForUser(context.Background(), c.userID, c.page)
 			if err != nil && c.expectedError == nil {
 				t.Errorf("unexpected error returned: %s", err)
 				return
 			}
 
 			if c.expectedResult != nil {
-				if len(c.expectedResult) != len(result) {
-					t.Errorf("expected %d campaigns got %d", len(c.expectedResult), len(result))
+				if len(c.expectedResult) != len(result.Items) {
+					t.Errorf("expected %d campaigns got %d", len(c.expectedResult), len(result.Items))
 					return
 				}
 				for i, expectedCampaign := range c.expectedResult {
-					actualCampaign := result[i]
+					actualCampaign := result.Items[i]
 					assert.Equal(t, expectedCampaign.Link, actualCampaign.Link)
 					assert.Equal(t, expectedCampaign.Name, actualCampaign.Name)
 					assert.Equal(t, expectedCampaign.ID, actualCampaign.ID)
@@ -202,3 +377,516 @@ func TestCampaignsForUser(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateInvite(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cases := []struct {
+		description    string
+		invite         models.CampaignInvite
+		callerRole     models.CampaignRole
+		storedPlayer   *models.Player
+		players        []models.Player
+		expectedInvite *models.CampaignInvite
+		expectedError  error
+	}{
+		{
+			description: "owner invites a new player",
+			invite:      models.CampaignInvite{PlayerType: models.StandardPlayer},
+			callerRole:  models.CampaignOwner,
+			expectedInvite: &models.CampaignInvite{
+				CampaignID: "cmp123",
+				PlayerType: models.StandardPlayer,
+				ExpiresAt:  now.Add(inviteTTL),
+			},
+		},
+		{
+			description:  "owner invites a user to take over an existing GM player",
+			invite:       models.CampaignInvite{PlayerID: "player-1", PlayerType: models.StandardPlayer},
+			callerRole:   models.CampaignOwner,
+			storedPlayer: &models.Player{ID: "player-1", Name: "Matt", Type: models.GM},
+			expectedInvite: &models.CampaignInvite{
+				CampaignID: "cmp123",
+				PlayerID:   "player-1",
+				PlayerType: models.GM,
+				ExpiresAt:  now.Add(inviteTTL),
+			},
+		},
+		{
+			description: "owner invites a GM to a campaign without one",
+			invite:      models.CampaignInvite{PlayerType: models.GM},
+			callerRole:  models.CampaignOwner,
+			players:     []models.Player{{ID: "player-2", Name: "Laura", Type: models.StandardPlayer}},
+			expectedInvite: &models.CampaignInvite{
+				CampaignID: "cmp123",
+				PlayerType: models.GM,
+				ExpiresAt:  now.Add(inviteTTL),
+			},
+		},
+		{
+			description:   "owner invites a GM to a campaign that has one, Conflicted returned",
+			invite:        models.CampaignInvite{PlayerType: models.GM},
+			callerRole:    models.CampaignOwner,
+			players:       []models.Player{{ID: "player-1", Name: "Matt", Type: models.GM}},
+			expectedError: models.Conflicted,
+		},
+		{
+			description:   "player tries to invite, Forbidden returned",
+			invite:        models.CampaignInvite{PlayerType: models.StandardPlayer},
+			callerRole:    models.CampaignPlayer,
+			expectedError: models.Forbidden,
+		},
+		{
+			description:   "invited player is already linked, Conflicted returned",
+			invite:        models.CampaignInvite{PlayerID: "player-1"},
+			callerRole:    models.CampaignOwner,
+			storedPlayer:  &models.Player{ID: "player-1", Name: "Matt", Type: models.GM, UserID: "user-9"},
+			expectedError: models.Conflicted,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockCampaignDB{}
+			mockDb.On("GetCampaignForUser", mock.Anything, "owner-1", "cmp123").Return(&models.Campaign{ID: "cmp123", Role: c.callerRole}, nil)
+			if c.storedPlayer != nil {
+				mockDb.On("GetPlayer", mock.Anything, "cmp123", c.storedPlayer.ID).Return(c.storedPlayer, nil)
+			}
+			mockDb.On("GetPlayersForCampaign", mock.Anything, "cmp123").Return(c.players, nil)
+			if c.expectedInvite != nil {
+				created := *c.expectedInvite
+				created.Code = "code-1"
+				mockDb.On("AddCampaignInvite", mock.Anything, *c.expectedInvite).Return(&created, nil)
+			}
+			testManager := NewCampaignManager(mockDb, &MockTranscriptFileRemover{})
+			testManager.now = func() time.Time { return now }
+
+			ctx := models.ContextWithIdentity(context.Background(), models.Identity{UserID: "owner-1"})
+			result, err := testManager.CreateInvite(ctx, "cmp123", c.invite)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				mockDb.AssertNotCalled(t, "AddCampaignInvite", mock.Anything, mock.Anything)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error returned: %s", err)
+			}
+			assert.Equal(t, "code-1", result.Code)
+			assert.Equal(t, c.expectedInvite.PlayerType, result.PlayerType)
+		})
+	}
+}
+
+func TestAcceptInvite(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	acceptedAt := now.Add(-time.Hour)
+	cases := []struct {
+		description      string
+		invite           *models.CampaignInvite
+		inviteError      error
+		alreadyMember    bool
+		acceptError      error
+		expectedPlayer   *models.Player
+		expectedCampaign *models.Campaign
+		expectedError    error
+	}{
+		{
+			description:      "user joins the campaign as a new player named after their handle",
+			invite:           &models.CampaignInvite{Code: "code-1", CampaignID: "cmp123", PlayerType: models.StandardPlayer, ExpiresAt: now.Add(time.Hour)},
+			expectedPlayer:   &models.Player{Name: "laura", Type: models.StandardPlayer},
+			expectedCampaign: &models.Campaign{ID: "cmp123", Name: "Vox Machina", Role: models.CampaignPlayer},
+		},
+		{
+			description:   "invite does not exist, EntityNotFound returned",
+			inviteError:   models.EntityNotFound,
+			expectedError: models.EntityNotFound,
+		},
+		{
+			description:   "invite already accepted, Conflicted returned",
+			invite:        &models.CampaignInvite{Code: "code-1", CampaignID: "cmp123", ExpiresAt: now.Add(time.Hour), AcceptedAt: &acceptedAt},
+			expectedError: models.Conflicted,
+		},
+		{
+			description:   "invite expired, Conflicted returned",
+			invite:        &models.CampaignInvite{Code: "code-1", CampaignID: "cmp123", ExpiresAt: now.Add(-time.Minute)},
+			expectedError: models.Conflicted,
+		},
+		{
+			description:    "GM invite accepted after the campaign got a GM, Conflicted returned",
+			invite:         &models.CampaignInvite{Code: "code-1", CampaignID: "cmp123", PlayerType: models.GM, ExpiresAt: now.Add(time.Hour)},
+			acceptError:    models.Conflicted,
+			expectedPlayer: &models.Player{Name: "laura", Type: models.GM},
+			expectedError:  models.Conflicted,
+		},
+		{
+			description:   "user already plays in the campaign, EntityAlreadyExists returned",
+			invite:        &models.CampaignInvite{Code: "code-1", CampaignID: "cmp123", ExpiresAt: now.Add(time.Hour)},
+			alreadyMember: true,
+			expectedError: models.EntityAlreadyExists,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockCampaignDB{}
+			if c.inviteError != nil {
+				mockDb.On("GetCampaignInvite", mock.Anything, "code-1").Return(nil, c.inviteError)
+			} else {
+				mockDb.On("GetCampaignInvite", mock.Anything, "code-1").Return(c.invite, nil)
+			}
+			if c.alreadyMember {
+				mockDb.On("GetCampaignForUser", mock.Anything, "user-1", "cmp123").Return(&models.Campaign{ID: "cmp123", Role: models.CampaignPlayer}, nil)
+			} else if c.expectedCampaign != nil || c.acceptError != nil {
+				mockDb.On("GetCampaignForUser", mock.Anything, "user-1", "cmp123").Return(nil, models.EntityNotFound).Once()
+				mockDb.On("GetCampaignForUser", mock.Anything, "user-1", "cmp123").Return(c.expectedCampaign, nil).Once()
+			}
+			mockDb.On("GetUserByID", mock.Anything, "user-1").Return(&models.User{ID: "user-1", Handle: "laura"}, nil)
+			if c.expectedPlayer != nil {
+				linked := *c.expectedPlayer
+				linked.ID = "player-7"
+				linked.UserID = "user-1"
+				if c.acceptError != nil {
+					mockDb.On("AcceptCampaignInvite", mock.Anything, "code-1", "user-1", *c.expectedPlayer).Return(nil, c.acceptError)
+				} else {
+					mockDb.On("AcceptCampaignInvite", mock.Anything, "code-1", "user-1", *c.expectedPlayer).Return(&linked, nil)
+				}
+			}
+			testManager := NewCampaignManager(mockDb, &MockTranscriptFileRemover{})
+			testManager.now = func() time.Time { return now }
+
+			result, err := testManager.AcceptInvite(context.Background(), "user-1", "code-1")
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				if c.acceptError == nil {
+					mockDb.AssertNotCalled(t, "AcceptCampaignInvite", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error returned: %s", err)
+			}
+			assert.Equal(t, c.expectedCampaign, result)
+			mockDb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateCampaign(t *testing.T) {
+	newName := "Critical Role"
+	autoLanguage := "AUTO"
+	unsupportedLanguage := "tlh-KL"
+	storedCampaign := models.Campaign{ID: "cmp123", Name: "Vox Machina", Role: models.CampaignOwner, Version: 3}
+	cases := []struct {
+		description      string
+		update           models.CampaignUpdate
+		updateError      error
+		expectedCampaign models.Campaign
+		expectedError    error
+	}{
+		{
+			description:      "name updated at the current version",
+			update:           models.CampaignUpdate{Name: &newName, Version: 3},
+			expectedCampaign: models.Campaign{ID: "cmp123", Name: newName, Role: models.CampaignOwner, Version: 3},
+		},
+		{
+			description:   "update made from a stale version, Conflicted returned",
+			update:        models.CampaignUpdate{Name: &newName, Version: 2},
+			expectedError: models.Conflicted,
+		},
+		{
+			description:      "default language set to language identification",
+			update:           models.CampaignUpdate{DefaultLanguage: &autoLanguage, Version: 3},
+			expectedCampaign: models.Campaign{ID: "cmp123", Name: "Vox Machina", DefaultLanguage: models.AutoLanguage, Role: models.CampaignOwner, Version: 3},
+		},
+		{
+			description:   "default language is not supported, InvalidEntity returned",
+			update:        models.CampaignUpdate{DefaultLanguage: &unsupportedLanguage, Version: 3},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:      "campaign changed before the write, Conflicted returned",
+			update:           models.CampaignUpdate{Name: &newName, Version: 3},
+			expectedCampaign: models.Campaign{ID: "cmp123", Name: newName, Role: models.CampaignOwner, Version: 3},
+			updateError:      models.Conflicted,
+			expectedError:    models.Conflicted,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			ctx := models.ContextWithIdentity(context.Background(), models.Identity{UserID: "usr123"})
+			mockDb := &MockCampaignDB{}
+			campaign := storedCampaign
+			mockDb.On("GetCampaignForUser", mock.Anything, "usr123", "cmp123").Return(&campaign, nil)
+			updated := c.expectedCampaign
+			updated.Version++
+			mockDb.On("UpdateCampaign", mock.Anything, c.expectedCampaign).Return(&updated, c.updateError)
+			testManager := NewCampaignManager(mockDb, &MockTranscriptFileRemover{})
+			result, err := testManager.UpdateCampaign(ctx, "cmp123", c.update)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			assert.Equal(t, updated, *result)
+		})
+	}
+}
+
+func TestDeleteCampaign(t *testing.T) {
+	dbError := errors.New("db error")
+	transcripts := []models.Transcript{{JobID: "job-1", AudioLocation: "audio/job-1.mp3"}}
+	cases := []struct {
+		description   string
+		role          models.CampaignRole
+		deleteError   error
+		expectedError error
+		filesRemoved  bool
+	}{
+		{
+			description:  "owner deletes the campaign, transcript files are removed",
+			role:         models.CampaignOwner,
+			filesRemoved: true,
+		},
+		{
+			description:   "player deletes the campaign, Forbidden returned",
+			role:          models.CampaignPlayer,
+			expectedError: models.Forbidden,
+		},
+		{
+			description:   "database returned an error, files are kept",
+			role:          models.CampaignOwner,
+			deleteError:   dbError,
+			expectedError: dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			ctx := models.ContextWithIdentity(context.Background(), models.Identity{UserID: "usr123"})
+			mockDb := &MockCampaignDB{}
+			mockDb.On("GetCampaignForUser", mock.Anything, "usr123", "cmp123").Return(&models.Campaign{ID: "cmp123", Role: c.role}, nil)
+			mockDb.On("GetTranscriptsForCampaign", mock.Anything, "cmp123").Return(transcripts, nil)
+			mockDb.On("DeleteCampaign", mock.Anything, "cmp123").Return(c.deleteError)
+			mockRemover := &MockTranscriptFileRemover{}
+			mockRemover.On("DeleteTranscriptFiles", mock.Anything, transcripts).Return()
+			testManager := NewCampaignManager(mockDb, mockRemover)
+			err := testManager.DeleteCampaign(ctx, "cmp123")
+			if c.filesRemoved {
+				mockRemover.AssertCalled(t, "DeleteTranscriptFiles", mock.Anything, transcripts)
+			} else {
+				mockRemover.AssertNotCalled(t, "DeleteTranscriptFiles", mock.Anything, mock.Anything)
+			}
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+			}
+		})
+	}
+}
+
+func TestGetVocabulary(t *testing.T) {
+	dbError := errors.New("db error")
+	cases := []struct {
+		description        string
+		storedTerms        []models.VocabularyTerm
+		dbError            error
+		expectedVocabulary []models.VocabularyTerm
+		expectedError      error
+	}{
+		{
+			description: "custom terms come before the seeded names",
+			storedTerms: []models.VocabularyTerm{
+				{Phrase: "Vox Machina", Source: models.VocabularyCampaign},
+				{Phrase: "Grog Strongjaw", Source: models.VocabularyCharacter},
+				{ID: "term-1", Phrase: "Tiamat", SoundsLike: []string{"tee-ah-mat"}, Source: models.VocabularyCustom},
+				{Phrase: "Marisha", Source: models.VocabularyPlayer},
+			},
+			expectedVocabulary: []models.VocabularyTerm{
+				{ID: "term-1", Phrase: "Tiamat", SoundsLike: []string{"tee-ah-mat"}, Source: models.VocabularyCustom},
+				{Phrase: "Vox Machina", SoundsLike: []string{}, Source: models.VocabularyCampaign},
+				{Phrase: "Marisha", SoundsLike: []string{}, Source: models.VocabularyPlayer},
+				{Phrase: "Grog Strongjaw", SoundsLike: []string{}, Source: models.VocabularyCharacter},
+			},
+		},
+		{
+			description: "seeded name with a custom term of the same phrase, the custom term is kept",
+			storedTerms: []models.VocabularyTerm{
+				{Phrase: "Scanlan", Source: models.VocabularyCharacter},
+				{ID: "term-1", Phrase: "scanlan", SoundsLike: []string{"scan-lan"}, Source: models.VocabularyCustom},
+			},
+			expectedVocabulary: []models.VocabularyTerm{
+				{ID: "term-1", Phrase: "scanlan", SoundsLike: []string{"scan-lan"}, Source: models.VocabularyCustom},
+			},
+		},
+		{
+			description: "seeded names that aren't usable phrases are skipped",
+			storedTerms: []models.VocabularyTerm{
+				{Phrase: "Player 1", Source: models.VocabularyPlayer},
+				{Phrase: "matt_mercer", Source: models.VocabularyPlayer},
+				{Phrase: "Keyleth", Source: models.VocabularyCharacter},
+			},
+			expectedVocabulary: []models.VocabularyTerm{
+				{Phrase: "Keyleth", SoundsLike: []string{}, Source: models.VocabularyCharacter},
+			},
+		},
+		{
+			description:   "database returns an error, error returned",
+			dbError:       dbError,
+			expectedError: dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockCampaignDB{}
+			mockDb.On("GetVocabularyForCampaign", mock.Anything, "cmp123").Return(c.storedTerms, c.dbError)
+			testManager := NewCampaignManager(mockDb, &MockTranscriptFileRemover{})
+
+			vocabulary, err := testManager.GetVocabulary(context.Background(), "cmp123")
+			if c.expectedError != nil {
+				assert.ErrorIs(t, err, c.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedVocabulary, vocabulary)
+		})
+	}
+}
+
+func TestAddVocabularyTerm(t *testing.T) {
+	manyTerms := []models.VocabularyTerm{}
+	for i := 0; i < maxCustomVocabularyTerms; i++ {
+		manyTerms = append(manyTerms, models.VocabularyTerm{ID: fmt.Sprintf("term-%d", i), Phrase: "Tiamat", Source: models.VocabularyCustom})
+	}
+	cases := []struct {
+		description   string
+		term          models.VocabularyTerm
+		storedTerms   []models.VocabularyTerm
+		expectedTerm  models.VocabularyTerm
+		dbError       error
+		expectedError error
+	}{
+		{
+			description:  "term is normalized and added",
+			term:         models.VocabularyTerm{Phrase: "  Bryn   Shander ", SoundsLike: []string{"brin shan der", ""}},
+			storedTerms:  []models.VocabularyTerm{{Phrase: "Icewind Dale", Source: models.VocabularyCampaign}},
+			expectedTerm: models.VocabularyTerm{Phrase: "Bryn Shander", SoundsLike: []string{"brin-shan-der"}, Source: models.VocabularyCustom},
+		},
+		{
+			description:   "phrase is missing, InvalidEntity returned",
+			term:          models.VocabularyTerm{Phrase: " "},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "phrase has digits, InvalidEntity returned",
+			term:          models.VocabularyTerm{Phrase: "Waterdeep 2"},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "sounds like hint has punctuation, InvalidEntity returned",
+			term:          models.VocabularyTerm{Phrase: "Tiamat", SoundsLike: []string{"tee.ah.mat"}},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "campaign has as many custom terms as allowed, Conflicted returned",
+			term:          models.VocabularyTerm{Phrase: "Bahamut"},
+			storedTerms:   manyTerms,
+			expectedError: models.Conflicted,
+		},
+		{
+			description:   "campaign has a term with the phrase, EntityAlreadyExists returned",
+			term:          models.VocabularyTerm{Phrase: "Tiamat"},
+			storedTerms:   []models.VocabularyTerm{},
+			expectedTerm:  models.VocabularyTerm{Phrase: "Tiamat", SoundsLike: []string{}, Source: models.VocabularyCustom},
+			dbError:       models.EntityAlreadyExists,
+			expectedError: models.EntityAlreadyExists,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockCampaignDB{}
+			mockDb.On("GetVocabularyForCampaign", mock.Anything, "cmp123").Return(c.storedTerms, nil)
+			added := c.expectedTerm
+			added.ID = "term-1"
+			mockDb.On("AddVocabularyTerm", mock.Anything, "cmp123", c.expectedTerm).Return(&added, c.dbError)
+			testManager := NewCampaignManager(mockDb, &MockTranscriptFileRemover{})
+
+			result, err := testManager.AddVocabularyTerm(context.Background(), "cmp123", c.term)
+			if c.expectedError != nil {
+				assert.ErrorIs(t, err, c.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, added, *result)
+		})
+	}
+}
+
+func TestUpdateVocabularyTerm(t *testing.T) {
+	newPhrase := "Lady Tiamat"
+	newSoundsLike := []string{"tya-mat"}
+	invalidPhrase := "Tiamat!"
+	storedTerm := models.VocabularyTerm{ID: "term-1", Phrase: "Tiamat", SoundsLike: []string{"tee-ah-mat"}, Source: models.VocabularyCustom}
+	cases := []struct {
+		description   string
+		update        models.VocabularyTermUpdate
+		getError      error
+		expectedTerm  models.VocabularyTerm
+		expectedError error
+	}{
+		{
+			description:  "phrase updated, sounds like hints are kept",
+			update:       models.VocabularyTermUpdate{Phrase: &newPhrase},
+			expectedTerm: models.VocabularyTerm{ID: "term-1", Phrase: newPhrase, SoundsLike: []string{"tee-ah-mat"}, Source: models.VocabularyCustom},
+		},
+		{
+			description:  "sounds like hints replaced",
+			update:       models.VocabularyTermUpdate{SoundsLike: &newSoundsLike},
+			expectedTerm: models.VocabularyTerm{ID: "term-1", Phrase: "Tiamat", SoundsLike: newSoundsLike, Source: models.VocabularyCustom},
+		},
+		{
+			description:   "phrase is invalid, InvalidEntity returned",
+			update:        models.VocabularyTermUpdate{Phrase: &invalidPhrase},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "term does not exist, EntityNotFound returned",
+			update:        models.VocabularyTermUpdate{Phrase: &newPhrase},
+			getError:      models.EntityNotFound,
+			expectedError: models.EntityNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockCampaignDB{}
+			term := storedTerm
+			mockDb.On("GetVocabularyTerm", mock.Anything, "cmp123", "term-1").Return(&term, c.getError)
+			mockDb.On("UpdateVocabularyTerm", mock.Anything, "cmp123", c.expectedTerm).Return(&c.expectedTerm, nil)
+			testManager := NewCampaignManager(mockDb, &MockTranscriptFileRemover{})
+
+			result, err := testManager.UpdateVocabularyTerm(context.Background(), "cmp123", "term-1", c.update)
+			if c.expectedError != nil {
+				assert.ErrorIs(t, err, c.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedTerm, *result)
+		})
+	}
+}
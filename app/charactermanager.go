@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+type characterDB interface {
+	AddCharacter(ctx context.Context, campaignID, playerID string, character models.Character) (*models.Character, error)
+	GetCharactersForPlayer(ctx context.Context, campaignID, playerID string) ([]models.Character, error)
+	GetCharacter(ctx context.Context, campaignID, playerID, characterID string) (*models.Character, error)
+	UpdateCharacter(ctx context.Context, campaignID, playerID string, character models.Character) (*models.Character, error)
+}
+
+type CharacterManager struct {
+	characterDB characterDB
+}
+
+func NewCharacterManager(characterDB characterDB) *CharacterManager {
+	return &CharacterManager{
+		characterDB: characterDB,
+	}
+}
+
+// AddCharacter adds a new active character for the player
+func (c *CharacterManager) AddCharacter(ctx context.Context, campaignID, playerID string, character models.Character) (*models.Character, error) {
+	if character.Name == "" {
+		return nil, fmt.Errorf("missing field: Name %w", models.InvalidEntity)
+	}
+	character.Status = models.CharacterActive
+	return c.characterDB.AddCharacter(ctx, campaignID, playerID, character)
+}
+
+func (c *CharacterManager) GetCharactersForPlayer(ctx context.Context, campaignID, playerID string) ([]models.Character, error) {
+	return c.characterDB.GetCharactersForPlayer(ctx, campaignID, playerID)
+}
+
+func (c *CharacterManager) GetCharacter(ctx context.Context, campaignID, playerID, characterID string) (*models.Character, error) {
+	return c.characterDB.GetCharacter(ctx, campaignID, playerID, characterID)
+}
+
+// UpdateCharacter applies the fields set in the update to an existing character
+func (c *CharacterManager) UpdateCharacter(ctx context.Context, campaignID, playerID, characterID string, update models.CharacterUpdate) (*models.Character, error) {
+	character, err := c.characterDB.GetCharacter(ctx, campaignID, playerID, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if update.Name != nil {
+		character.Name = *update.Name
+	}
+	if update.Link != nil {
+		character.Link = *update.Link
+	}
+	if character.Name == "" {
+		return nil, fmt.Errorf("missing field: Name %w", models.InvalidEntity)
+	}
+	return c.characterDB.UpdateCharacter(ctx, campaignID, playerID, *character)
+}
+
+// RetireCharacter marks an active character as dead or retired, the character is kept for history
+func (c *CharacterManager) RetireCharacter(ctx context.Context, campaignID, playerID, characterID string, status models.CharacterStatus) (*models.Character, error) {
+	if status != models.CharacterDead && status != models.CharacterRetired {
+		return nil, fmt.Errorf("characters can only be retired as Dead or Retired, got %s: %w", status, models.InvalidEntity)
+	}
+	character, err := c.characterDB.GetCharacter(ctx, campaignID, playerID, characterID)
+	if err != nil {
+		return nil, err
+	}
+	if character.Status != models.CharacterActive {
+		return nil, fmt.Errorf("character is already %s: %w", character.Status, models.Conflicted)
+	}
+	character.Status = status
+	return c.characterDB.UpdateCharacter(ctx, campaignID, playerID, *character)
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCharacterDB struct {
+	mock.Mock
+}
+
+func (m *MockCharacterDB) AddCharacter(ctx context.Context, campaignID, playerID string, character models.Character) (*models.Character, error) {
+	args := m.Called(ctx, campaignID, playerID, character)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Character), nil
+}
+
+func (m *MockCharacterDB) GetCharactersForPlayer(ctx context.Context, campaignID, playerID string) ([]models.Character, error) {
+	args := m.Called(ctx, campaignID, playerID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Character), nil
+}
+
+func (m *MockCharacterDB) GetCharacter(ctx context.Context, campaignID, playerID, characterID string) (*models.Character, error) {
+	args := m.Called(ctx, campaignID, playerID, characterID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Character), nil
+}
+
+func (m *MockCharacterDB) UpdateCharacter(ctx context.Context, campaignID, playerID string, character models.Character) (*models.Character, error) {
+	args := m.Called(ctx, campaignID, playerID, character)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Character), nil
+}
+
+func TestAddCharacter(t *testing.T) {
+	dbError := errors.New("db error")
+	cases := []struct {
+		description       string
+		characterToAdd    models.Character
+		expectedCharacter models.Character
+		dbError           error
+		dbResult          *models.Character
+		expectedError     error
+	}{
+		{
+			description:       "character is added as active",
+			characterToAdd:    models.Character{Name: "Vex", Status: models.CharacterDead},
+			expectedCharacter: models.Character{Name: "Vex", Status: models.CharacterActive},
+			dbResult:          &models.Character{ID: "chr-1", OwnerID: "player-1", Name: "Vex", Status: models.CharacterActive},
+		},
+		{
+			description:    "character does not have a name, InvalidEntity returned",
+			characterToAdd: models.Character{Link: "https://example.com"},
+			expectedError:  models.InvalidEntity,
+		},
+		{
+			description:       "database returned an error, error is returned",
+			characterToAdd:    models.Character{Name: "Vex"},
+			expectedCharacter: models.Character{Name: "Vex"},
+			dbError:           dbError,
+			expectedError:     dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockCharacterDB{}
+			if c.dbError != nil {
+				mockDb.On("AddCharacter", mock.Anything, "cmp123", "player-1", c.expectedCharacter).Return(nil, c.dbError)
+			} else {
+				mockDb.On("AddCharacter", mock.Anything, "cmp123", "player-1", c.expectedCharacter).Return(c.dbResult, nil)
+			}
+			testManager := NewCharacterManager(mockDb)
+			result, err := testManager.AddCharacter(context.Background(), "cmp123", "player-1", c.characterToAdd)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			assert.Equal(t, c.dbResult, result)
+		})
+	}
+}
+
+func TestRetireCharacter(t *testing.T) {
+	cases := []struct {
+		description     string
+		status          models.CharacterStatus
+		storedCharacter *models.Character
+		getError        error
+		expectedUpdated *models.Character
+		expectedError   error
+	}{
+		{
+			description:     "active character is retired",
+			status:          models.CharacterRetired,
+			storedCharacter: &models.Character{ID: "chr-1", Name: "Vex", Status: models.CharacterActive},
+			expectedUpdated: &models.Character{ID: "chr-1", Name: "Vex", Status: models.CharacterRetired},
+		},
+		{
+			description:     "active character dies",
+			status:          models.CharacterDead,
+			storedCharacter: &models.Character{ID: "chr-1", Name: "Vex", Status: models.CharacterActive},
+			expectedUpdated: &models.Character{ID: "chr-1", Name: "Vex", Status: models.CharacterDead},
+		},
+		{
+			description:   "retiring as active, InvalidEntity returned",
+			status:        models.CharacterActive,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:     "character already dead, Conflicted returned",
+			status:          models.CharacterRetired,
+			storedCharacter: &models.Character{ID: "chr-1", Name: "Vex", Status: models.CharacterDead},
+			expectedError:   models.Conflicted,
+		},
+		{
+			description:   "character does not exist, EntityNotFound returned",
+			status:        models.CharacterRetired,
+			getError:      models.EntityNotFound,
+			expectedError: models.EntityNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockCharacterDB{}
+			if c.getError != nil {
+				mockDb.On("GetCharacter", mock.Anything, "cmp123", "player-1", "chr-1").Return(nil, c.getError)
+			} else {
+				mockDb.On("GetCharacter", mock.Anything, "cmp123", "player-1", "chr-1").Return(c.storedCharacter, nil)
+			}
+			if c.expectedUpdated != nil {
+				mockDb.On("UpdateCharacter", mock.Anything, "cmp123", "player-1", *c.expectedUpdated).Return(c.expectedUpdated, nil)
+			}
+			testManager := NewCharacterManager(mockDb)
+			result, err := testManager.RetireCharacter(context.Background(), "cmp123", "player-1", "chr-1", c.status)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				mockDb.AssertNotCalled(t, "UpdateCharacter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			assert.Equal(t, c.expectedUpdated, result)
+		})
+	}
+}
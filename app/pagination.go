@@ -0,0 +1,22 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// checkPageRequest applies the default page size and rejects limits a list can't return, as well as cursors
+// taken from the list in another order
+func checkPageRequest(page models.PageRequest) (models.PageRequest, error) {
+	if page.Limit == 0 {
+		page.Limit = models.DefaultPageLimit
+	}
+	if page.Limit < 0 || page.Limit > models.MaxPageLimit {
+		return page, fmt.Errorf("limit must be between 1 and %d: %w", models.MaxPageLimit, models.InvalidEntity)
+	}
+	if page.Cursor != nil && page.Cursor.Sort != page.Sort {
+		return page, fmt.Errorf("cursor was taken from the list in another order: %w", models.InvalidEntity)
+	}
+	return page, nil
+}
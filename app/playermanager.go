@@ -1,10 +1,18 @@
 package app
 
-import "github.com/EdgarH78/dragonspeak-service/models"
+import (
+	"context"
+	"fmt"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
 
 type playerDB interface {
-	AddNewPlayer(campaignID string, player models.Player) (*models.Player, error)
-	GetPlayersForCampaign(campaignID string) ([]models.Player, error)
+	AddNewPlayer(ctx context.Context, campaignID string, player models.Player) (*models.Player, error)
+	GetPlayersForCampaign(ctx context.Context, campaignID string) ([]models.Player, error)
+	GetPlayer(ctx context.Context, campaignID, playerID string) (*models.Player, error)
+	UpdatePlayer(ctx context.Context, campaignID string, player models.Player) (*models.Player, error)
+	DeletePlayer(ctx context.Context, campaignID, playerID string) error
 }
 
 type PlayerManager struct {
@@ -16,3 +24,71 @@ func NewPlayerManager(playerDB playerDB) *PlayerManager {
 		playerDB: playerDB,
 	}
 }
+
+func (p *PlayerManager) AddPlayer(ctx context.Context, campaignID string, player models.Player) (*models.Player, error) {
+	if player.Name == "" {
+		return nil, fmt.Errorf("missing field: Name %w", models.InvalidEntity)
+	}
+	if err := p.checkSingleGM(ctx, campaignID, player); err != nil {
+		return nil, err
+	}
+	return p.playerDB.AddNewPlayer(ctx, campaignID, player)
+}
+
+func (p *PlayerManager) GetPlayersForCampaign(ctx context.Context, campaignID string) ([]models.Player, error) {
+	return p.playerDB.GetPlayersForCampaign(ctx, campaignID)
+}
+
+func (p *PlayerManager) GetPlayer(ctx context.Context, campaignID, playerID string) (*models.Player, error) {
+	return p.playerDB.GetPlayer(ctx, campaignID, playerID)
+}
+
+// UpdatePlayer applies the fields set in the update to an existing player
+func (p *PlayerManager) UpdatePlayer(ctx context.Context, campaignID, playerID string, update models.PlayerUpdate) (*models.Player, error) {
+	player, err := p.playerDB.GetPlayer(ctx, campaignID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if update.Name != nil {
+		player.Name = *update.Name
+	}
+	if update.Type != nil {
+		player.Type = *update.Type
+	}
+	if player.Name == "" {
+		return nil, fmt.Errorf("missing field: Name %w", models.InvalidEntity)
+	}
+	if err := p.checkSingleGM(ctx, campaignID, *player); err != nil {
+		return nil, err
+	}
+	return p.playerDB.UpdatePlayer(ctx, campaignID, *player)
+}
+
+func (p *PlayerManager) DeletePlayer(ctx context.Context, campaignID, playerID string) error {
+	return p.playerDB.DeletePlayer(ctx, campaignID, playerID)
+}
+
+// checkSingleGM returns Conflicted when the player is a GM and the campaign already has a different GM
+func (p *PlayerManager) checkSingleGM(ctx context.Context, campaignID string, player models.Player) error {
+	if player.Type != models.GM {
+		return nil
+	}
+	players, err := p.playerDB.GetPlayersForCampaign(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	if hasOtherGM(players, player.ID) {
+		return fmt.Errorf("campaign already has a GM: %w", models.Conflicted)
+	}
+	return nil
+}
+
+// hasOtherGM reports whether a player other than playerID is the GM, a campaign has a single GM
+func hasOtherGM(players []models.Player, playerID string) bool {
+	for _, existing := range players {
+		if existing.Type == models.GM && existing.ID != playerID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPlayerDB struct {
+	mock.Mock
+}
+
+func (m *MockPlayerDB) AddNewPlayer(ctx context.Context, campaignID string, player models.Player) (*models.Player, error) {
+	args := m.Called(ctx, campaignID, player)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Player), nil
+}
+
+func (m *MockPlayerDB) GetPlayersForCampaign(ctx context.Context, campaignID string) ([]models.Player, error) {
+	args := m.Called(ctx, campaignID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Player), nil
+}
+
+func (m *MockPlayerDB) GetPlayer(ctx context.Context, campaignID, playerID string) (*models.Player, error) {
+	args := m.Called(ctx, campaignID, playerID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Player), nil
+}
+
+func (m *MockPlayerDB) UpdatePlayer(ctx context.Context, campaignID string, player models.Player) (*models.Player, error) {
+	args := m.Called(ctx, campaignID, player)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Player), nil
+}
+
+func (m *MockPlayerDB) DeletePlayer(ctx context.Context, campaignID, playerID string) error {
+	args := m.Called(ctx, campaignID, playerID)
+	return args.Error(0)
+}
+
+func TestAddPlayer(t *testing.T) {
+	dbError := errors.New("db error")
+	cases := []struct {
+		description     string
+		playerToAdd     models.Player
+		existingPlayers []models.Player
+		dbError         error
+		dbResult        *models.Player
+		expectedError   error
+		expectedResult  *models.Player
+	}{
+		{
+			description:    "player is added to the database",
+			playerToAdd:    models.Player{Name: "Laura", Type: models.StandardPlayer},
+			dbResult:       &models.Player{ID: "player-1", Name: "Laura", Type: models.StandardPlayer},
+			expectedResult: &models.Player{ID: "player-1", Name: "Laura", Type: models.StandardPlayer},
+		},
+		{
+			description:     "first GM is added to the campaign",
+			playerToAdd:     models.Player{Name: "Matt", Type: models.GM},
+			existingPlayers: []models.Player{{ID: "player-1", Name: "Laura", Type: models.StandardPlayer}},
+			dbResult:        &models.Player{ID: "player-2", Name: "Matt", Type: models.GM},
+			expectedResult:  &models.Player{ID: "player-2", Name: "Matt", Type: models.GM},
+		},
+		{
+			description:     "campaign already has a GM, Conflicted returned",
+			playerToAdd:     models.Player{Name: "Sam", Type: models.GM},
+			existingPlayers: []models.Player{{ID: "player-2", Name: "Matt", Type: models.GM}},
+			expectedError:   models.Conflicted,
+		},
+		{
+			description:   "player does not have a name, InvalidEntity returned",
+			playerToAdd:   models.Player{Type: models.StandardPlayer},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "database returned an error, error is returned",
+			playerToAdd:   models.Player{Name: "Laura", Type: models.StandardPlayer},
+			dbError:       dbError,
+			expectedError: dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockPlayerDB{}
+			mockDb.On("GetPlayersForCampaign", mock.Anything, "cmp123").Return(c.existingPlayers, nil)
+			if c.dbError != nil {
+				mockDb.On("AddNewPlayer", mock.Anything, "cmp123", c.playerToAdd).Return(nil, c.dbError)
+			} else {
+				mockDb.On("AddNewPlayer", mock.Anything, "cmp123", c.playerToAdd).Return(c.dbResult, nil)
+			}
+			testManager := NewPlayerManager(mockDb)
+			result, err := testManager.AddPlayer(context.Background(), "cmp123", c.playerToAdd)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			assert.Equal(t, c.expectedResult, result)
+		})
+	}
+}
+
+func TestUpdatePlayer(t *testing.T) {
+	newName := "Matthew"
+	emptyName := ""
+	gm := models.GM
+	cases := []struct {
+		description     string
+		playerID        string
+		update          models.PlayerUpdate
+		storedPlayer    *models.Player
+		getError        error
+		existingPlayers []models.Player
+		expectedUpdated *models.Player
+		expectedError   error
+	}{
+		{
+			description:     "name is updated and type is kept",
+			playerID:        "player-2",
+			update:          models.PlayerUpdate{Name: &newName},
+			storedPlayer:    &models.Player{ID: "player-2", Name: "Matt", Type: models.GM},
+			existingPlayers: []models.Player{{ID: "player-2", Name: "Matt", Type: models.GM}},
+			expectedUpdated: &models.Player{ID: "player-2", Name: "Matthew", Type: models.GM},
+		},
+		{
+			description:     "player promoted to GM while another GM exists, Conflicted returned",
+			playerID:        "player-1",
+			update:          models.PlayerUpdate{Type: &gm},
+			storedPlayer:    &models.Player{ID: "player-1", Name: "Laura", Type: models.StandardPlayer},
+			existingPlayers: []models.Player{{ID: "player-2", Name: "Matt", Type: models.GM}},
+			expectedError:   models.Conflicted,
+		},
+		{
+			description:   "name cleared, InvalidEntity returned",
+			playerID:      "player-1",
+			update:        models.PlayerUpdate{Name: &emptyName},
+			storedPlayer:  &models.Player{ID: "player-1", Name: "Laura", Type: models.StandardPlayer},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "player does not exist, EntityNotFound returned",
+			playerID:      "missing",
+			update:        models.PlayerUpdate{Name: &newName},
+			getError:      models.EntityNotFound,
+			expectedError: models.EntityNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockPlayerDB{}
+			if c.getError != nil {
+				mockDb.On("GetPlayer", mock.Anything, "cmp123", c.playerID).Return(nil, c.getError)
+			} else {
+				mockDb.On("GetPlayer", mock.Anything, "cmp123", c.playerID).Return(c.storedPlayer, nil)
+			}
+			mockDb.On("GetPlayersForCampaign", mock.Anything, "cmp123").Return(c.existingPlayers, nil)
+			if c.expectedUpdated != nil {
+				mockDb.On("UpdatePlayer", mock.Anything, "cmp123", *c.expectedUpdated).Return(c.expectedUpdated, nil)
+			}
+			testManager := NewPlayerManager(mockDb)
+			result, err := testManager.UpdatePlayer(context.Background(), "cmp123", c.playerID, c.update)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				mockDb.AssertNotCalled(t, "UpdatePlayer", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			assert.Equal(t, c.expectedUpdated, result)
+		})
+	}
+}
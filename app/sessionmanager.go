@@ -9,16 +9,25 @@ import (
 
 type sessionDb interface {
 	AddSession(ctx context.Context, campaignID string, session models.Session) (*models.Session, error)
-	GetSessionsForCampaign(ctx context.Context, campaignID string) ([]models.Session, error)
+	GetSessionsForCampaign(ctx context.Context, campaignID string, filter models.SessionFilter, page models.PageRequest) (*models.Page[models.Session], error)
+	SetSessionAttendance(ctx context.Context, campaignID, sessionID string, playerIDs []string) error
+	GetSessionAttendance(ctx context.Context, campaignID, sessionID string) ([]models.Player, error)
+	GetCampaignAttendance(ctx context.Context, campaignID string) (*models.AttendanceReport, error)
+	GetSession(ctx context.Context, campaignID, sessionID string) (*models.Session, error)
+	UpdateSession(ctx context.Context, campaignID string, session models.Session) (*models.Session, error)
+	DeleteSession(ctx context.Context, campaignID, sessionID string) error
+	GetTranscriptsForSession(ctx context.Context, sessionID string) ([]models.Transcript, error)
 }
 
 type SessionManager struct {
-	sessionDb sessionDb
+	sessionDb       sessionDb
+	transcriptFiles transcriptFileRemover
 }
 
-func NewSessionManager(sessionDb sessionDb) *SessionManager {
+func NewSessionManager(sessionDb sessionDb, transcriptFiles transcriptFileRemover) *SessionManager {
 	return &SessionManager{
-		sessionDb: sessionDb,
+		sessionDb:       sessionDb,
+		transcriptFiles: transcriptFiles,
 	}
 }
 
@@ -29,6 +38,78 @@ func (s *SessionManager) AddSession(ctx context.Context, campaignID string, sess
 	return s.sessionDb.AddSession(ctx, campaignID, session)
 }
 
-func (s *SessionManager) GetSessionsForCampaign(ctx context.Context, campaignID string) ([]models.Session, error) {
-	return s.sessionDb.GetSessionsForCampaign(ctx, campaignID)
+// GetSessionsForCampaign returns a page of the sessions of a campaign held within the filter's date range
+func (s *SessionManager) GetSessionsForCampaign(ctx context.Context, campaignID string, filter models.SessionFilter, page models.PageRequest) (*models.Page[models.Session], error) {
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, fmt.Errorf("from date is after to date: %w", models.InvalidEntity)
+	}
+	page, err := checkPageRequest(page)
+	if err != nil {
+		return nil, err
+	}
+	return s.sessionDb.GetSessionsForCampaign(ctx, campaignID, filter, page)
+}
+
+func (s *SessionManager) GetSession(ctx context.Context, campaignID, sessionID string) (*models.Session, error) {
+	return s.sessionDb.GetSession(ctx, campaignID, sessionID)
+}
+
+// UpdateSession applies the fields set in the update to an existing session
+func (s *SessionManager) UpdateSession(ctx context.Context, campaignID, sessionID string, update models.SessionUpdate) (*models.Session, error) {
+	session, err := s.sessionDb.GetSession(ctx, campaignID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Version != update.Version {
+		return nil, fmt.Errorf("session %s is at version %d: %w", sessionID, session.Version, models.Conflicted)
+	}
+	if update.Title != nil {
+		session.Title = *update.Title
+	}
+	if update.SessionDate != nil {
+		session.SessionDate = *update.SessionDate
+	}
+	if session.Title == "" {
+		return nil, fmt.Errorf("missing field: Title %w", models.InvalidEntity)
+	}
+	return s.sessionDb.UpdateSession(ctx, campaignID, *session)
+}
+
+// DeleteSession removes a session with its attendance and transcripts, including the transcripts' files
+func (s *SessionManager) DeleteSession(ctx context.Context, campaignID, sessionID string) error {
+	transcripts, err := s.sessionDb.GetTranscriptsForSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if err = s.sessionDb.DeleteSession(ctx, campaignID, sessionID); err != nil {
+		return err
+	}
+	s.transcriptFiles.DeleteTranscriptFiles(ctx, transcripts)
+	return nil
+}
+
+// SetAttendance replaces the players that attended a session and returns the stored attendance
+func (s *SessionManager) SetAttendance(ctx context.Context, campaignID, sessionID string, playerIDs []string) ([]models.Player, error) {
+	seen := map[string]bool{}
+	for _, playerID := range playerIDs {
+		if playerID == "" {
+			return nil, fmt.Errorf("missing field: PlayerID %w", models.InvalidEntity)
+		}
+		if seen[playerID] {
+			return nil, fmt.Errorf("player %s listed more than once: %w", playerID, models.InvalidEntity)
+		}
+		seen[playerID] = true
+	}
+	if err := s.sessionDb.SetSessionAttendance(ctx, campaignID, sessionID, playerIDs); err != nil {
+		return nil, err
+	}
+	return s.sessionDb.GetSessionAttendance(ctx, campaignID, sessionID)
+}
+
+func (s *SessionManager) GetAttendance(ctx context.Context, campaignID, sessionID string) ([]models.Player, error) {
+	return s.sessionDb.GetSessionAttendance(ctx, campaignID, sessionID)
+}
+
+func (s *SessionManager) GetAttendanceReport(ctx context.Context, campaignID string) (*models.AttendanceReport, error) {
+	return s.sessionDb.GetCampaignAttendance(ctx, campaignID)
 }
@@ -23,16 +23,67 @@ func (m *MockSessionDB) AddSession(ctx context.Context, campaignID string, sessi
 	return args.Get(0).(*models.Session), nil
 }
 
-func (m *MockSessionDB) GetSessionsForCampaign(ctx context.Context, campaignID string) ([]models.Session, error) {
+func (m *MockSessionDB) GetSessionsForCampaign(ctx context.Context, campaignID string, filter models.SessionFilter, page models.PageRequest) (*models.Page[models.Session], error) {
+	args := m.Called(ctx, campaignID, filter, page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Session]), nil
+}
+
+func (m *MockSessionDB) SetSessionAttendance(ctx context.Context, campaignID, sessionID string, playerIDs []string) error {
+	args := m.Called(ctx, campaignID, sessionID, playerIDs)
+	return args.Error(0)
+}
+
+func (m *MockSessionDB) GetSessionAttendance(ctx context.Context, campaignID, sessionID string) ([]models.Player, error) {
+	args := m.Called(ctx, campaignID, sessionID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Player), nil
+}
+
+func (m *MockSessionDB) GetCampaignAttendance(ctx context.Context, campaignID string) (*models.AttendanceReport, error) {
 	args := m.Called(ctx, campaignID)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]models.Session), nil
+	return args.Get(0).(*models.AttendanceReport), nil
+}
+
+func (m *MockSessionDB) GetSession(ctx context.Context, campaignID, sessionID string) (*models.Session, error) {
+	args := m.Called(ctx, campaignID, sessionID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), nil
+}
+
+func (m *MockSessionDB) UpdateSession(ctx context.Context, campaignID string, session models.Session) (*models.Session, error) {
+	args := m.Called(ctx, campaignID, session)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), nil
+}
+
+func (m *MockSessionDB) DeleteSession(ctx context.Context, campaignID, sessionID string) error {
+	args := m.Called(ctx, campaignID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockSessionDB) GetTranscriptsForSession(ctx context.Context, sessionID string) ([]models.Transcript, error) {
+	args := m.Called(ctx, sessionID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Transcript), nil
 }
 
 func TestAddSession(t *testing.T) {
 	dbError := errors.New("db error")
+	sessionDate := time.Now()
 	cases := []struct {
 		description    string
 		campaignID     string
@@ -46,16 +97,16 @@ func TestAddSession(t *testing.T) {
 			description: "session is added to the database",
 			campaignID:  "testCampaign123",
 			sessionToAdd: models.Session{
-				SessionDate: time.Now(),
+				SessionDate: sessionDate,
 				Title:       "session-0",
 			},
 			dbResult: &models.Session{
-				SessionDate: time.Now(),
+				SessionDate: sessionDate,
 				Title:       "session-0",
 				ID:          "abc123",
 			},
 			expectedResult: &models.Session{
-				SessionDate: time.Now(),
+				SessionDate: sessionDate,
 				Title:       "session-0",
 				ID:          "abc123",
 			},
@@ -63,14 +114,14 @@ func TestAddSession(t *testing.T) {
 		{
 			description: "session does not have a title, InvalidEntity returned",
 			sessionToAdd: models.Session{
-				SessionDate: time.Now(),
+				SessionDate: sessionDate,
 			},
 			expectedError: models.InvalidEntity,
 		},
 		{
 			description: "database returned an error, error is returned",
 			sessionToAdd: models.Session{
-				SessionDate: time.Now(),
+				SessionDate: sessionDate,
 				Title:       "session-0",
 			},
 			dbError:       dbError,
@@ -88,7 +139,7 @@ func TestAddSession(t *testing.T) {
 			} else {
 				mockDb.On("AddSession", mock.Anything, c.campaignID, c.sessionToAdd).Return(c.dbResult, nil)
 			}
-			testManager := NewSessionManager(mockDb)
+			testManager := NewSessionManager(mockDb, &MockTranscriptFileRemover{})
 			result, err := testManager.AddSession(context.Background(), c.campaignID, c.sessionToAdd)
 			if err != nil && c.expectedError == nil {
 				t.Errorf("unexpected error returned: %s", err)
@@ -115,9 +166,14 @@ func TestAddSession(t *testing.T) {
 
 func TestGetSessionsForCampaign(t *testing.T) {
 	dbError := errors.New("db error")
+	defaultPage := models.PageRequest{Limit: models.DefaultPageLimit}
+	sessionDate := time.Now()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
 	cases := []struct {
 		description    string
 		campaignID     string
+		filter         models.SessionFilter
 		dbError        error
 		dbResult       []models.Session
 		expectedError  error
@@ -129,28 +185,41 @@ func TestGetSessionsForCampaign(t *testing.T) {
 			dbResult: []models.Session{
 				{
 					Title:       "session-0",
-					SessionDate: time.Now(),
+					SessionDate: sessionDate,
 					ID:          "abc123",
 				},
 				{
 					Title:       "session-1",
-					SessionDate: time.Now(),
+					SessionDate: sessionDate,
 					ID:          "abc456",
 				},
 			},
 			expectedResult: []models.Session{
 				{
 					Title:       "session-0",
-					SessionDate: time.Now(),
+					SessionDate: sessionDate,
 					ID:          "abc123",
 				},
 				{
 					Title:       "session-1",
-					SessionDate: time.Now(),
+					SessionDate: sessionDate,
 					ID:          "abc456",
 				},
 			},
 		},
+		{
+			description:    "sessions filtered on a date range",
+			campaignID:     "campaign123",
+			filter:         models.SessionFilter{From: &from, To: &to},
+			dbResult:       []models.Session{},
+			expectedResult: []models.Session{},
+		},
+		{
+			description:   "from date after to date, InvalidEntity returned",
+			campaignID:    "campaign123",
+			filter:        models.SessionFilter{From: &to, To: &from},
+			expectedError: models.InvalidEntity,
+		},
 		{
 			description:   "database returns error",
 			campaignID:    "campaign123",
@@ -165,24 +234,24 @@ func TestGetSessionsForCampaign(t *testing.T) {
 
 			mockDb := &MockSessionDB{}
 			if c.dbError != nil {
-				mockDb.On("GetSessionsForCampaign", mock.Anything, c.campaignID).Return(nil, c.dbError)
+				mockDb.On("GetSessionsForCampaign", mock.Anything, c.campaignID, c.filter, defaultPage).Return(nil, c.dbError)
 			} else {
-				mockDb.On("GetSessionsForCampaign", mock.Anything, c.campaignID).Return(c.dbResult, nil)
+				mockDb.On("GetSessionsForCampaign", mock.Anything, c.campaignID, c.filter, defaultPage).Return(&models.Page[models.Session]{Items: c.dbResult}, nil)
 			}
-			testManager := NewSessionManager(mockDb)
-			result, err := testManager.GetSessionsForCampaign(context.Background(), c.campaignID)
+			testManager := NewSessionManager(mockDb, &MockTranscriptFileRemover{})
+			result, err := testManager.GetSessionsForCampaign(context.Background(), c.campaignID, c.filter, models.PageRequest{})
 			if err != nil && c.expectedError == nil {
 				t.Errorf("unexpected error returned: %s", err)
 				return
 			}
 
 			if c.expectedResult != nil {
-				if len(c.expectedResult) != len(result) {
-					t.Errorf("expected %d campaigns got %d", len(c.expectedResult), len(result))
+				if len(c.expectedResult) != len(result.Items) {
+					t.Errorf("expected %d campaigns got %d", len(c.expectedResult), len(result.Items))
 					return
 				}
 				for i, expectedSession := range c.expectedResult {
-					actualSession := result[i]
+					actualSession := result.Items[i]
 					assert.Equal(t, expectedSession.SessionDate, actualSession.SessionDate)
 					assert.Equal(t, expectedSession.Title, actualSession.Title)
 					assert.Equal(t, expectedSession.ID, actualSession.ID)
@@ -200,3 +269,130 @@ func TestGetSessionsForCampaign(t *testing.T) {
 		})
 	}
 }
+
+func TestSetAttendance(t *testing.T) {
+	dbError := errors.New("db error")
+	cases := []struct {
+		description    string
+		playerIDs      []string
+		setError       error
+		storedPlayers  []models.Player
+		expectedError  error
+		expectedResult []models.Player
+	}{
+		{
+			description:    "attendance stored and returned",
+			playerIDs:      []string{"player-1", "player-2"},
+			storedPlayers:  []models.Player{{ID: "player-1", Name: "Laura"}, {ID: "player-2", Name: "Matt"}},
+			expectedResult: []models.Player{{ID: "player-1", Name: "Laura"}, {ID: "player-2", Name: "Matt"}},
+		},
+		{
+			description:    "empty attendance clears the session",
+			playerIDs:      []string{},
+			storedPlayers:  []models.Player{},
+			expectedResult: []models.Player{},
+		},
+		{
+			description:   "player listed twice, InvalidEntity returned",
+			playerIDs:     []string{"player-1", "player-1"},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "blank player id, InvalidEntity returned",
+			playerIDs:     []string{""},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "database returned an error, error is returned",
+			playerIDs:     []string{"player-1"},
+			setError:      dbError,
+			expectedError: dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockSessionDB{}
+			mockDb.On("SetSessionAttendance", mock.Anything, "cmp123", "ses123", c.playerIDs).Return(c.setError)
+			mockDb.On("GetSessionAttendance", mock.Anything, "cmp123", "ses123").Return(c.storedPlayers, nil)
+			testManager := NewSessionManager(mockDb, &MockTranscriptFileRemover{})
+			result, err := testManager.SetAttendance(context.Background(), "cmp123", "ses123", c.playerIDs)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			assert.Equal(t, c.expectedResult, result)
+		})
+	}
+}
+
+func TestUpdateSession(t *testing.T) {
+	sessionDate := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	newDate := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+	storedSession := models.Session{ID: "ses123", Title: "The Heist", SessionDate: sessionDate, Version: 2}
+	newTitle := "The Great Heist"
+	emptyTitle := ""
+	cases := []struct {
+		description     string
+		update          models.SessionUpdate
+		getError        error
+		expectedSession models.Session
+		expectedError   error
+	}{
+		{
+			description:     "title updated, date kept",
+			update:          models.SessionUpdate{Title: &newTitle, Version: 2},
+			expectedSession: models.Session{ID: "ses123", Title: newTitle, SessionDate: sessionDate, Version: 2},
+		},
+		{
+			description:     "date updated, title kept",
+			update:          models.SessionUpdate{SessionDate: &newDate, Version: 2},
+			expectedSession: models.Session{ID: "ses123", Title: "The Heist", SessionDate: newDate, Version: 2},
+		},
+		{
+			description:   "title cleared, InvalidEntity returned",
+			update:        models.SessionUpdate{Title: &emptyTitle, Version: 2},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "update made from a stale version, Conflicted returned",
+			update:        models.SessionUpdate{Title: &newTitle, Version: 1},
+			expectedError: models.Conflicted,
+		},
+		{
+			description:   "session not found, EntityNotFound returned",
+			update:        models.SessionUpdate{Title: &newTitle, Version: 2},
+			getError:      models.EntityNotFound,
+			expectedError: models.EntityNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockSessionDB{}
+			session := storedSession
+			mockDb.On("GetSession", mock.Anything, "cmp123", "ses123").Return(&session, c.getError)
+			mockDb.On("UpdateSession", mock.Anything, "cmp123", c.expectedSession).Return(&c.expectedSession, nil)
+			testManager := NewSessionManager(mockDb, &MockTranscriptFileRemover{})
+			result, err := testManager.UpdateSession(context.Background(), "cmp123", "ses123", c.update)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				mockDb.AssertNotCalled(t, "UpdateSession", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			assert.Equal(t, c.expectedSession, *result)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// jobEventParser is implemented by the transcription providers that notify the service when a job changes state
+type jobEventParser interface {
+	ParseJobEvent(data []byte) (*models.TranscriptionJobEvent, error)
+}
+
+// HandleTranscriptionJobEvent advances the transcript of a job state change event sent by the named provider.
+// Events of jobs the service doesn't know, of an attempt that was retried since and of jobs that already left
+// ProviderStarted are ignored, so the provider can deliver an event more than once. EntityNotFound is returned
+// for a provider that isn't registered or doesn't send events and InvalidEntity for an event that can't be read.
+func (t *TranscriptionManager) HandleTranscriptionJobEvent(ctx context.Context, providerName string, data []byte) error {
+	name, provider, err := t.providers.provider(providerName)
+	if err != nil {
+		return fmt.Errorf("transcription provider %q is not registered: %w", providerName, models.EntityNotFound)
+	}
+	parser, ok := provider.(jobEventParser)
+	if !ok {
+		return fmt.Errorf("transcription provider %q doesn't send job events: %w", name, models.EntityNotFound)
+	}
+	event, err := parser.ParseJobEvent(data)
+	if err != nil || event == nil {
+		return err
+	}
+
+	transcript, err := t.transcriptForJobName(ctx, event.JobName)
+	if errors.Is(err, models.EntityNotFound) {
+		log.Printf("ignoring %s event of unknown transcription job %s", event.Status, event.JobName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if transcript.Provider != name || providerJobName(*transcript) != event.JobName || transcript.JobState != models.JobProviderStarted {
+		log.Printf("ignoring %s event of transcription job %s, transcript %s is %s on attempt %d", event.Status, event.JobName, transcript.JobID, transcript.JobState, transcript.Attempts)
+		return nil
+	}
+	return t.advanceTranscriptionJob(ctx, *transcript, event.Status, event.FailureReason)
+}
+
+// transcriptForJobName returns the transcript a provider job was started for, the job of a retried transcript
+// is named after the transcript with the attempt as a suffix
+func (t *TranscriptionManager) transcriptForJobName(ctx context.Context, jobName string) (*models.Transcript, error) {
+	transcript, err := t.transcriptionDb.GetTranscript(ctx, jobName)
+	if !errors.Is(err, models.EntityNotFound) {
+		return transcript, err
+	}
+	i := strings.LastIndex(jobName, "-")
+	if attempt, convErr := strconv.Atoi(jobName[i+1:]); i < 0 || convErr != nil || attempt < 2 {
+		return nil, err
+	}
+	return t.transcriptionDb.GetTranscript(ctx, jobName[:i])
+}
@@ -4,24 +4,51 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/google/uuid"
 )
 
+// downloadURLExpiry is how long a presigned URL to a transcript file can be used
+const downloadURLExpiry = 15 * time.Minute
+
 type transcriptionProvider interface {
-	StartTranscriptionJob(jobName, audioLocation, resultLocation string, audioFormat models.AudioFormat) error
+	// StartTranscriptionJob treats a job that was already started under jobName as started
+	StartTranscriptionJob(jobName, audioLocation, resultLocation string, audioFormat models.AudioFormat, options models.TranscriptionOptions) error
+	GetTranscriptionJobStatus(jobName string) (models.TranscriptionJobStatus, error)
+	ParseTranscript(data []byte) (*models.TranscriptDocument, error)
+}
+
+type summarizer interface {
+	Summarize(ctx context.Context, transcript string) (string, error)
 }
 
 type fileStore interface {
 	UploadData(bucket, fileKey string, body io.Reader) error
 	DownloadData(bucket, fileKey string, w io.WriterAt) (int64, error)
+	DeleteData(bucket, fileKey string) error
+	PresignDownload(bucket, fileKey string, expiry time.Duration) (*models.PresignedURL, error)
 }
 
 type transcriptionDb interface {
-	AddTranscriptToSession(ctx context.Context, sessionID string, transcript models.Transcript) (*models.Transcript, error)
-	GetTranscriptsForSession(ctx context.Context, sessionID string) ([]models.Transcript, error)
+	AddTranscriptToSession(ctx context.Context, sessionID string, transcript models.Transcript, message models.OutboxMessage) (*models.Transcript, error)
+	AdvanceTranscriptJob(ctx context.Context, transcript models.Transcript, from models.JobState, next *models.OutboxMessage) (*models.Transcript, error)
+	ClaimOutboxMessages(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error)
+	RetryOutboxMessage(ctx context.Context, jobID, lastError string, nextAttemptAt time.Time) error
+	ListTranscriptsForSession(ctx context.Context, sessionID string, filter models.TranscriptFilter, page models.PageRequest) (*models.Page[models.Transcript], error)
 	GetTranscript(ctx context.Context, jobID string) (*models.Transcript, error)
+	GetTranscriptsByStatus(ctx context.Context, status models.TranscriptStatus) ([]models.Transcript, error)
+	CountSessionAttendees(ctx context.Context, sessionID string) (int, error)
+	GetSessionLanguage(ctx context.Context, sessionID string) (string, error)
+	GetVocabularyForSession(ctx context.Context, sessionID string) ([]models.VocabularyTerm, error)
+	SetTranscriptSpeakers(ctx context.Context, jobID string, assignments []models.SpeakerAssignment) error
+	GetTranscriptSpeakers(ctx context.Context, jobID string) ([]models.SpeakerAssignment, error)
+	MoveTranscriptToSession(ctx context.Context, jobID, campaignID, sessionID string, version int) error
+	DeleteTranscript(ctx context.Context, jobID string) error
 }
 
 type uuidProvider interface {
@@ -37,57 +64,355 @@ func (d *DefaultUUIDProvider) NewUUID() string {
 }
 
 type TranscriptionManager struct {
-	bucket                string
-	transcriptionProvider transcriptionProvider
-	fileStore             fileStore
-	transcriptionDb       transcriptionDb
-	uuidProvider          uuidProvider
+	bucket          string
+	providers       *TranscriptionProviders
+	fileStore       fileStore
+	transcriptionDb transcriptionDb
+	uuidProvider    uuidProvider
+	summarizer      summarizer
+	maxAudioBytes   int64
 }
 
-func NewTranscriptionManager(bucket string, transcriptionProvider transcriptionProvider, fileSfileStore fileStore, tratranscriptionDb transcriptionDb, uuidProvider uuidProvider) *TranscriptionManager {
+func NewTranscriptionManager(bucket string, providers *TranscriptionProviders, fileSfileStore fileStore, tratranscriptionDb transcriptionDb, uuidProvider uuidProvider, summarizer summarizer, maxAudioBytes int64) *TranscriptionManager {
 	return &TranscriptionManager{
-		bucket:                bucket,
-		transcriptionProvider: transcriptionProvider,
-		fileStore:             fileSfileStore,
-		transcriptionDb:       tratranscriptionDb,
-		uuidProvider:          uuidProvider,
+		bucket:          bucket,
+		providers:       providers,
+		fileStore:       fileSfileStore,
+		transcriptionDb: tratranscriptionDb,
+		uuidProvider:    uuidProvider,
+		summarizer:      summarizer,
+		maxAudioBytes:   maxAudioBytes,
 	}
 }
 
-func (t *TranscriptionManager) SubmitTranscriptionJob(ctx context.Context, userID, campaignID, sessionID string, audioFormat models.AudioFormat, audioFile io.Reader) (*models.Transcript, error) {
-	jobID := t.uuidProvider.NewUUID()
-	audioLocation := fmt.Sprintf("audio-%s", t.uuidProvider.NewUUID())
-	transcriptLocation := fmt.Sprintf("transcript-%s", t.uuidProvider.NewUUID())
-	transcriptionJob := models.Transcript{
+// SubmitTranscriptionJob uploads the audio of a session and starts transcribing it. When options do not set the
+// number of speakers it defaults to the number of players attending the session, when they do not set the
+// language it defaults to the campaign's default language. The campaign's vocabulary is passed to the provider.
+// The transcript is stored before the audio is uploaded, a job whose upload never completes is failed after
+// uploadExpiry. Audio over the size limit is rejected with TooLarge, audio that stops short or isn't in the
+// declared format with InvalidEntity, in all cases nothing is kept.
+func (t *TranscriptionManager) SubmitTranscriptionJob(ctx context.Context, userID, campaignID, sessionID string, audio models.AudioUpload, options models.TranscriptionOptions) (*models.Transcript, error) {
+	if audio.Size > t.maxAudioBytes {
+		return nil, fmt.Errorf("audio is larger than %d bytes: %w", t.maxAudioBytes, models.TooLarge)
+	}
+	if err := t.CheckTranscriptionOptions(options); err != nil {
+		return nil, err
+	}
+	body, err := sniffAudio(audio.Body, audio.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	storedAudio := models.StoredAudio{
+		Location: fmt.Sprintf("audio-%s", t.uuidProvider.NewUUID()),
+		Format:   audio.Format,
+	}
+	transcript, options, err := t.newTranscriptionJob(ctx, sessionID, t.uuidProvider.NewUUID(), storedAudio, options)
+	if err != nil {
+		return nil, err
+	}
+	transcript.JobState = models.JobPendingUpload
+	expireUpload := models.OutboxMessage{
+		JobID:         transcript.JobID,
+		Step:          models.ExpireUploadStep,
+		NextAttemptAt: time.Now().Add(uploadExpiry),
+	}
+	if _, err = t.transcriptionDb.AddTranscriptToSession(ctx, sessionID, *transcript, expireUpload); err != nil {
+		return nil, err
+	}
+
+	audioReader := newAudioReader(body, t.maxAudioBytes)
+	err = t.fileStore.UploadData(t.bucket, storedAudio.Location, audioReader)
+	if completeErr := audioReader.checkComplete(audio.Size); completeErr != nil {
+		err = completeErr
+	}
+	if err != nil {
+		t.discardTranscriptionJob(ctx, *transcript)
+		return nil, err
+	}
+
+	transcript.AudioSHA256 = audioReader.sha256()
+	transcript.AudioSize = audioReader.size
+	transcript.JobState = models.JobUploaded
+	start := startTranscriptionMessage(transcript.JobID, options)
+	uploaded, err := t.transcriptionDb.AdvanceTranscriptJob(ctx, *transcript, models.JobPendingUpload, &start)
+	if err != nil {
+		t.discardTranscriptionJob(ctx, *transcript)
+		return nil, err
+	}
+	return t.startSubmittedTranscription(ctx, *uploaded, start), nil
+}
+
+// SubmitStoredTranscriptionJob starts transcribing audio of a session that is already in the file store as the
+// transcript jobID, which the caller picks so it can claim the audio before submitting it. The audio is left in
+// place when this fails, it's up to the caller to keep or delete it.
+func (t *TranscriptionManager) SubmitStoredTranscriptionJob(ctx context.Context, sessionID, jobID string, audio models.StoredAudio, options models.TranscriptionOptions) (*models.Transcript, error) {
+	transcript, options, err := t.newTranscriptionJob(ctx, sessionID, jobID, audio, options)
+	if err != nil {
+		return nil, err
+	}
+	transcript.JobState = models.JobUploaded
+	start := startTranscriptionMessage(transcript.JobID, options)
+	added, err := t.transcriptionDb.AddTranscriptToSession(ctx, sessionID, *transcript, start)
+	if err != nil {
+		return nil, err
+	}
+	return t.startSubmittedTranscription(ctx, *added, start), nil
+}
+
+// newTranscriptionJob resolves the options audio of a session is transcribed with and describes the transcript
+// of the job, which isn't stored yet
+func (t *TranscriptionManager) newTranscriptionJob(ctx context.Context, sessionID, jobID string, audio models.StoredAudio, options models.TranscriptionOptions) (*models.Transcript, models.TranscriptionOptions, error) {
+	options, err := t.transcriptionOptions(ctx, sessionID, options)
+	if err != nil {
+		return nil, options, err
+	}
+	return &models.Transcript{
 		JobID:              jobID,
-		AudioLocation:      audioLocation,
-		AudioFormat:        audioFormat,
-		TranscriptLocation: transcriptLocation,
-		Status:             models.Transcribing,
+		AudioLocation:      audio.Location,
+		AudioFormat:        audio.Format,
+		TranscriptLocation: fmt.Sprintf("transcript-%s", t.uuidProvider.NewUUID()),
+		Status:             models.NotStarted,
+		AudioSHA256:        audio.SHA256,
+		AudioSize:          audio.Size,
+		Provider:           options.Provider,
+		Language:           options.Language,
+		Attempts:           1,
+	}, options, nil
+}
+
+// transcriptionOptions fills in the provider, number of speakers, language and vocabulary audio of a session is
+// transcribed with where options leave them out
+func (t *TranscriptionManager) transcriptionOptions(ctx context.Context, sessionID string, options models.TranscriptionOptions) (models.TranscriptionOptions, error) {
+	if err := checkTranscriptionOptions(options); err != nil {
+		return options, err
+	}
+	providerName, _, err := t.providers.provider(options.Provider)
+	if err != nil {
+		return options, err
+	}
+	options.Provider = providerName
+	if options.MaxSpeakers == 0 {
+		attendees, err := t.transcriptionDb.CountSessionAttendees(ctx, sessionID)
+		if err != nil {
+			return options, err
+		}
+		options.MaxSpeakers = attendees
 	}
+	options.Language, err = t.transcriptionLanguage(ctx, sessionID, options.Language)
+	if err != nil {
+		return options, err
+	}
+	vocabulary, err := t.transcriptionDb.GetVocabularyForSession(ctx, sessionID)
+	if err != nil {
+		return options, err
+	}
+	options.Vocabulary = buildVocabulary(vocabulary)
+	return options, nil
+}
 
-	_, err := t.transcriptionDb.AddTranscriptToSession(ctx, sessionID, transcriptionJob)
+// startSubmittedTranscription takes the start step of a transcript that was just submitted. The transcript is
+// accepted either way, when the step fails it's left NotStarted for the dispatcher to take the step again.
+func (t *TranscriptionManager) startSubmittedTranscription(ctx context.Context, transcript models.Transcript, start models.OutboxMessage) *models.Transcript {
+	started, err := t.startTranscription(ctx, transcript, start)
+	if err != nil {
+		log.Printf("failed to record the start of transcription job %s: %s", transcript.JobID, err)
+		return &transcript
+	}
+	return started
+}
+
+// discardTranscriptionJob removes a transcript whose audio could not be uploaded along with the audio. A
+// transcript that can't be removed is failed by its ExpireUpload step.
+func (t *TranscriptionManager) discardTranscriptionJob(ctx context.Context, transcript models.Transcript) {
+	if err := t.transcriptionDb.DeleteTranscript(context.WithoutCancel(ctx), transcript.JobID); err != nil {
+		log.Printf("failed to delete transcript %s of a rejected upload: %s", transcript.JobID, err)
+	}
+	t.deleteAudio(transcript.AudioLocation)
+}
+
+// RetryTranscript runs the stage a failed transcript of a session failed at again, without its audio being
+// uploaded again. A transcript that failed transcribing is transcribed again from its stored audio with the
+// session's current attendance and vocabulary, one that failed summarizing is only summarized again. Conflicted
+// is returned for a transcript that didn't fail and InvalidEntity for one whose audio never finished uploading.
+func (t *TranscriptionManager) RetryTranscript(ctx context.Context, sessionID, jobID string) (*models.Transcript, error) {
+	transcript, err := t.transcriptionDb.GetTranscript(ctx, jobID)
 	if err != nil {
 		return nil, err
 	}
-	err = t.fileStore.UploadData(t.bucket, audioLocation, audioFile)
+	switch transcript.Status {
+	case models.TranscriptionFailed:
+		return t.retryTranscription(ctx, sessionID, *transcript)
+	case models.SummarizingFailed:
+		return t.retrySummary(ctx, *transcript)
+	}
+	return nil, fmt.Errorf("transcript %s is %s: %w", jobID, transcript.Status, models.Conflicted)
+}
+
+func (t *TranscriptionManager) retryTranscription(ctx context.Context, sessionID string, transcript models.Transcript) (*models.Transcript, error) {
+	if transcript.FailureReason == uploadExpiredReason {
+		return nil, fmt.Errorf("audio of transcript %s was never uploaded: %w", transcript.JobID, models.InvalidEntity)
+	}
+	options, err := t.transcriptionOptions(ctx, sessionID, models.TranscriptionOptions{Provider: transcript.Provider, Language: transcript.Language})
 	if err != nil {
 		return nil, err
 	}
-	err = t.transcriptionProvider.StartTranscriptionJob(jobID, audioLocation, transcriptLocation, audioFormat)
+	from := transcript.JobState
+	transcript.Status = models.NotStarted
+	transcript.JobState = models.JobUploaded
+	transcript.FailureReason = ""
+	transcript.Attempts++
+	start := startTranscriptionMessage(transcript.JobID, options)
+	retried, err := t.transcriptionDb.AdvanceTranscriptJob(ctx, transcript, from, &start)
 	if err != nil {
 		return nil, err
 	}
+	return t.startSubmittedTranscription(ctx, *retried, start), nil
+}
+
+// retrySummary leaves summarizing to the dispatcher, since a summary can take longer than a request should
+func (t *TranscriptionManager) retrySummary(ctx context.Context, transcript models.Transcript) (*models.Transcript, error) {
+	from := transcript.JobState
+	transcript.Status = models.Summarizing
+	transcript.JobState = models.JobCompleted
+	transcript.FailureReason = ""
+	transcript.Attempts++
+	summarize := summarizeMessage(transcript.JobID)
+	return t.transcriptionDb.AdvanceTranscriptJob(ctx, transcript, from, &summarize)
+}
+
+// CheckTranscriptionOptions returns InvalidEntity when options can't be used to start a transcription job, so
+// they can be rejected before any audio is uploaded
+func (t *TranscriptionManager) CheckTranscriptionOptions(options models.TranscriptionOptions) error {
+	if err := checkTranscriptionOptions(options); err != nil {
+		return err
+	}
+	_, _, err := t.providers.provider(options.Provider)
+	return err
+}
 
-	return &transcriptionJob, nil
+// checkTranscriptionOptions returns InvalidEntity when options can't be passed to the transcription provider
+func checkTranscriptionOptions(options models.TranscriptionOptions) error {
+	if options.MaxSpeakers < 0 || options.MaxSpeakers > models.MaxSpeakerLabels {
+		return fmt.Errorf("max speakers must be between 1 and %d: %w", models.MaxSpeakerLabels, models.InvalidEntity)
+	}
+	if options.Language != "" {
+		if _, err := models.LanguageFromString(options.Language); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transcriptionLanguage resolves the language audio of a session is transcribed in, the submitted language
+// takes precedence over the campaign's default which takes precedence over the service default
+func (t *TranscriptionManager) transcriptionLanguage(ctx context.Context, sessionID, language string) (string, error) {
+	if language == "" {
+		campaignLanguage, err := t.transcriptionDb.GetSessionLanguage(ctx, sessionID)
+		if err != nil {
+			return "", err
+		}
+		language = campaignLanguage
+	}
+	if language == "" {
+		return models.DefaultLanguage, nil
+	}
+	return models.LanguageFromString(language)
 }
 
 func (t *TranscriptionManager) GetTranscriptJob(ctx context.Context, jobID string) (*models.Transcript, error) {
 	return t.transcriptionDb.GetTranscript(ctx, jobID)
 }
 
-func (t *TranscriptionManager) GetTranscriptsForSession(ctx context.Context, sessionID string) ([]models.Transcript, error) {
-	return t.transcriptionDb.GetTranscriptsForSession(ctx, sessionID)
+// GetTranscriptsForSession returns a page of the transcripts of a session matching the filter
+func (t *TranscriptionManager) GetTranscriptsForSession(ctx context.Context, sessionID string, filter models.TranscriptFilter, page models.PageRequest) (*models.Page[models.Transcript], error) {
+	page, err := checkPageRequest(page)
+	if err != nil {
+		return nil, err
+	}
+	return t.transcriptionDb.ListTranscriptsForSession(ctx, sessionID, filter, page)
+}
+
+// UpdateTranscript applies the fields set in the update to a transcript of the campaign
+func (t *TranscriptionManager) UpdateTranscript(ctx context.Context, campaignID, jobID string, update models.TranscriptUpdate) (*models.Transcript, error) {
+	transcript, err := t.transcriptionDb.GetTranscript(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if transcript.Version != update.Version {
+		return nil, fmt.Errorf("transcript %s is at version %d: %w", jobID, transcript.Version, models.Conflicted)
+	}
+	if update.SessionID == nil {
+		return transcript, nil
+	}
+	if *update.SessionID == "" {
+		return nil, fmt.Errorf("missing field: SessionID %w", models.InvalidEntity)
+	}
+	if err = t.transcriptionDb.MoveTranscriptToSession(ctx, jobID, campaignID, *update.SessionID, update.Version); err != nil {
+		return nil, err
+	}
+	return t.transcriptionDb.GetTranscript(ctx, jobID)
+}
+
+// GetTranscriptFileURL returns a presigned URL the audio, transcript or summary file of a transcript can be
+// downloaded from directly. Conflicted is returned for a transcript that is still being transcribed and
+// EntityNotFound for a summary that wasn't written yet.
+func (t *TranscriptionManager) GetTranscriptFileURL(ctx context.Context, jobID string, file models.TranscriptFile) (*models.PresignedURL, error) {
+	transcript, err := t.transcriptionDb.GetTranscript(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	location := ""
+	switch file {
+	case models.AudioFile:
+		location = transcript.AudioLocation
+	case models.TranscriptDataFile:
+		if transcript.Status == models.NotStarted || transcript.Status == models.Transcribing {
+			return nil, fmt.Errorf("transcript %s is %s: %w", jobID, transcript.Status, models.Conflicted)
+		}
+		location = transcript.TranscriptLocation
+	case models.SummaryFile:
+		location = transcript.SummaryLocation
+	}
+	if location == "" {
+		return nil, fmt.Errorf("transcript %s has no %s file: %w", jobID, file, models.EntityNotFound)
+	}
+	return t.fileStore.PresignDownload(t.bucket, location, downloadURLExpiry)
+}
+
+// DeleteTranscript removes a transcript along with its audio, transcript and summary files
+func (t *TranscriptionManager) DeleteTranscript(ctx context.Context, jobID string) error {
+	transcript, err := t.transcriptionDb.GetTranscript(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if err = t.transcriptionDb.DeleteTranscript(ctx, jobID); err != nil {
+		return err
+	}
+	t.DeleteTranscriptFiles(ctx, []models.Transcript{*transcript})
+	return nil
+}
+
+// deleteAudio removes audio that was stored for a job that could not be created
+func (t *TranscriptionManager) deleteAudio(audioLocation string) {
+	if err := t.fileStore.DeleteData(t.bucket, audioLocation); err != nil {
+		log.Printf("failed to delete audio %s of a rejected upload: %s", audioLocation, err)
+	}
+}
+
+// DeleteTranscriptFiles removes the stored files of transcripts whose rows were already deleted. Failures are
+// logged rather than returned since the transcripts are gone either way.
+func (t *TranscriptionManager) DeleteTranscriptFiles(ctx context.Context, transcripts []models.Transcript) {
+	for _, transcript := range transcripts {
+		for _, location := range []string{transcript.AudioLocation, transcript.TranscriptLocation, transcript.SummaryLocation} {
+			if location == "" {
+				continue
+			}
+			if err := t.fileStore.DeleteData(t.bucket, location); err != nil {
+				log.Printf("failed to delete %s of transcript %s: %s", location, transcript.JobID, err)
+			}
+		}
+	}
 }
 
 func (t *TranscriptionManager) DownloadTranscript(ctx context.Context, jobID string, w io.WriterAt) (int64, error) {
@@ -95,9 +420,143 @@ func (t *TranscriptionManager) DownloadTranscript(ctx context.Context, jobID str
 	if err != nil {
 		return 0, err
 	}
+	if transcript.Status == models.NotStarted || transcript.Status == models.Transcribing {
+		return 0, fmt.Errorf("transcript %s is %s: %w", jobID, transcript.Status, models.Conflicted)
+	}
 	bytesWritten, err := t.fileStore.DownloadData(t.bucket, transcript.TranscriptLocation, w)
 	if err != nil {
 		return 0, err
 	}
 	return bytesWritten, nil
 }
+
+// GetTranscriptDocument downloads and parses the transcript of a job into its structured form
+func (t *TranscriptionManager) GetTranscriptDocument(ctx context.Context, jobID string) (*models.TranscriptDocument, error) {
+	transcript, err := t.transcriptionDb.GetTranscript(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if transcript.Status == models.NotStarted || transcript.Status == models.Transcribing {
+		return nil, fmt.Errorf("transcript %s is %s: %w", jobID, transcript.Status, models.Conflicted)
+	}
+	return t.parseTranscript(ctx, *transcript)
+}
+
+// parseTranscript downloads and parses a transcript, naming the speakers that have been assigned to players
+func (t *TranscriptionManager) parseTranscript(ctx context.Context, transcript models.Transcript) (*models.TranscriptDocument, error) {
+	buffer := aws.NewWriteAtBuffer([]byte{})
+	bytesWritten, err := t.fileStore.DownloadData(t.bucket, transcript.TranscriptLocation, buffer)
+	if err != nil {
+		return nil, err
+	}
+	_, provider, err := t.providers.provider(transcript.Provider)
+	if err != nil {
+		return nil, err
+	}
+	document, err := provider.ParseTranscript(buffer.Bytes()[:bytesWritten])
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := t.transcriptionDb.GetTranscriptSpeakers(ctx, transcript.JobID)
+	if err != nil {
+		return nil, err
+	}
+	document.AssignSpeakers(assignments)
+	return document, nil
+}
+
+// SetSpeakerAssignments replaces the mapping of the transcript's speaker labels to campaign players
+func (t *TranscriptionManager) SetSpeakerAssignments(ctx context.Context, jobID string, assignments []models.SpeakerAssignment) ([]models.SpeakerAssignment, error) {
+	labels := map[string]bool{}
+	for _, assignment := range assignments {
+		if assignment.SpeakerLabel == "" {
+			return nil, fmt.Errorf("missing field: SpeakerLabel %w", models.InvalidEntity)
+		}
+		if assignment.PlayerID == "" {
+			return nil, fmt.Errorf("missing field: PlayerID %w", models.InvalidEntity)
+		}
+		if labels[assignment.SpeakerLabel] {
+			return nil, fmt.Errorf("speaker %s is assigned more than once: %w", assignment.SpeakerLabel, models.InvalidEntity)
+		}
+		labels[assignment.SpeakerLabel] = true
+	}
+	if _, err := t.transcriptionDb.GetTranscript(ctx, jobID); err != nil {
+		return nil, err
+	}
+	if err := t.transcriptionDb.SetTranscriptSpeakers(ctx, jobID, assignments); err != nil {
+		return nil, err
+	}
+	return t.transcriptionDb.GetTranscriptSpeakers(ctx, jobID)
+}
+
+// GetSpeakerAssignments retrieves the mapping of the transcript's speaker labels to campaign players
+func (t *TranscriptionManager) GetSpeakerAssignments(ctx context.Context, jobID string) ([]models.SpeakerAssignment, error) {
+	if _, err := t.transcriptionDb.GetTranscript(ctx, jobID); err != nil {
+		return nil, err
+	}
+	return t.transcriptionDb.GetTranscriptSpeakers(ctx, jobID)
+}
+
+// DownloadSummary writes the summary of a transcript to w. Conflicted is returned while the transcript is
+// still being transcribed or summarized.
+func (t *TranscriptionManager) DownloadSummary(ctx context.Context, jobID string, w io.WriterAt) (int64, error) {
+	transcript, err := t.transcriptionDb.GetTranscript(ctx, jobID)
+	if err != nil {
+		return 0, err
+	}
+	switch transcript.Status {
+	case models.NotStarted, models.Transcribing, models.Summarizing:
+		return 0, fmt.Errorf("transcript %s is %s: %w", jobID, transcript.Status, models.Conflicted)
+	}
+	if transcript.SummaryLocation == "" {
+		return 0, fmt.Errorf("transcript %s has no summary: %w", jobID, models.EntityNotFound)
+	}
+	bytesWritten, err := t.fileStore.DownloadData(t.bucket, transcript.SummaryLocation, w)
+	if err != nil {
+		return 0, err
+	}
+	return bytesWritten, nil
+}
+
+// PollTranscriptionJobs checks the provider status of every transcript that is still transcribing and records
+// the jobs that have failed or completed. It is the fallback for providers that don't send job events and for
+// events that were missed.
+func (t *TranscriptionManager) PollTranscriptionJobs(ctx context.Context) error {
+	transcripts, err := t.transcriptionDb.GetTranscriptsByStatus(ctx, models.Transcribing)
+	if err != nil {
+		return err
+	}
+	for _, transcript := range transcripts {
+		if err := t.refreshTranscriptionJob(ctx, transcript); err != nil {
+			log.Printf("failed to refresh transcription job %s: %s", transcript.JobID, err)
+		}
+	}
+	return nil
+}
+
+func (t *TranscriptionManager) refreshTranscriptionJob(ctx context.Context, transcript models.Transcript) error {
+	_, provider, err := t.providers.provider(transcript.Provider)
+	if err != nil {
+		return err
+	}
+	jobStatus, err := provider.GetTranscriptionJobStatus(providerJobName(transcript))
+	if err != nil {
+		return err
+	}
+	return t.advanceTranscriptionJob(ctx, transcript, jobStatus, "")
+}
+
+// generateSummary summarizes a transcript into summaryLocation, recording the language the provider detected
+// on the transcript
+func (t *TranscriptionManager) generateSummary(ctx context.Context, transcript *models.Transcript, summaryLocation string) error {
+	document, err := t.parseTranscript(ctx, *transcript)
+	if err != nil {
+		return err
+	}
+	transcript.DetectedLanguage = document.Language
+	summary, err := t.summarizer.Summarize(ctx, document.Text())
+	if err != nil {
+		return err
+	}
+	return t.fileStore.UploadData(t.bucket, summaryLocation, strings.NewReader(summary))
+}
@@ -5,17 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 const (
-	uuidString = "testUUID"
-	testBucket = "testBucket"
+	uuidString        = "testUUID"
+	testBucket        = "testBucket"
+	testMaxAudioBytes = 1 << 20
+	// testAudio starts with an ID3 tag so it sniffs as MP3, testAudioSHA256 is its SHA-256
+	testAudio       = "ID3testaudio"
+	testAudioSHA256 = "bf1fc7dc805ab689610de62edc4bdae66c56321a016e03e34dd7f5745ecb81a7"
 )
 
 // BufferWriterAt is an in-memory buffer that implements io.WriterAt.
@@ -56,6 +63,7 @@ type CapturedStartTranscriptionJobArgs struct {
 	audioLocation  string
 	resultLocation string
 	audioFormat    models.AudioFormat
+	options        models.TranscriptionOptions
 }
 
 type MockTranscriptionProvider struct {
@@ -63,24 +71,76 @@ type MockTranscriptionProvider struct {
 	capturedArgs *CapturedStartTranscriptionJobArgs
 }
 
-func (m *MockTranscriptionProvider) StartTranscriptionJob(jobName, audioLocation, resultLocation string, audioFormat models.AudioFormat) error {
-	args := m.Called(jobName, audioLocation, resultLocation, audioFormat)
+func (m *MockTranscriptionProvider) StartTranscriptionJob(jobName, audioLocation, resultLocation string, audioFormat models.AudioFormat, options models.TranscriptionOptions) error {
+	args := m.Called(jobName, audioLocation, resultLocation, audioFormat, options)
 	m.capturedArgs = &CapturedStartTranscriptionJobArgs{
 		jobName:        jobName,
 		audioLocation:  audioLocation,
 		resultLocation: resultLocation,
 		audioFormat:    audioFormat,
+		options:        options,
 	}
 	return args.Error(0)
 }
 
+func (m *MockTranscriptionProvider) GetTranscriptionJobStatus(jobName string) (models.TranscriptionJobStatus, error) {
+	args := m.Called(jobName)
+	return args.Get(0).(models.TranscriptionJobStatus), args.Error(1)
+}
+
+func (m *MockTranscriptionProvider) ParseTranscript(data []byte) (*models.TranscriptDocument, error) {
+	args := m.Called(data)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TranscriptDocument), nil
+}
+
+// MockEventTranscriptionProvider is a provider that sends job events
+type MockEventTranscriptionProvider struct {
+	MockTranscriptionProvider
+}
+
+func (m *MockEventTranscriptionProvider) ParseJobEvent(data []byte) (*models.TranscriptionJobEvent, error) {
+	args := m.Called(data)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	event, _ := args.Get(0).(*models.TranscriptionJobEvent)
+	return event, nil
+}
+
+// testProvider is the name the mock provider is registered under as the default provider
+const testProvider = "test"
+
+func testProviders(provider transcriptionProvider) *TranscriptionProviders {
+	providers := NewTranscriptionProviders(testProvider)
+	providers.Register(testProvider, provider)
+	return providers
+}
+
+type MockSummarizer struct {
+	mock.Mock
+}
+
+func (m *MockSummarizer) Summarize(ctx context.Context, transcript string) (string, error) {
+	args := m.Called(ctx, transcript)
+	return args.String(0), args.Error(1)
+}
+
 type MockFileStore struct {
 	files map[string][]byte
+	// parts holds the parts of unfinished multipart uploads by upload ID
+	parts map[string]map[int][]byte
+	// contentTypes holds the content type of files uploaded through a presigned URL
+	contentTypes map[string]string
 }
 
 func NewMockFileStore() *MockFileStore {
 	return &MockFileStore{
-		files: map[string][]byte{},
+		files:        map[string][]byte{},
+		parts:        map[string]map[int][]byte{},
+		contentTypes: map[string]string{},
 	}
 }
 
@@ -113,24 +173,58 @@ func (m *MockFileStore) GetContentFromPath(bucket, fileKey string) (string, bool
 	return string(b), true
 }
 
+func (m *MockFileStore) DeleteData(bucket, fileKey string) error {
+	key := fmt.Sprintf("%s/%s", bucket, fileKey)
+	delete(m.files, key)
+	return nil
+}
+
+func (m *MockFileStore) PresignDownload(bucket, fileKey string, expiry time.Duration) (*models.PresignedURL, error) {
+	return &models.PresignedURL{
+		URL:    fmt.Sprintf("https://files.test/%s/%s?expires=%s", bucket, fileKey, expiry),
+		Method: http.MethodGet,
+	}, nil
+}
+
 type MockTranscriptDb struct {
 	mock.Mock
 }
 
-func (m *MockTranscriptDb) AddTranscriptToSession(ctx context.Context, sessionID string, transcript models.Transcript) (*models.Transcript, error) {
-	args := m.Called(ctx, sessionID, transcript)
+func (m *MockTranscriptDb) AddTranscriptToSession(ctx context.Context, sessionID string, transcript models.Transcript, message models.OutboxMessage) (*models.Transcript, error) {
+	args := m.Called(ctx, sessionID, transcript, message)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Transcript), nil
 }
 
-func (m *MockTranscriptDb) GetTranscriptsForSession(ctx context.Context, sessionID string) ([]models.Transcript, error) {
-	args := m.Called(ctx, sessionID)
+func (m *MockTranscriptDb) AdvanceTranscriptJob(ctx context.Context, transcript models.Transcript, from models.JobState, next *models.OutboxMessage) (*models.Transcript, error) {
+	args := m.Called(ctx, transcript, from, next)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]models.Transcript), nil
+	return args.Get(0).(*models.Transcript), nil
+}
+
+func (m *MockTranscriptDb) ClaimOutboxMessages(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error) {
+	args := m.Called(ctx, now, leaseUntil, limit)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OutboxMessage), nil
+}
+
+func (m *MockTranscriptDb) RetryOutboxMessage(ctx context.Context, jobID, lastError string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, jobID, lastError, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockTranscriptDb) ListTranscriptsForSession(ctx context.Context, sessionID string, filter models.TranscriptFilter, page models.PageRequest) (*models.Page[models.Transcript], error) {
+	args := m.Called(ctx, sessionID, filter, page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Transcript]), nil
 }
 
 func (m *MockTranscriptDb) GetTranscript(ctx context.Context, jobID string) (*models.Transcript, error) {
@@ -141,134 +235,338 @@ func (m *MockTranscriptDb) GetTranscript(ctx context.Context, jobID string) (*mo
 	return args.Get(0).(*models.Transcript), nil
 }
 
+func (m *MockTranscriptDb) GetTranscriptsByStatus(ctx context.Context, status models.TranscriptStatus) ([]models.Transcript, error) {
+	args := m.Called(ctx, status)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Transcript), nil
+}
+
+func (m *MockTranscriptDb) CountSessionAttendees(ctx context.Context, sessionID string) (int, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTranscriptDb) GetSessionLanguage(ctx context.Context, sessionID string) (string, error) {
+	args := m.Called(ctx, sessionID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTranscriptDb) GetVocabularyForSession(ctx context.Context, sessionID string) ([]models.VocabularyTerm, error) {
+	args := m.Called(ctx, sessionID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.VocabularyTerm), nil
+}
+
+func (m *MockTranscriptDb) SetTranscriptSpeakers(ctx context.Context, jobID string, assignments []models.SpeakerAssignment) error {
+	args := m.Called(ctx, jobID, assignments)
+	return args.Error(0)
+}
+
+func (m *MockTranscriptDb) GetTranscriptSpeakers(ctx context.Context, jobID string) ([]models.SpeakerAssignment, error) {
+	args := m.Called(ctx, jobID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SpeakerAssignment), nil
+}
+
+func (m *MockTranscriptDb) MoveTranscriptToSession(ctx context.Context, jobID, campaignID, sessionID string, version int) error {
+	args := m.Called(ctx, jobID, campaignID, sessionID, version)
+	return args.Error(0)
+}
+
+func (m *MockTranscriptDb) DeleteTranscript(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
 func TestSubmitTranscriptionJob(t *testing.T) {
 	dbError := errors.New("db error")
 	transcriptionJobError := errors.New("transcription job error")
+	pendingRecord := func(language string) *models.Transcript {
+		return &models.Transcript{
+			JobID:              "testUUID",
+			AudioLocation:      "audio-testUUID",
+			AudioFormat:        models.MP3,
+			TranscriptLocation: "transcript-testUUID",
+			Status:             models.NotStarted,
+			Provider:           testProvider,
+			Language:           language,
+			JobState:           models.JobPendingUpload,
+			Attempts:           1,
+		}
+	}
 	cases := []struct {
-		description        string
-		userID             string
-		campaignID         string
-		sessionID          string
-		audioFormat        models.AudioFormat
-		fileContent        string
-		audioPath          string
-		dbError            error
-		dbResult           *models.Transcript
-		fileStoreError     error
-		transcriptionError error
-		expectedDbRecord   *models.Transcript
-		expectedError      error
-		expectedResult     *models.Transcript
+		description             string
+		userID                  string
+		campaignID              string
+		sessionID               string
+		audioFormat             models.AudioFormat
+		fileContent             string
+		declaredSize            *int64
+		options                 models.TranscriptionOptions
+		attendees               int
+		campaignLanguage        string
+		vocabulary              []models.VocabularyTerm
+		expectedOptions         models.TranscriptionOptions
+		dbError                 error
+		transcriptionError      error
+		expectedDbRecord        *models.Transcript
+		expectedError           error
+		expectedStatus          models.TranscriptStatus
+		expectTranscriptDeleted bool
+		expectAudioDeleted      bool
 	}{
 		{
-			description: "transcription job is created",
+			description:      "transcription job is created",
+			userID:           "user1",
+			campaignID:       "campaign1",
+			sessionID:        "session0",
+			audioFormat:      models.MP3,
+			fileContent:      testAudio,
+			attendees:        5,
+			expectedOptions:  models.TranscriptionOptions{Provider: testProvider, MaxSpeakers: 5, Language: models.DefaultLanguage},
+			expectedDbRecord: pendingRecord(models.DefaultLanguage),
+			expectedStatus:   models.Transcribing,
+		},
+		{
+			description:      "max speakers is set, session attendance is not used",
+			userID:           "user1",
+			campaignID:       "campaign1",
+			sessionID:        "session0",
+			audioFormat:      models.MP3,
+			fileContent:      testAudio,
+			options:          models.TranscriptionOptions{MaxSpeakers: 3},
+			attendees:        5,
+			expectedOptions:  models.TranscriptionOptions{Provider: testProvider, MaxSpeakers: 3, Language: models.DefaultLanguage},
+			expectedDbRecord: pendingRecord(models.DefaultLanguage),
+			expectedStatus:   models.Transcribing,
+		},
+		{
+			description:      "language is not set, campaign default language is used",
+			userID:           "user1",
+			campaignID:       "campaign1",
+			sessionID:        "session0",
+			audioFormat:      models.MP3,
+			fileContent:      testAudio,
+			options:          models.TranscriptionOptions{MaxSpeakers: 2},
+			campaignLanguage: "fr-FR",
+			expectedOptions:  models.TranscriptionOptions{Provider: testProvider, MaxSpeakers: 2, Language: "fr-FR"},
+			expectedDbRecord: pendingRecord("fr-FR"),
+			expectedStatus:   models.Transcribing,
+		},
+		{
+			description: "campaign vocabulary is passed to the provider",
 			userID:      "user1",
 			campaignID:  "campaign1",
 			sessionID:   "session0",
 			audioFormat: models.MP3,
-			fileContent: "testaudio",
-			audioPath:   "user1/campaign1/session0/audio-testUUID",
-			expectedDbRecord: &models.Transcript{
-				JobID:              "testUUID",
-				AudioLocation:      "audio-testUUID",
-				AudioFormat:        models.MP3,
-				TranscriptLocation: "transcript-testUUID",
-				Status:             models.Transcribing,
-			},
-			dbResult: &models.Transcript{
-				JobID:              "testUUID",
-				AudioLocation:      "audio-testUUID",
-				AudioFormat:        models.MP3,
-				TranscriptLocation: "transcript-testUUID",
-				Status:             models.Transcribing,
-			},
-			expectedResult: &models.Transcript{
-				JobID:              "testUUID",
-				AudioLocation:      "audio-testUUID",
-				AudioFormat:        models.MP3,
-				TranscriptLocation: "transcript-testUUID",
-				Status:             models.Transcribing,
+			fileContent: testAudio,
+			options:     models.TranscriptionOptions{MaxSpeakers: 2},
+			vocabulary: []models.VocabularyTerm{
+				{Phrase: "Neverwinter", Source: models.VocabularyCampaign},
+				{ID: "term-1", Phrase: "Tiamat", SoundsLike: []string{"tee-ah-mat"}, Source: models.VocabularyCustom},
 			},
+			expectedOptions: models.TranscriptionOptions{Provider: testProvider, MaxSpeakers: 2, Language: models.DefaultLanguage, Vocabulary: []models.VocabularyTerm{
+				{ID: "term-1", Phrase: "Tiamat", SoundsLike: []string{"tee-ah-mat"}, Source: models.VocabularyCustom},
+				{Phrase: "Neverwinter", SoundsLike: []string{}, Source: models.VocabularyCampaign},
+			}},
+			expectedDbRecord: pendingRecord(models.DefaultLanguage),
+			expectedStatus:   models.Transcribing,
 		},
 		{
-			description:   "database returns an error, error returned",
+			description:      "language identification is requested, campaign default language is not used",
+			userID:           "user1",
+			campaignID:       "campaign1",
+			sessionID:        "session0",
+			audioFormat:      models.MP3,
+			fileContent:      testAudio,
+			options:          models.TranscriptionOptions{MaxSpeakers: 2, Language: "Auto"},
+			campaignLanguage: "fr-FR",
+			expectedOptions:  models.TranscriptionOptions{Provider: testProvider, MaxSpeakers: 2, Language: models.AutoLanguage},
+			expectedDbRecord: pendingRecord(models.AutoLanguage),
+			expectedStatus:   models.Transcribing,
+		},
+		{
+			description:   "language is not supported, InvalidEntity returned before anything is stored",
 			userID:        "user1",
 			campaignID:    "campaign1",
 			sessionID:     "session0",
 			audioFormat:   models.MP3,
-			fileContent:   "testaudio",
-			audioPath:     "audio-testUUID",
-			dbError:       dbError,
-			expectedError: dbError,
-			expectedDbRecord: &models.Transcript{
-				JobID:              "testUUID",
-				AudioLocation:      "audio-testUUID",
-				AudioFormat:        models.MP3,
-				TranscriptLocation: "transcript-testUUID",
-				Status:             models.Transcribing,
-			},
+			fileContent:   testAudio,
+			options:       models.TranscriptionOptions{Language: "tlh-KL"},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "provider is not registered, InvalidEntity returned before anything is stored",
+			userID:        "user1",
+			campaignID:    "campaign1",
+			sessionID:     "session0",
+			audioFormat:   models.MP3,
+			fileContent:   testAudio,
+			options:       models.TranscriptionOptions{Provider: "unknown"},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "max speakers is out of range, InvalidEntity returned",
+			userID:        "user1",
+			campaignID:    "campaign1",
+			sessionID:     "session0",
+			audioFormat:   models.MP3,
+			fileContent:   testAudio,
+			options:       models.TranscriptionOptions{MaxSpeakers: 31},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:        "database returns an error, error returned before the audio is uploaded",
+			userID:             "user1",
+			campaignID:         "campaign1",
+			sessionID:          "session0",
+			audioFormat:        models.MP3,
+			fileContent:        testAudio,
+			dbError:            dbError,
+			expectedError:      dbError,
+			expectAudioDeleted: true,
 		},
 		{
-			description:        "transcription service returns an error, error returned",
+			description:        "transcription service returns an error, transcript returned not started and the start retried",
 			userID:             "user1",
 			campaignID:         "campaign1",
 			sessionID:          "session0",
 			audioFormat:        models.MP3,
-			fileContent:        "testaudio",
-			audioPath:          "audio-testUUID",
+			fileContent:        testAudio,
+			expectedOptions:    models.TranscriptionOptions{Provider: testProvider, Language: models.DefaultLanguage},
 			transcriptionError: transcriptionJobError,
-			expectedError:      transcriptionJobError,
-			dbResult: &models.Transcript{
-				JobID:              "testUUID",
-				AudioLocation:      "audio-testUUID",
-				AudioFormat:        models.MP3,
-				TranscriptLocation: "transcript/transcript-testUUID",
-				Status:             models.Transcribing,
-			},
-			expectedDbRecord: &models.Transcript{
-				JobID:              "testUUID",
-				AudioLocation:      "audio-testUUID",
-				AudioFormat:        models.MP3,
-				TranscriptLocation: "transcript-testUUID",
-				Status:             models.Transcribing,
-			},
+			expectedDbRecord:   pendingRecord(models.DefaultLanguage),
+			expectedStatus:     models.NotStarted,
+		},
+		{
+			description:   "declared size is over the limit, TooLarge returned",
+			userID:        "user1",
+			campaignID:    "campaign1",
+			sessionID:     "session0",
+			audioFormat:   models.MP3,
+			fileContent:   testAudio,
+			declaredSize:  aws.Int64(testMaxAudioBytes + 1),
+			expectedError: models.TooLarge,
+		},
+		{
+			description:             "audio of unknown size streams past the limit, TooLarge returned and transcript and audio deleted",
+			userID:                  "user1",
+			campaignID:              "campaign1",
+			sessionID:               "session0",
+			audioFormat:             models.MP3,
+			fileContent:             testAudio + strings.Repeat("a", testMaxAudioBytes),
+			declaredSize:            aws.Int64(-1),
+			attendees:               5,
+			expectedError:           models.TooLarge,
+			expectTranscriptDeleted: true,
+			expectAudioDeleted:      true,
+		},
+		{
+			description:             "fewer bytes arrive than declared, InvalidEntity returned and transcript and audio deleted",
+			userID:                  "user1",
+			campaignID:              "campaign1",
+			sessionID:               "session0",
+			audioFormat:             models.MP3,
+			fileContent:             testAudio,
+			declaredSize:            aws.Int64(100),
+			attendees:               5,
+			expectedError:           models.InvalidEntity,
+			expectTranscriptDeleted: true,
+			expectAudioDeleted:      true,
+		},
+		{
+			description:        "audio is not in the declared format, InvalidEntity returned and nothing stored",
+			userID:             "user1",
+			campaignID:         "campaign1",
+			sessionID:          "session0",
+			audioFormat:        models.WAV,
+			fileContent:        testAudio,
+			attendees:          5,
+			expectedError:      models.InvalidEntity,
+			expectAudioDeleted: true,
+		},
+		{
+			description:        "audio is not in a supported container, InvalidEntity returned and nothing stored",
+			userID:             "user1",
+			campaignID:         "campaign1",
+			sessionID:          "session0",
+			audioFormat:        models.MP3,
+			fileContent:        "<html>not audio</html>",
+			attendees:          5,
+			expectedError:      models.InvalidEntity,
+			expectAudioDeleted: true,
 		},
 	}
 
 	// Iterate through test cases
 	for _, c := range cases {
 		t.Run(c.description, func(t *testing.T) {
+			isStep := func(step models.OutboxStep) any {
+				return mock.MatchedBy(func(message models.OutboxMessage) bool {
+					return message.JobID == "testUUID" && message.Step == step
+				})
+			}
 
 			mockDb := &MockTranscriptDb{}
-			if c.dbError != nil {
-				mockDb.On("AddTranscriptToSession", mock.Anything, mock.Anything, mock.Anything).Return(nil, c.dbError)
-			} else {
-				mockDb.On("AddTranscriptToSession", mock.Anything, c.sessionID, *c.expectedDbRecord).Return(c.dbResult, nil)
-			}
+			mockDb.On("CountSessionAttendees", mock.Anything, c.sessionID).Return(c.attendees, nil)
+			mockDb.On("GetSessionLanguage", mock.Anything, c.sessionID).Return(c.campaignLanguage, nil)
+			mockDb.On("GetVocabularyForSession", mock.Anything, c.sessionID).Return(c.vocabulary, nil)
+			mockDb.On("DeleteTranscript", mock.Anything, "testUUID").Return(nil)
 			mockTranscriptionProvider := &MockTranscriptionProvider{}
-			if c.transcriptionError != nil {
-				mockTranscriptionProvider.On("StartTranscriptionJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(c.transcriptionError)
+			if c.dbError != nil {
+				mockDb.On("AddTranscriptToSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, c.dbError)
+			} else if c.expectedDbRecord != nil {
+				uploaded := *c.expectedDbRecord
+				uploaded.AudioSHA256 = testAudioSHA256
+				uploaded.AudioSize = int64(len(c.fileContent))
+				uploaded.JobState = models.JobUploaded
+				started := uploaded
+				started.Status = models.Transcribing
+				started.JobState = models.JobProviderStarted
+				startMessage := mock.MatchedBy(func(message *models.OutboxMessage) bool {
+					return message.Step == models.StartTranscriptionStep && message.Attempts == 1 && assert.ObjectsAreEqual(c.expectedOptions, message.Options)
+				})
+
+				mockDb.On("AddTranscriptToSession", mock.Anything, c.sessionID, *c.expectedDbRecord, isStep(models.ExpireUploadStep)).Return(c.expectedDbRecord, nil)
+				mockDb.On("AdvanceTranscriptJob", mock.Anything, uploaded, models.JobState(models.JobPendingUpload), startMessage).Return(&uploaded, nil)
+				mockDb.On("AdvanceTranscriptJob", mock.Anything, started, models.JobState(models.JobUploaded), (*models.OutboxMessage)(nil)).Return(&started, nil)
+				mockDb.On("RetryOutboxMessage", mock.Anything, "testUUID", mock.Anything, mock.Anything).Return(nil)
+				mockTranscriptionProvider.On("StartTranscriptionJob", uploaded.JobID, uploaded.AudioLocation, uploaded.TranscriptLocation, c.audioFormat, c.expectedOptions).Return(c.transcriptionError)
 			} else {
-				mockTranscriptionProvider.On("StartTranscriptionJob", c.expectedDbRecord.JobID, c.expectedDbRecord.AudioLocation, c.expectedDbRecord.TranscriptLocation, c.audioFormat).Return(nil)
+				mockDb.On("AddTranscriptToSession", mock.Anything, c.sessionID, mock.Anything, isStep(models.ExpireUploadStep)).Return(&models.Transcript{}, nil)
 			}
 			mockFileStore := NewMockFileStore()
 			mockUUIDProver := &MockUUIDProvier{}
 
-			testManager := NewTranscriptionManager(testBucket, mockTranscriptionProvider, mockFileStore, mockDb, mockUUIDProver)
+			testManager := NewTranscriptionManager(testBucket, testProviders(mockTranscriptionProvider), mockFileStore, mockDb, mockUUIDProver, &MockSummarizer{}, testMaxAudioBytes)
 
-			result, err := testManager.SubmitTranscriptionJob(context.Background(), c.userID, c.campaignID, c.sessionID, c.audioFormat, strings.NewReader(c.fileContent))
+			audio := models.AudioUpload{
+				Format: c.audioFormat,
+				Body:   strings.NewReader(c.fileContent),
+				Size:   int64(len(c.fileContent)),
+			}
+			if c.declaredSize != nil {
+				audio.Size = *c.declaredSize
+			}
+			result, err := testManager.SubmitTranscriptionJob(context.Background(), c.userID, c.campaignID, c.sessionID, audio, c.options)
 			if err != nil && c.expectedError == nil {
 				t.Errorf("unexpected error returned: %s", err)
 				return
 			}
 
-			if c.expectedResult != nil {
-				assert.Equal(t, c.expectedResult.JobID, result.JobID)
-				assert.Equal(t, c.expectedResult.AudioLocation, result.AudioLocation)
-				assert.Equal(t, c.expectedResult.AudioFormat, result.AudioFormat)
-				assert.Equal(t, c.expectedResult.Status, result.Status)
-				assert.Equal(t, c.expectedResult.SummaryLocation, result.SummaryLocation)
-				assert.Equal(t, c.expectedResult.TranscriptLocation, result.TranscriptLocation)
+			if c.expectedError == nil {
+				assert.Equal(t, c.expectedDbRecord.JobID, result.JobID)
+				assert.Equal(t, c.expectedDbRecord.AudioLocation, result.AudioLocation)
+				assert.Equal(t, c.expectedDbRecord.TranscriptLocation, result.TranscriptLocation)
+				assert.Equal(t, c.expectedStatus, result.Status)
+				assert.Equal(t, testAudioSHA256, result.AudioSHA256)
+				assert.Equal(t, int64(len(c.fileContent)), result.AudioSize)
 
 				uploadedContent, ok := mockFileStore.GetContentFromPath(testBucket, c.expectedDbRecord.AudioLocation)
 				if !ok {
@@ -276,6 +574,20 @@ func TestSubmitTranscriptionJob(t *testing.T) {
 				} else if uploadedContent != c.fileContent {
 					t.Errorf("expected file content to be %s got %s", c.fileContent, uploadedContent)
 				}
+				if c.transcriptionError != nil {
+					mockDb.AssertCalled(t, "RetryOutboxMessage", mock.Anything, "testUUID", c.transcriptionError.Error(), mock.Anything)
+				} else {
+					mockDb.AssertNotCalled(t, "RetryOutboxMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				}
+			}
+			if c.expectTranscriptDeleted {
+				mockDb.AssertCalled(t, "DeleteTranscript", mock.Anything, "testUUID")
+			} else {
+				mockDb.AssertNotCalled(t, "DeleteTranscript", mock.Anything, mock.Anything)
+			}
+			if c.expectAudioDeleted {
+				_, ok := mockFileStore.GetContentFromPath(testBucket, "audio-testUUID")
+				assert.False(t, ok, "audio of a rejected upload should be deleted")
 			}
 			if c.expectedError != nil {
 				if err == nil {
@@ -342,7 +654,7 @@ func TestGetTranscriptionJob(t *testing.T) {
 			mockUUIDProver := &MockUUIDProvier{}
 			mockTranscriptionProvider := &MockTranscriptionProvider{}
 
-			testManager := NewTranscriptionManager(testBucket, mockTranscriptionProvider, mockFileStore, mockDb, mockUUIDProver)
+			testManager := NewTranscriptionManager(testBucket, testProviders(mockTranscriptionProvider), mockFileStore, mockDb, mockUUIDProver, &MockSummarizer{}, testMaxAudioBytes)
 
 			result, err := testManager.GetTranscriptJob(context.Background(), c.jobID)
 			if err != nil && c.expectedError == nil {
@@ -373,6 +685,7 @@ func TestGetTranscriptionJob(t *testing.T) {
 
 func TestGetTranscriptsForSession(t *testing.T) {
 	dbError := errors.New("db error")
+	defaultPage := models.PageRequest{Limit: models.DefaultPageLimit}
 	cases := []struct {
 		description    string
 		sessionID      string
@@ -419,28 +732,28 @@ func TestGetTranscriptsForSession(t *testing.T) {
 
 			mockDb := &MockTranscriptDb{}
 			if c.dbError != nil {
-				mockDb.On("GetTranscriptsForSession", mock.Anything, c.sessionID).Return(nil, c.dbError)
+				mockDb.On("ListTranscriptsForSession", mock.Anything, c.sessionID, models.TranscriptFilter{}, defaultPage).Return(nil, c.dbError)
 			} else {
-				mockDb.On("GetTranscriptsForSession", mock.Anything, c.sessionID).Return(c.dbResult, nil)
+				mockDb.On("ListTranscriptsForSession", mock.Anything, c.sessionID, models.TranscriptFilter{}, defaultPage).Return(&models.Page[models.Transcript]{Items: c.dbResult}, nil)
 			}
 			mockFileStore := NewMockFileStore()
 			mockUUIDProver := &MockUUIDProvier{}
 			mockTranscriptionProvider := &MockTranscriptionProvider{}
 
-			testManager := NewTranscriptionManager(testBucket, mockTranscriptionProvider, mockFileStore, mockDb, mockUUIDProver)
+			testManager := NewTranscriptionManager(testBucket, testProviders(mockTranscriptionProvider), mockFileStore, mockDb, mockUUIDProver, &MockSummarizer{}, testMaxAudioBytes)
 
-			result, err := testManager.GetTranscriptsForSession(context.Background(), c.sessionID)
+			result, err := testManager.GetTranscriptsForSession(context.Background(), c.sessionID, models.TranscriptFilter{}, models.PageRequest{})
 			if err != nil && c.expectedError == nil {
 				t.Errorf("unexpected error returned: %s", err)
 				return
 			}
 
 			if c.expectedResult != nil {
-				if len(c.expectedResult) != len(result) {
-					t.Errorf("expected %d results got %d", len(c.expectedResult), len(result))
+				if len(c.expectedResult) != len(result.Items) {
+					t.Errorf("expected %d results got %d", len(c.expectedResult), len(result.Items))
 				}
 				for i, expected := range c.expectedResult {
-					actual := result[i]
+					actual := result.Items[i]
 					assert.Equal(t, expected.JobID, actual.JobID)
 					assert.Equal(t, expected.AudioLocation, actual.AudioLocation)
 					assert.Equal(t, expected.AudioFormat, actual.AudioFormat)
@@ -554,7 +867,7 @@ func TestDownloadTranscript(t *testing.T) {
 			mockUUIDProver := &MockUUIDProvier{}
 			mockTranscriptionProvider := &MockTranscriptionProvider{}
 
-			testManager := NewTranscriptionManager(testBucket, mockTranscriptionProvider, mockFileStore, mockDb, mockUUIDProver)
+			testManager := NewTranscriptionManager(testBucket, testProviders(mockTranscriptionProvider), mockFileStore, mockDb, mockUUIDProver, &MockSummarizer{}, testMaxAudioBytes)
 
 			bufferWriter := NewBufferWriterAt(len([]byte(c.filecontent)))
 			_, err := testManager.DownloadTranscript(context.Background(), c.jobID, bufferWriter)
@@ -575,3 +888,924 @@ func TestDownloadTranscript(t *testing.T) {
 	}
 
 }
+
+func TestGetTranscriptDocument(t *testing.T) {
+	dbError := fmt.Errorf("db error")
+
+	cases := []struct {
+		description      string
+		jobID            string
+		transcript       *models.Transcript
+		dbError          error
+		expectedError    error
+		expectedDocument *models.TranscriptDocument
+	}{
+		{
+			description: "transcript parsed",
+			jobID:       "jobId-1",
+			transcript: &models.Transcript{
+				JobID:              "jobId-1",
+				TranscriptLocation: "transcript.json",
+				Status:             models.Summarizing,
+			},
+			expectedDocument: &models.TranscriptDocument{
+				Segments: []models.TranscriptSegment{{Speaker: "spk_0", PlayerID: "player-1", PlayerName: "Mercer", Text: "welcome to the tavern"}},
+			},
+		},
+		{
+			description: "transcript status is Transcribing, Conflicted error returned",
+			jobID:       "jobId-1",
+			transcript: &models.Transcript{
+				JobID:              "jobId-1",
+				TranscriptLocation: "transcript.json",
+				Status:             models.Transcribing,
+			},
+			expectedError: models.Conflicted,
+		},
+		{
+			description:   "database returns an error, error returned",
+			jobID:         "jobId-1",
+			dbError:       dbError,
+			expectedError: dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockTranscriptDb{}
+			if c.dbError != nil {
+				mockDb.On("GetTranscript", mock.Anything, c.jobID).Return(nil, c.dbError)
+			} else {
+				mockDb.On("GetTranscript", mock.Anything, c.jobID).Return(c.transcript, nil)
+			}
+			mockDb.On("GetTranscriptSpeakers", mock.Anything, c.jobID).Return([]models.SpeakerAssignment{{SpeakerLabel: "spk_0", PlayerID: "player-1", PlayerName: "Mercer"}}, nil)
+			mockFileStore := NewMockFileStore()
+			mockFileStore.UploadData(testBucket, "transcript.json", strings.NewReader("{}"))
+			mockTranscriptionProvider := &MockTranscriptionProvider{}
+			mockTranscriptionProvider.On("ParseTranscript", []byte("{}")).Return(&models.TranscriptDocument{
+				Segments: []models.TranscriptSegment{{Speaker: "spk_0", Text: "welcome to the tavern"}},
+			}, nil)
+
+			testManager := NewTranscriptionManager(testBucket, testProviders(mockTranscriptionProvider), mockFileStore, mockDb, &MockUUIDProvier{}, &MockSummarizer{}, testMaxAudioBytes)
+
+			result, err := testManager.GetTranscriptDocument(context.Background(), c.jobID)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error to be %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			assert.Equal(t, c.expectedDocument, result)
+		})
+	}
+}
+
+func TestSetSpeakerAssignments(t *testing.T) {
+	dbError := errors.New("db error")
+	assignments := []models.SpeakerAssignment{
+		{SpeakerLabel: "spk_0", PlayerID: "player-1"},
+		{SpeakerLabel: "spk_1", PlayerID: "player-2"},
+	}
+	savedAssignments := []models.SpeakerAssignment{
+		{SpeakerLabel: "spk_0", PlayerID: "player-1", PlayerName: "Mercer"},
+		{SpeakerLabel: "spk_1", PlayerID: "player-2", PlayerName: "Vex"},
+	}
+
+	cases := []struct {
+		description    string
+		jobID          string
+		assignments    []models.SpeakerAssignment
+		getError       error
+		setError       error
+		expectedError  error
+		expectedResult []models.SpeakerAssignment
+	}{
+		{
+			description:    "speakers are assigned",
+			jobID:          "job-1",
+			assignments:    assignments,
+			expectedResult: savedAssignments,
+		},
+		{
+			description:   "speaker label missing, InvalidEntity returned",
+			jobID:         "job-1",
+			assignments:   []models.SpeakerAssignment{{PlayerID: "player-1"}},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "player missing, InvalidEntity returned",
+			jobID:         "job-1",
+			assignments:   []models.SpeakerAssignment{{SpeakerLabel: "spk_0"}},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description: "speaker assigned twice, InvalidEntity returned",
+			jobID:       "job-1",
+			assignments: []models.SpeakerAssignment{
+				{SpeakerLabel: "spk_0", PlayerID: "player-1"},
+				{SpeakerLabel: "spk_0", PlayerID: "player-2"},
+			},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "transcript not found, EntityNotFound returned",
+			jobID:         "job-1",
+			assignments:   assignments,
+			getError:      models.EntityNotFound,
+			expectedError: models.EntityNotFound,
+		},
+		{
+			description:   "player not in campaign, InvalidEntity returned",
+			jobID:         "job-1",
+			assignments:   assignments,
+			setError:      models.InvalidEntity,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "database returns an error, error returned",
+			jobID:         "job-1",
+			assignments:   assignments,
+			setError:      dbError,
+			expectedError: dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockTranscriptDb{}
+			if c.getError != nil {
+				mockDb.On("GetTranscript", mock.Anything, c.jobID).Return(nil, c.getError)
+			} else {
+				mockDb.On("GetTranscript", mock.Anything, c.jobID).Return(&models.Transcript{JobID: c.jobID}, nil)
+			}
+			mockDb.On("SetTranscriptSpeakers", mock.Anything, c.jobID, c.assignments).Return(c.setError)
+			mockDb.On("GetTranscriptSpeakers", mock.Anything, c.jobID).Return(savedAssignments, nil)
+
+			testManager := NewTranscriptionManager(testBucket, testProviders(&MockTranscriptionProvider{}), NewMockFileStore(), mockDb, &MockUUIDProvier{}, &MockSummarizer{}, testMaxAudioBytes)
+
+			result, err := testManager.SetSpeakerAssignments(context.Background(), c.jobID, c.assignments)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			assert.Equal(t, c.expectedResult, result)
+		})
+	}
+}
+
+func TestDownloadSummary(t *testing.T) {
+	dbError := fmt.Errorf("db error")
+
+	cases := []struct {
+		description     string
+		jobID           string
+		transcript      *models.Transcript
+		dbError         error
+		expectedError   error
+		expectedContent string
+	}{
+		{
+			description:     "summary downloaded",
+			jobID:           "jobId-1",
+			expectedContent: "# the party met in a tavern",
+			transcript: &models.Transcript{
+				JobID:              "jobId-1",
+				TranscriptLocation: "transcript.json",
+				SummaryLocation:    "summary.md",
+				Status:             models.Done,
+			},
+		},
+		{
+			description: "transcript status is Summarizing, Conflicted error returned",
+			jobID:       "jobId-1",
+			transcript: &models.Transcript{
+				JobID:              "jobId-1",
+				TranscriptLocation: "transcript.json",
+				Status:             models.Summarizing,
+			},
+			expectedError: models.Conflicted,
+		},
+		{
+			description: "transcript status is Transcribing, Conflicted error returned",
+			jobID:       "jobId-1",
+			transcript: &models.Transcript{
+				JobID:              "jobId-1",
+				TranscriptLocation: "transcript.json",
+				Status:             models.Transcribing,
+			},
+			expectedError: models.Conflicted,
+		},
+		{
+			description: "summarizing failed, EntityNotFound error returned",
+			jobID:       "jobId-1",
+			transcript: &models.Transcript{
+				JobID:              "jobId-1",
+				TranscriptLocation: "transcript.json",
+				Status:             models.SummarizingFailed,
+			},
+			expectedError: models.EntityNotFound,
+		},
+		{
+			description:   "database returns an error, error returned",
+			jobID:         "jobId-1",
+			dbError:       dbError,
+			expectedError: dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockTranscriptDb{}
+			if c.dbError != nil {
+				mockDb.On("GetTranscript", mock.Anything, c.jobID).Return(nil, c.dbError)
+			} else {
+				mockDb.On("GetTranscript", mock.Anything, c.jobID).Return(c.transcript, nil)
+			}
+			mockFileStore := NewMockFileStore()
+			mockFileStore.UploadData(testBucket, "summary.md", strings.NewReader("# the party met in a tavern"))
+
+			testManager := NewTranscriptionManager(testBucket, testProviders(&MockTranscriptionProvider{}), mockFileStore, mockDb, &MockUUIDProvier{}, &MockSummarizer{}, testMaxAudioBytes)
+
+			bufferWriter := NewBufferWriterAt(len(c.expectedContent))
+			_, err := testManager.DownloadSummary(context.Background(), c.jobID, bufferWriter)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error to be %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			assert.Equal(t, c.expectedContent, string(bufferWriter.GetData()))
+		})
+	}
+}
+
+func TestGetTranscriptFileURL(t *testing.T) {
+	cases := []struct {
+		description   string
+		transcript    models.Transcript
+		file          models.TranscriptFile
+		expectedURL   string
+		expectedError error
+	}{
+		{
+			description: "audio URL returned",
+			transcript:  models.Transcript{JobID: "job0", AudioLocation: "audio-testUUID", Status: models.Transcribing},
+			file:        models.AudioFile,
+			expectedURL: "https://files.test/testBucket/audio-testUUID?expires=15m0s",
+		},
+		{
+			description: "summary URL returned",
+			transcript:  models.Transcript{JobID: "job0", SummaryLocation: "summary-testUUID", Status: models.Done},
+			file:        models.SummaryFile,
+			expectedURL: "https://files.test/testBucket/summary-testUUID?expires=15m0s",
+		},
+		{
+			description:   "summary not written yet, EntityNotFound returned",
+			transcript:    models.Transcript{JobID: "job0", Status: models.Summarizing},
+			file:          models.SummaryFile,
+			expectedError: models.EntityNotFound,
+		},
+		{
+			description:   "transcript still transcribing, Conflicted returned",
+			transcript:    models.Transcript{JobID: "job0", TranscriptLocation: "transcript-testUUID", Status: models.Transcribing},
+			file:          models.TranscriptDataFile,
+			expectedError: models.Conflicted,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockTranscriptDb{}
+			mockDb.On("GetTranscript", mock.Anything, "job0").Return(&c.transcript, nil)
+			testManager := NewTranscriptionManager(testBucket, testProviders(&MockTranscriptionProvider{}), NewMockFileStore(), mockDb, &MockUUIDProvier{}, &MockSummarizer{}, testMaxAudioBytes)
+
+			result, err := testManager.GetTranscriptFileURL(context.Background(), "job0", c.file)
+			if c.expectedError != nil {
+				assert.ErrorIs(t, err, c.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedURL, result.URL)
+		})
+	}
+}
+
+func TestPollTranscriptionJobs(t *testing.T) {
+	dbError := errors.New("db error")
+	providerError := errors.New("provider error")
+	transcribing := models.Transcript{
+		JobID:              "job-1",
+		AudioLocation:      "audio.wav",
+		AudioFormat:        models.WAV,
+		TranscriptLocation: "transcript.json",
+		Status:             models.Transcribing,
+		JobState:           models.JobProviderStarted,
+	}
+	summarizing := transcribing
+	summarizing.Status = models.Summarizing
+	summarizing.JobState = models.JobCompleted
+	failed := transcribing
+	failed.Status = models.TranscriptionFailed
+	failed.JobState = models.JobFailed
+	failed.FailureReason = "the transcription provider reported the job as failed"
+	isSummarizeStep := mock.MatchedBy(func(message *models.OutboxMessage) bool {
+		return message != nil && message.JobID == transcribing.JobID && message.Step == models.SummarizeStep
+	})
+
+	cases := []struct {
+		description     string
+		dbError         error
+		jobStatus       models.TranscriptionJobStatus
+		providerError   error
+		expectedAdvance *models.Transcript
+		expectedNext    any
+		advanceError    error
+		expectedError   error
+	}{
+		{
+			description:     "job completed, transcript is left to the dispatcher to summarize",
+			jobStatus:       models.TranscriptionJobCompleted,
+			expectedAdvance: &summarizing,
+			expectedNext:    isSummarizeStep,
+		},
+		{
+			description:     "job failed, transcript is marked failed with a reason",
+			jobStatus:       models.TranscriptionJobFailed,
+			expectedAdvance: &failed,
+			expectedNext:    (*models.OutboxMessage)(nil),
+		},
+		{
+			description:     "job completed and already advanced by an event, nothing happens",
+			jobStatus:       models.TranscriptionJobCompleted,
+			expectedAdvance: &summarizing,
+			expectedNext:    isSummarizeStep,
+			advanceError:    models.Conflicted,
+		},
+		{
+			description: "job in progress, transcript is not updated",
+			jobStatus:   models.TranscriptionJobInProgress,
+		},
+		{
+			description:   "provider returns an error, transcript is not updated",
+			providerError: providerError,
+		},
+		{
+			description:   "database returns an error, error returned",
+			dbError:       dbError,
+			expectedError: dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockTranscriptDb{}
+			if c.dbError != nil {
+				mockDb.On("GetTranscriptsByStatus", mock.Anything, models.TranscriptStatus(models.Transcribing)).Return(nil, c.dbError)
+			} else {
+				mockDb.On("GetTranscriptsByStatus", mock.Anything, models.TranscriptStatus(models.Transcribing)).Return([]models.Transcript{transcribing}, nil)
+			}
+			if c.expectedAdvance != nil {
+				mockDb.On("AdvanceTranscriptJob", mock.Anything, *c.expectedAdvance, models.JobState(models.JobProviderStarted), c.expectedNext).Return(c.expectedAdvance, c.advanceError).Once()
+			}
+			mockTranscriptionProvider := &MockTranscriptionProvider{}
+			mockTranscriptionProvider.On("GetTranscriptionJobStatus", transcribing.JobID).Return(c.jobStatus, c.providerError)
+
+			testManager := NewTranscriptionManager(testBucket, testProviders(mockTranscriptionProvider), NewMockFileStore(), mockDb, &MockUUIDProvier{}, &MockSummarizer{}, testMaxAudioBytes)
+
+			err := testManager.PollTranscriptionJobs(context.Background())
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			if c.expectedAdvance == nil {
+				mockDb.AssertNotCalled(t, "AdvanceTranscriptJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockDb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPollTranscriptionJobsOfSeveralProviders(t *testing.T) {
+	amazonJob := models.Transcript{JobID: "job-1", Status: models.Transcribing, Provider: "amazon", JobState: models.JobProviderStarted}
+	whisperJob := models.Transcript{JobID: "job-2", Status: models.Transcribing, Provider: "whisper", JobState: models.JobProviderStarted}
+	unknownJob := models.Transcript{JobID: "job-3", Status: models.Transcribing, Provider: "retired", JobState: models.JobProviderStarted}
+	mockDb := &MockTranscriptDb{}
+	mockDb.On("GetTranscriptsByStatus", mock.Anything, models.TranscriptStatus(models.Transcribing)).Return([]models.Transcript{amazonJob, whisperJob, unknownJob}, nil)
+	failedJob := whisperJob
+	failedJob.Status = models.TranscriptionFailed
+	failedJob.JobState = models.JobFailed
+	failedJob.FailureReason = "the transcription provider reported the job as failed"
+	mockDb.On("AdvanceTranscriptJob", mock.Anything, failedJob, models.JobState(models.JobProviderStarted), (*models.OutboxMessage)(nil)).Return(&failedJob, nil).Once()
+	amazon := &MockTranscriptionProvider{}
+	amazon.On("GetTranscriptionJobStatus", amazonJob.JobID).Return(models.TranscriptionJobStatus(models.TranscriptionJobInProgress), nil)
+	whisper := &MockTranscriptionProvider{}
+	whisper.On("GetTranscriptionJobStatus", whisperJob.JobID).Return(models.TranscriptionJobStatus(models.TranscriptionJobFailed), nil)
+	providers := NewTranscriptionProviders("amazon")
+	providers.Register("amazon", amazon)
+	providers.Register("Whisper", whisper)
+
+	testManager := NewTranscriptionManager(testBucket, providers, NewMockFileStore(), mockDb, &MockUUIDProvier{}, &MockSummarizer{}, testMaxAudioBytes)
+
+	err := testManager.PollTranscriptionJobs(context.Background())
+	assert.NoError(t, err, "a job of a provider that is no longer configured should not stop the others")
+	amazon.AssertExpectations(t)
+	whisper.AssertExpectations(t)
+	mockDb.AssertExpectations(t)
+}
+
+func TestHandleTranscriptionJobEvent(t *testing.T) {
+	transcribing := models.Transcript{
+		JobID:    "job-1",
+		Status:   models.Transcribing,
+		Provider: testProvider,
+		JobState: models.JobProviderStarted,
+		Attempts: 1,
+	}
+	retried := transcribing
+	retried.Attempts = 2
+	summarizing := transcribing
+	summarizing.Status = models.Summarizing
+	summarizing.JobState = models.JobCompleted
+	withState := func(transcript models.Transcript, status models.TranscriptStatus, jobState models.JobState, reason string) *models.Transcript {
+		transcript.Status = status
+		transcript.JobState = jobState
+		transcript.FailureReason = reason
+		return &transcript
+	}
+	isSummarizeStep := mock.MatchedBy(func(message *models.OutboxMessage) bool {
+		return message != nil && message.JobID == "job-1" && message.Step == models.SummarizeStep
+	})
+	event := []byte(`{"detail-type": "Transcribe Job State Change"}`)
+
+	cases := []struct {
+		description     string
+		providerName    string
+		parsedEvent     *models.TranscriptionJobEvent
+		parseError      error
+		transcripts     map[string]models.Transcript
+		expectedAdvance *models.Transcript
+		expectedNext    any
+		expectedError   error
+	}{
+		{
+			description:     "job completed, transcript is left to the dispatcher to summarize",
+			parsedEvent:     &models.TranscriptionJobEvent{JobName: "job-1", Status: models.TranscriptionJobCompleted},
+			transcripts:     map[string]models.Transcript{"job-1": transcribing},
+			expectedAdvance: withState(transcribing, models.Summarizing, models.JobCompleted, ""),
+			expectedNext:    isSummarizeStep,
+		},
+		{
+			description:     "job failed, transcript is failed with the reason the provider gave",
+			parsedEvent:     &models.TranscriptionJobEvent{JobName: "job-1", Status: models.TranscriptionJobFailed, FailureReason: "Unsupported audio format"},
+			transcripts:     map[string]models.Transcript{"job-1": transcribing},
+			expectedAdvance: withState(transcribing, models.TranscriptionFailed, models.JobFailed, "Unsupported audio format"),
+			expectedNext:    (*models.OutboxMessage)(nil),
+		},
+		{
+			description:     "job of a retried transcript completed, transcript is found by the name of its attempt",
+			parsedEvent:     &models.TranscriptionJobEvent{JobName: "job-1-2", Status: models.TranscriptionJobCompleted},
+			transcripts:     map[string]models.Transcript{"job-1": retried},
+			expectedAdvance: withState(retried, models.Summarizing, models.JobCompleted, ""),
+			expectedNext:    isSummarizeStep,
+		},
+		{
+			description: "job of an earlier attempt failed, event is ignored",
+			parsedEvent: &models.TranscriptionJobEvent{JobName: "job-1", Status: models.TranscriptionJobFailed},
+			transcripts: map[string]models.Transcript{"job-1": retried},
+		},
+		{
+			description: "event delivered again after the job advanced, event is ignored",
+			parsedEvent: &models.TranscriptionJobEvent{JobName: "job-1", Status: models.TranscriptionJobCompleted},
+			transcripts: map[string]models.Transcript{"job-1": summarizing},
+		},
+		{
+			description: "job still in progress, transcript is not updated",
+			parsedEvent: &models.TranscriptionJobEvent{JobName: "job-1", Status: models.TranscriptionJobInProgress},
+			transcripts: map[string]models.Transcript{"job-1": transcribing},
+		},
+		{
+			description: "job the service doesn't know, event is ignored",
+			parsedEvent: &models.TranscriptionJobEvent{JobName: "other-job-3", Status: models.TranscriptionJobCompleted},
+		},
+		{
+			description: "event that isn't a job state change, event is ignored",
+		},
+		{
+			description:   "event can't be parsed, error returned",
+			parseError:    fmt.Errorf("failed to parse job event: %w", models.InvalidEntity),
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "provider isn't registered, not found",
+			providerName:  "retired",
+			expectedError: models.EntityNotFound,
+		},
+		{
+			description:   "provider doesn't send events, not found",
+			providerName:  "whisper",
+			expectedError: models.EntityNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockTranscriptDb{}
+			for jobID, transcript := range c.transcripts {
+				transcript := transcript
+				mockDb.On("GetTranscript", mock.Anything, jobID).Return(&transcript, nil)
+			}
+			mockDb.On("GetTranscript", mock.Anything, mock.Anything).Return(nil, models.EntityNotFound).Maybe()
+			if c.expectedAdvance != nil {
+				mockDb.On("AdvanceTranscriptJob", mock.Anything, *c.expectedAdvance, models.JobState(models.JobProviderStarted), c.expectedNext).Return(c.expectedAdvance, nil).Once()
+			}
+			mockProvider := &MockEventTranscriptionProvider{}
+			mockProvider.On("ParseJobEvent", event).Return(c.parsedEvent, c.parseError)
+			providers := testProviders(mockProvider)
+			providers.Register("whisper", &MockTranscriptionProvider{})
+			providerName := c.providerName
+			if providerName == "" {
+				providerName = testProvider
+			}
+
+			testManager := NewTranscriptionManager(testBucket, providers, NewMockFileStore(), mockDb, &MockUUIDProvier{}, &MockSummarizer{}, testMaxAudioBytes)
+
+			err := testManager.HandleTranscriptionJobEvent(context.Background(), providerName, event)
+			if c.expectedError != nil {
+				assert.True(t, errors.Is(err, c.expectedError), "expected error %s got %v", c.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			if c.expectedAdvance == nil {
+				mockDb.AssertNotCalled(t, "AdvanceTranscriptJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockDb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDispatchTranscriptionJobs(t *testing.T) {
+	dbError := errors.New("db error")
+	providerError := errors.New("provider error")
+	uploaded := models.Transcript{
+		JobID:              "job-1",
+		AudioLocation:      "audio-1",
+		AudioFormat:        models.MP3,
+		TranscriptLocation: "transcript-1",
+		Status:             models.NotStarted,
+		Provider:           testProvider,
+		JobState:           models.JobUploaded,
+	}
+	pendingUpload := uploaded
+	pendingUpload.JobState = models.JobPendingUpload
+	retried := uploaded
+	retried.Attempts = 2
+	summarizing := uploaded
+	summarizing.Status = models.Summarizing
+	summarizing.JobState = models.JobCompleted
+	identified := summarizing
+	identified.DetectedLanguage = "de-DE"
+	done := identified
+	done.Status = models.Done
+	done.SummaryLocation = "summary-testUUID"
+	withState := func(transcript models.Transcript, status models.TranscriptStatus, jobState models.JobState, reason string) *models.Transcript {
+		transcript.Status = status
+		transcript.JobState = jobState
+		transcript.FailureReason = reason
+		return &transcript
+	}
+	options := models.TranscriptionOptions{MaxSpeakers: 4, Language: models.DefaultLanguage}
+
+	cases := []struct {
+		description        string
+		claimError         error
+		message            models.OutboxMessage
+		transcript         models.Transcript
+		providerError      error
+		expectStart        bool
+		expectedJobName    string
+		expectedAdvance    *models.Transcript
+		expectedFrom       models.JobState
+		summarizerError    error
+		expectedRetryDelay time.Duration
+		expectAudioDeleted bool
+		expectedSummary    string
+		expectedError      error
+	}{
+		{
+			description:     "start step succeeds, transcript is transcribing",
+			message:         models.OutboxMessage{JobID: "job-1", Step: models.StartTranscriptionStep, Options: options, Attempts: 1},
+			transcript:      uploaded,
+			expectStart:     true,
+			expectedAdvance: withState(uploaded, models.Transcribing, models.JobProviderStarted, ""),
+			expectedFrom:    models.JobUploaded,
+		},
+		{
+			description:        "start step fails, step is retried after a delay that doubles with each attempt",
+			message:            models.OutboxMessage{JobID: "job-1", Step: models.StartTranscriptionStep, Options: options, Attempts: 3},
+			transcript:         uploaded,
+			providerError:      providerError,
+			expectStart:        true,
+			expectedRetryDelay: 4 * outboxRetryDelay,
+		},
+		{
+			description:     "start step fails on its last attempt, transcript is failed with the error as its reason",
+			message:         models.OutboxMessage{JobID: "job-1", Step: models.StartTranscriptionStep, Options: options, Attempts: maxOutboxAttempts},
+			transcript:      uploaded,
+			providerError:   providerError,
+			expectStart:     true,
+			expectedAdvance: withState(uploaded, models.TranscriptionFailed, models.JobFailed, "provider error"),
+			expectedFrom:    models.JobUploaded,
+		},
+		{
+			description:     "start step of a retried transcript, provider job is started under a name of its own",
+			message:         models.OutboxMessage{JobID: "job-1", Step: models.StartTranscriptionStep, Options: options, Attempts: 1},
+			transcript:      retried,
+			expectStart:     true,
+			expectedJobName: "job-1-2",
+			expectedAdvance: withState(retried, models.Transcribing, models.JobProviderStarted, ""),
+			expectedFrom:    models.JobUploaded,
+		},
+		{
+			description:     "summarize step succeeds, transcript is done with its detected language",
+			message:         models.OutboxMessage{JobID: "job-1", Step: models.SummarizeStep, Attempts: 1},
+			transcript:      summarizing,
+			expectedAdvance: &done,
+			expectedFrom:    models.JobCompleted,
+			expectedSummary: "# the party met in a tavern",
+		},
+		{
+			description:     "summarize step fails on its last attempt, transcript is summarizing failed with the error as its reason",
+			message:         models.OutboxMessage{JobID: "job-1", Step: models.SummarizeStep, Attempts: maxOutboxAttempts},
+			transcript:      summarizing,
+			summarizerError: errors.New("summarizer error"),
+			expectedAdvance: withState(identified, models.SummarizingFailed, models.JobCompleted, "summarizer error"),
+			expectedFrom:    models.JobCompleted,
+		},
+		{
+			description:        "upload expired, transcript is failed and its audio deleted",
+			message:            models.OutboxMessage{JobID: "job-1", Step: models.ExpireUploadStep, Attempts: 1},
+			transcript:         pendingUpload,
+			expectedAdvance:    withState(pendingUpload, models.TranscriptionFailed, models.JobFailed, "the audio upload did not complete"),
+			expectedFrom:       models.JobPendingUpload,
+			expectAudioDeleted: true,
+		},
+		{
+			description:   "claiming messages fails, error returned",
+			claimError:    dbError,
+			expectedError: dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockTranscriptDb{}
+			if c.claimError != nil {
+				mockDb.On("ClaimOutboxMessages", mock.Anything, mock.Anything, mock.Anything, outboxBatchSize).Return(nil, c.claimError)
+			} else {
+				mockDb.On("ClaimOutboxMessages", mock.Anything, mock.Anything, mock.Anything, outboxBatchSize).Return([]models.OutboxMessage{c.message}, nil)
+			}
+			mockDb.On("GetTranscript", mock.Anything, c.transcript.JobID).Return(&c.transcript, nil)
+			if c.expectedAdvance != nil {
+				mockDb.On("AdvanceTranscriptJob", mock.Anything, *c.expectedAdvance, c.expectedFrom, (*models.OutboxMessage)(nil)).Return(c.expectedAdvance, nil).Once()
+			}
+			if c.expectedRetryDelay != 0 {
+				inRetryWindow := mock.MatchedBy(func(nextAttemptAt time.Time) bool {
+					delay := time.Until(nextAttemptAt)
+					return delay > c.expectedRetryDelay-time.Minute && delay <= c.expectedRetryDelay
+				})
+				mockDb.On("RetryOutboxMessage", mock.Anything, c.transcript.JobID, c.providerError.Error(), inRetryWindow).Return(nil).Once()
+			}
+			mockDb.On("GetTranscriptSpeakers", mock.Anything, c.transcript.JobID).Return([]models.SpeakerAssignment{}, nil).Maybe()
+			mockTranscriptionProvider := &MockTranscriptionProvider{}
+			mockTranscriptionProvider.On("ParseTranscript", []byte("{}")).Return(&models.TranscriptDocument{Language: "de-DE"}, nil).Maybe()
+			if c.expectStart {
+				jobName := c.expectedJobName
+				if jobName == "" {
+					jobName = c.transcript.JobID
+				}
+				mockTranscriptionProvider.On("StartTranscriptionJob", jobName, c.transcript.AudioLocation, c.transcript.TranscriptLocation, c.transcript.AudioFormat, options).Return(c.providerError).Once()
+			}
+			mockFileStore := NewMockFileStore()
+			mockFileStore.UploadData(testBucket, c.transcript.AudioLocation, strings.NewReader(testAudio))
+			mockFileStore.UploadData(testBucket, c.transcript.TranscriptLocation, strings.NewReader("{}"))
+			mockSummarizer := &MockSummarizer{}
+			mockSummarizer.On("Summarize", mock.Anything, mock.Anything).Return("# the party met in a tavern", c.summarizerError).Maybe()
+
+			testManager := NewTranscriptionManager(testBucket, testProviders(mockTranscriptionProvider), mockFileStore, mockDb, &MockUUIDProvier{}, mockSummarizer, testMaxAudioBytes)
+
+			err := testManager.DispatchTranscriptionJobs(context.Background())
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			mockDb.AssertExpectations(t)
+			mockTranscriptionProvider.AssertExpectations(t)
+			_, ok := mockFileStore.GetContentFromPath(testBucket, c.transcript.AudioLocation)
+			assert.Equal(t, !c.expectAudioDeleted, ok)
+			if c.expectedSummary != "" {
+				summary, _ := mockFileStore.GetContentFromPath(testBucket, "summary-testUUID")
+				assert.Equal(t, c.expectedSummary, summary)
+			}
+		})
+	}
+}
+
+func TestRetryTranscript(t *testing.T) {
+	failedTranscription := models.Transcript{
+		JobID:              "job-1",
+		AudioLocation:      "audio-1",
+		AudioFormat:        models.MP3,
+		TranscriptLocation: "transcript-1",
+		Status:             models.TranscriptionFailed,
+		Provider:           testProvider,
+		Language:           "fr-FR",
+		JobState:           models.JobFailed,
+		FailureReason:      "the transcription provider reported the job as failed",
+		Attempts:           1,
+	}
+	expiredUpload := failedTranscription
+	expiredUpload.FailureReason = uploadExpiredReason
+	failedSummary := failedTranscription
+	failedSummary.Status = models.SummarizingFailed
+	failedSummary.JobState = models.JobCompleted
+	failedSummary.FailureReason = "summarizer error"
+	done := failedSummary
+	done.Status = models.Done
+	done.FailureReason = ""
+	reset := func(transcript models.Transcript, status models.TranscriptStatus, jobState models.JobState) models.Transcript {
+		transcript.Status = status
+		transcript.JobState = jobState
+		transcript.FailureReason = ""
+		transcript.Attempts = 2
+		return transcript
+	}
+	retriedTranscription := reset(failedTranscription, models.NotStarted, models.JobUploaded)
+	startedTranscription := reset(failedTranscription, models.Transcribing, models.JobProviderStarted)
+	retriedSummary := reset(failedSummary, models.Summarizing, models.JobCompleted)
+
+	cases := []struct {
+		description    string
+		transcript     *models.Transcript
+		getError       error
+		expectStart    bool
+		expectedStatus models.TranscriptStatus
+		expectedError  error
+	}{
+		{
+			description:    "transcription failed, transcript is transcribed again from its stored audio",
+			transcript:     &failedTranscription,
+			expectStart:    true,
+			expectedStatus: models.Transcribing,
+		},
+		{
+			description:    "summarizing failed, only the summary is retried",
+			transcript:     &failedSummary,
+			expectedStatus: models.Summarizing,
+		},
+		{
+			description:   "audio upload never completed, InvalidEntity returned",
+			transcript:    &expiredUpload,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "transcript did not fail, Conflicted returned",
+			transcript:    &done,
+			expectedError: models.Conflicted,
+		},
+		{
+			description:   "transcript does not exist, EntityNotFound returned",
+			getError:      models.EntityNotFound,
+			expectedError: models.EntityNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockTranscriptDb{}
+			mockDb.On("GetTranscript", mock.Anything, "job-1").Return(c.transcript, c.getError)
+			mockDb.On("CountSessionAttendees", mock.Anything, "session-1").Return(4, nil)
+			mockDb.On("GetVocabularyForSession", mock.Anything, "session-1").Return([]models.VocabularyTerm{}, nil)
+			options := models.TranscriptionOptions{Provider: testProvider, MaxSpeakers: 4, Language: "fr-FR"}
+			isStartMessage := mock.MatchedBy(func(message *models.OutboxMessage) bool {
+				return message.Step == models.StartTranscriptionStep && assert.ObjectsAreEqual(options, message.Options)
+			})
+			isSummarizeMessage := mock.MatchedBy(func(message *models.OutboxMessage) bool {
+				return message.Step == models.SummarizeStep
+			})
+			mockDb.On("AdvanceTranscriptJob", mock.Anything, retriedTranscription, models.JobState(models.JobFailed), isStartMessage).Return(&retriedTranscription, nil)
+			mockDb.On("AdvanceTranscriptJob", mock.Anything, startedTranscription, models.JobState(models.JobUploaded), (*models.OutboxMessage)(nil)).Return(&startedTranscription, nil)
+			mockDb.On("AdvanceTranscriptJob", mock.Anything, retriedSummary, models.JobState(models.JobCompleted), isSummarizeMessage).Return(&retriedSummary, nil)
+			mockTranscriptionProvider := &MockTranscriptionProvider{}
+			mockTranscriptionProvider.On("StartTranscriptionJob", "job-1-2", "audio-1", "transcript-1", models.AudioFormat(models.MP3), options).Return(nil)
+
+			testManager := NewTranscriptionManager(testBucket, testProviders(mockTranscriptionProvider), NewMockFileStore(), mockDb, &MockUUIDProvier{}, &MockSummarizer{}, testMaxAudioBytes)
+
+			result, err := testManager.RetryTranscript(context.Background(), "session-1", "job-1")
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				mockDb.AssertNotCalled(t, "AdvanceTranscriptJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+				return
+			}
+			assert.Equal(t, c.expectedStatus, result.Status)
+			assert.Equal(t, 2, result.Attempts)
+			assert.Empty(t, result.FailureReason)
+			if c.expectStart {
+				mockTranscriptionProvider.AssertExpectations(t)
+			} else {
+				mockTranscriptionProvider.AssertNotCalled(t, "StartTranscriptionJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestDeleteTranscript(t *testing.T) {
+	dbError := errors.New("db error")
+	storedTranscript := models.Transcript{
+		JobID:              "job123",
+		AudioLocation:      "audio/job123.mp3",
+		TranscriptLocation: "transcripts/job123.json",
+		SummaryLocation:    "summaries/job123.txt",
+	}
+	cases := []struct {
+		description   string
+		getError      error
+		deleteError   error
+		expectedError error
+		filesRemoved  bool
+	}{
+		{
+			description:  "transcript deleted, files removed",
+			filesRemoved: true,
+		},
+		{
+			description:   "transcript not found, EntityNotFound returned",
+			getError:      models.EntityNotFound,
+			expectedError: models.EntityNotFound,
+		},
+		{
+			description:   "database returned an error, files are kept",
+			deleteError:   dbError,
+			expectedError: dbError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockTranscriptDb{}
+			mockDb.On("GetTranscript", mock.Anything, "job123").Return(&storedTranscript, c.getError)
+			mockDb.On("DeleteTranscript", mock.Anything, "job123").Return(c.deleteError)
+			mockFileStore := NewMockFileStore()
+			for _, location := range []string{storedTranscript.AudioLocation, storedTranscript.TranscriptLocation, storedTranscript.SummaryLocation} {
+				mockFileStore.UploadData(testBucket, location, strings.NewReader("data"))
+			}
+			testManager := NewTranscriptionManager(testBucket, testProviders(&MockTranscriptionProvider{}), mockFileStore, mockDb, &MockUUIDProvier{}, &MockSummarizer{}, testMaxAudioBytes)
+			err := testManager.DeleteTranscript(context.Background(), "job123")
+			if c.filesRemoved {
+				assert.Empty(t, mockFileStore.files)
+			} else {
+				assert.Len(t, mockFileStore.files, 3)
+			}
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error returned: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+const (
+	// uploadExpiry is how long the audio of a submitted transcript can take to upload before the job is failed
+	uploadExpiry = 2 * time.Hour
+	// outboxLease is how long a claimed outbox message is held before it is taken again, it outlasts a provider
+	// start that waits for a custom vocabulary to be built
+	outboxLease = 10 * time.Minute
+	// outboxBatchSize is the number of outbox messages claimed at a time
+	outboxBatchSize = 20
+	// maxOutboxAttempts is the number of times a step is taken before its job is failed
+	maxOutboxAttempts = 5
+	// outboxRetryDelay is how long a failed step waits before it is taken again, doubled on every attempt
+	outboxRetryDelay = 30 * time.Second
+	// uploadExpiredReason is the failure reason of a job whose audio upload never completed
+	uploadExpiredReason = "the audio upload did not complete"
+	// providerFailedReason is the failure reason of a job the provider failed without saying why
+	providerFailedReason = "the transcription provider reported the job as failed"
+)
+
+// providerJobName names the provider job of a transcript's current attempt. Providers keep the jobs they ran
+// under their name, so a retried transcript needs a new one.
+func providerJobName(transcript models.Transcript) string {
+	if transcript.Attempts <= 1 {
+		return transcript.JobID
+	}
+	return fmt.Sprintf("%s-%d", transcript.JobID, transcript.Attempts)
+}
+
+// startTranscriptionMessage is the outbox message that starts the provider job of a transcript. It is claimed
+// from the start, since the submitter takes the step right after storing it.
+func startTranscriptionMessage(jobID string, options models.TranscriptionOptions) models.OutboxMessage {
+	return models.OutboxMessage{
+		JobID:         jobID,
+		Step:          models.StartTranscriptionStep,
+		Options:       options,
+		Attempts:      1,
+		NextAttemptAt: time.Now().Add(outboxLease),
+	}
+}
+
+// summarizeMessage is the outbox message that summarizes a transcript once its provider job completed
+func summarizeMessage(jobID string) models.OutboxMessage {
+	return models.OutboxMessage{
+		JobID:         jobID,
+		Step:          models.SummarizeStep,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+// DispatchTranscriptionJobs takes the steps of transcription jobs that are due in the outbox. Steps that fail are
+// logged and left in the outbox to be retried.
+func (t *TranscriptionManager) DispatchTranscriptionJobs(ctx context.Context) error {
+	now := time.Now()
+	messages, err := t.transcriptionDb.ClaimOutboxMessages(ctx, now, now.Add(outboxLease), outboxBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, message := range messages {
+		if err := t.dispatch(ctx, message); err != nil {
+			log.Printf("failed to take step %s of transcription job %s: %s", message.Step, message.JobID, err)
+		}
+	}
+	return nil
+}
+
+func (t *TranscriptionManager) dispatch(ctx context.Context, message models.OutboxMessage) error {
+	transcript, err := t.transcriptionDb.GetTranscript(ctx, message.JobID)
+	if err != nil {
+		return err
+	}
+	switch message.Step {
+	case models.StartTranscriptionStep:
+		_, err = t.startTranscription(ctx, *transcript, message)
+	case models.ExpireUploadStep:
+		err = t.expireUpload(ctx, *transcript)
+	case models.SummarizeStep:
+		err = t.summarize(ctx, *transcript, message)
+	}
+	return err
+}
+
+// startTranscription starts the provider job of an uploaded transcript and records it as Transcribing. Providers
+// treat a job that was already started as started, so the step can be taken again when the service stopped
+// before recording it. A start that fails is retried with a growing delay, once it was attempted
+// maxOutboxAttempts times the transcript is failed with the error as its reason.
+func (t *TranscriptionManager) startTranscription(ctx context.Context, transcript models.Transcript, message models.OutboxMessage) (*models.Transcript, error) {
+	_, provider, err := t.providers.provider(transcript.Provider)
+	if err == nil {
+		err = provider.StartTranscriptionJob(providerJobName(transcript), transcript.AudioLocation, transcript.TranscriptLocation, transcript.AudioFormat, message.Options)
+	}
+	if err != nil {
+		return t.retryStep(ctx, transcript, models.JobUploaded, message, err)
+	}
+	transcript.Status = models.Transcribing
+	transcript.JobState = models.JobProviderStarted
+	return t.transcriptionDb.AdvanceTranscriptJob(ctx, transcript, models.JobUploaded, nil)
+}
+
+// expireUpload fails a transcript whose audio upload didn't complete and deletes whatever was uploaded
+func (t *TranscriptionManager) expireUpload(ctx context.Context, transcript models.Transcript) error {
+	if _, err := t.failStep(ctx, transcript, models.JobPendingUpload, models.TranscriptionFailed, uploadExpiredReason); err != nil {
+		return err
+	}
+	t.deleteAudio(transcript.AudioLocation)
+	return nil
+}
+
+// summarize summarizes a transcript whose provider job completed and records it as Done, recording the language
+// the provider detected along the way
+func (t *TranscriptionManager) summarize(ctx context.Context, transcript models.Transcript, message models.OutboxMessage) error {
+	summaryLocation := fmt.Sprintf("summary-%s", t.uuidProvider.NewUUID())
+	if err := t.generateSummary(ctx, &transcript, summaryLocation); err != nil {
+		_, err = t.retryStep(ctx, transcript, models.JobCompleted, message, err)
+		return err
+	}
+	transcript.SummaryLocation = summaryLocation
+	transcript.Status = models.Done
+	_, err := t.transcriptionDb.AdvanceTranscriptJob(ctx, transcript, models.JobCompleted, nil)
+	return err
+}
+
+// advanceTranscriptionJob records the status a provider reported for the job of a transcript, whether it was
+// polled or sent in an event. A completed job is left to the dispatcher to summarize, since a summary can take
+// longer than a provider waits for its event to be accepted. The transcript only advances from
+// ProviderStarted, so a status that is reported twice is only recorded once.
+func (t *TranscriptionManager) advanceTranscriptionJob(ctx context.Context, transcript models.Transcript, status models.TranscriptionJobStatus, reason string) error {
+	var err error
+	switch status {
+	case models.TranscriptionJobCompleted:
+		transcript.Status = models.Summarizing
+		transcript.JobState = models.JobCompleted
+		summarize := summarizeMessage(transcript.JobID)
+		_, err = t.transcriptionDb.AdvanceTranscriptJob(ctx, transcript, models.JobProviderStarted, &summarize)
+	case models.TranscriptionJobFailed:
+		if reason == "" {
+			reason = providerFailedReason
+		}
+		_, err = t.failStep(ctx, transcript, models.JobProviderStarted, models.TranscriptionFailed, reason)
+	default:
+		return nil
+	}
+	if errors.Is(err, models.Conflicted) {
+		log.Printf("transcription job %s already left %s, ignoring its %s status", transcript.JobID, models.JobState(models.JobProviderStarted), status)
+		return nil
+	}
+	return err
+}
+
+// retryStep records why the step of a transcript in the from job state failed and when it is taken again, or
+// fails the transcript when the step has been attempted maxOutboxAttempts times
+func (t *TranscriptionManager) retryStep(ctx context.Context, transcript models.Transcript, from models.JobState, message models.OutboxMessage, stepErr error) (*models.Transcript, error) {
+	log.Printf("step %s of transcription job %s failed on attempt %d: %s", message.Step, transcript.JobID, message.Attempts, stepErr)
+	if message.Attempts >= maxOutboxAttempts {
+		status := models.TranscriptStatus(models.TranscriptionFailed)
+		if message.Step == models.SummarizeStep {
+			status = models.SummarizingFailed
+		}
+		return t.failStep(ctx, transcript, from, status, stepErr.Error())
+	}
+	nextAttemptAt := time.Now().Add(outboxRetryDelay << (message.Attempts - 1))
+	if err := t.transcriptionDb.RetryOutboxMessage(ctx, transcript.JobID, stepErr.Error(), nextAttemptAt); err != nil {
+		return nil, err
+	}
+	return &transcript, nil
+}
+
+// failStep ends a transcript in a failed status with the reason it failed, a transcript that failed transcribing
+// also leaves the job state machine
+func (t *TranscriptionManager) failStep(ctx context.Context, transcript models.Transcript, from models.JobState, status models.TranscriptStatus, reason string) (*models.Transcript, error) {
+	transcript.Status = status
+	if status == models.TranscriptionFailed {
+		transcript.JobState = models.JobFailed
+	}
+	transcript.FailureReason = reason
+	return t.transcriptionDb.AdvanceTranscriptJob(ctx, transcript, from, nil)
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type transcriptionJobPoller interface {
+	DispatchTranscriptionJobs(ctx context.Context) error
+	PollTranscriptionJobs(ctx context.Context) error
+}
+
+// TranscriptionPoller periodically advances the status of in-flight transcription jobs and takes the steps of
+// transcription jobs that are due in the outbox. When providers send job events the status only needs to be
+// polled for the events that were missed, so it can be polled less often than the outbox is dispatched.
+type TranscriptionPoller struct {
+	poller       transcriptionJobPoller
+	interval     time.Duration
+	pollInterval time.Duration
+}
+
+// NewTranscriptionPoller creates a TranscriptionPoller that dispatches every interval and polls every
+// pollInterval, which is rounded up to a multiple of interval
+func NewTranscriptionPoller(poller transcriptionJobPoller, interval, pollInterval time.Duration) *TranscriptionPoller {
+	return &TranscriptionPoller{
+		poller:       poller,
+		interval:     interval,
+		pollInterval: pollInterval,
+	}
+}
+
+// Run polls when pollInterval has passed since the last poll and dispatches on every interval until the
+// context is cancelled. Jobs found completed by a poll are summarized by the dispatch that follows it.
+func (p *TranscriptionPoller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	var lastPoll time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if now.Sub(lastPoll) >= p.pollInterval {
+				lastPoll = now
+				if err := p.poller.PollTranscriptionJobs(ctx); err != nil {
+					log.Printf("failed to poll transcription jobs: %s", err)
+				}
+			}
+			if err := p.poller.DispatchTranscriptionJobs(ctx); err != nil {
+				log.Printf("failed to dispatch transcription jobs: %s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// TranscriptionProviders holds the transcription providers of a deployment by name. A job is started with the
+// provider it asks for, or the default one when it doesn't ask, and is then followed up with the same provider.
+type TranscriptionProviders struct {
+	providers       map[string]transcriptionProvider
+	defaultProvider string
+}
+
+func NewTranscriptionProviders(defaultProvider string) *TranscriptionProviders {
+	return &TranscriptionProviders{
+		providers:       map[string]transcriptionProvider{},
+		defaultProvider: defaultProvider,
+	}
+}
+
+// Register makes a provider available under name, replacing any provider already registered under it
+func (p *TranscriptionProviders) Register(name string, provider transcriptionProvider) {
+	p.providers[strings.ToLower(name)] = provider
+}
+
+// Names returns the names of the registered providers in alphabetical order
+func (p *TranscriptionProviders) Names() []string {
+	names := make([]string, 0, len(p.providers))
+	for name := range p.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultProvider returns the name of the provider used by jobs that don't ask for one
+func (p *TranscriptionProviders) DefaultProvider() string {
+	return strings.ToLower(p.defaultProvider)
+}
+
+// provider returns the named provider along with its registered name, the default provider is returned for an
+// empty name. InvalidEntity is returned for a provider that isn't registered.
+func (p *TranscriptionProviders) provider(name string) (string, transcriptionProvider, error) {
+	if name == "" {
+		name = p.defaultProvider
+	}
+	name = strings.ToLower(name)
+	provider, ok := p.providers[name]
+	if !ok {
+		return "", nil, fmt.Errorf("transcription provider %q is not one of %s: %w", name, strings.Join(p.Names(), ", "), models.InvalidEntity)
+	}
+	return name, provider, nil
+}
@@ -0,0 +1,331 @@
+package app
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// directUploadExpiry is how long a client has to start an upload with a presigned URL
+const directUploadExpiry = time.Hour
+
+type uploadFileStore interface {
+	StartMultipartUpload(bucket, fileKey string) (string, error)
+	UploadPart(bucket, fileKey, uploadID string, partNumber int, body io.Reader) (string, error)
+	CompleteMultipartUpload(bucket, fileKey, uploadID string, parts []models.UploadPart) error
+	AbortMultipartUpload(bucket, fileKey, uploadID string) error
+	DeleteData(bucket, fileKey string) error
+	PresignUpload(bucket, fileKey string, constraints models.UploadConstraints, expiry time.Duration) (*models.PresignedURL, error)
+	StatData(bucket, fileKey string) (*models.StoredFile, error)
+	ReadHead(bucket, fileKey string, length int64) ([]byte, error)
+}
+
+type uploadDb interface {
+	AddAudioUpload(ctx context.Context, upload models.ResumableUpload) (*models.ResumableUpload, error)
+	GetAudioUpload(ctx context.Context, sessionID, uploadID string) (*models.ResumableUpload, error)
+	UpdateAudioUpload(ctx context.Context, upload models.ResumableUpload, previousOffset int64) error
+	ClaimAudioUpload(ctx context.Context, uploadID, jobID string) error
+	ReleaseAudioUpload(ctx context.Context, uploadID, jobID string) error
+	DeleteAudioUpload(ctx context.Context, uploadID string) error
+	AddDirectUpload(ctx context.Context, upload models.DirectUpload) (*models.DirectUpload, error)
+	GetDirectUpload(ctx context.Context, sessionID, uploadID string) (*models.DirectUpload, error)
+	ClaimDirectUpload(ctx context.Context, uploadID, jobID string) error
+	ReleaseDirectUpload(ctx context.Context, uploadID, jobID string) error
+}
+
+type storedAudioSubmitter interface {
+	CheckTranscriptionOptions(options models.TranscriptionOptions) error
+	SubmitStoredTranscriptionJob(ctx context.Context, sessionID, jobID string, audio models.StoredAudio, options models.TranscriptionOptions) (*models.Transcript, error)
+}
+
+// UploadManager receives session recordings in chunks so an upload interrupted by a dropped connection can be
+// resumed from the last chunk that arrived. The chunks are written as parts of a multipart upload to the file
+// store and the assembled recording is handed to the transcription manager once the upload is finalized.
+// Clients can also skip the API and upload straight to the file store with a presigned URL.
+type UploadManager struct {
+	bucket        string
+	fileStore     uploadFileStore
+	uploadDb      uploadDb
+	uuidProvider  uuidProvider
+	submitter     storedAudioSubmitter
+	maxAudioBytes int64
+}
+
+func NewUploadManager(bucket string, fileStore uploadFileStore, uploadDb uploadDb, uuidProvider uuidProvider, submitter storedAudioSubmitter, maxAudioBytes int64) *UploadManager {
+	return &UploadManager{
+		bucket:        bucket,
+		fileStore:     fileStore,
+		uploadDb:      uploadDb,
+		uuidProvider:  uuidProvider,
+		submitter:     submitter,
+		maxAudioBytes: maxAudioBytes,
+	}
+}
+
+// CreateUpload starts a resumable upload of length bytes of audio to a session
+func (u *UploadManager) CreateUpload(ctx context.Context, sessionID string, audioFormat models.AudioFormat, length int64, options models.TranscriptionOptions) (*models.ResumableUpload, error) {
+	if length <= 0 {
+		return nil, fmt.Errorf("upload length must be positive: %w", models.InvalidEntity)
+	}
+	if length > u.maxAudioBytes {
+		return nil, fmt.Errorf("audio is larger than %d bytes: %w", u.maxAudioBytes, models.TooLarge)
+	}
+	if err := u.submitter.CheckTranscriptionOptions(options); err != nil {
+		return nil, err
+	}
+
+	audioLocation := fmt.Sprintf("audio-%s", u.uuidProvider.NewUUID())
+	storageUploadID, err := u.fileStore.StartMultipartUpload(u.bucket, audioLocation)
+	if err != nil {
+		return nil, err
+	}
+	upload, err := u.uploadDb.AddAudioUpload(ctx, models.ResumableUpload{
+		ID:              u.uuidProvider.NewUUID(),
+		SessionID:       sessionID,
+		AudioFormat:     audioFormat,
+		Length:          length,
+		AudioLocation:   audioLocation,
+		StorageUploadID: storageUploadID,
+		Options:         options,
+	})
+	if err != nil {
+		u.abort(audioLocation, storageUploadID)
+		return nil, err
+	}
+	return upload, nil
+}
+
+// GetUpload returns a resumable upload of a session, its Offset is where the next chunk has to start
+func (u *UploadManager) GetUpload(ctx context.Context, sessionID, uploadID string) (*models.ResumableUpload, error) {
+	return u.uploadDb.GetAudioUpload(ctx, sessionID, uploadID)
+}
+
+// AppendChunk writes the next chunkLength bytes of an upload starting at offset. Conflicted is returned when
+// offset isn't where the upload left off. Chunks other than the last have to hold at least MinUploadChunkBytes.
+// A chunk that arrives incomplete is discarded as a whole and has to be sent again. The first chunk is rejected
+// with InvalidEntity unless it starts a recording in the upload's format.
+func (u *UploadManager) AppendChunk(ctx context.Context, sessionID, uploadID string, offset int64, chunk io.Reader, chunkLength int64) (*models.ResumableUpload, error) {
+	upload, err := u.uploadDb.GetAudioUpload(ctx, sessionID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Assembled {
+		return nil, fmt.Errorf("upload %s was already finalized: %w", uploadID, models.Conflicted)
+	}
+	if offset != upload.Offset {
+		return nil, fmt.Errorf("upload %s is at offset %d: %w", uploadID, upload.Offset, models.Conflicted)
+	}
+	if chunkLength <= 0 || offset+chunkLength > upload.Length {
+		return nil, fmt.Errorf("chunk of %d bytes at offset %d doesn't fit an upload of %d bytes: %w", chunkLength, offset, upload.Length, models.InvalidEntity)
+	}
+	if chunkLength < models.MinUploadChunkBytes && offset+chunkLength < upload.Length {
+		return nil, fmt.Errorf("chunks other than the last must hold at least %d bytes: %w", models.MinUploadChunkBytes, models.InvalidEntity)
+	}
+
+	if offset == 0 {
+		if chunk, err = sniffAudio(chunk, upload.AudioFormat); err != nil {
+			return nil, err
+		}
+	}
+	chunkReader, err := resumeAudioReader(chunk, chunkLength, upload.HashState)
+	if err != nil {
+		return nil, err
+	}
+	partNumber := len(upload.Parts) + 1
+	etag, err := u.fileStore.UploadPart(u.bucket, upload.AudioLocation, upload.StorageUploadID, partNumber, chunkReader)
+	if completeErr := chunkReader.checkComplete(chunkLength); completeErr != nil {
+		err = completeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if upload.HashState, err = chunkReader.hashState(); err != nil {
+		return nil, err
+	}
+	upload.Parts = append(upload.Parts, models.UploadPart{Number: partNumber, ETag: etag})
+	upload.Offset += chunkLength
+	if err = u.uploadDb.UpdateAudioUpload(ctx, *upload, offset); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// FinalizeUpload assembles a fully received upload into the session's audio and starts transcribing it. The
+// upload is claimed for the transcript before it is submitted, so finalizing it twice at once submits it only
+// once and the other finalize gets Conflicted. A finalize that failed after the audio was assembled can be
+// retried.
+func (u *UploadManager) FinalizeUpload(ctx context.Context, sessionID, uploadID string) (*models.Transcript, error) {
+	upload, err := u.uploadDb.GetAudioUpload(ctx, sessionID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.TranscriptJobID != "" {
+		return nil, fmt.Errorf("upload %s was already finalized as transcript %s: %w", uploadID, upload.TranscriptJobID, models.Conflicted)
+	}
+	if upload.Offset != upload.Length {
+		return nil, fmt.Errorf("upload %s has %d of %d bytes: %w", uploadID, upload.Offset, upload.Length, models.InvalidEntity)
+	}
+
+	if !upload.Assembled {
+		if err = u.fileStore.CompleteMultipartUpload(u.bucket, upload.AudioLocation, upload.StorageUploadID, upload.Parts); err != nil {
+			return nil, err
+		}
+		upload.Assembled = true
+		if err = u.uploadDb.UpdateAudioUpload(ctx, *upload, upload.Offset); err != nil {
+			return nil, err
+		}
+	}
+
+	audioSHA256, err := sha256FromState(upload.HashState)
+	if err != nil {
+		return nil, err
+	}
+	audio := models.StoredAudio{
+		Location: upload.AudioLocation,
+		Format:   upload.AudioFormat,
+		SHA256:   audioSHA256,
+		Size:     upload.Length,
+	}
+	jobID := u.uuidProvider.NewUUID()
+	if err = u.uploadDb.ClaimAudioUpload(ctx, uploadID, jobID); err != nil {
+		return nil, err
+	}
+	transcript, err := u.submitter.SubmitStoredTranscriptionJob(ctx, sessionID, jobID, audio, upload.Options)
+	if err != nil {
+		if releaseErr := u.uploadDb.ReleaseAudioUpload(ctx, uploadID, jobID); releaseErr != nil {
+			log.Printf("failed to release upload %s claimed as transcript %s: %s", uploadID, jobID, releaseErr)
+		}
+		return nil, err
+	}
+	return transcript, nil
+}
+
+// DeleteUpload cancels an upload that wasn't finalized and discards the chunks received so far
+func (u *UploadManager) DeleteUpload(ctx context.Context, sessionID, uploadID string) error {
+	upload, err := u.uploadDb.GetAudioUpload(ctx, sessionID, uploadID)
+	if err != nil {
+		return err
+	}
+	if upload.TranscriptJobID != "" {
+		return fmt.Errorf("upload %s was already finalized as transcript %s: %w", uploadID, upload.TranscriptJobID, models.Conflicted)
+	}
+	if err = u.uploadDb.DeleteAudioUpload(ctx, uploadID); err != nil {
+		return err
+	}
+	if upload.Assembled {
+		if err = u.fileStore.DeleteData(u.bucket, upload.AudioLocation); err != nil {
+			log.Printf("failed to delete audio %s of cancelled upload %s: %s", upload.AudioLocation, uploadID, err)
+		}
+		return nil
+	}
+	u.abort(upload.AudioLocation, upload.StorageUploadID)
+	return nil
+}
+
+// CreateDirectUpload issues a presigned URL the audio of a session can be uploaded to directly. The URL only
+// accepts audio matching the constraints and expires after an hour.
+func (u *UploadManager) CreateDirectUpload(ctx context.Context, sessionID string, audioFormat models.AudioFormat, constraints models.UploadConstraints, options models.TranscriptionOptions) (*models.DirectUpload, error) {
+	if constraints.Length <= 0 {
+		return nil, fmt.Errorf("upload length must be positive: %w", models.InvalidEntity)
+	}
+	if constraints.Length > u.maxAudioBytes {
+		return nil, fmt.Errorf("audio is larger than %d bytes: %w", u.maxAudioBytes, models.TooLarge)
+	}
+	if sum, err := hex.DecodeString(constraints.SHA256); err != nil || (len(sum) != 0 && len(sum) != 32) {
+		return nil, fmt.Errorf("sha256 must be a hex encoded SHA-256: %w", models.InvalidEntity)
+	}
+	if err := u.submitter.CheckTranscriptionOptions(options); err != nil {
+		return nil, err
+	}
+
+	audioLocation := fmt.Sprintf("audio-%s", u.uuidProvider.NewUUID())
+	uploadURL, err := u.fileStore.PresignUpload(u.bucket, audioLocation, constraints, directUploadExpiry)
+	if err != nil {
+		return nil, err
+	}
+	upload, err := u.uploadDb.AddDirectUpload(ctx, models.DirectUpload{
+		ID:            u.uuidProvider.NewUUID(),
+		SessionID:     sessionID,
+		AudioFormat:   audioFormat,
+		ContentType:   constraints.ContentType,
+		Length:        constraints.Length,
+		SHA256:        constraints.SHA256,
+		AudioLocation: audioLocation,
+		Options:       options,
+	})
+	if err != nil {
+		return nil, err
+	}
+	upload.UploadURL = uploadURL
+	return upload, nil
+}
+
+// ConfirmDirectUpload checks the audio of a direct upload arrived in the file store as declared and starts
+// transcribing it. InvalidEntity is returned when the audio is missing or doesn't match the upload, audio in
+// the wrong format is deleted. The upload is claimed for the transcript before it is submitted, so confirming
+// it twice at once submits it only once and the other confirm gets Conflicted.
+func (u *UploadManager) ConfirmDirectUpload(ctx context.Context, sessionID, uploadID string) (*models.Transcript, error) {
+	upload, err := u.uploadDb.GetDirectUpload(ctx, sessionID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.TranscriptJobID != "" {
+		return nil, fmt.Errorf("upload %s was already confirmed as transcript %s: %w", uploadID, upload.TranscriptJobID, models.Conflicted)
+	}
+	file, err := u.fileStore.StatData(u.bucket, upload.AudioLocation)
+	if errors.Is(err, models.EntityNotFound) {
+		return nil, fmt.Errorf("audio of upload %s was not uploaded: %w", uploadID, models.InvalidEntity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if file.Size != upload.Length || file.ContentType != upload.ContentType {
+		return nil, fmt.Errorf("uploaded %d bytes of %s, expected %d bytes of %s: %w", file.Size, file.ContentType, upload.Length, upload.ContentType, models.InvalidEntity)
+	}
+	header, err := u.fileStore.ReadHead(u.bucket, upload.AudioLocation, sniffLength)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkAudioFormat(header, upload.AudioFormat); err != nil {
+		// the audio went straight to storage so it can only be checked, and thrown away, after the fact
+		if deleteErr := u.fileStore.DeleteData(u.bucket, upload.AudioLocation); deleteErr != nil {
+			log.Printf("failed to delete audio %s of rejected upload %s: %s", upload.AudioLocation, uploadID, deleteErr)
+		}
+		return nil, err
+	}
+
+	audio := models.StoredAudio{
+		Location: upload.AudioLocation,
+		Format:   upload.AudioFormat,
+		SHA256:   upload.SHA256,
+		Size:     file.Size,
+	}
+	if audio.SHA256 == "" {
+		audio.SHA256 = file.SHA256
+	}
+	jobID := u.uuidProvider.NewUUID()
+	if err = u.uploadDb.ClaimDirectUpload(ctx, uploadID, jobID); err != nil {
+		return nil, err
+	}
+	transcript, err := u.submitter.SubmitStoredTranscriptionJob(ctx, sessionID, jobID, audio, upload.Options)
+	if err != nil {
+		if releaseErr := u.uploadDb.ReleaseDirectUpload(ctx, uploadID, jobID); releaseErr != nil {
+			log.Printf("failed to release upload %s claimed as transcript %s: %s", uploadID, jobID, releaseErr)
+		}
+		return nil, err
+	}
+	return transcript, nil
+}
+
+// abort discards the parts of a multipart upload that won't be completed
+func (u *UploadManager) abort(audioLocation, storageUploadID string) {
+	if err := u.fileStore.AbortMultipartUpload(u.bucket, audioLocation, storageUploadID); err != nil {
+		log.Printf("failed to abort upload of %s: %s", audioLocation, err)
+	}
+}
@@ -0,0 +1,626 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testUploadID       = "storageUpload"
+	testMaxUploadBytes = 1 << 30
+)
+
+func (m *MockFileStore) StartMultipartUpload(bucket, fileKey string) (string, error) {
+	m.parts[testUploadID] = map[int][]byte{}
+	return testUploadID, nil
+}
+
+func (m *MockFileStore) UploadPart(bucket, fileKey, uploadID string, partNumber int, body io.Reader) (string, error) {
+	parts, ok := m.parts[uploadID]
+	if !ok {
+		return "", models.EntityNotFound
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	parts[partNumber] = b
+	return fmt.Sprintf("etag-%d", partNumber), nil
+}
+
+func (m *MockFileStore) CompleteMultipartUpload(bucket, fileKey, uploadID string, uploadParts []models.UploadPart) error {
+	parts, ok := m.parts[uploadID]
+	if !ok {
+		return models.EntityNotFound
+	}
+	file := []byte{}
+	for _, part := range uploadParts {
+		file = append(file, parts[part.Number]...)
+	}
+	m.files[fmt.Sprintf("%s/%s", bucket, fileKey)] = file
+	delete(m.parts, uploadID)
+	return nil
+}
+
+func (m *MockFileStore) AbortMultipartUpload(bucket, fileKey, uploadID string) error {
+	delete(m.parts, uploadID)
+	return nil
+}
+
+func (m *MockFileStore) PresignUpload(bucket, fileKey string, constraints models.UploadConstraints, expiry time.Duration) (*models.PresignedURL, error) {
+	return &models.PresignedURL{
+		URL:     fmt.Sprintf("https://files.test/%s/%s?expires=%s", bucket, fileKey, expiry),
+		Method:  http.MethodPut,
+		Headers: map[string]string{"Content-Type": constraints.ContentType},
+	}, nil
+}
+
+func (m *MockFileStore) ReadHead(bucket, fileKey string, length int64) ([]byte, error) {
+	b, ok := m.files[fmt.Sprintf("%s/%s", bucket, fileKey)]
+	if !ok {
+		return nil, models.EntityNotFound
+	}
+	if int64(len(b)) > length {
+		b = b[:length]
+	}
+	return b, nil
+}
+
+func (m *MockFileStore) StatData(bucket, fileKey string) (*models.StoredFile, error) {
+	key := fmt.Sprintf("%s/%s", bucket, fileKey)
+	b, ok := m.files[key]
+	if !ok {
+		return nil, models.EntityNotFound
+	}
+	return &models.StoredFile{Size: int64(len(b)), ContentType: m.contentTypes[key]}, nil
+}
+
+type MockUploadDb struct {
+	mock.Mock
+}
+
+func (m *MockUploadDb) AddAudioUpload(ctx context.Context, upload models.ResumableUpload) (*models.ResumableUpload, error) {
+	args := m.Called(ctx, upload)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ResumableUpload), nil
+}
+
+func (m *MockUploadDb) GetAudioUpload(ctx context.Context, sessionID, uploadID string) (*models.ResumableUpload, error) {
+	args := m.Called(ctx, sessionID, uploadID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ResumableUpload), nil
+}
+
+func (m *MockUploadDb) UpdateAudioUpload(ctx context.Context, upload models.ResumableUpload, previousOffset int64) error {
+	args := m.Called(ctx, upload, previousOffset)
+	return args.Error(0)
+}
+
+func (m *MockUploadDb) ClaimAudioUpload(ctx context.Context, uploadID, jobID string) error {
+	args := m.Called(ctx, uploadID, jobID)
+	return args.Error(0)
+}
+
+func (m *MockUploadDb) ReleaseAudioUpload(ctx context.Context, uploadID, jobID string) error {
+	args := m.Called(ctx, uploadID, jobID)
+	return args.Error(0)
+}
+
+func (m *MockUploadDb) DeleteAudioUpload(ctx context.Context, uploadID string) error {
+	args := m.Called(ctx, uploadID)
+	return args.Error(0)
+}
+
+func (m *MockUploadDb) AddDirectUpload(ctx context.Context, upload models.DirectUpload) (*models.DirectUpload, error) {
+	args := m.Called(ctx, upload)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DirectUpload), nil
+}
+
+func (m *MockUploadDb) GetDirectUpload(ctx context.Context, sessionID, uploadID string) (*models.DirectUpload, error) {
+	args := m.Called(ctx, sessionID, uploadID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DirectUpload), nil
+}
+
+func (m *MockUploadDb) ClaimDirectUpload(ctx context.Context, uploadID, jobID string) error {
+	args := m.Called(ctx, uploadID, jobID)
+	return args.Error(0)
+}
+
+func (m *MockUploadDb) ReleaseDirectUpload(ctx context.Context, uploadID, jobID string) error {
+	args := m.Called(ctx, uploadID, jobID)
+	return args.Error(0)
+}
+
+type MockStoredAudioSubmitter struct {
+	mock.Mock
+}
+
+func (m *MockStoredAudioSubmitter) CheckTranscriptionOptions(options models.TranscriptionOptions) error {
+	if options.Provider != "" && options.Provider != testProvider {
+		return models.InvalidEntity
+	}
+	return checkTranscriptionOptions(options)
+}
+
+func (m *MockStoredAudioSubmitter) SubmitStoredTranscriptionJob(ctx context.Context, sessionID, jobID string, audio models.StoredAudio, options models.TranscriptionOptions) (*models.Transcript, error) {
+	args := m.Called(ctx, sessionID, jobID, audio, options)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transcript), nil
+}
+
+func TestCreateUpload(t *testing.T) {
+	dbError := errors.New("db error")
+	cases := []struct {
+		description     string
+		length          int64
+		options         models.TranscriptionOptions
+		dbError         error
+		expectedRecord  *models.ResumableUpload
+		expectedError   error
+		expectedAborted bool
+	}{
+		{
+			description: "upload created",
+			length:      12000000,
+			options:     models.TranscriptionOptions{MaxSpeakers: 4},
+			expectedRecord: &models.ResumableUpload{
+				ID:              "testUUID",
+				SessionID:       "session0",
+				AudioFormat:     models.MP3,
+				Length:          12000000,
+				AudioLocation:   "audio-testUUID",
+				StorageUploadID: testUploadID,
+				Options:         models.TranscriptionOptions{MaxSpeakers: 4},
+			},
+		},
+		{
+			description:   "length is over the limit, TooLarge returned",
+			length:        testMaxUploadBytes + 1,
+			expectedError: models.TooLarge,
+		},
+		{
+			description:   "length is zero, InvalidEntity returned",
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "max speakers is out of range, InvalidEntity returned",
+			length:        100,
+			options:       models.TranscriptionOptions{MaxSpeakers: 31},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:     "database returns an error, storage upload aborted",
+			length:          100,
+			dbError:         dbError,
+			expectedError:   dbError,
+			expectedAborted: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockUploadDb{}
+			if c.expectedRecord != nil {
+				mockDb.On("AddAudioUpload", mock.Anything, *c.expectedRecord).Return(c.expectedRecord, nil)
+			} else if c.dbError != nil {
+				mockDb.On("AddAudioUpload", mock.Anything, mock.Anything).Return(nil, c.dbError)
+			}
+			mockFileStore := NewMockFileStore()
+			testManager := NewUploadManager(testBucket, mockFileStore, mockDb, &MockUUIDProvier{}, &MockStoredAudioSubmitter{}, testMaxUploadBytes)
+
+			result, err := testManager.CreateUpload(context.Background(), "session0", models.MP3, c.length, c.options)
+			if c.expectedError != nil {
+				assert.ErrorIs(t, err, c.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, c.expectedRecord, result)
+			}
+			if c.expectedAborted {
+				assert.NotContains(t, mockFileStore.parts, testUploadID)
+			}
+			mockDb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAppendChunk(t *testing.T) {
+	firstChunk := "ID3" + strings.Repeat("a", models.MinUploadChunkBytes-3)
+	cases := []struct {
+		description    string
+		upload         models.ResumableUpload
+		offset         int64
+		chunk          string
+		chunkLength    int64
+		expectedOffset int64
+		expectedError  error
+	}{
+		{
+			description:    "first chunk appended",
+			upload:         models.ResumableUpload{ID: "upload0", Length: models.MinUploadChunkBytes + 5},
+			chunk:          firstChunk,
+			chunkLength:    models.MinUploadChunkBytes,
+			expectedOffset: models.MinUploadChunkBytes,
+		},
+		{
+			description:    "last chunk may be smaller than the minimum",
+			upload:         models.ResumableUpload{ID: "upload0", Length: 5},
+			chunk:          "ID3au",
+			chunkLength:    5,
+			expectedOffset: 5,
+		},
+		{
+			description:   "offset is not where the upload left off, Conflicted returned",
+			upload:        models.ResumableUpload{ID: "upload0", Length: 10, Offset: 5},
+			chunk:         "ID3au",
+			chunkLength:   5,
+			expectedError: models.Conflicted,
+		},
+		{
+			description:   "chunk runs past the upload length, InvalidEntity returned",
+			upload:        models.ResumableUpload{ID: "upload0", Length: 4},
+			chunk:         "ID3au",
+			chunkLength:   5,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "chunk other than the last is under the minimum, InvalidEntity returned",
+			upload:        models.ResumableUpload{ID: "upload0", Length: models.MinUploadChunkBytes + 5},
+			chunk:         "ID3au",
+			chunkLength:   5,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "chunk is cut short, InvalidEntity returned and nothing recorded",
+			upload:        models.ResumableUpload{ID: "upload0", Length: 10},
+			chunk:         "ID3au",
+			chunkLength:   10,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "first chunk is not in the upload's format, InvalidEntity returned",
+			upload:        models.ResumableUpload{ID: "upload0", AudioFormat: models.MP3, Length: 5},
+			chunk:         "OggS!",
+			chunkLength:   5,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "upload was already assembled, Conflicted returned",
+			upload:        models.ResumableUpload{ID: "upload0", Length: 5, Offset: 5, Assembled: true},
+			offset:        5,
+			chunk:         "ID3au",
+			chunkLength:   5,
+			expectedError: models.Conflicted,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			c.upload.AudioLocation = "audio-testUUID"
+			c.upload.StorageUploadID = testUploadID
+			mockDb := &MockUploadDb{}
+			mockDb.On("GetAudioUpload", mock.Anything, "session0", "upload0").Return(&c.upload, nil)
+			if c.expectedError == nil {
+				mockDb.On("UpdateAudioUpload", mock.Anything, mock.Anything, c.offset).Return(nil)
+			}
+			mockFileStore := NewMockFileStore()
+			mockFileStore.StartMultipartUpload(testBucket, "audio-testUUID")
+			testManager := NewUploadManager(testBucket, mockFileStore, mockDb, &MockUUIDProvier{}, &MockStoredAudioSubmitter{}, testMaxUploadBytes)
+
+			result, err := testManager.AppendChunk(context.Background(), "session0", "upload0", c.offset, strings.NewReader(c.chunk), c.chunkLength)
+			if c.expectedError != nil {
+				assert.ErrorIs(t, err, c.expectedError)
+				mockDb.AssertNotCalled(t, "UpdateAudioUpload", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedOffset, result.Offset)
+			assert.Equal(t, []models.UploadPart{{Number: 1, ETag: "etag-1"}}, result.Parts)
+			assert.Equal(t, c.chunk, string(mockFileStore.parts[testUploadID][1]))
+			mockDb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFinalizeUpload(t *testing.T) {
+	submitError := errors.New("submit error")
+	firstChunk := "ID3" + strings.Repeat("a", models.MinUploadChunkBytes-3)
+	lastChunk := "the end"
+	audioSHA256 := sha256.Sum256([]byte(firstChunk + lastChunk))
+	cases := []struct {
+		description        string
+		offset             int64
+		assembled          bool
+		transcriptJobID    string
+		claimError         error
+		submitError        error
+		expectedTranscript *models.Transcript
+		expectedError      error
+	}{
+		{
+			description:        "upload finalized, transcript submitted",
+			expectedTranscript: &models.Transcript{JobID: uuidString},
+		},
+		{
+			description:   "transcription can't be submitted, upload stays assembled and is released for a retry",
+			submitError:   submitError,
+			expectedError: submitError,
+		},
+		{
+			description:   "upload is missing bytes, InvalidEntity returned",
+			offset:        models.MinUploadChunkBytes,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:     "upload was already finalized, Conflicted returned",
+			assembled:       true,
+			transcriptJobID: "job0",
+			expectedError:   models.Conflicted,
+		},
+		{
+			description:   "upload was claimed by a concurrent finalize, Conflicted returned without submitting",
+			claimError:    models.Conflicted,
+			expectedError: models.Conflicted,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockFileStore := NewMockFileStore()
+			mockDb := &MockUploadDb{}
+			mockDb.On("AddAudioUpload", mock.Anything, mock.Anything).Return(&models.ResumableUpload{
+				ID:              "upload0",
+				SessionID:       "session0",
+				AudioFormat:     models.MP3,
+				Length:          int64(len(firstChunk) + len(lastChunk)),
+				AudioLocation:   "audio-testUUID",
+				StorageUploadID: testUploadID,
+			}, nil)
+			mockDb.On("UpdateAudioUpload", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockDb.On("ClaimAudioUpload", mock.Anything, "upload0", uuidString).Return(c.claimError)
+			mockDb.On("ReleaseAudioUpload", mock.Anything, "upload0", uuidString).Return(nil)
+			mockSubmitter := &MockStoredAudioSubmitter{}
+			testManager := NewUploadManager(testBucket, mockFileStore, mockDb, &MockUUIDProvier{}, mockSubmitter, testMaxUploadBytes)
+
+			// send the chunks through the manager so the hash state is the one it saves
+			upload, err := testManager.CreateUpload(context.Background(), "session0", models.MP3, int64(len(firstChunk)+len(lastChunk)), models.TranscriptionOptions{})
+			assert.NoError(t, err)
+			for _, chunk := range []string{firstChunk, lastChunk} {
+				getCall := mockDb.On("GetAudioUpload", mock.Anything, "session0", "upload0").Return(upload, nil).Once()
+				upload, err = testManager.AppendChunk(context.Background(), "session0", "upload0", upload.Offset, strings.NewReader(chunk), int64(len(chunk)))
+				assert.NoError(t, err)
+				getCall.Unset()
+			}
+			if c.offset != 0 {
+				upload.Offset = c.offset
+			}
+			upload.Assembled = c.assembled
+			upload.TranscriptJobID = c.transcriptJobID
+			mockDb.On("GetAudioUpload", mock.Anything, "session0", "upload0").Return(upload, nil)
+
+			expectedAudio := models.StoredAudio{
+				Location: "audio-testUUID",
+				Format:   models.MP3,
+				SHA256:   hex.EncodeToString(audioSHA256[:]),
+				Size:     int64(len(firstChunk) + len(lastChunk)),
+			}
+			if c.expectedTranscript != nil || c.submitError != nil {
+				mockSubmitter.On("SubmitStoredTranscriptionJob", mock.Anything, "session0", uuidString, expectedAudio, models.TranscriptionOptions{}).Return(c.expectedTranscript, c.submitError)
+			}
+
+			result, err := testManager.FinalizeUpload(context.Background(), "session0", "upload0")
+			if c.expectedError != nil {
+				assert.ErrorIs(t, err, c.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, c.expectedTranscript, result)
+				mockDb.AssertCalled(t, "ClaimAudioUpload", mock.Anything, "upload0", uuidString)
+			}
+			if c.submitError != nil {
+				mockDb.AssertCalled(t, "ReleaseAudioUpload", mock.Anything, "upload0", uuidString)
+			} else {
+				mockDb.AssertNotCalled(t, "ReleaseAudioUpload", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if c.submitError != nil || c.expectedTranscript != nil {
+				audio, ok := mockFileStore.GetContentFromPath(testBucket, "audio-testUUID")
+				assert.True(t, ok, "audio should be assembled")
+				assert.Equal(t, firstChunk+lastChunk, audio)
+				mockDb.AssertCalled(t, "UpdateAudioUpload", mock.Anything, mock.MatchedBy(func(u models.ResumableUpload) bool {
+					return u.Assembled && u.TranscriptJobID == ""
+				}), upload.Length)
+			}
+			mockSubmitter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateDirectUpload(t *testing.T) {
+	cases := []struct {
+		description    string
+		constraints    models.UploadConstraints
+		expectedRecord *models.DirectUpload
+		expectedError  error
+	}{
+		{
+			description: "upload URL issued",
+			constraints: models.UploadConstraints{ContentType: "audio/mpeg", Length: 100, SHA256: testAudioSHA256},
+			expectedRecord: &models.DirectUpload{
+				ID:            "testUUID",
+				SessionID:     "session0",
+				AudioFormat:   models.MP3,
+				ContentType:   "audio/mpeg",
+				Length:        100,
+				SHA256:        testAudioSHA256,
+				AudioLocation: "audio-testUUID",
+			},
+		},
+		{
+			description:   "length is over the limit, TooLarge returned",
+			constraints:   models.UploadConstraints{ContentType: "audio/mpeg", Length: testMaxUploadBytes + 1},
+			expectedError: models.TooLarge,
+		},
+		{
+			description:   "sha256 is not a SHA-256, InvalidEntity returned",
+			constraints:   models.UploadConstraints{ContentType: "audio/mpeg", Length: 100, SHA256: "abc123"},
+			expectedError: models.InvalidEntity,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockUploadDb{}
+			if c.expectedRecord != nil {
+				record := *c.expectedRecord
+				mockDb.On("AddDirectUpload", mock.Anything, *c.expectedRecord).Return(&record, nil)
+			}
+			testManager := NewUploadManager(testBucket, NewMockFileStore(), mockDb, &MockUUIDProvier{}, &MockStoredAudioSubmitter{}, testMaxUploadBytes)
+
+			result, err := testManager.CreateDirectUpload(context.Background(), "session0", models.MP3, c.constraints, models.TranscriptionOptions{})
+			if c.expectedError != nil {
+				assert.ErrorIs(t, err, c.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "testUUID", result.ID)
+			assert.Equal(t, http.MethodPut, result.UploadURL.Method)
+			assert.Equal(t, "https://files.test/testBucket/audio-testUUID?expires=1h0m0s", result.UploadURL.URL)
+			assert.Equal(t, "audio/mpeg", result.UploadURL.Headers["Content-Type"])
+			mockDb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConfirmDirectUpload(t *testing.T) {
+	submitError := errors.New("submit error")
+	cases := []struct {
+		description        string
+		uploadedContent    string
+		uploadedType       string
+		transcriptJobID    string
+		claimError         error
+		submitError        error
+		expectedTranscript *models.Transcript
+		expectedError      error
+		expectAudioDeleted bool
+	}{
+		{
+			description:        "upload confirmed, transcript submitted",
+			uploadedContent:    testAudio,
+			uploadedType:       "audio/mpeg",
+			expectedTranscript: &models.Transcript{JobID: uuidString},
+		},
+		{
+			description:   "audio was never uploaded, InvalidEntity returned",
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:     "uploaded audio is shorter than declared, InvalidEntity returned",
+			uploadedContent: "ID3t",
+			uploadedType:    "audio/mpeg",
+			expectedError:   models.InvalidEntity,
+		},
+		{
+			description:        "uploaded audio is not in the declared format, InvalidEntity returned and audio deleted",
+			uploadedContent:    "OggSaudio123",
+			uploadedType:       "audio/mpeg",
+			expectedError:      models.InvalidEntity,
+			expectAudioDeleted: true,
+		},
+		{
+			description:     "upload was already confirmed, Conflicted returned",
+			uploadedContent: testAudio,
+			uploadedType:    "audio/mpeg",
+			transcriptJobID: "job0",
+			expectedError:   models.Conflicted,
+		},
+		{
+			description:     "upload was claimed by a concurrent confirm, Conflicted returned without submitting",
+			uploadedContent: testAudio,
+			uploadedType:    "audio/mpeg",
+			claimError:      models.Conflicted,
+			expectedError:   models.Conflicted,
+		},
+		{
+			description:     "transcription can't be submitted, upload released and error returned",
+			uploadedContent: testAudio,
+			uploadedType:    "audio/mpeg",
+			submitError:     submitError,
+			expectedError:   submitError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockFileStore := NewMockFileStore()
+			if c.uploadedType != "" {
+				mockFileStore.files["testBucket/audio-testUUID"] = []byte(c.uploadedContent)
+				mockFileStore.contentTypes["testBucket/audio-testUUID"] = c.uploadedType
+			}
+			mockDb := &MockUploadDb{}
+			mockDb.On("GetDirectUpload", mock.Anything, "session0", "upload0").Return(&models.DirectUpload{
+				ID:              "upload0",
+				SessionID:       "session0",
+				AudioFormat:     models.MP3,
+				ContentType:     "audio/mpeg",
+				Length:          12,
+				SHA256:          testAudioSHA256,
+				AudioLocation:   "audio-testUUID",
+				TranscriptJobID: c.transcriptJobID,
+			}, nil)
+			if c.expectedTranscript != nil || c.claimError != nil || c.submitError != nil {
+				mockDb.On("ClaimDirectUpload", mock.Anything, "upload0", uuidString).Return(c.claimError)
+			}
+			if c.submitError != nil {
+				mockDb.On("ReleaseDirectUpload", mock.Anything, "upload0", uuidString).Return(nil)
+			}
+			mockSubmitter := &MockStoredAudioSubmitter{}
+			if c.expectedTranscript != nil || c.submitError != nil {
+				expectedAudio := models.StoredAudio{
+					Location: "audio-testUUID",
+					Format:   models.MP3,
+					SHA256:   testAudioSHA256,
+					Size:     12,
+				}
+				mockSubmitter.On("SubmitStoredTranscriptionJob", mock.Anything, "session0", uuidString, expectedAudio, models.TranscriptionOptions{}).Return(c.expectedTranscript, c.submitError)
+			}
+			testManager := NewUploadManager(testBucket, mockFileStore, mockDb, &MockUUIDProvier{}, mockSubmitter, testMaxUploadBytes)
+
+			result, err := testManager.ConfirmDirectUpload(context.Background(), "session0", "upload0")
+			if c.expectAudioDeleted {
+				_, ok := mockFileStore.GetContentFromPath(testBucket, "audio-testUUID")
+				assert.False(t, ok, "audio in the wrong format should be deleted")
+			}
+			if c.expectedError != nil {
+				assert.ErrorIs(t, err, c.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, c.expectedTranscript, result)
+			}
+			mockDb.AssertExpectations(t)
+			mockSubmitter.AssertExpectations(t)
+		})
+	}
+}
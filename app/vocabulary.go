@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+const (
+	// maxVocabularyPhraseLength is the longest phrase, in characters, the transcription providers accept
+	maxVocabularyPhraseLength = 256
+	// maxSoundsLikeHints is the number of pronunciations a custom term can have
+	maxSoundsLikeHints = 5
+	// maxCustomVocabularyTerms is the number of custom terms a campaign can have
+	maxCustomVocabularyTerms = 500
+)
+
+// normalizeVocabularyTerm collapses the whitespace of a term's phrase and joins the syllables of its sounds like
+// hints with hyphens. InvalidEntity is returned for a term the transcription providers would reject.
+func normalizeVocabularyTerm(term models.VocabularyTerm) (models.VocabularyTerm, error) {
+	term.Phrase = strings.Join(strings.Fields(term.Phrase), " ")
+	if term.Phrase == "" {
+		return term, fmt.Errorf("missing field: Phrase %w", models.InvalidEntity)
+	}
+	if len([]rune(term.Phrase)) > maxVocabularyPhraseLength {
+		return term, fmt.Errorf("phrase is longer than %d characters: %w", maxVocabularyPhraseLength, models.InvalidEntity)
+	}
+	if !isVocabularyText(term.Phrase, " .") {
+		return term, fmt.Errorf("phrase %q may only hold letters, spaces, apostrophes, hyphens and periods: %w", term.Phrase, models.InvalidEntity)
+	}
+	if len(term.SoundsLike) > maxSoundsLikeHints {
+		return term, fmt.Errorf("a term can have at most %d sounds like hints: %w", maxSoundsLikeHints, models.InvalidEntity)
+	}
+	soundsLike := []string{}
+	for _, hint := range term.SoundsLike {
+		hint = strings.Join(strings.Fields(hint), "-")
+		if hint == "" {
+			continue
+		}
+		if !isVocabularyText(hint, "") {
+			return term, fmt.Errorf("sounds like hint %q may only hold letters, apostrophes and hyphens: %w", hint, models.InvalidEntity)
+		}
+		soundsLike = append(soundsLike, hint)
+	}
+	term.SoundsLike = soundsLike
+	return term, nil
+}
+
+// isVocabularyText reports whether text is made of letters, apostrophes, hyphens and the extra characters only.
+// Digits and symbols have to be spelled out for speech recognition.
+func isVocabularyText(text, extra string) bool {
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.Is(unicode.Mn, r) || r == '\'' || r == '-' || strings.ContainsRune(extra, r) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// buildVocabulary orders the custom terms of a campaign before the names it is seeded with and drops every
+// term whose phrase already appeared, so a custom term can add pronunciations to a seeded name. Seeded names
+// that can't be used as a phrase, like ones with digits, are skipped.
+func buildVocabulary(terms []models.VocabularyTerm) []models.VocabularyTerm {
+	sorted := append([]models.VocabularyTerm{}, terms...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Source < sorted[j].Source
+	})
+	var vocabulary []models.VocabularyTerm
+	seen := map[string]bool{}
+	for _, term := range sorted {
+		normalized, err := normalizeVocabularyTerm(term)
+		if err != nil {
+			continue
+		}
+		phrase := strings.ToLower(normalized.Phrase)
+		if seen[phrase] {
+			continue
+		}
+		seen[phrase] = true
+		vocabulary = append(vocabulary, normalized)
+	}
+	return vocabulary
+}
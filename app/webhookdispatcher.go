@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type webhookDeliverer interface {
+	DeliverWebhooks(ctx context.Context) error
+}
+
+// WebhookDispatcher periodically sends the webhook events that are due to their subscriptions.
+type WebhookDispatcher struct {
+	deliverer webhookDeliverer
+	interval  time.Duration
+}
+
+func NewWebhookDispatcher(deliverer webhookDeliverer, interval time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		deliverer: deliverer,
+		interval:  interval,
+	}
+}
+
+// Run delivers on every interval until the context is cancelled.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.deliverer.DeliverWebhooks(ctx); err != nil {
+				log.Printf("failed to deliver webhooks: %s", err)
+			}
+		}
+	}
+}
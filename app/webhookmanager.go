@@ -0,0 +1,356 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+const (
+	// maxWebhookSubscriptions is the number of webhook subscriptions a campaign can have
+	maxWebhookSubscriptions = 10
+	// minWebhookSecretLength is the shortest secret a subscription can sign its events with
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 256
+	maxWebhookURLLength    = 2048
+	// webhookTimeout bounds how long a subscription can take to accept an event
+	webhookTimeout = 10 * time.Second
+	// webhookLease is how long a claimed delivery is held before it is taken again, it outlasts webhookTimeout
+	webhookLease = time.Minute
+	// webhookBatchSize is the number of deliveries claimed at a time
+	webhookBatchSize = 20
+	// maxWebhookAttempts is the number of times an event is sent before its delivery is given up on
+	maxWebhookAttempts = 8
+	// webhookRetryDelay is how long a failed delivery waits before it is sent again, doubled on every attempt
+	webhookRetryDelay = 30 * time.Second
+	// maxWebhookErrorBytes caps the part of an error response kept in the delivery log
+	maxWebhookErrorBytes = 512
+
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookEventHeader     = "X-Webhook-Event"
+	webhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+type webhookDb interface {
+	GetCampaignForUser(ctx context.Context, userID, campaignID string) (*models.Campaign, error)
+	AddWebhookSubscription(ctx context.Context, campaignID string, subscription models.WebhookSubscription) (*models.WebhookSubscription, error)
+	GetWebhookSubscriptions(ctx context.Context, campaignID string) ([]models.WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, campaignID, subscriptionID string) (*models.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, campaignID, subscriptionID string) error
+	GetWebhookDeliveries(ctx context.Context, campaignID, subscriptionID string, page models.PageRequest) (*models.Page[models.WebhookDelivery], error)
+	GetWebhookDelivery(ctx context.Context, campaignID, subscriptionID, deliveryID string) (*models.WebhookDelivery, error)
+	AddWebhookDelivery(ctx context.Context, subscriptionID string, event models.WebhookEvent) (*models.WebhookDelivery, error)
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+}
+
+// webhookPayload is the JSON body an event is sent to a webhook subscription as
+type webhookPayload struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	OccurredAt    time.Time `json:"occurredAt"`
+	CampaignID    string    `json:"campaignId"`
+	SessionID     string    `json:"sessionId"`
+	TranscriptID  string    `json:"transcriptId"`
+	Status        string    `json:"status"`
+	FailureReason string    `json:"failureReason,omitempty"`
+}
+
+// WebhookManager keeps the webhook subscriptions of campaigns and delivers the transcript events they subscribed
+// to. Events are queued along with the transcript change that raised them and sent with an HMAC-SHA256
+// signature of the body, keyed with the subscription's secret, in the X-Webhook-Signature header. Deliveries
+// that fail are retried with a growing delay. Only the campaign owner can manage its subscriptions, and only
+// https URLs of public hosts can be subscribed.
+type WebhookManager struct {
+	webhookDb webhookDb
+	client    *http.Client
+	lookupIP  func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+func NewWebhookManager(webhookDb webhookDb) *WebhookManager {
+	return &WebhookManager{
+		webhookDb: webhookDb,
+		client:    newWebhookClient(),
+		lookupIP:  net.DefaultResolver.LookupIPAddr,
+	}
+}
+
+// newWebhookClient returns the client events are sent with. Subscriptions are URLs users pick, so the client only
+// connects to public addresses, checked as it dials so a host that resolves elsewhere after it was subscribed
+// is caught too, and doesn't follow redirects or use a proxy.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: checkWebhookDial,
+	}
+	return &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkWebhookDial refuses connections to addresses that aren't public
+func checkWebhookDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
+
+// publicIP reports whether ip can be reached from outside the service's network, loopback, private, link
+// local, multicast and unspecified addresses can't
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
+
+// authorizeOwner returns Forbidden unless the caller owns the campaign
+func (w *WebhookManager) authorizeOwner(ctx context.Context, campaignID string) error {
+	identity, ok := models.IdentityFromContext(ctx)
+	if !ok {
+		return models.Unauthorized
+	}
+	campaign, err := w.webhookDb.GetCampaignForUser(ctx, identity.UserID, campaignID)
+	if err != nil {
+		return err
+	}
+	if campaign.Role != models.CampaignOwner {
+		return fmt.Errorf("only the campaign owner can manage webhooks: %w", models.Forbidden)
+	}
+	return nil
+}
+
+// AddSubscription subscribes an https URL to events of a campaign. InvalidEntity is returned for a subscription
+// that can't be delivered to, including URLs of hosts that aren't public, and Conflicted when the campaign has
+// maxWebhookSubscriptions already.
+func (w *WebhookManager) AddSubscription(ctx context.Context, campaignID string, subscription models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	if err := w.authorizeOwner(ctx, campaignID); err != nil {
+		return nil, err
+	}
+	subscription, err := checkWebhookSubscription(subscription)
+	if err != nil {
+		return nil, err
+	}
+	if err = w.checkWebhookHost(ctx, subscription.URL); err != nil {
+		return nil, err
+	}
+	subscriptions, err := w.webhookDb.GetWebhookSubscriptions(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if len(subscriptions) >= maxWebhookSubscriptions {
+		return nil, fmt.Errorf("campaign already has %d webhook subscriptions: %w", len(subscriptions), models.Conflicted)
+	}
+	return w.webhookDb.AddWebhookSubscription(ctx, campaignID, subscription)
+}
+
+// checkWebhookSubscription drops repeated event types and returns InvalidEntity for a subscription without a
+// usable URL, a long enough secret or any event type
+func checkWebhookSubscription(subscription models.WebhookSubscription) (models.WebhookSubscription, error) {
+	parsed, err := url.Parse(subscription.URL)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return subscription, fmt.Errorf("url %q must be an absolute https URL: %w", subscription.URL, models.InvalidEntity)
+	}
+	if len(subscription.URL) > maxWebhookURLLength {
+		return subscription, fmt.Errorf("url is longer than %d characters: %w", maxWebhookURLLength, models.InvalidEntity)
+	}
+	if len(subscription.Secret) < minWebhookSecretLength || len(subscription.Secret) > maxWebhookSecretLength {
+		return subscription, fmt.Errorf("secret must be between %d and %d characters: %w", minWebhookSecretLength, maxWebhookSecretLength, models.InvalidEntity)
+	}
+	eventTypes := []models.WebhookEventType{}
+	seen := map[models.WebhookEventType]bool{}
+	for _, eventType := range subscription.EventTypes {
+		if !seen[eventType] {
+			seen[eventType] = true
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	if len(eventTypes) == 0 {
+		return subscription, fmt.Errorf("missing field: EventTypes %w", models.InvalidEntity)
+	}
+	subscription.EventTypes = eventTypes
+	return subscription, nil
+}
+
+// checkWebhookHost returns InvalidEntity unless the host of rawURL resolves to public addresses only
+func (w *WebhookManager) checkWebhookHost(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("url %q: %w", rawURL, models.InvalidEntity)
+	}
+	host := parsed.Hostname()
+	addrs := []net.IPAddr{{IP: net.ParseIP(host)}}
+	if addrs[0].IP == nil {
+		if addrs, err = w.lookupIP(ctx, host); err != nil || len(addrs) == 0 {
+			return fmt.Errorf("host %s of url can't be resolved: %w", host, models.InvalidEntity)
+		}
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return fmt.Errorf("host %s of url resolves to %s which isn't public: %w", host, addr.IP, models.InvalidEntity)
+		}
+	}
+	return nil
+}
+
+func (w *WebhookManager) GetSubscriptions(ctx context.Context, campaignID string) ([]models.WebhookSubscription, error) {
+	if err := w.authorizeOwner(ctx, campaignID); err != nil {
+		return nil, err
+	}
+	return w.webhookDb.GetWebhookSubscriptions(ctx, campaignID)
+}
+
+func (w *WebhookManager) GetSubscription(ctx context.Context, campaignID, subscriptionID string) (*models.WebhookSubscription, error) {
+	if err := w.authorizeOwner(ctx, campaignID); err != nil {
+		return nil, err
+	}
+	return w.webhookDb.GetWebhookSubscription(ctx, campaignID, subscriptionID)
+}
+
+// DeleteSubscription removes a subscription, events that weren't delivered to it yet are dropped
+func (w *WebhookManager) DeleteSubscription(ctx context.Context, campaignID, subscriptionID string) error {
+	if err := w.authorizeOwner(ctx, campaignID); err != nil {
+		return err
+	}
+	return w.webhookDb.DeleteWebhookSubscription(ctx, campaignID, subscriptionID)
+}
+
+// GetDeliveries returns a page of the delivery log of a subscription, newest first unless another order is asked
+func (w *WebhookManager) GetDeliveries(ctx context.Context, campaignID, subscriptionID string, page models.PageRequest) (*models.Page[models.WebhookDelivery], error) {
+	if err := w.authorizeOwner(ctx, campaignID); err != nil {
+		return nil, err
+	}
+	page, err := checkPageRequest(page)
+	if err != nil {
+		return nil, err
+	}
+	if page.Sort.Field == "" {
+		page.Sort = models.Sort{Field: "created", Descending: true}
+	}
+	if _, err = w.webhookDb.GetWebhookSubscription(ctx, campaignID, subscriptionID); err != nil {
+		return nil, err
+	}
+	return w.webhookDb.GetWebhookDeliveries(ctx, campaignID, subscriptionID, page)
+}
+
+// Redeliver sends the event of a delivery to its subscription again as a new delivery, whatever became of the
+// original one
+func (w *WebhookManager) Redeliver(ctx context.Context, campaignID, subscriptionID, deliveryID string) (*models.WebhookDelivery, error) {
+	if err := w.authorizeOwner(ctx, campaignID); err != nil {
+		return nil, err
+	}
+	delivery, err := w.webhookDb.GetWebhookDelivery(ctx, campaignID, subscriptionID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	return w.webhookDb.AddWebhookDelivery(ctx, subscriptionID, delivery.Event)
+}
+
+// DeliverWebhooks sends the events whose deliveries are due. Deliveries that fail are logged and retried.
+func (w *WebhookManager) DeliverWebhooks(ctx context.Context) error {
+	now := time.Now()
+	deliveries, err := w.webhookDb.ClaimWebhookDeliveries(ctx, now, now.Add(webhookLease), webhookBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		if err := w.deliver(ctx, delivery); err != nil {
+			log.Printf("failed to deliver webhook %s: %s", delivery.ID, err)
+		}
+	}
+	return nil
+}
+
+func (w *WebhookManager) deliver(ctx context.Context, delivery models.WebhookDelivery) error {
+	subscription, err := w.webhookDb.GetWebhookSubscription(ctx, delivery.Event.CampaignID, delivery.SubscriptionID)
+	if err != nil {
+		return err
+	}
+	responseStatus, sendErr := w.send(ctx, *subscription, delivery)
+	delivery.ResponseStatus = responseStatus
+	if sendErr == nil {
+		deliveredAt := time.Now()
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &deliveredAt
+		delivery.LastError = ""
+		return w.webhookDb.UpdateWebhookDelivery(ctx, delivery)
+	}
+
+	log.Printf("delivery %s of webhook event %s failed on attempt %d: %s", delivery.ID, delivery.Event.ID, delivery.Attempts, sendErr)
+	delivery.LastError = sendErr.Error()
+	if delivery.Attempts >= maxWebhookAttempts {
+		delivery.Status = models.DeliveryFailed
+	} else {
+		delivery.NextAttemptAt = time.Now().Add(webhookRetryDelay << (delivery.Attempts - 1))
+	}
+	return w.webhookDb.UpdateWebhookDelivery(ctx, delivery)
+}
+
+// send posts the signed event of a delivery to its subscription and returns the status the subscription
+// answered with, any status outside 2xx is an error
+func (w *WebhookManager) send(ctx context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery) (int, error) {
+	event := delivery.Event
+	body, err := json.Marshal(webhookPayload{
+		ID:            event.ID,
+		Type:          event.Type.String(),
+		OccurredAt:    event.OccurredAt,
+		CampaignID:    event.CampaignID,
+		SessionID:     event.SessionID,
+		TranscriptID:  event.JobID,
+		Status:        event.Status.String(),
+		FailureReason: event.FailureReason,
+	})
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, event.Type.String())
+	req.Header.Set(webhookDeliveryHeader, delivery.ID)
+	req.Header.Set(webhookSignatureHeader, signWebhookPayload(subscription.Secret, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorBytes))
+		return resp.StatusCode, fmt.Errorf("subscription answered %s: %s", resp.Status, message)
+	}
+	return resp.StatusCode, nil
+}
+
+// signWebhookPayload returns the X-Webhook-Signature of body, "sha256=" followed by the hex HMAC-SHA256 of body
+// keyed with the secret
+func signWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
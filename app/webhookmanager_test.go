@@ -0,0 +1,457 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookDb struct {
+	mock.Mock
+}
+
+func (m *MockWebhookDb) GetCampaignForUser(ctx context.Context, userID, campaignID string) (*models.Campaign, error) {
+	args := m.Called(ctx, userID, campaignID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Campaign), nil
+}
+
+func (m *MockWebhookDb) AddWebhookSubscription(ctx context.Context, campaignID string, subscription models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, campaignID, subscription)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), nil
+}
+
+func (m *MockWebhookDb) GetWebhookSubscriptions(ctx context.Context, campaignID string) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx, campaignID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookSubscription), nil
+}
+
+func (m *MockWebhookDb) GetWebhookSubscription(ctx context.Context, campaignID, subscriptionID string) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, campaignID, subscriptionID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), nil
+}
+
+func (m *MockWebhookDb) DeleteWebhookSubscription(ctx context.Context, campaignID, subscriptionID string) error {
+	args := m.Called(ctx, campaignID, subscriptionID)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDb) GetWebhookDeliveries(ctx context.Context, campaignID, subscriptionID string, page models.PageRequest) (*models.Page[models.WebhookDelivery], error) {
+	args := m.Called(ctx, campaignID, subscriptionID, page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.WebhookDelivery]), nil
+}
+
+func (m *MockWebhookDb) GetWebhookDelivery(ctx context.Context, campaignID, subscriptionID, deliveryID string) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, campaignID, subscriptionID, deliveryID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), nil
+}
+
+func (m *MockWebhookDb) AddWebhookDelivery(ctx context.Context, subscriptionID string, event models.WebhookEvent) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, event)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), nil
+}
+
+func (m *MockWebhookDb) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, now, leaseUntil, limit)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookDelivery), nil
+}
+
+func (m *MockWebhookDb) UpdateWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func TestAddWebhookSubscription(t *testing.T) {
+	valid := models.WebhookSubscription{
+		URL:        "https://bot.example.com/dragonspeak",
+		Secret:     "0123456789abcdef",
+		EventTypes: []models.WebhookEventType{models.SummaryDoneEvent, models.TranscriptFailedEvent},
+	}
+	withURL := func(url string) models.WebhookSubscription {
+		subscription := valid
+		subscription.URL = url
+		return subscription
+	}
+	withSecret := func(secret string) models.WebhookSubscription {
+		subscription := valid
+		subscription.Secret = secret
+		return subscription
+	}
+	withEventTypes := func(eventTypes ...models.WebhookEventType) models.WebhookSubscription {
+		subscription := valid
+		subscription.EventTypes = eventTypes
+		return subscription
+	}
+
+	cases := []struct {
+		description          string
+		subscription         models.WebhookSubscription
+		callerRole           models.CampaignRole
+		existing             int
+		expectedSubscription *models.WebhookSubscription
+		expectedError        error
+	}{
+		{
+			description:          "owner subscribes a URL",
+			subscription:         valid,
+			callerRole:           models.CampaignOwner,
+			expectedSubscription: &valid,
+		},
+		{
+			description:          "repeated event types are dropped",
+			subscription:         withEventTypes(models.SummaryDoneEvent, models.TranscriptFailedEvent, models.SummaryDoneEvent),
+			callerRole:           models.CampaignOwner,
+			expectedSubscription: &valid,
+		},
+		{
+			description:   "player subscribes, Forbidden returned",
+			subscription:  valid,
+			callerRole:    models.CampaignPlayer,
+			expectedError: models.Forbidden,
+		},
+		{
+			description:   "URL isn't http, invalid",
+			subscription:  withURL("ftp://bot.example.com/dragonspeak"),
+			callerRole:    models.CampaignOwner,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "URL is plain http, invalid",
+			subscription:  withURL("http://bot.example.com/dragonspeak"),
+			callerRole:    models.CampaignOwner,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "URL is a loopback address, invalid",
+			subscription:  withURL("https://127.0.0.1:8080/dragonspeak"),
+			callerRole:    models.CampaignOwner,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "URL is the cloud metadata address, invalid",
+			subscription:  withURL("https://169.254.169.254/latest/meta-data"),
+			callerRole:    models.CampaignOwner,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "host resolves to a private address, invalid",
+			subscription:  withURL("https://internal.example.com/dragonspeak"),
+			callerRole:    models.CampaignOwner,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "host can't be resolved, invalid",
+			subscription:  withURL("https://missing.example.com/dragonspeak"),
+			callerRole:    models.CampaignOwner,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "URL is relative, invalid",
+			subscription:  withURL("/dragonspeak"),
+			callerRole:    models.CampaignOwner,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "secret is too short, invalid",
+			subscription:  withSecret("hunter2"),
+			callerRole:    models.CampaignOwner,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "no event types, invalid",
+			subscription:  withEventTypes(),
+			callerRole:    models.CampaignOwner,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "campaign has as many subscriptions as it can, Conflicted returned",
+			subscription:  valid,
+			callerRole:    models.CampaignOwner,
+			existing:      maxWebhookSubscriptions,
+			expectedError: models.Conflicted,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockWebhookDb{}
+			mockDb.On("GetCampaignForUser", mock.Anything, "owner-1", "cmp123").Return(&models.Campaign{ID: "cmp123", Role: c.callerRole}, nil)
+			mockDb.On("GetWebhookSubscriptions", mock.Anything, "cmp123").Return(make([]models.WebhookSubscription, c.existing), nil)
+			if c.expectedSubscription != nil {
+				created := *c.expectedSubscription
+				created.ID = "hook-1"
+				mockDb.On("AddWebhookSubscription", mock.Anything, "cmp123", *c.expectedSubscription).Return(&created, nil)
+			}
+			testManager := NewWebhookManager(mockDb)
+			testManager.lookupIP = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+				switch host {
+				case "bot.example.com":
+					return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+				case "internal.example.com":
+					return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("10.0.0.7")}}, nil
+				}
+				return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+			}
+
+			ctx := models.ContextWithIdentity(context.Background(), models.Identity{UserID: "owner-1"})
+			result, err := testManager.AddSubscription(ctx, "cmp123", c.subscription)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				mockDb.AssertNotCalled(t, "AddWebhookSubscription", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error returned: %s", err)
+			}
+			assert.Equal(t, "hook-1", result.ID)
+		})
+	}
+}
+
+func TestDeliverWebhooks(t *testing.T) {
+	occurredAt := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	event := models.WebhookEvent{
+		ID:         "event-1",
+		Type:       models.SummaryDoneEvent,
+		CampaignID: "cmp123",
+		SessionID:  "ses123",
+		JobID:      "job123",
+		Status:     models.Done,
+		OccurredAt: occurredAt,
+	}
+	claimError := errors.New("db error")
+
+	cases := []struct {
+		description        string
+		attempts           int
+		responseStatus     int
+		unreachable        bool
+		privateAddress     bool
+		claimError         error
+		expectedStatus     models.WebhookDeliveryStatus
+		expectedRetryDelay time.Duration
+		expectedError      error
+	}{
+		{
+			description:    "subscription accepts the event, delivery is delivered",
+			attempts:       1,
+			responseStatus: http.StatusNoContent,
+			expectedStatus: models.DeliveryDelivered,
+		},
+		{
+			description:        "subscription fails, delivery is retried after a delay that doubles with each attempt",
+			attempts:           3,
+			responseStatus:     http.StatusInternalServerError,
+			expectedStatus:     models.DeliveryPending,
+			expectedRetryDelay: 4 * webhookRetryDelay,
+		},
+		{
+			description:        "subscription is unreachable, delivery is retried",
+			attempts:           1,
+			unreachable:        true,
+			expectedStatus:     models.DeliveryPending,
+			expectedRetryDelay: webhookRetryDelay,
+		},
+		{
+			description:        "subscription resolves to a private address, delivery is retried without connecting",
+			attempts:           1,
+			responseStatus:     http.StatusNoContent,
+			privateAddress:     true,
+			expectedStatus:     models.DeliveryPending,
+			expectedRetryDelay: webhookRetryDelay,
+		},
+		{
+			description:    "subscription fails on the last attempt, delivery is given up on",
+			attempts:       maxWebhookAttempts,
+			responseStatus: http.StatusGone,
+			expectedStatus: models.DeliveryFailed,
+		},
+		{
+			description:   "claiming deliveries fails, error returned",
+			claimError:    claimError,
+			expectedError: claimError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			var received *http.Request
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(c.responseStatus)
+			}))
+			defer server.Close()
+			subscription := models.WebhookSubscription{ID: "hook-1", URL: server.URL, Secret: "0123456789abcdef"}
+			if c.unreachable {
+				server.Close()
+			}
+			delivery := models.WebhookDelivery{ID: "delivery-1", SubscriptionID: "hook-1", Event: event, Attempts: c.attempts}
+
+			mockDb := &MockWebhookDb{}
+			if c.claimError != nil {
+				mockDb.On("ClaimWebhookDeliveries", mock.Anything, mock.Anything, mock.Anything, webhookBatchSize).Return(nil, c.claimError)
+			} else {
+				mockDb.On("ClaimWebhookDeliveries", mock.Anything, mock.Anything, mock.Anything, webhookBatchSize).Return([]models.WebhookDelivery{delivery}, nil)
+			}
+			mockDb.On("GetWebhookSubscription", mock.Anything, "cmp123", "hook-1").Return(&subscription, nil)
+			var updated models.WebhookDelivery
+			mockDb.On("UpdateWebhookDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				updated = args.Get(1).(models.WebhookDelivery)
+			}).Return(nil)
+			testManager := NewWebhookManager(mockDb)
+			if !c.privateAddress {
+				// the test server listens on loopback, which the webhook client refuses to connect to
+				testManager.client = server.Client()
+			}
+
+			err := testManager.DeliverWebhooks(context.Background())
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error returned: %s", err)
+			}
+			assert.Equal(t, c.expectedStatus, updated.Status)
+			if c.expectedStatus == models.DeliveryDelivered {
+				assert.NotNil(t, updated.DeliveredAt)
+				assert.Empty(t, updated.LastError)
+			} else {
+				assert.NotEmpty(t, updated.LastError)
+			}
+			if c.expectedRetryDelay != 0 {
+				delay := time.Until(updated.NextAttemptAt)
+				assert.True(t, delay > c.expectedRetryDelay-time.Minute && delay <= c.expectedRetryDelay, "expected a retry in %s got %s", c.expectedRetryDelay, delay)
+			}
+			if c.unreachable || c.privateAddress {
+				assert.Equal(t, 0, updated.ResponseStatus)
+				assert.Nil(t, received, "subscription should not be reached")
+				return
+			}
+			assert.Equal(t, c.responseStatus, updated.ResponseStatus)
+			assert.Equal(t, "summary.done", received.Header.Get("X-Webhook-Event"))
+			assert.Equal(t, "delivery-1", received.Header.Get("X-Webhook-Delivery"))
+			assert.Equal(t, signWebhookPayload("0123456789abcdef", body), received.Header.Get("X-Webhook-Signature"))
+			var payload map[string]any
+			assert.NoError(t, json.Unmarshal(body, &payload))
+			assert.Equal(t, map[string]any{
+				"id":           "event-1",
+				"type":         "summary.done",
+				"occurredAt":   "2024-03-01T18:00:00Z",
+				"campaignId":   "cmp123",
+				"sessionId":    "ses123",
+				"transcriptId": "job123",
+				"status":       "Done",
+			}, payload)
+		})
+	}
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	event := models.WebhookEvent{ID: "event-1", Type: models.TranscriptDoneEvent, CampaignID: "cmp123", JobID: "job123"}
+	cases := []struct {
+		description   string
+		deliveryError error
+		expectedError error
+	}{
+		{
+			description: "event is queued again as a new delivery",
+		},
+		{
+			description:   "delivery not found",
+			deliveryError: models.EntityNotFound,
+			expectedError: models.EntityNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mockDb := &MockWebhookDb{}
+			mockDb.On("GetCampaignForUser", mock.Anything, "owner-1", "cmp123").Return(&models.Campaign{ID: "cmp123", Role: models.CampaignOwner}, nil)
+			mockDb.On("GetWebhookDelivery", mock.Anything, "cmp123", "hook-1", "delivery-1").Return(&models.WebhookDelivery{ID: "delivery-1", Event: event, Status: models.DeliveryFailed, Attempts: maxWebhookAttempts}, c.deliveryError)
+			mockDb.On("AddWebhookDelivery", mock.Anything, "hook-1", event).Return(&models.WebhookDelivery{ID: "delivery-2", Event: event, Status: models.DeliveryPending}, nil)
+			testManager := NewWebhookManager(mockDb)
+
+			ctx := models.ContextWithIdentity(context.Background(), models.Identity{UserID: "owner-1"})
+			result, err := testManager.Redeliver(ctx, "cmp123", "hook-1", "delivery-1")
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				mockDb.AssertNotCalled(t, "AddWebhookDelivery", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error returned: %s", err)
+			}
+			assert.Equal(t, "delivery-2", result.ID)
+			assert.Equal(t, models.WebhookDeliveryStatus(models.DeliveryPending), result.Status)
+		})
+	}
+}
+
+func TestCheckWebhookDial(t *testing.T) {
+	cases := []struct {
+		address  string
+		expected bool
+	}{
+		{address: "93.184.216.34:443", expected: true},
+		{address: "[2606:2800:220:1:248:1893:25c8:1946]:443", expected: true},
+		{address: "127.0.0.1:443"},
+		{address: "[::1]:443"},
+		{address: "10.1.2.3:443"},
+		{address: "172.16.0.1:443"},
+		{address: "192.168.1.1:443"},
+		{address: "[fd00::1]:443"},
+		{address: "169.254.169.254:80"},
+		{address: "[fe80::1]:443"},
+		{address: "0.0.0.0:443"},
+		{address: "[::ffff:127.0.0.1]:443"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.address, func(t *testing.T) {
+			err := checkWebhookDial("tcp", c.address, nil)
+			assert.Equal(t, c.expected, err == nil, "dialing %s returned %v", c.address, err)
+		})
+	}
+}
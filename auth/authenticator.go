@@ -0,0 +1,12 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// Authenticator resolves a bearer token to the identity of the caller. Failures wrap models.Unauthorized.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (models.Identity, error)
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// minJWKSRefreshInterval limits how often an unknown kid can trigger a refetch of the key set
+const minJWKSRefreshInterval = time.Minute
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// jwksKeySet caches the RSA signing keys published at a JWKS endpoint and refetches them when a token
+// references a kid it doesn't know, so that key rotation is picked up.
+type jwksKeySet struct {
+	url         string
+	client      *http.Client
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	lastFetched time.Time
+}
+
+func newJWKSKeySet(url string) *jwksKeySet {
+	return &jwksKeySet{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		keys:   map[string]*rsa.PublicKey{},
+	}
+}
+
+func (k *jwksKeySet) Key(ctx context.Context, alg, kid string) (interface{}, error) {
+	if !strings.HasPrefix(alg, "RS") {
+		return nil, fmt.Errorf("unsupported token algorithm %q: %w", alg, models.Unauthorized)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if key, ok := k.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(k.lastFetched) < minJWKSRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q: %w", kid, models.Unauthorized)
+	}
+	if err := k.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := k.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q: %w", kid, models.Unauthorized)
+}
+
+func (k *jwksKeySet) refresh(ctx context.Context) error {
+	k.lastFetched = time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	keySet := jsonWebKeySet{}
+	if err = json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range keySet.Keys {
+		if jwk.KeyType != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		publicKey, err := rsaPublicKey(jwk)
+		if err != nil {
+			return err
+		}
+		keys[jwk.KeyID] = publicKey
+	}
+	k.keys = keys
+	return nil
+}
+
+func rsaPublicKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus for key %s: %w", jwk.KeyID, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent for key %s: %w", jwk.KeyID, err)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
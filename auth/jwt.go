@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// clockSkew is how far a token's exp and nbf claims may be off from our clock
+const clockSkew = time.Minute
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *int64   `json:"exp"`
+	NotBefore *int64   `json:"nbf"`
+}
+
+// audience accepts both the string and the array form of the aud claim
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+func (a audience) contains(value string) bool {
+	for _, aud := range a {
+		if aud == value {
+			return true
+		}
+	}
+	return false
+}
+
+type keyProvider interface {
+	// Key returns the key used to verify a token signed with alg and kid
+	Key(ctx context.Context, alg, kid string) (interface{}, error)
+}
+
+// JWTAuthenticator verifies bearer JWTs and uses the sub claim as the dragonspeak user ID.
+type JWTAuthenticator struct {
+	keys     keyProvider
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// NewHMACAuthenticator verifies HS256/384/512 tokens signed with a shared secret. An empty issuer or audience
+// is not checked.
+func NewHMACAuthenticator(secret []byte, issuer, audience string) *JWTAuthenticator {
+	return &JWTAuthenticator{
+		keys:     hmacKey(secret),
+		issuer:   issuer,
+		audience: audience,
+		now:      time.Now,
+	}
+}
+
+// NewJWKSAuthenticator verifies RS256/384/512 tokens against the keys published at jwksURL.
+func NewJWKSAuthenticator(jwksURL, issuer, audience string) *JWTAuthenticator {
+	return &JWTAuthenticator{
+		keys:     newJWKSKeySet(jwksURL),
+		issuer:   issuer,
+		audience: audience,
+		now:      time.Now,
+	}
+}
+
+func (j *JWTAuthenticator) Authenticate(ctx context.Context, token string) (models.Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return models.Identity{}, fmt.Errorf("malformed token: %w", models.Unauthorized)
+	}
+
+	header := jwtHeader{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return models.Identity{}, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return models.Identity{}, fmt.Errorf("malformed token signature: %w", models.Unauthorized)
+	}
+	key, err := j.keys.Key(ctx, header.Algorithm, header.KeyID)
+	if err != nil {
+		return models.Identity{}, err
+	}
+	if err = verifySignature(header.Algorithm, key, parts[0]+"."+parts[1], signature); err != nil {
+		return models.Identity{}, err
+	}
+
+	claims := jwtClaims{}
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return models.Identity{}, err
+	}
+	if err = j.validateClaims(claims); err != nil {
+		return models.Identity{}, err
+	}
+	return models.Identity{UserID: claims.Subject}, nil
+}
+
+func (j *JWTAuthenticator) validateClaims(claims jwtClaims) error {
+	now := j.now()
+	if claims.Subject == "" {
+		return fmt.Errorf("token has no subject: %w", models.Unauthorized)
+	}
+	if claims.ExpiresAt == nil || now.After(time.Unix(*claims.ExpiresAt, 0).Add(clockSkew)) {
+		return fmt.Errorf("token is expired: %w", models.Unauthorized)
+	}
+	if claims.NotBefore != nil && now.Add(clockSkew).Before(time.Unix(*claims.NotBefore, 0)) {
+		return fmt.Errorf("token is not valid yet: %w", models.Unauthorized)
+	}
+	if j.issuer != "" && claims.Issuer != j.issuer {
+		return fmt.Errorf("unexpected token issuer %s: %w", claims.Issuer, models.Unauthorized)
+	}
+	if j.audience != "" && !claims.Audience.contains(j.audience) {
+		return fmt.Errorf("token is not meant for this audience: %w", models.Unauthorized)
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("malformed token segment: %w", models.Unauthorized)
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("malformed token segment: %w", models.Unauthorized)
+	}
+	return nil
+}
+
+func verifySignature(alg string, key interface{}, signingInput string, signature []byte) error {
+	switch alg {
+	case "HS256", "HS384", "HS512":
+		secret, ok := key.([]byte)
+		if !ok {
+			return fmt.Errorf("no HMAC key for %s: %w", alg, models.Unauthorized)
+		}
+		mac := hmac.New(hmacHash(alg), secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("invalid token signature: %w", models.Unauthorized)
+		}
+		return nil
+	case "RS256", "RS384", "RS512":
+		publicKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("no RSA key for %s: %w", alg, models.Unauthorized)
+		}
+		hashType := rsaHash(alg)
+		digest := hashType.New()
+		digest.Write([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(publicKey, hashType, digest.Sum(nil), signature); err != nil {
+			return fmt.Errorf("invalid token signature: %w", models.Unauthorized)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported token algorithm %q: %w", alg, models.Unauthorized)
+	}
+}
+
+func hmacHash(alg string) func() hash.Hash {
+	switch alg {
+	case "HS384":
+		return sha512.New384
+	case "HS512":
+		return sha512.New
+	default:
+		return sha256.New
+	}
+}
+
+func rsaHash(alg string) crypto.Hash {
+	switch alg {
+	case "RS384":
+		return crypto.SHA384
+	case "RS512":
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+type hmacKey []byte
+
+func (h hmacKey) Key(ctx context.Context, alg, kid string) (interface{}, error) {
+	if !strings.HasPrefix(alg, "HS") {
+		return nil, fmt.Errorf("unsupported token algorithm %q: %w", alg, models.Unauthorized)
+	}
+	return []byte(h), nil
+}
@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling token segment: %s", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func hmacToken(t *testing.T, alg string, secret []byte, claims map[string]interface{}) string {
+	signingInput := encodeSegment(t, map[string]string{"alg": alg, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestHMACAuthenticate(t *testing.T) {
+	secret := []byte("test-secret")
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"sub": "user-1",
+			"iss": "https://issuer.test",
+			"aud": []string{"dragonspeak"},
+			"exp": testNow.Add(time.Hour).Unix(),
+		}
+	}
+	cases := []struct {
+		description      string
+		token            func() string
+		expectedIdentity models.Identity
+		expectedError    error
+	}{
+		{
+			description:      "valid token, subject returned as the user",
+			token:            func() string { return hmacToken(t, "HS256", secret, validClaims()) },
+			expectedIdentity: models.Identity{UserID: "user-1"},
+		},
+		{
+			description: "audience given as a string, token accepted",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "dragonspeak"
+				return hmacToken(t, "HS256", secret, claims)
+			},
+			expectedIdentity: models.Identity{UserID: "user-1"},
+		},
+		{
+			description:   "signed with another secret, Unauthorized returned",
+			token:         func() string { return hmacToken(t, "HS256", []byte("other"), validClaims()) },
+			expectedError: models.Unauthorized,
+		},
+		{
+			description: "expired token, Unauthorized returned",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = testNow.Add(-time.Hour).Unix()
+				return hmacToken(t, "HS256", secret, claims)
+			},
+			expectedError: models.Unauthorized,
+		},
+		{
+			description: "token without expiry, Unauthorized returned",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "exp")
+				return hmacToken(t, "HS256", secret, claims)
+			},
+			expectedError: models.Unauthorized,
+		},
+		{
+			description: "wrong issuer, Unauthorized returned",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://evil.test"
+				return hmacToken(t, "HS256", secret, claims)
+			},
+			expectedError: models.Unauthorized,
+		},
+		{
+			description: "wrong audience, Unauthorized returned",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "another-service"
+				return hmacToken(t, "HS256", secret, claims)
+			},
+			expectedError: models.Unauthorized,
+		},
+		{
+			description: "unsigned token, Unauthorized returned",
+			token: func() string {
+				return encodeSegment(t, map[string]string{"alg": "none"}) + "." + encodeSegment(t, validClaims()) + "."
+			},
+			expectedError: models.Unauthorized,
+		},
+		{
+			description:   "not a jwt, Unauthorized returned",
+			token:         func() string { return "not-a-token" },
+			expectedError: models.Unauthorized,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			authenticator := NewHMACAuthenticator(secret, "https://issuer.test", "dragonspeak")
+			authenticator.now = func() time.Time { return testNow }
+
+			identity, err := authenticator.Authenticate(context.Background(), c.token())
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error returned: %s", err)
+			}
+			assert.Equal(t, c.expectedIdentity, identity)
+		})
+	}
+}
+
+func TestJWKSAuthenticate(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error generating key: %s", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jsonWebKeySet{
+			Keys: []jsonWebKey{{
+				KeyType: "RSA",
+				KeyID:   "key-1",
+				Use:     "sig",
+				N:       base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+				E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+			}},
+		})
+	}))
+	defer server.Close()
+
+	rsaToken := func(kid string) string {
+		signingInput := encodeSegment(t, map[string]string{"alg": "RS256", "kid": kid}) + "." +
+			encodeSegment(t, map[string]interface{}{"sub": "user-1", "exp": testNow.Add(time.Hour).Unix()})
+		digest := sha256.Sum256([]byte(signingInput))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("unexpected error signing token: %s", err)
+		}
+		return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	cases := []struct {
+		description   string
+		token         string
+		expectedError error
+	}{
+		{
+			description: "token signed with a published key, accepted",
+			token:       rsaToken("key-1"),
+		},
+		{
+			description:   "token signed with an unknown key id, Unauthorized returned",
+			token:         rsaToken("key-2"),
+			expectedError: models.Unauthorized,
+		},
+		{
+			description:   "HMAC token presented to a JWKS authenticator, Unauthorized returned",
+			token:         hmacToken(t, "HS256", []byte("secret"), map[string]interface{}{"sub": "user-1", "exp": testNow.Add(time.Hour).Unix()}),
+			expectedError: models.Unauthorized,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			authenticator := NewJWKSAuthenticator(server.URL, "", "")
+			authenticator.now = func() time.Time { return testNow }
+
+			identity, err := authenticator.Authenticate(context.Background(), c.token)
+			if c.expectedError != nil {
+				if !errors.Is(err, c.expectedError) {
+					t.Errorf("expected error: %s got %v", c.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error returned: %s", err)
+			}
+			assert.Equal(t, models.Identity{UserID: "user-1"}, identity)
+		})
+	}
+}
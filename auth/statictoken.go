@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// StaticTokenAuthenticator maps fixed bearer tokens to user IDs. It is meant for local development only.
+type StaticTokenAuthenticator struct {
+	tokens map[string]string
+}
+
+func NewStaticTokenAuthenticator(tokens map[string]string) *StaticTokenAuthenticator {
+	return &StaticTokenAuthenticator{
+		tokens: tokens,
+	}
+}
+
+// ParseStaticTokens parses a comma separated list of token:userID pairs
+func ParseStaticTokens(value string) (map[string]string, error) {
+	tokens := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		token, userID, found := strings.Cut(pair, ":")
+		if !found || token == "" || userID == "" {
+			return nil, fmt.Errorf("static token entries must look like token:userId, got %q", pair)
+		}
+		tokens[token] = userID
+	}
+	return tokens, nil
+}
+
+func (s *StaticTokenAuthenticator) Authenticate(ctx context.Context, token string) (models.Identity, error) {
+	for knownToken, userID := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(knownToken), []byte(token)) == 1 {
+			return models.Identity{UserID: userID}, nil
+		}
+	}
+	return models.Identity{}, fmt.Errorf("unknown token: %w", models.Unauthorized)
+}
@@ -3,11 +3,14 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/EdgarH78/dragonspeak-service/models"
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 var (
@@ -15,6 +18,11 @@ var (
 	maxIdleConns = 10
 )
 
+const uniqueViolationCode = "23505"
+
+// dataExceptionClass is the class of the errors Postgres returns for values it can't convert or compare
+const dataExceptionClass = "22"
+
 type SQLConfig struct {
 	User         string
 	Password     string
@@ -42,20 +50,28 @@ func NewPostgresDao(config SQLConfig) (*PostgresDao, error) {
 	return &PostgresDao{db: db}, nil
 }
 
-// AddNewUser adds a new user to the Users table
+// AddNewUser adds a user, keeping the user's ID when one is given (the subject of the caller's token)
 func (dao *PostgresDao) AddNewUser(ctx context.Context, user models.User) (*models.User, error) {
-	userID, err := uuid.NewUUID()
-	if err != nil {
-		return nil, err
+	userID := user.ID
+	if userID == "" {
+		generatedID, err := uuid.NewUUID()
+		if err != nil {
+			return nil, err
+		}
+		userID = generatedID.String()
 	}
-	_, err = dao.db.ExecContext(ctx, "INSERT INTO Users (UserId, Handle, Email) VALUES ($1, $2, $3)", userID.String(), user.Handle, user.Email)
+	_, err := dao.db.ExecContext(ctx, "INSERT INTO Users (UserId, Handle, Email) VALUES ($1, $2, $3)", userID, user.Handle, user.Email)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("user %s: %w", user.Email, models.EntityAlreadyExists)
+		}
 		return nil, err
 	}
 
 	return &models.User{
-		ID:     userID.String(),
+		ID:     userID,
 		Handle: user.Handle,
+		Email:  user.Email,
 	}, nil
 }
 
@@ -105,45 +121,294 @@ func (dao *PostgresDao) AddCampaign(ctx context.Context, ownerID string, campaig
 	if err != nil {
 		return nil, err
 	}
-	insertStmt := `INSERT INTO Campaigns (CampaignId, OwnerUserId, CampaignName, CampaignLink) 
-					SELECT $1, UserKey, $2, $3 
+	insertStmt := `INSERT INTO Campaigns (CampaignId, OwnerUserId, CampaignName, CampaignLink, DefaultLanguage) 
+					SELECT $1, UserKey, $2, $3, NULLIF($4, '')
 					FROM Users
-					WHERE UserId=$4`
+					WHERE UserId=$5`
 
-	_, err = dao.db.ExecContext(ctx, insertStmt, campaignID.String(), campaign.Name, campaign.Link, ownerID)
+	_, err = dao.db.ExecContext(ctx, insertStmt, campaignID.String(), campaign.Name, campaign.Link, campaign.DefaultLanguage, ownerID)
 	if err != nil {
 		return nil, err
 	}
 	return &models.Campaign{
-		ID:   campaignID.String(),
-		Name: campaign.Name,
-		Link: campaign.Link,
+		ID:              campaignID.String(),
+		Name:            campaign.Name,
+		Link:            campaign.Link,
+		DefaultLanguage: campaign.DefaultLanguage,
+		Role:            models.CampaignOwner,
+		Version:         1,
 	}, nil
 }
 
-// GetAllCampaignsForUser retrieves all campaigns for a specific user
-func (dao *PostgresDao) GetCampaignsForUser(ctx context.Context, ownerID string) ([]models.Campaign, error) {
-	qs := ` SELECT c.CampaignId, c.CampaignName, c.CampaignLink
-			FROM Campaigns c
-			JOIN Users u on u.UserKey=c.OwnerUserId
-			WHERE u.UserId=$1
-	`
-	rows, err := dao.db.QueryContext(ctx, qs, ownerID)
+// campaignsForUserColumns and campaignsForUserFrom select the campaigns a user owns or plays in along with
+// the user's role, they are split so lists can add the column they sort on
+const campaignsForUserColumns = `SELECT c.CampaignId, c.CampaignName, COALESCE(c.CampaignLink, ''), COALESCE(c.DefaultLanguage, ''),
+			CASE WHEN c.OwnerUserId = u.UserKey THEN 'Owner'
+				 WHEN p.PlayerType = 'GM' THEN 'GM'
+				 ELSE 'Player' END,
+			c.Version`
+
+const campaignsForUserFrom = `
+		FROM Campaigns c
+		JOIN Users u ON u.UserId = $1
+		LEFT JOIN Players p ON p.CampaignKey = c.CampaignKey AND p.UserKey = u.UserKey
+		WHERE (c.OwnerUserId = u.UserKey OR p.PlayerKey IS NOT NULL)`
+
+var campaignSortColumns = map[string]string{
+	"name": "c.CampaignName",
+}
+
+// GetCampaignsForUser retrieves a page of the campaigns a user owns or plays in, sorted by name by default
+func (dao *PostgresDao) GetCampaignsForUser(ctx context.Context, userID string, page models.PageRequest) (*models.Page[models.Campaign], error) {
+	column, err := sortColumn(campaignSortColumns, "name", page.Sort)
 	if err != nil {
 		return nil, err
 	}
+	qs := campaignsForUserColumns + fmt.Sprintf(", CAST(%s AS TEXT)", column) + campaignsForUserFrom
+	qs, args := pageQuery(qs, []any{userID}, column, "c.CampaignId", page)
+	rows, err := dao.db.QueryContext(ctx, qs, args...)
+	if err != nil {
+		return nil, pageQueryError(err, page)
+	}
 	defer rows.Close()
 
 	campaigns := []models.Campaign{}
+	cursors := []models.Cursor{}
 	for rows.Next() {
-		c := models.Campaign{}
+		cursor := models.Cursor{}
+		c, err := scanCampaign(rows, &cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+		cursor.ID = c.ID
+		campaigns = append(campaigns, *c)
+		cursors = append(cursors, cursor)
+	}
+	return newPage(campaigns, cursors, page), nil
+}
+
+// GetCampaignForUser retrieves a campaign with the user's role, EntityNotFound is returned when the user
+// neither owns nor plays in it
+func (dao *PostgresDao) GetCampaignForUser(ctx context.Context, userID, campaignID string) (*models.Campaign, error) {
+	rows, err := dao.db.QueryContext(ctx, campaignsForUserColumns+campaignsForUserFrom+` AND c.CampaignId = $2`, userID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, models.EntityNotFound
+	}
+	return scanCampaign(rows)
+}
+
+// scanCampaign scans a campaign followed by any extra columns the query selected into extra
+func scanCampaign(rows *sql.Rows, extra ...any) (*models.Campaign, error) {
+	campaign := models.Campaign{}
+	roleStr := ""
+	dest := append([]any{&campaign.ID, &campaign.Name, &campaign.Link, &campaign.DefaultLanguage, &roleStr, &campaign.Version}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	role, err := models.CampaignRoleFromString(roleStr)
+	if err != nil {
+		return nil, err
+	}
+	campaign.Role = role
+	return &campaign, nil
+}
+
+// UpdateCampaign writes a campaign if it is still at campaign.Version and returns it with its new version,
+// Conflicted is returned when it was changed in the meantime
+func (dao *PostgresDao) UpdateCampaign(ctx context.Context, campaign models.Campaign) (*models.Campaign, error) {
+	updateStmt := `UPDATE Campaigns 
+				   SET CampaignName=$1, CampaignLink=$2, DefaultLanguage=NULLIF($3, ''), Version=Version+1
+				   WHERE CampaignId=$4 AND Version=$5
+				   RETURNING Version`
+	err := dao.db.QueryRowContext(ctx, updateStmt, campaign.Name, campaign.Link, campaign.DefaultLanguage, campaign.ID, campaign.Version).Scan(&campaign.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("campaign %s was changed: %w", campaign.ID, models.Conflicted)
+		}
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// DeleteCampaign removes a campaign along with its sessions, transcripts, players and invites
+func (dao *PostgresDao) DeleteCampaign(ctx context.Context, campaignID string) error {
+	tx, err := dao.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	campaignKey := 0
+	if err = tx.QueryRowContext(ctx, `SELECT CampaignKey FROM Campaigns WHERE CampaignId=$1`, campaignID).Scan(&campaignKey); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.EntityNotFound
+		}
+		return err
+	}
+
+	deleteStmts := []string{
+		`DELETE FROM TranscriptSpeakers WHERE TranscriptKey IN (
+			SELECT t.TranscriptKey FROM SessionTranscripts t
+			JOIN Sessions s ON s.SessionKey = t.SessionId
+			WHERE s.CampaignKey=$1)`,
+		`DELETE FROM TranscriptionOutbox WHERE TranscriptKey IN (
+			SELECT t.TranscriptKey FROM SessionTranscripts t
+			JOIN Sessions s ON s.SessionKey = t.SessionId
+			WHERE s.CampaignKey=$1)`,
+		`DELETE FROM SessionTranscripts WHERE SessionId IN (SELECT SessionKey FROM Sessions WHERE CampaignKey=$1)`,
+		`DELETE FROM SessionAttendance WHERE SessionKey IN (SELECT SessionKey FROM Sessions WHERE CampaignKey=$1)`,
+		`DELETE FROM AudioUploads WHERE SessionKey IN (SELECT SessionKey FROM Sessions WHERE CampaignKey=$1)`,
+		`DELETE FROM DirectUploads WHERE SessionKey IN (SELECT SessionKey FROM Sessions WHERE CampaignKey=$1)`,
+		`DELETE FROM Sessions WHERE CampaignKey=$1`,
+		`DELETE FROM CampaignInvites WHERE CampaignKey=$1`,
+		`DELETE FROM CampaignVocabulary WHERE CampaignKey=$1`,
+		`DELETE FROM WebhookDeliveries WHERE SubscriptionKey IN (SELECT SubscriptionKey FROM WebhookSubscriptions WHERE CampaignKey=$1)`,
+		`DELETE FROM WebhookSubscriptions WHERE CampaignKey=$1`,
+		`DELETE FROM Characters WHERE PlayerKey IN (SELECT PlayerKey FROM Players WHERE CampaignKey=$1)`,
+		`DELETE FROM Players WHERE CampaignKey=$1`,
+		`DELETE FROM Campaigns WHERE CampaignKey=$1`,
+	}
+	for _, deleteStmt := range deleteStmts {
+		if _, err = tx.ExecContext(ctx, deleteStmt, campaignKey); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// AddCampaignInvite stores a new invite under a random, unguessable code
+func (dao *PostgresDao) AddCampaignInvite(ctx context.Context, invite models.CampaignInvite) (*models.CampaignInvite, error) {
+	code, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	insertStmt := `INSERT INTO CampaignInvites(InviteCode, CampaignKey, PlayerKey, PlayerType, ExpiresAt)
+				   SELECT $1, c.CampaignKey, p.PlayerKey, $2, $3
+				   FROM Campaigns c
+				   LEFT JOIN Players p ON p.CampaignKey = c.CampaignKey AND p.PlayerID = $4
+				   WHERE c.CampaignId = $5`
+	result, err := dao.db.ExecContext(ctx, insertStmt, code.String(), invite.PlayerType.String(), invite.ExpiresAt, invite.PlayerID, invite.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+	if err = expectRowsAffected(result); err != nil {
+		return nil, err
+	}
+	invite.Code = code.String()
+	return &invite, nil
+}
+
+func (dao *PostgresDao) GetCampaignInvite(ctx context.Context, code string) (*models.CampaignInvite, error) {
+	qs := `SELECT i.InviteCode, c.CampaignId, COALESCE(p.PlayerID, ''), i.PlayerType, i.ExpiresAt, i.AcceptedAt
+		   FROM CampaignInvites i
+		   JOIN Campaigns c ON c.CampaignKey = i.CampaignKey
+		   LEFT JOIN Players p ON p.PlayerKey = i.PlayerKey
+		   WHERE i.InviteCode = $1`
+	invite := models.CampaignInvite{}
+	playerTypeStr := ""
+	err := dao.db.QueryRowContext(ctx, qs, code).Scan(&invite.Code, &invite.CampaignID, &invite.PlayerID, &playerTypeStr, &invite.ExpiresAt, &invite.AcceptedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.EntityNotFound
+		}
+		return nil, err
+	}
+	if invite.PlayerType, err = models.PlayerTypeFromString(playerTypeStr); err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// AcceptCampaignInvite marks the invite as used and links the user to the invited player, creating the
+// player when the invite isn't for an existing one. Conflicted is returned when the invite was already used,
+// the invited player is already linked to a user or the invite would add a second GM to the campaign.
+func (dao *PostgresDao) AcceptCampaignInvite(ctx context.Context, code, userID string, player models.Player) (*models.Player, error) {
+	tx, err := dao.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	userKey := 0
+	if err = tx.QueryRowContext(ctx, `SELECT UserKey FROM Users WHERE UserId=$1`, userID).Scan(&userKey); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.EntityNotFound
+		}
+		return nil, err
+	}
+
+	campaignKey := 0
+	var playerKey sql.NullInt64
+	updateStmt := `UPDATE CampaignInvites
+				   SET AcceptedByUserKey=$1, AcceptedAt=NOW()
+				   WHERE InviteCode=$2 AND AcceptedAt IS NULL
+				   RETURNING CampaignKey, PlayerKey`
+	if err = tx.QueryRowContext(ctx, updateStmt, userKey, code).Scan(&campaignKey, &playerKey); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invite was already accepted: %w", models.Conflicted)
+		}
+		return nil, err
+	}
 
-		if err := rows.Scan(&c.ID, &c.Name, &c.Link); err != nil {
+	if playerKey.Valid {
+		linkStmt := `UPDATE Players SET UserKey=$1
+					 WHERE PlayerKey=$2 AND UserKey IS NULL
+					 RETURNING PlayerID, PlayerName`
+		if err = tx.QueryRowContext(ctx, linkStmt, userKey, playerKey.Int64).Scan(&player.ID, &player.Name); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("invited player is already linked to a user: %w", models.Conflicted)
+			}
+			if isUniqueViolation(err) {
+				return nil, fmt.Errorf("user already plays in the campaign: %w", models.EntityAlreadyExists)
+			}
+			return nil, err
+		}
+	} else {
+		if player.Type == models.GM {
+			if err = checkNoGM(ctx, tx, campaignKey); err != nil {
+				return nil, err
+			}
+		}
+		playerID, err := uuid.NewUUID()
+		if err != nil {
+			return nil, err
+		}
+		insertStmt := `INSERT INTO Players(CampaignKey, PlayerID, UserKey, PlayerName, PlayerType)
+					   VALUES ($1, $2, $3, $4, $5)`
+		if _, err = tx.ExecContext(ctx, insertStmt, campaignKey, playerID.String(), userKey, player.Name, player.Type.String()); err != nil {
+			if isUniqueViolation(err) {
+				return nil, fmt.Errorf("player %s: %w", player.Name, models.EntityAlreadyExists)
+			}
 			return nil, err
 		}
-		campaigns = append(campaigns, c)
+		player.ID = playerID.String()
 	}
-	return campaigns, nil
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	player.UserID = userID
+	return &player, nil
+}
+
+// checkNoGM returns Conflicted when the campaign has a GM. The campaign row stays locked until tx ends, so two
+// transactions can't both add a GM.
+func checkNoGM(ctx context.Context, tx *sql.Tx, campaignKey int) error {
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM Campaigns WHERE CampaignKey=$1 FOR UPDATE`, campaignKey); err != nil {
+		return err
+	}
+	hasGM := false
+	qs := `SELECT EXISTS(SELECT 1 FROM Players WHERE CampaignKey=$1 AND PlayerType=$2)`
+	if err := tx.QueryRowContext(ctx, qs, campaignKey, models.GM.String()).Scan(&hasGM); err != nil {
+		return err
+	}
+	if hasGM {
+		return fmt.Errorf("campaign already has a GM: %w", models.Conflicted)
+	}
+	return nil
 }
 
 // AddNewPlayer adds a new player to the Players table
@@ -152,26 +417,36 @@ func (dao *PostgresDao) AddNewPlayer(ctx context.Context, campaignID string, pla
 	if err != nil {
 		return nil, err
 	}
-	insertStmt := `INSERT INTO Players(CampaignKey, PlayerID, PlayerName, PlayerType) 
-					SELECT CampaignKey, $1, $2, $3 
-					FROM Campaigns
-					WHERE CampaignId=$4`
-	_, err = dao.db.ExecContext(ctx, insertStmt, playerID.String(), player.Name, player.Type.String(), campaignID)
+	insertStmt := `INSERT INTO Players(CampaignKey, PlayerID, UserKey, PlayerName, PlayerType) 
+					SELECT c.CampaignKey, $1, u.UserKey, $2, $3 
+					FROM Campaigns c
+					LEFT JOIN Users u ON u.UserId = $4
+					WHERE c.CampaignId=$5`
+	result, err := dao.db.ExecContext(ctx, insertStmt, playerID.String(), player.Name, player.Type.String(), player.UserID, campaignID)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("player %s: %w", player.Name, models.EntityAlreadyExists)
+		}
+		return nil, err
+	}
+	if err = expectRowsAffected(result); err != nil {
 		return nil, err
 	}
 	return &models.Player{
-		ID:   playerID.String(),
-		Name: player.Name,
-		Type: player.Type,
+		ID:     playerID.String(),
+		Name:   player.Name,
+		Type:   player.Type,
+		UserID: player.UserID,
 	}, nil
 }
 
 func (dao *PostgresDao) GetPlayersForCampaign(ctx context.Context, campaignID string) ([]models.Player, error) {
-	qs := `SELECT p.PlayerID, p.PlayerName, p.PlayerType 
+	qs := `SELECT p.PlayerID, p.PlayerName, p.PlayerType, COALESCE(u.UserId, '') 
 		   FROM Players p 
-		   JOIN Campaign c on c.CampaignKey=p.CampaignKey 
-		   WHERE c.CampaignId = $1`
+		   JOIN Campaigns c on c.CampaignKey=p.CampaignKey 
+		   LEFT JOIN Users u on u.UserKey=p.UserKey
+		   WHERE c.CampaignId = $1
+		   ORDER BY p.PlayerName`
 
 	rows, err := dao.db.QueryContext(ctx, qs, campaignID)
 	if err != nil {
@@ -181,47 +456,134 @@ func (dao *PostgresDao) GetPlayersForCampaign(ctx context.Context, campaignID st
 
 	players := []models.Player{}
 	for rows.Next() {
-		p := models.Player{}
-		playerTypeStr := ""
-		if err := rows.Scan(&p.ID, &p.Name, &playerTypeStr); err != nil {
-			return nil, err
-		}
-		var playerType models.PlayerType
-		if playerType, err = models.PlayerTypeFromString(playerTypeStr); err != nil {
+		player, err := scanPlayer(rows)
+		if err != nil {
 			return nil, err
 		}
-		p.Type = playerType
-		players = append(players, p)
+		players = append(players, *player)
 	}
 	return players, nil
 }
 
-func (dao *PostgresDao) AddCharacter(ctx context.Context, ownerID string, character models.Character) (*models.Character, error) {
+func (dao *PostgresDao) GetPlayer(ctx context.Context, campaignID, playerID string) (*models.Player, error) {
+	qs := `SELECT p.PlayerID, p.PlayerName, p.PlayerType, COALESCE(u.UserId, '') 
+		   FROM Players p 
+		   JOIN Campaigns c on c.CampaignKey=p.CampaignKey 
+		   LEFT JOIN Users u on u.UserKey=p.UserKey
+		   WHERE c.CampaignId = $1 AND p.PlayerID = $2`
+
+	rows, err := dao.db.QueryContext(ctx, qs, campaignID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, models.EntityNotFound
+	}
+	return scanPlayer(rows)
+}
+
+func (dao *PostgresDao) UpdatePlayer(ctx context.Context, campaignID string, player models.Player) (*models.Player, error) {
+	updateStmt := `UPDATE Players 
+				   SET PlayerName=$1, PlayerType=$2
+				   WHERE PlayerID=$3 AND CampaignKey=(SELECT CampaignKey FROM Campaigns WHERE CampaignId=$4)`
+	result, err := dao.db.ExecContext(ctx, updateStmt, player.Name, player.Type.String(), player.ID, campaignID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("player %s: %w", player.Name, models.EntityAlreadyExists)
+		}
+		return nil, err
+	}
+	if err = expectRowsAffected(result); err != nil {
+		return nil, err
+	}
+	return &player, nil
+}
+
+// DeletePlayer removes a player along with their characters, attendance and speaker assignments
+func (dao *PostgresDao) DeletePlayer(ctx context.Context, campaignID, playerID string) error {
+	tx, err := dao.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	playerKey := 0
+	qs := `SELECT p.PlayerKey
+		   FROM Players p
+		   JOIN Campaigns c on c.CampaignKey=p.CampaignKey
+		   WHERE c.CampaignId = $1 AND p.PlayerID = $2`
+	if err = tx.QueryRowContext(ctx, qs, campaignID, playerID).Scan(&playerKey); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.EntityNotFound
+		}
+		return err
+	}
+
+	deleteStmts := []string{
+		`DELETE FROM Characters WHERE PlayerKey=$1`,
+		`DELETE FROM SessionAttendance WHERE PlayerKey=$1`,
+		`DELETE FROM TranscriptSpeakers WHERE PlayerKey=$1`,
+		`DELETE FROM CampaignInvites WHERE PlayerKey=$1`,
+		`DELETE FROM Players WHERE PlayerKey=$1`,
+	}
+	for _, deleteStmt := range deleteStmts {
+		if _, err = tx.ExecContext(ctx, deleteStmt, playerKey); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func scanPlayer(rows *sql.Rows) (*models.Player, error) {
+	player := models.Player{}
+	playerTypeStr := ""
+	if err := rows.Scan(&player.ID, &player.Name, &playerTypeStr, &player.UserID); err != nil {
+		return nil, err
+	}
+	playerType, err := models.PlayerTypeFromString(playerTypeStr)
+	if err != nil {
+		return nil, err
+	}
+	player.Type = playerType
+	return &player, nil
+}
+
+func (dao *PostgresDao) AddCharacter(ctx context.Context, campaignID, playerID string, character models.Character) (*models.Character, error) {
 	characterID, err := uuid.NewUUID()
 	if err != nil {
 		return nil, err
 	}
-	insertStmt := `INSERT INTO Characters(CharacterId, PlayerKey, CharacterName, CharacterLink)
-				   SELECT $1, PlayerKey, $2, $3 
-				   FROM Players WHERE PlayerID = $4`
-	_, err = dao.db.ExecContext(ctx, insertStmt, characterID.String(), character.Name, character.Link, ownerID)
+	insertStmt := `INSERT INTO Characters(CharacterId, PlayerKey, CharacterName, CharacterLink, CharacterStatus)
+				   SELECT $1, p.PlayerKey, $2, $3, $4 
+				   FROM Players p
+				   JOIN Campaigns c on c.CampaignKey = p.CampaignKey
+				   WHERE p.PlayerID = $5 AND c.CampaignId = $6`
+	result, err := dao.db.ExecContext(ctx, insertStmt, characterID.String(), character.Name, character.Link, character.Status.String(), playerID, campaignID)
 	if err != nil {
 		return nil, err
 	}
+	if err = expectRowsAffected(result); err != nil {
+		return nil, err
+	}
 	return &models.Character{
-		OwnerID: ownerID,
+		OwnerID: playerID,
 		ID:      characterID.String(),
 		Name:    character.Name,
 		Link:    character.Link,
+		Status:  character.Status,
 	}, nil
 }
 
-func (dao *PostgresDao) GetCharactersForPlayer(ctx context.Context, playerID string) ([]models.Character, error) {
-	qs := `SELECT c.CharacterId, c.CharacterName, c.CharacterLink. p.PlayerID 
+// GetCharactersForPlayer returns every character the player has had, including dead and retired ones
+func (dao *PostgresDao) GetCharactersForPlayer(ctx context.Context, campaignID, playerID string) ([]models.Character, error) {
+	qs := `SELECT c.CharacterId, c.CharacterName, COALESCE(c.CharacterLink, ''), c.CharacterStatus, p.PlayerID 
 		   FROM Characters c 
 		   JOIN Players p on p.PlayerKey = c.PlayerKey
-		   WHERE p.PlayerID = $1`
-	rows, err := dao.db.QueryContext(ctx, qs, playerID)
+		   JOIN Campaigns cp on cp.CampaignKey = p.CampaignKey
+		   WHERE p.PlayerID = $1 AND cp.CampaignId = $2
+		   ORDER BY c.CharacterKey`
+	rows, err := dao.db.QueryContext(ctx, qs, playerID, campaignID)
 	if err != nil {
 		return nil, err
 	}
@@ -229,15 +591,64 @@ func (dao *PostgresDao) GetCharactersForPlayer(ctx context.Context, playerID str
 
 	characters := []models.Character{}
 	for rows.Next() {
-		character := models.Character{}
-		if err = rows.Scan(&character.ID, &character.Name, &character.Link, &character.OwnerID); err != nil {
+		character, err := scanCharacter(rows)
+		if err != nil {
 			return nil, err
 		}
-		characters = append(characters, character)
+		characters = append(characters, *character)
 	}
 	return characters, nil
 }
 
+func (dao *PostgresDao) GetCharacter(ctx context.Context, campaignID, playerID, characterID string) (*models.Character, error) {
+	qs := `SELECT c.CharacterId, c.CharacterName, COALESCE(c.CharacterLink, ''), c.CharacterStatus, p.PlayerID 
+		   FROM Characters c 
+		   JOIN Players p on p.PlayerKey = c.PlayerKey
+		   JOIN Campaigns cp on cp.CampaignKey = p.CampaignKey
+		   WHERE c.CharacterId = $1 AND p.PlayerID = $2 AND cp.CampaignId = $3`
+	rows, err := dao.db.QueryContext(ctx, qs, characterID, playerID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, models.EntityNotFound
+	}
+	return scanCharacter(rows)
+}
+
+func (dao *PostgresDao) UpdateCharacter(ctx context.Context, campaignID, playerID string, character models.Character) (*models.Character, error) {
+	updateStmt := `UPDATE Characters 
+				   SET CharacterName=$1, CharacterLink=$2, CharacterStatus=$3
+				   WHERE CharacterId=$4 AND PlayerKey=(
+					   SELECT p.PlayerKey FROM Players p
+					   JOIN Campaigns c on c.CampaignKey = p.CampaignKey
+					   WHERE p.PlayerID=$5 AND c.CampaignId=$6)`
+	result, err := dao.db.ExecContext(ctx, updateStmt, character.Name, character.Link, character.Status.String(), character.ID, playerID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if err = expectRowsAffected(result); err != nil {
+		return nil, err
+	}
+	character.OwnerID = playerID
+	return &character, nil
+}
+
+func scanCharacter(rows *sql.Rows) (*models.Character, error) {
+	character := models.Character{}
+	statusStr := ""
+	if err := rows.Scan(&character.ID, &character.Name, &character.Link, &statusStr, &character.OwnerID); err != nil {
+		return nil, err
+	}
+	status, err := models.CharacterStatusFromString(statusStr)
+	if err != nil {
+		return nil, err
+	}
+	character.Status = status
+	return &character, nil
+}
+
 func (dao *PostgresDao) AddSession(ctx context.Context, campaignID string, session models.Session) (*models.Session, error) {
 	sessionID, err := uuid.NewUUID()
 	if err != nil {
@@ -254,46 +665,161 @@ func (dao *PostgresDao) AddSession(ctx context.Context, campaignID string, sessi
 		ID:          sessionID.String(),
 		SessionDate: session.SessionDate,
 		Title:       session.Title,
+		Version:     1,
 	}, nil
 }
 
-func (dao *PostgresDao) GetSessionsForCampaign(ctx context.Context, campaignID string) ([]models.Session, error) {
-	qs := `SELECT s.SessionId, s.SessionDate, s.Title
+var sessionSortColumns = map[string]string{
+	"sessionDate": "s.SessionDate",
+	"title":       "COALESCE(s.Title, '')",
+}
+
+// GetSessionsForCampaign retrieves a page of the sessions of a campaign held within the filter's date range,
+// sorted by date by default
+func (dao *PostgresDao) GetSessionsForCampaign(ctx context.Context, campaignID string, filter models.SessionFilter, page models.PageRequest) (*models.Page[models.Session], error) {
+	column, err := sortColumn(sessionSortColumns, "sessionDate", page.Sort)
+	if err != nil {
+		return nil, err
+	}
+	qs := fmt.Sprintf(`SELECT s.SessionId, s.SessionDate, s.Title, s.Version, CAST(%s AS TEXT)
 		   FROM Sessions s 
 		   JOIN Campaigns c ON c.CampaignKey = s.CampaignKey 
-		   WHERE c.CampaignId = $1`
+		   WHERE c.CampaignId = $1`, column)
+	args := []any{campaignID}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		qs += fmt.Sprintf(" AND s.SessionDate >= $%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		qs += fmt.Sprintf(" AND s.SessionDate <= $%d", len(args))
+	}
+	qs, args = pageQuery(qs, args, column, "s.SessionId", page)
 
-	rows, err := dao.db.QueryContext(ctx, qs, campaignID)
+	rows, err := dao.db.QueryContext(ctx, qs, args...)
 	if err != nil {
-		return nil, err
+		return nil, pageQueryError(err, page)
 	}
 	defer rows.Close()
 
 	sessions := []models.Session{}
+	cursors := []models.Cursor{}
 	for rows.Next() {
 		session := models.Session{}
-		if err = rows.Scan(&session.ID, &session.SessionDate, &session.Title); err != nil {
+		cursor := models.Cursor{}
+		if err = rows.Scan(&session.ID, &session.SessionDate, &session.Title, &session.Version, &cursor.Value); err != nil {
 			return nil, err
 		}
+		cursor.ID = session.ID
 		sessions = append(sessions, session)
+		cursors = append(cursors, cursor)
+	}
+	return newPage(sessions, cursors, page), nil
+}
+
+func (dao *PostgresDao) GetSession(ctx context.Context, campaignID, sessionID string) (*models.Session, error) {
+	qs := `SELECT s.SessionId, s.SessionDate, s.Title, s.Version
+		   FROM Sessions s 
+		   JOIN Campaigns c ON c.CampaignKey = s.CampaignKey 
+		   WHERE c.CampaignId = $1 AND s.SessionId = $2`
+	session := models.Session{}
+	err := dao.db.QueryRowContext(ctx, qs, campaignID, sessionID).Scan(&session.ID, &session.SessionDate, &session.Title, &session.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.EntityNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UpdateSession writes a session if it is still at session.Version and returns it with its new version,
+// Conflicted is returned when it was changed in the meantime
+func (dao *PostgresDao) UpdateSession(ctx context.Context, campaignID string, session models.Session) (*models.Session, error) {
+	updateStmt := `UPDATE Sessions 
+				   SET SessionDate=$1, Title=$2, Version=Version+1
+				   WHERE SessionId=$3 AND Version=$4 AND CampaignKey=(SELECT CampaignKey FROM Campaigns WHERE CampaignId=$5)
+				   RETURNING Version`
+	err := dao.db.QueryRowContext(ctx, updateStmt, session.SessionDate, session.Title, session.ID, session.Version, campaignID).Scan(&session.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("session %s was changed: %w", session.ID, models.Conflicted)
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// DeleteSession removes a session along with its transcripts and attendance
+func (dao *PostgresDao) DeleteSession(ctx context.Context, campaignID, sessionID string) error {
+	tx, err := dao.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sessionKey := 0
+	qs := `SELECT s.SessionKey
+		   FROM Sessions s
+		   JOIN Campaigns c ON c.CampaignKey = s.CampaignKey
+		   WHERE c.CampaignId = $1 AND s.SessionId = $2`
+	if err = tx.QueryRowContext(ctx, qs, campaignID, sessionID).Scan(&sessionKey); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.EntityNotFound
+		}
+		return err
 	}
-	return sessions, nil
+
+	deleteStmts := []string{
+		`DELETE FROM TranscriptSpeakers WHERE TranscriptKey IN (SELECT TranscriptKey FROM SessionTranscripts WHERE SessionId=$1)`,
+		`DELETE FROM TranscriptionOutbox WHERE TranscriptKey IN (SELECT TranscriptKey FROM SessionTranscripts WHERE SessionId=$1)`,
+		`DELETE FROM SessionTranscripts WHERE SessionId=$1`,
+		`DELETE FROM SessionAttendance WHERE SessionKey=$1`,
+		`DELETE FROM AudioUploads WHERE SessionKey=$1`,
+		`DELETE FROM DirectUploads WHERE SessionKey=$1`,
+		`DELETE FROM Sessions WHERE SessionKey=$1`,
+	}
+	for _, deleteStmt := range deleteStmts {
+		if _, err = tx.ExecContext(ctx, deleteStmt, sessionKey); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-func (dao *PostgresDao) AddTranscriptToSession(ctx context.Context, sessionID string, transcript models.Transcript) (*models.Transcript, error) {
-	insertStmt := `INSERT INTO SessionTranscripts(SessionId, TranscriptionJobId, AudioLocation, AudioFormat, TranscriptLocation, SummaryLocation, Status)
-				   SELECT SessionKey, $1, $2, $3, $4, $5, $6
+// AddTranscriptToSession stores a new transcript of a session along with the outbox message of its first step
+func (dao *PostgresDao) AddTranscriptToSession(ctx context.Context, sessionID string, transcript models.Transcript, message models.OutboxMessage) (*models.Transcript, error) {
+	tx, err := dao.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	insertStmt := `INSERT INTO SessionTranscripts(SessionId, TranscriptionJobId, AudioLocation, AudioFormat, TranscriptLocation, SummaryLocation, Status, AudioSha256, AudioSize, Provider, Language, JobState, Attempts)
+				   SELECT SessionKey, $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 				   FROM Sessions 
-				   WHERE SessionId=$7`
-	_, err := dao.db.ExecContext(ctx, insertStmt, transcript.JobID, transcript.AudioLocation, transcript.AudioFormat.String(), transcript.TranscriptLocation, transcript.SummaryLocation, transcript.Status.String(), sessionID)
+				   WHERE SessionId=$13
+				   RETURNING TranscriptKey, CreatedAt, UpdatedAt`
+	transcriptKey := 0
+	err = tx.QueryRowContext(ctx, insertStmt, transcript.JobID, transcript.AudioLocation, transcript.AudioFormat.String(), transcript.TranscriptLocation, transcript.SummaryLocation, transcript.Status.String(), transcript.AudioSHA256, transcript.AudioSize, transcript.Provider, transcript.Language, transcript.JobState.String(), transcript.Attempts, sessionID).Scan(&transcriptKey, &transcript.CreatedAt, &transcript.UpdatedAt)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("session %s: %w", sessionID, models.EntityNotFound)
+		}
+		return nil, err
+	}
+	if err = addOutboxMessage(ctx, tx, transcriptKey, message); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
+	transcript.Version = 1
 	return &transcript, nil
 }
 
 func (dao *PostgresDao) GetTranscriptsForSession(ctx context.Context, sessionID string) ([]models.Transcript, error) {
-	qs := `SELECT t.TranscriptionJobId, t.AudioLocation, t.AudioFormat, t.TranscriptLocation, t.SummaryLocation, t.Status 
+	qs := `SELECT t.TranscriptionJobId, t.AudioLocation, t.AudioFormat, t.TranscriptLocation, t.SummaryLocation, t.Status, t.Version, COALESCE(t.AudioSha256, ''), COALESCE(t.AudioSize, 0), t.Provider, t.Language, COALESCE(t.DetectedLanguage, ''), t.JobState, COALESCE(t.FailureReason, ''), t.Attempts, t.CreatedAt, t.UpdatedAt
 		   FROM SessionTranscripts t 
 		   JOIN Sessions s on s.SessionKey = t.SessionId 
 		   WHERE s.SessionId=$1`
@@ -305,54 +831,1255 @@ func (dao *PostgresDao) GetTranscriptsForSession(ctx context.Context, sessionID
 
 	transcripts := []models.Transcript{}
 	for rows.Next() {
-		transcript := models.Transcript{}
-		statusString := ""
-		audioFormatString := ""
-		if err = rows.Scan(&transcript.JobID, &transcript.AudioLocation, &audioFormatString, &transcript.TranscriptLocation, &transcript.SummaryLocation, &statusString); err != nil {
-			return nil, err
-		}
-		transcriptStatus, err := models.TranscriptStatusFromString(statusString)
+		transcript, err := scanTranscript(rows)
 		if err != nil {
 			return nil, err
 		}
-		audioFormat, err := models.AudioFormatFromString(audioFormatString)
+		transcripts = append(transcripts, *transcript)
+	}
+	return transcripts, nil
+}
+
+var transcriptSortColumns = map[string]string{
+	"created": "t.TranscriptKey",
+	"status":  "t.Status",
+}
+
+// ListTranscriptsForSession retrieves a page of the transcripts of a session matching the filter, in the order
+// they were submitted by default
+func (dao *PostgresDao) ListTranscriptsForSession(ctx context.Context, sessionID string, filter models.TranscriptFilter, page models.PageRequest) (*models.Page[models.Transcript], error) {
+	column, err := sortColumn(transcriptSortColumns, "created", page.Sort)
+	if err != nil {
+		return nil, err
+	}
+	qs := fmt.Sprintf(`SELECT t.TranscriptionJobId, t.AudioLocation, t.AudioFormat, t.TranscriptLocation, t.SummaryLocation, t.Status, t.Version, COALESCE(t.AudioSha256, ''), COALESCE(t.AudioSize, 0), t.Provider, t.Language, COALESCE(t.DetectedLanguage, ''), t.JobState, COALESCE(t.FailureReason, ''), t.Attempts, t.CreatedAt, t.UpdatedAt, CAST(%s AS TEXT)
+		   FROM SessionTranscripts t 
+		   JOIN Sessions s on s.SessionKey = t.SessionId 
+		   WHERE s.SessionId=$1`, column)
+	args := []any{sessionID}
+	if filter.Status != nil {
+		args = append(args, filter.Status.String())
+		qs += fmt.Sprintf(" AND t.Status = $%d", len(args))
+	}
+	qs, args = pageQuery(qs, args, column, "t.TranscriptionJobId", page)
+
+	rows, err := dao.db.QueryContext(ctx, qs, args...)
+	if err != nil {
+		return nil, pageQueryError(err, page)
+	}
+	defer rows.Close()
+
+	transcripts := []models.Transcript{}
+	cursors := []models.Cursor{}
+	for rows.Next() {
+		cursor := models.Cursor{}
+		transcript, err := scanTranscript(rows, &cursor.Value)
 		if err != nil {
 			return nil, err
 		}
-		transcript.Status = transcriptStatus
-		transcript.AudioFormat = audioFormat
-		transcripts = append(transcripts, transcript)
+		cursor.ID = transcript.JobID
+		transcripts = append(transcripts, *transcript)
+		cursors = append(cursors, cursor)
 	}
-	return transcripts, nil
+	return newPage(transcripts, cursors, page), nil
 }
 
-func (dao *PostgresDao) GetTranscript(ctx context.Context, jobID string) (*models.Transcript, error) {
-	qs := `SELECT t.TranscriptionJobId, t.AudioLocation, t.AudioFormat, t.TranscriptLocation, t.SummaryLocation, t.Status 
+// GetTranscriptsForCampaign retrieves the transcripts of every session of a campaign
+func (dao *PostgresDao) GetTranscriptsForCampaign(ctx context.Context, campaignID string) ([]models.Transcript, error) {
+	qs := `SELECT t.TranscriptionJobId, t.AudioLocation, t.AudioFormat, t.TranscriptLocation, t.SummaryLocation, t.Status, t.Version, COALESCE(t.AudioSha256, ''), COALESCE(t.AudioSize, 0), t.Provider, t.Language, COALESCE(t.DetectedLanguage, ''), t.JobState, COALESCE(t.FailureReason, ''), t.Attempts, t.CreatedAt, t.UpdatedAt
 		   FROM SessionTranscripts t 
-		   WHERE t.TranscriptionJobId = $1`
-	rows, err := dao.db.QueryContext(ctx, qs, jobID)
+		   JOIN Sessions s on s.SessionKey = t.SessionId 
+		   JOIN Campaigns c on c.CampaignKey = s.CampaignKey 
+		   WHERE c.CampaignId=$1`
+	rows, err := dao.db.QueryContext(ctx, qs, campaignID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	if !rows.Next() {
-		return nil, models.EntityNotFound
+
+	transcripts := []models.Transcript{}
+	for rows.Next() {
+		transcript, err := scanTranscript(rows)
+		if err != nil {
+			return nil, err
+		}
+		transcripts = append(transcripts, *transcript)
 	}
+	return transcripts, nil
+}
 
-	transcript := models.Transcript{}
-	statusStr := ""
-	audioFormatStr := ""
-	rows.Scan(&transcript.JobID, &transcript.AudioLocation, &audioFormatStr, &transcript.TranscriptLocation, &transcript.SummaryLocation, &statusStr)
-	status, err := models.TranscriptStatusFromString(statusStr)
+// MoveTranscriptToSession moves a transcript that is still at version to another session of the same campaign.
+// Conflicted is returned when the transcript was changed in the meantime.
+func (dao *PostgresDao) MoveTranscriptToSession(ctx context.Context, jobID, campaignID, sessionID string, version int) error {
+	updateStmt := `UPDATE SessionTranscripts t
+				   SET SessionId = target.SessionKey, Version = t.Version + 1, UpdatedAt = NOW()
+				   FROM Sessions target
+				   JOIN Campaigns c ON c.CampaignKey = target.CampaignKey
+				   WHERE t.TranscriptionJobId = $1 AND c.CampaignId = $2 AND target.SessionId = $3 AND t.Version = $4
+				   AND t.SessionId IN (SELECT SessionKey FROM Sessions WHERE CampaignKey = c.CampaignKey)`
+	result, err := dao.db.ExecContext(ctx, updateStmt, jobID, campaignID, sessionID, version)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	audioFormat, err := models.AudioFormatFromString(audioFormatStr)
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		sessionInCampaign, err := dao.SessionBelongsToCampaign(ctx, campaignID, sessionID)
+		if err != nil {
+			return err
+		}
+		if sessionInCampaign {
+			return fmt.Errorf("transcript %s was changed: %w", jobID, models.Conflicted)
+		}
+		return fmt.Errorf("session %s is not part of campaign %s: %w", sessionID, campaignID, models.InvalidEntity)
+	}
+	return nil
+}
+
+// DeleteTranscript removes a transcript along with its speaker assignments and outbox message
+func (dao *PostgresDao) DeleteTranscript(ctx context.Context, jobID string) error {
+	tx, err := dao.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	deleteStmt := `DELETE FROM TranscriptSpeakers
+				   WHERE TranscriptKey IN (SELECT TranscriptKey FROM SessionTranscripts WHERE TranscriptionJobId=$1)`
+	if _, err = tx.ExecContext(ctx, deleteStmt, jobID); err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM SessionTranscripts WHERE TranscriptionJobId=$1`, jobID)
+	if err != nil {
+		return err
+	}
+	if err = expectRowsAffected(result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (dao *PostgresDao) GetTranscript(ctx context.Context, jobID string) (*models.Transcript, error) {
+	qs := `SELECT t.TranscriptionJobId, t.AudioLocation, t.AudioFormat, t.TranscriptLocation, t.SummaryLocation, t.Status, t.Version, COALESCE(t.AudioSha256, ''), COALESCE(t.AudioSize, 0), t.Provider, t.Language, COALESCE(t.DetectedLanguage, ''), t.JobState, COALESCE(t.FailureReason, ''), t.Attempts, t.CreatedAt, t.UpdatedAt
+		   FROM SessionTranscripts t 
+		   WHERE t.TranscriptionJobId = $1`
+	rows, err := dao.db.QueryContext(ctx, qs, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, models.EntityNotFound
+	}
+
+	return scanTranscript(rows)
+}
+
+// GetTranscriptsByStatus retrieves every transcript, across all sessions, in the given status
+func (dao *PostgresDao) GetTranscriptsByStatus(ctx context.Context, status models.TranscriptStatus) ([]models.Transcript, error) {
+	qs := `SELECT t.TranscriptionJobId, t.AudioLocation, t.AudioFormat, t.TranscriptLocation, t.SummaryLocation, t.Status, t.Version, COALESCE(t.AudioSha256, ''), COALESCE(t.AudioSize, 0), t.Provider, t.Language, COALESCE(t.DetectedLanguage, ''), t.JobState, COALESCE(t.FailureReason, ''), t.Attempts, t.CreatedAt, t.UpdatedAt
+		   FROM SessionTranscripts t 
+		   WHERE t.Status = $1`
+	rows, err := dao.db.QueryContext(ctx, qs, status.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transcripts := []models.Transcript{}
+	for rows.Next() {
+		transcript, err := scanTranscript(rows)
+		if err != nil {
+			return nil, err
+		}
+		transcripts = append(transcripts, *transcript)
+	}
+	return transcripts, nil
+}
+
+// AdvanceTranscriptJob moves a transcript that is still in the from job state to its new status and job state,
+// replacing its outbox message with next and queueing the webhook event the move raises in the same
+// transaction. Conflicted is returned when the job left the from state in the meantime.
+func (dao *PostgresDao) AdvanceTranscriptJob(ctx context.Context, transcript models.Transcript, from models.JobState, next *models.OutboxMessage) (*models.Transcript, error) {
+	tx, err := dao.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	updateStmt := `UPDATE SessionTranscripts 
+				   SET Status=$1, JobState=$2, FailureReason=NULLIF($3, ''), AudioSha256=$4, AudioSize=$5, SummaryLocation=$6, DetectedLanguage=NULLIF($7, ''), Attempts=$8, Version=Version+1, UpdatedAt=NOW()
+				   WHERE TranscriptionJobId=$9 AND JobState=$10
+				   RETURNING TranscriptKey, Version, UpdatedAt`
+	transcriptKey := 0
+	err = tx.QueryRowContext(ctx, updateStmt, transcript.Status.String(), transcript.JobState.String(), transcript.FailureReason, transcript.AudioSHA256, transcript.AudioSize, transcript.SummaryLocation, transcript.DetectedLanguage, transcript.Attempts, transcript.JobID, from.String()).Scan(&transcriptKey, &transcript.Version, &transcript.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("transcript %s is no longer %s: %w", transcript.JobID, from, models.Conflicted)
+		}
+		return nil, err
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM TranscriptionOutbox WHERE TranscriptKey=$1`, transcriptKey); err != nil {
+		return nil, err
+	}
+	if next != nil {
+		if err = addOutboxMessage(ctx, tx, transcriptKey, *next); err != nil {
+			return nil, err
+		}
+	}
+	if eventType, ok := models.TranscriptEvent(from, transcript); ok {
+		if err = addWebhookDeliveries(ctx, tx, transcriptKey, eventType, transcript); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &transcript, nil
+}
+
+func addOutboxMessage(ctx context.Context, tx *sql.Tx, transcriptKey int, message models.OutboxMessage) error {
+	options, err := json.Marshal(message.Options)
+	if err != nil {
+		return err
+	}
+	insertStmt := `INSERT INTO TranscriptionOutbox(TranscriptKey, Step, Options, Attempts, NextAttemptAt)
+				   VALUES ($1, $2, $3, $4, $5)`
+	_, err = tx.ExecContext(ctx, insertStmt, transcriptKey, message.Step.String(), options, message.Attempts, message.NextAttemptAt)
+	return err
+}
+
+// ClaimOutboxMessages takes up to limit outbox messages that are due at now and holds them until leaseUntil by
+// pushing back when they are due, so a message whose step doesn't finish is taken again once the lease runs out.
+// The attempts of every claimed message are counted up.
+func (dao *PostgresDao) ClaimOutboxMessages(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error) {
+	updateStmt := `UPDATE TranscriptionOutbox o
+				   SET Attempts = o.Attempts + 1, NextAttemptAt = $1
+				   FROM SessionTranscripts t
+				   WHERE t.TranscriptKey = o.TranscriptKey AND o.TranscriptKey IN (
+				       SELECT TranscriptKey FROM TranscriptionOutbox
+				       WHERE NextAttemptAt <= $2
+				       ORDER BY NextAttemptAt
+				       LIMIT $3
+				       FOR UPDATE SKIP LOCKED)
+				   RETURNING t.TranscriptionJobId, o.Step, o.Options, o.Attempts, o.NextAttemptAt, COALESCE(o.LastError, '')`
+	rows, err := dao.db.QueryContext(ctx, updateStmt, leaseUntil, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []models.OutboxMessage{}
+	for rows.Next() {
+		message := models.OutboxMessage{}
+		stepStr := ""
+		options := []byte{}
+		if err = rows.Scan(&message.JobID, &stepStr, &options, &message.Attempts, &message.NextAttemptAt, &message.LastError); err != nil {
+			return nil, err
+		}
+		if message.Step, err = models.OutboxStepFromString(stepStr); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(options, &message.Options); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+// RetryOutboxMessage records why the step of a job's outbox message failed and when it is due again
+func (dao *PostgresDao) RetryOutboxMessage(ctx context.Context, jobID, lastError string, nextAttemptAt time.Time) error {
+	updateStmt := `UPDATE TranscriptionOutbox 
+				   SET LastError=$1, NextAttemptAt=$2
+				   WHERE TranscriptKey=(SELECT TranscriptKey FROM SessionTranscripts WHERE TranscriptionJobId=$3)`
+	result, err := dao.db.ExecContext(ctx, updateStmt, lastError, nextAttemptAt, jobID)
+	if err != nil {
+		return err
+	}
+	return expectRowsAffected(result)
+}
+
+// CountSessionAttendees returns the number of players recorded as attending a session
+func (dao *PostgresDao) CountSessionAttendees(ctx context.Context, sessionID string) (int, error) {
+	qs := `SELECT COUNT(*)
+		   FROM SessionAttendance a
+		   JOIN Sessions s ON s.SessionKey = a.SessionKey
+		   WHERE s.SessionId = $1`
+	count := 0
+	if err := dao.db.QueryRowContext(ctx, qs, sessionID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetSessionLanguage returns the default language of the campaign a session belongs to, empty when the
+// campaign has none
+func (dao *PostgresDao) GetSessionLanguage(ctx context.Context, sessionID string) (string, error) {
+	qs := `SELECT COALESCE(c.DefaultLanguage, '')
+		   FROM Sessions s
+		   JOIN Campaigns c ON c.CampaignKey = s.CampaignKey
+		   WHERE s.SessionId = $1`
+	language := ""
+	if err := dao.db.QueryRowContext(ctx, qs, sessionID).Scan(&language); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", models.EntityNotFound
+		}
+		return "", err
+	}
+	return language, nil
+}
+
+// vocabularyQuery selects the custom vocabulary of a campaign followed by the names of the campaign, its players
+// and their characters, campaignKey selects the key of the campaign
+func vocabularyQuery(campaignKey string) string {
+	return fmt.Sprintf(`WITH campaign AS (%s)
+		SELECT v.TermId, v.Phrase, v.SoundsLike, 'Custom' AS Source
+		FROM CampaignVocabulary v
+		JOIN campaign ON campaign.CampaignKey = v.CampaignKey
+		UNION ALL
+		SELECT '', c.CampaignName, '[]'::JSONB, 'Campaign'
+		FROM Campaigns c
+		JOIN campaign ON campaign.CampaignKey = c.CampaignKey
+		UNION ALL
+		SELECT '', p.PlayerName, '[]'::JSONB, 'Player'
+		FROM Players p
+		JOIN campaign ON campaign.CampaignKey = p.CampaignKey
+		WHERE p.PlayerName IS NOT NULL
+		UNION ALL
+		SELECT '', ch.CharacterName, '[]'::JSONB, 'Character'
+		FROM Characters ch
+		JOIN Players p ON p.PlayerKey = ch.PlayerKey
+		JOIN campaign ON campaign.CampaignKey = p.CampaignKey
+		ORDER BY 2`, campaignKey)
+}
+
+// GetVocabularyForCampaign retrieves the custom vocabulary of a campaign along with the names it is seeded with
+func (dao *PostgresDao) GetVocabularyForCampaign(ctx context.Context, campaignID string) ([]models.VocabularyTerm, error) {
+	return dao.getVocabulary(ctx, vocabularyQuery(`SELECT CampaignKey FROM Campaigns WHERE CampaignId = $1`), campaignID)
+}
+
+// GetVocabularyForSession retrieves the vocabulary of the campaign a session belongs to
+func (dao *PostgresDao) GetVocabularyForSession(ctx context.Context, sessionID string) ([]models.VocabularyTerm, error) {
+	return dao.getVocabulary(ctx, vocabularyQuery(`SELECT CampaignKey FROM Sessions WHERE SessionId = $1`), sessionID)
+}
+
+func (dao *PostgresDao) getVocabulary(ctx context.Context, qs string, args ...any) ([]models.VocabularyTerm, error) {
+	rows, err := dao.db.QueryContext(ctx, qs, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	terms := []models.VocabularyTerm{}
+	for rows.Next() {
+		term, err := scanVocabularyTerm(rows)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, *term)
+	}
+	return terms, nil
+}
+
+// GetVocabularyTerm retrieves a custom vocabulary term of a campaign
+func (dao *PostgresDao) GetVocabularyTerm(ctx context.Context, campaignID, termID string) (*models.VocabularyTerm, error) {
+	qs := `SELECT v.TermId, v.Phrase, v.SoundsLike, 'Custom'
+		   FROM CampaignVocabulary v
+		   JOIN Campaigns c ON c.CampaignKey = v.CampaignKey
+		   WHERE c.CampaignId = $1 AND v.TermId = $2`
+	rows, err := dao.db.QueryContext(ctx, qs, campaignID, termID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, models.EntityNotFound
+	}
+	return scanVocabularyTerm(rows)
+}
+
+// AddVocabularyTerm adds a custom term to the vocabulary of a campaign, phrases are unique per campaign
+// regardless of case
+func (dao *PostgresDao) AddVocabularyTerm(ctx context.Context, campaignID string, term models.VocabularyTerm) (*models.VocabularyTerm, error) {
+	termID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	soundsLike, err := json.Marshal(term.SoundsLike)
+	if err != nil {
+		return nil, err
+	}
+	insertStmt := `INSERT INTO CampaignVocabulary(TermId, CampaignKey, Phrase, SoundsLike)
+				   SELECT $1, CampaignKey, $2, $3
+				   FROM Campaigns
+				   WHERE CampaignId=$4`
+	result, err := dao.db.ExecContext(ctx, insertStmt, termID.String(), term.Phrase, soundsLike, campaignID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("vocabulary term %s: %w", term.Phrase, models.EntityAlreadyExists)
+		}
+		return nil, err
+	}
+	if err = expectRowsAffected(result); err != nil {
+		return nil, err
+	}
+	term.ID = termID.String()
+	term.Source = models.VocabularyCustom
+	return &term, nil
+}
+
+// UpdateVocabularyTerm writes the phrase and sounds like hints of a custom vocabulary term
+func (dao *PostgresDao) UpdateVocabularyTerm(ctx context.Context, campaignID string, term models.VocabularyTerm) (*models.VocabularyTerm, error) {
+	soundsLike, err := json.Marshal(term.SoundsLike)
+	if err != nil {
+		return nil, err
+	}
+	updateStmt := `UPDATE CampaignVocabulary
+				   SET Phrase=$1, SoundsLike=$2
+				   WHERE TermId=$3 AND CampaignKey=(SELECT CampaignKey FROM Campaigns WHERE CampaignId=$4)`
+	result, err := dao.db.ExecContext(ctx, updateStmt, term.Phrase, soundsLike, term.ID, campaignID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, fmt.Errorf("vocabulary term %s: %w", term.Phrase, models.EntityAlreadyExists)
+		}
+		return nil, err
+	}
+	if err = expectRowsAffected(result); err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+// DeleteVocabularyTerm removes a custom term from the vocabulary of a campaign
+func (dao *PostgresDao) DeleteVocabularyTerm(ctx context.Context, campaignID, termID string) error {
+	deleteStmt := `DELETE FROM CampaignVocabulary
+				   WHERE TermId=$1 AND CampaignKey=(SELECT CampaignKey FROM Campaigns WHERE CampaignId=$2)`
+	result, err := dao.db.ExecContext(ctx, deleteStmt, termID, campaignID)
+	if err != nil {
+		return err
+	}
+	return expectRowsAffected(result)
+}
+
+// SetSessionAttendance replaces the players recorded as attending a session. Every player must belong to the
+// session's campaign.
+func (dao *PostgresDao) SetSessionAttendance(ctx context.Context, campaignID, sessionID string, playerIDs []string) error {
+	tx, err := dao.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sessionKey := 0
+	qs := `SELECT s.SessionKey
+		   FROM Sessions s
+		   JOIN Campaigns c ON c.CampaignKey = s.CampaignKey
+		   WHERE c.CampaignId = $1 AND s.SessionId = $2`
+	if err = tx.QueryRowContext(ctx, qs, campaignID, sessionID).Scan(&sessionKey); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.EntityNotFound
+		}
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM SessionAttendance WHERE SessionKey=$1`, sessionKey); err != nil {
+		return err
+	}
+
+	insertStmt := `INSERT INTO SessionAttendance(SessionKey, PlayerKey)
+				   SELECT s.SessionKey, p.PlayerKey
+				   FROM Sessions s
+				   JOIN Players p ON p.CampaignKey = s.CampaignKey
+				   WHERE s.SessionKey=$1 AND p.PlayerID=$2`
+	for _, playerID := range playerIDs {
+		result, err := tx.ExecContext(ctx, insertStmt, sessionKey, playerID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("player %s is not part of the session's campaign: %w", playerID, models.InvalidEntity)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetSessionAttendance retrieves the players that attended a session
+func (dao *PostgresDao) GetSessionAttendance(ctx context.Context, campaignID, sessionID string) ([]models.Player, error) {
+	qs := `SELECT p.PlayerID, p.PlayerName, p.PlayerType, COALESCE(u.UserId, '')
+		   FROM SessionAttendance a
+		   JOIN Sessions s ON s.SessionKey = a.SessionKey
+		   JOIN Campaigns c ON c.CampaignKey = s.CampaignKey
+		   JOIN Players p ON p.PlayerKey = a.PlayerKey
+		   LEFT JOIN Users u ON u.UserKey = p.UserKey
+		   WHERE c.CampaignId = $1 AND s.SessionId = $2
+		   ORDER BY p.PlayerName`
+	rows, err := dao.db.QueryContext(ctx, qs, campaignID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	players := []models.Player{}
+	for rows.Next() {
+		player, err := scanPlayer(rows)
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, *player)
+	}
+	return players, nil
+}
+
+// GetCampaignAttendance counts the sessions each player of a campaign attended
+func (dao *PostgresDao) GetCampaignAttendance(ctx context.Context, campaignID string) (*models.AttendanceReport, error) {
+	report := models.AttendanceReport{
+		Players: []models.PlayerAttendance{},
+	}
+	qs := `SELECT COUNT(*)
+		   FROM Sessions s
+		   JOIN Campaigns c ON c.CampaignKey = s.CampaignKey
+		   WHERE c.CampaignId = $1`
+	if err := dao.db.QueryRowContext(ctx, qs, campaignID).Scan(&report.TotalSessions); err != nil {
+		return nil, err
+	}
+
+	qs = `SELECT p.PlayerID, p.PlayerName, p.PlayerType, COUNT(a.SessionKey)
+		  FROM Players p
+		  JOIN Campaigns c ON c.CampaignKey = p.CampaignKey
+		  LEFT JOIN SessionAttendance a ON a.PlayerKey = p.PlayerKey
+		  WHERE c.CampaignId = $1
+		  GROUP BY p.PlayerKey, p.PlayerID, p.PlayerName, p.PlayerType
+		  ORDER BY p.PlayerName`
+	rows, err := dao.db.QueryContext(ctx, qs, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attendance := models.PlayerAttendance{}
+		playerTypeStr := ""
+		if err = rows.Scan(&attendance.Player.ID, &attendance.Player.Name, &playerTypeStr, &attendance.SessionsAttended); err != nil {
+			return nil, err
+		}
+		if attendance.Player.Type, err = models.PlayerTypeFromString(playerTypeStr); err != nil {
+			return nil, err
+		}
+		report.Players = append(report.Players, attendance)
+	}
+	return &report, nil
+}
+
+// SetTranscriptSpeakers replaces the speaker assignments of a transcript. Every player must belong to the
+// campaign the transcript was recorded in.
+func (dao *PostgresDao) SetTranscriptSpeakers(ctx context.Context, jobID string, assignments []models.SpeakerAssignment) error {
+	tx, err := dao.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	deleteStmt := `DELETE FROM TranscriptSpeakers
+				   WHERE TranscriptKey IN (SELECT TranscriptKey FROM SessionTranscripts WHERE TranscriptionJobId=$1)`
+	if _, err = tx.ExecContext(ctx, deleteStmt, jobID); err != nil {
+		return err
+	}
+
+	insertStmt := `INSERT INTO TranscriptSpeakers(TranscriptKey, SpeakerLabel, PlayerKey)
+				   SELECT t.TranscriptKey, $1, p.PlayerKey
+				   FROM SessionTranscripts t
+				   JOIN Sessions s ON s.SessionKey = t.SessionId
+				   JOIN Players p ON p.CampaignKey = s.CampaignKey
+				   WHERE t.TranscriptionJobId=$2 AND p.PlayerID=$3`
+	for _, assignment := range assignments {
+		result, err := tx.ExecContext(ctx, insertStmt, assignment.SpeakerLabel, jobID, assignment.PlayerID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("player %s is not part of the transcript's campaign: %w", assignment.PlayerID, models.InvalidEntity)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetTranscriptSpeakers retrieves the speaker assignments of a transcript
+func (dao *PostgresDao) GetTranscriptSpeakers(ctx context.Context, jobID string) ([]models.SpeakerAssignment, error) {
+	qs := `SELECT ts.SpeakerLabel, p.PlayerID, p.PlayerName
+		   FROM TranscriptSpeakers ts
+		   JOIN SessionTranscripts t ON t.TranscriptKey = ts.TranscriptKey
+		   JOIN Players p ON p.PlayerKey = ts.PlayerKey
+		   WHERE t.TranscriptionJobId = $1
+		   ORDER BY ts.SpeakerLabel`
+	rows, err := dao.db.QueryContext(ctx, qs, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := []models.SpeakerAssignment{}
+	for rows.Next() {
+		assignment := models.SpeakerAssignment{}
+		if err = rows.Scan(&assignment.SpeakerLabel, &assignment.PlayerID, &assignment.PlayerName); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments, nil
+}
+
+// AddAudioUpload starts tracking a resumable upload to a session
+func (dao *PostgresDao) AddAudioUpload(ctx context.Context, upload models.ResumableUpload) (*models.ResumableUpload, error) {
+	insertStmt := `INSERT INTO AudioUploads(UploadId, SessionKey, AudioFormat, UploadLength, AudioLocation, StorageUploadId, MaxSpeakers, Provider, Language)
+				   SELECT $1, SessionKey, $2, $3, $4, $5, $6, $7, $8
+				   FROM Sessions
+				   WHERE SessionId=$9
+				   RETURNING CreatedAt`
+	err := dao.db.QueryRowContext(ctx, insertStmt, upload.ID, upload.AudioFormat.String(), upload.Length, upload.AudioLocation, upload.StorageUploadID, upload.Options.MaxSpeakers, upload.Options.Provider, upload.Options.Language, upload.SessionID).Scan(&upload.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.EntityNotFound
+		}
+		return nil, err
+	}
+	upload.Parts = []models.UploadPart{}
+	return &upload, nil
+}
+
+// GetAudioUpload retrieves a resumable upload to a session
+func (dao *PostgresDao) GetAudioUpload(ctx context.Context, sessionID, uploadID string) (*models.ResumableUpload, error) {
+	qs := `SELECT u.UploadId, s.SessionId, u.AudioFormat, u.UploadLength, u.UploadOffset, u.AudioLocation, u.StorageUploadId, u.Parts, u.HashState, u.MaxSpeakers, u.Provider, u.Language, u.Assembled, COALESCE(u.TranscriptionJobId, ''), u.CreatedAt
+		   FROM AudioUploads u
+		   JOIN Sessions s ON s.SessionKey = u.SessionKey
+		   WHERE s.SessionId = $1 AND u.UploadId = $2`
+	upload := models.ResumableUpload{}
+	audioFormatStr := ""
+	parts := []byte{}
+	err := dao.db.QueryRowContext(ctx, qs, sessionID, uploadID).Scan(&upload.ID, &upload.SessionID, &audioFormatStr, &upload.Length, &upload.Offset, &upload.AudioLocation, &upload.StorageUploadID, &parts, &upload.HashState, &upload.Options.MaxSpeakers, &upload.Options.Provider, &upload.Options.Language, &upload.Assembled, &upload.TranscriptJobID, &upload.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.EntityNotFound
+		}
+		return nil, err
+	}
+	if upload.AudioFormat, err = models.AudioFormatFromString(audioFormatStr); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(parts, &upload.Parts); err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// UpdateAudioUpload records the progress of a resumable upload. Conflicted is returned when the upload moved
+// past previousOffset or was claimed for transcription since it was read.
+func (dao *PostgresDao) UpdateAudioUpload(ctx context.Context, upload models.ResumableUpload, previousOffset int64) error {
+	parts, err := json.Marshal(upload.Parts)
+	if err != nil {
+		return err
+	}
+	updateStmt := `UPDATE AudioUploads
+				   SET UploadOffset=$1, Parts=$2, HashState=$3, Assembled=$4
+				   WHERE UploadId=$5 AND UploadOffset=$6 AND TranscriptionJobId IS NULL`
+	result, err := dao.db.ExecContext(ctx, updateStmt, upload.Offset, parts, upload.HashState, upload.Assembled, upload.ID, previousOffset)
+	if err != nil {
+		return err
+	}
+	if err = expectRowsAffected(result); err != nil {
+		return fmt.Errorf("upload %s changed while it was being written: %w", upload.ID, models.Conflicted)
+	}
+	return nil
+}
+
+// ClaimAudioUpload records the transcript a resumable upload is handed off as before the transcript is
+// submitted. Conflicted is returned when the upload was already claimed.
+func (dao *PostgresDao) ClaimAudioUpload(ctx context.Context, uploadID, jobID string) error {
+	updateStmt := `UPDATE AudioUploads
+				   SET TranscriptionJobId=$1
+				   WHERE UploadId=$2 AND TranscriptionJobId IS NULL`
+	result, err := dao.db.ExecContext(ctx, updateStmt, jobID, uploadID)
+	if err != nil {
+		return err
+	}
+	if err = expectRowsAffected(result); err != nil {
+		return fmt.Errorf("upload %s was already finalized: %w", uploadID, models.Conflicted)
+	}
+	return nil
+}
+
+// ReleaseAudioUpload undoes the claim of a resumable upload whose transcript couldn't be submitted, so it can
+// be finalized again
+func (dao *PostgresDao) ReleaseAudioUpload(ctx context.Context, uploadID, jobID string) error {
+	updateStmt := `UPDATE AudioUploads
+				   SET TranscriptionJobId=NULL
+				   WHERE UploadId=$1 AND TranscriptionJobId=$2`
+	result, err := dao.db.ExecContext(ctx, updateStmt, uploadID, jobID)
+	if err != nil {
+		return err
+	}
+	return expectRowsAffected(result)
+}
+
+// DeleteAudioUpload stops tracking a resumable upload
+func (dao *PostgresDao) DeleteAudioUpload(ctx context.Context, uploadID string) error {
+	result, err := dao.db.ExecContext(ctx, `DELETE FROM AudioUploads WHERE UploadId=$1`, uploadID)
+	if err != nil {
+		return err
+	}
+	return expectRowsAffected(result)
+}
+
+// AddDirectUpload records an upload a client was given a presigned URL for
+func (dao *PostgresDao) AddDirectUpload(ctx context.Context, upload models.DirectUpload) (*models.DirectUpload, error) {
+	insertStmt := `INSERT INTO DirectUploads(UploadId, SessionKey, AudioFormat, ContentType, UploadLength, AudioSha256, AudioLocation, MaxSpeakers, Provider, Language)
+				   SELECT $1, SessionKey, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9
+				   FROM Sessions
+				   WHERE SessionId=$10
+				   RETURNING CreatedAt`
+	err := dao.db.QueryRowContext(ctx, insertStmt, upload.ID, upload.AudioFormat.String(), upload.ContentType, upload.Length, upload.SHA256, upload.AudioLocation, upload.Options.MaxSpeakers, upload.Options.Provider, upload.Options.Language, upload.SessionID).Scan(&upload.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.EntityNotFound
+		}
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// GetDirectUpload retrieves an upload to a session made with a presigned URL
+func (dao *PostgresDao) GetDirectUpload(ctx context.Context, sessionID, uploadID string) (*models.DirectUpload, error) {
+	qs := `SELECT u.UploadId, s.SessionId, u.AudioFormat, u.ContentType, u.UploadLength, COALESCE(u.AudioSha256, ''), u.AudioLocation, u.MaxSpeakers, u.Provider, u.Language, COALESCE(u.TranscriptionJobId, ''), u.CreatedAt
+		   FROM DirectUploads u
+		   JOIN Sessions s ON s.SessionKey = u.SessionKey
+		   WHERE s.SessionId = $1 AND u.UploadId = $2`
+	upload := models.DirectUpload{}
+	audioFormatStr := ""
+	err := dao.db.QueryRowContext(ctx, qs, sessionID, uploadID).Scan(&upload.ID, &upload.SessionID, &audioFormatStr, &upload.ContentType, &upload.Length, &upload.SHA256, &upload.AudioLocation, &upload.Options.MaxSpeakers, &upload.Options.Provider, &upload.Options.Language, &upload.TranscriptJobID, &upload.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.EntityNotFound
+		}
+		return nil, err
+	}
+	if upload.AudioFormat, err = models.AudioFormatFromString(audioFormatStr); err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// ClaimDirectUpload records the transcript a direct upload is handed off as before the transcript is
+// submitted. Conflicted is returned when the upload was already claimed.
+func (dao *PostgresDao) ClaimDirectUpload(ctx context.Context, uploadID, jobID string) error {
+	updateStmt := `UPDATE DirectUploads
+				   SET TranscriptionJobId=$1
+				   WHERE UploadId=$2 AND TranscriptionJobId IS NULL`
+	result, err := dao.db.ExecContext(ctx, updateStmt, jobID, uploadID)
+	if err != nil {
+		return err
+	}
+	if err = expectRowsAffected(result); err != nil {
+		return fmt.Errorf("upload %s was already confirmed: %w", uploadID, models.Conflicted)
+	}
+	return nil
+}
+
+// ReleaseDirectUpload undoes the claim of a direct upload whose transcript couldn't be submitted, so it can be
+// confirmed again
+func (dao *PostgresDao) ReleaseDirectUpload(ctx context.Context, uploadID, jobID string) error {
+	updateStmt := `UPDATE DirectUploads
+				   SET TranscriptionJobId=NULL
+				   WHERE UploadId=$1 AND TranscriptionJobId=$2`
+	result, err := dao.db.ExecContext(ctx, updateStmt, uploadID, jobID)
+	if err != nil {
+		return err
+	}
+	return expectRowsAffected(result)
+}
+
+func (dao *PostgresDao) SessionBelongsToCampaign(ctx context.Context, campaignID, sessionID string) (bool, error) {
+	qs := `SELECT EXISTS(
+			   SELECT 1
+			   FROM Sessions s
+			   JOIN Campaigns c ON c.CampaignKey = s.CampaignKey
+			   WHERE c.CampaignId = $1 AND s.SessionId = $2)`
+	return dao.exists(ctx, qs, campaignID, sessionID)
+}
+
+func (dao *PostgresDao) TranscriptBelongsToSession(ctx context.Context, campaignID, sessionID, jobID string) (bool, error) {
+	qs := `SELECT EXISTS(
+			   SELECT 1
+			   FROM SessionTranscripts t
+			   JOIN Sessions s ON s.SessionKey = t.SessionId
+			   JOIN Campaigns c ON c.CampaignKey = s.CampaignKey
+			   WHERE c.CampaignId = $1 AND s.SessionId = $2 AND t.TranscriptionJobId = $3)`
+	return dao.exists(ctx, qs, campaignID, sessionID, jobID)
+}
+
+// addWebhookDeliveries queues an event of a transcript for every webhook subscription of its campaign that
+// subscribed to the event type
+func addWebhookDeliveries(ctx context.Context, tx *sql.Tx, transcriptKey int, eventType models.WebhookEventType, transcript models.Transcript) error {
+	event := models.WebhookEvent{
+		Type:          eventType,
+		JobID:         transcript.JobID,
+		Status:        transcript.Status,
+		FailureReason: transcript.FailureReason,
+		OccurredAt:    transcript.UpdatedAt,
+	}
+	qs := `SELECT s.SessionId, c.CampaignId, c.CampaignKey
+		   FROM SessionTranscripts t
+		   JOIN Sessions s ON s.SessionKey = t.SessionId
+		   JOIN Campaigns c ON c.CampaignKey = s.CampaignKey
+		   WHERE t.TranscriptKey = $1`
+	campaignKey := 0
+	if err := tx.QueryRowContext(ctx, qs, transcriptKey).Scan(&event.SessionID, &event.CampaignID, &campaignKey); err != nil {
+		return err
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT SubscriptionKey FROM WebhookSubscriptions WHERE CampaignKey=$1 AND EventTypes ? $2`, campaignKey, eventType.String())
+	if err != nil {
+		return err
+	}
+	subscriptionKeys := []int{}
+	for rows.Next() {
+		subscriptionKey := 0
+		if err = rows.Scan(&subscriptionKey); err != nil {
+			rows.Close()
+			return err
+		}
+		subscriptionKeys = append(subscriptionKeys, subscriptionKey)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil || len(subscriptionKeys) == 0 {
+		return err
+	}
+
+	eventID, err := uuid.NewUUID()
+	if err != nil {
+		return err
+	}
+	event.ID = eventID.String()
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	for _, subscriptionKey := range subscriptionKeys {
+		deliveryID, err := uuid.NewUUID()
+		if err != nil {
+			return err
+		}
+		insertStmt := `INSERT INTO WebhookDeliveries(DeliveryId, SubscriptionKey, EventType, Event)
+					   VALUES ($1, $2, $3, $4)`
+		if _, err = tx.ExecContext(ctx, insertStmt, deliveryID.String(), subscriptionKey, eventType.String(), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddWebhookSubscription subscribes a URL to events of a campaign
+func (dao *PostgresDao) AddWebhookSubscription(ctx context.Context, campaignID string, subscription models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	subscriptionID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	eventTypes, err := json.Marshal(webhookEventTypeStrings(subscription.EventTypes))
+	if err != nil {
+		return nil, err
+	}
+	insertStmt := `INSERT INTO WebhookSubscriptions(SubscriptionId, CampaignKey, Url, Secret, EventTypes)
+				   SELECT $1, CampaignKey, $2, $3, $4
+				   FROM Campaigns
+				   WHERE CampaignId=$5
+				   RETURNING CreatedAt`
+	err = dao.db.QueryRowContext(ctx, insertStmt, subscriptionID.String(), subscription.URL, subscription.Secret, eventTypes, campaignID).Scan(&subscription.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.EntityNotFound
+		}
+		return nil, err
+	}
+	subscription.ID = subscriptionID.String()
+	return &subscription, nil
+}
+
+// GetWebhookSubscriptions retrieves the webhook subscriptions of a campaign in the order they were made
+func (dao *PostgresDao) GetWebhookSubscriptions(ctx context.Context, campaignID string) ([]models.WebhookSubscription, error) {
+	qs := `SELECT w.SubscriptionId, w.Url, w.Secret, w.EventTypes, w.CreatedAt
+		   FROM WebhookSubscriptions w
+		   JOIN Campaigns c ON c.CampaignKey = w.CampaignKey
+		   WHERE c.CampaignId = $1
+		   ORDER BY w.SubscriptionKey`
+	rows, err := dao.db.QueryContext(ctx, qs, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []models.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+	return subscriptions, nil
+}
+
+func (dao *PostgresDao) GetWebhookSubscription(ctx context.Context, campaignID, subscriptionID string) (*models.WebhookSubscription, error) {
+	qs := `SELECT w.SubscriptionId, w.Url, w.Secret, w.EventTypes, w.CreatedAt
+		   FROM WebhookSubscriptions w
+		   JOIN Campaigns c ON c.CampaignKey = w.CampaignKey
+		   WHERE c.CampaignId = $1 AND w.SubscriptionId = $2`
+	rows, err := dao.db.QueryContext(ctx, qs, campaignID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, models.EntityNotFound
+	}
+	return scanWebhookSubscription(rows)
+}
+
+// DeleteWebhookSubscription removes a webhook subscription of a campaign along with its delivery log
+func (dao *PostgresDao) DeleteWebhookSubscription(ctx context.Context, campaignID, subscriptionID string) error {
+	tx, err := dao.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	subscriptionKey := 0
+	qs := `SELECT w.SubscriptionKey
+		   FROM WebhookSubscriptions w
+		   JOIN Campaigns c ON c.CampaignKey = w.CampaignKey
+		   WHERE c.CampaignId = $1 AND w.SubscriptionId = $2`
+	if err = tx.QueryRowContext(ctx, qs, campaignID, subscriptionID).Scan(&subscriptionKey); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.EntityNotFound
+		}
+		return err
+	}
+	deleteStmts := []string{
+		`DELETE FROM WebhookDeliveries WHERE SubscriptionKey=$1`,
+		`DELETE FROM WebhookSubscriptions WHERE SubscriptionKey=$1`,
+	}
+	for _, deleteStmt := range deleteStmts {
+		if _, err = tx.ExecContext(ctx, deleteStmt, subscriptionKey); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+var webhookDeliverySortColumns = map[string]string{
+	"created": "d.DeliveryKey",
+}
+
+// GetWebhookDeliveries retrieves a page of the delivery log of a webhook subscription
+func (dao *PostgresDao) GetWebhookDeliveries(ctx context.Context, campaignID, subscriptionID string, page models.PageRequest) (*models.Page[models.WebhookDelivery], error) {
+	column, err := sortColumn(webhookDeliverySortColumns, "created", page.Sort)
+	if err != nil {
+		return nil, err
+	}
+	qs := fmt.Sprintf(`SELECT d.DeliveryId, w.SubscriptionId, d.Event, d.Status, d.Attempts, d.NextAttemptAt, d.ResponseStatus, COALESCE(d.LastError, ''), d.CreatedAt, d.DeliveredAt, CAST(%s AS TEXT)
+		   FROM WebhookDeliveries d
+		   JOIN WebhookSubscriptions w ON w.SubscriptionKey = d.SubscriptionKey
+		   JOIN Campaigns c ON c.CampaignKey = w.CampaignKey
+		   WHERE c.CampaignId = $1 AND w.SubscriptionId = $2`, column)
+	qs, args := pageQuery(qs, []any{campaignID, subscriptionID}, column, "d.DeliveryId", page)
+
+	rows, err := dao.db.QueryContext(ctx, qs, args...)
+	if err != nil {
+		return nil, pageQueryError(err, page)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	cursors := []models.Cursor{}
+	for rows.Next() {
+		cursor := models.Cursor{}
+		delivery, err := scanWebhookDelivery(rows, &cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+		cursor.ID = delivery.ID
+		deliveries = append(deliveries, *delivery)
+		cursors = append(cursors, cursor)
+	}
+	return newPage(deliveries, cursors, page), nil
+}
+
+func (dao *PostgresDao) GetWebhookDelivery(ctx context.Context, campaignID, subscriptionID, deliveryID string) (*models.WebhookDelivery, error) {
+	qs := `SELECT d.DeliveryId, w.SubscriptionId, d.Event, d.Status, d.Attempts, d.NextAttemptAt, d.ResponseStatus, COALESCE(d.LastError, ''), d.CreatedAt, d.DeliveredAt
+		   FROM WebhookDeliveries d
+		   JOIN WebhookSubscriptions w ON w.SubscriptionKey = d.SubscriptionKey
+		   JOIN Campaigns c ON c.CampaignKey = w.CampaignKey
+		   WHERE c.CampaignId = $1 AND w.SubscriptionId = $2 AND d.DeliveryId = $3`
+	rows, err := dao.db.QueryContext(ctx, qs, campaignID, subscriptionID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, models.EntityNotFound
+	}
+	return scanWebhookDelivery(rows)
+}
+
+// AddWebhookDelivery queues an event for a webhook subscription, it is due straight away
+func (dao *PostgresDao) AddWebhookDelivery(ctx context.Context, subscriptionID string, event models.WebhookEvent) (*models.WebhookDelivery, error) {
+	deliveryID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	delivery := models.WebhookDelivery{
+		ID:             deliveryID.String(),
+		SubscriptionID: subscriptionID,
+		Event:          event,
+		Status:         models.DeliveryPending,
+	}
+	insertStmt := `INSERT INTO WebhookDeliveries(DeliveryId, SubscriptionKey, EventType, Event)
+				   SELECT $1, SubscriptionKey, $2, $3
+				   FROM WebhookSubscriptions
+				   WHERE SubscriptionId=$4
+				   RETURNING NextAttemptAt, CreatedAt`
+	err = dao.db.QueryRowContext(ctx, insertStmt, delivery.ID, event.Type.String(), data, subscriptionID).Scan(&delivery.NextAttemptAt, &delivery.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.EntityNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ClaimWebhookDeliveries takes up to limit pending deliveries that are due at now and holds them until
+// leaseUntil, like ClaimOutboxMessages. The attempts of every claimed delivery are counted up.
+func (dao *PostgresDao) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	updateStmt := `UPDATE WebhookDeliveries d
+				   SET Attempts = d.Attempts + 1, NextAttemptAt = $1
+				   FROM WebhookSubscriptions w
+				   WHERE w.SubscriptionKey = d.SubscriptionKey AND d.DeliveryKey IN (
+				       SELECT DeliveryKey FROM WebhookDeliveries
+				       WHERE Status = $2 AND NextAttemptAt <= $3
+				       ORDER BY NextAttemptAt
+				       LIMIT $4
+				       FOR UPDATE SKIP LOCKED)
+				   RETURNING d.DeliveryId, w.SubscriptionId, d.Event, d.Status, d.Attempts, d.NextAttemptAt, d.ResponseStatus, COALESCE(d.LastError, ''), d.CreatedAt, d.DeliveredAt`
+	rows, err := dao.db.QueryContext(ctx, updateStmt, leaseUntil, models.WebhookDeliveryStatus(models.DeliveryPending).String(), now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// UpdateWebhookDelivery records the outcome of an attempt to deliver an event
+func (dao *PostgresDao) UpdateWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	updateStmt := `UPDATE WebhookDeliveries
+				   SET Status=$1, NextAttemptAt=$2, ResponseStatus=$3, LastError=NULLIF($4, ''), DeliveredAt=$5
+				   WHERE DeliveryId=$6`
+	result, err := dao.db.ExecContext(ctx, updateStmt, delivery.Status.String(), delivery.NextAttemptAt, delivery.ResponseStatus, delivery.LastError, delivery.DeliveredAt, delivery.ID)
+	if err != nil {
+		return err
+	}
+	return expectRowsAffected(result)
+}
+
+func (dao *PostgresDao) exists(ctx context.Context, qs string, args ...interface{}) (bool, error) {
+	exists := false
+	if err := dao.db.QueryRowContext(ctx, qs, args...).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// expectRowsAffected returns EntityNotFound when a statement did not touch any rows
+func expectRowsAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.EntityNotFound
+	}
+	return nil
+}
+
+// sortColumn returns the column a list is sorted on, InvalidEntity is returned for fields it can't be sorted by
+func sortColumn(columns map[string]string, defaultField string, sort models.Sort) (string, error) {
+	field := sort.Field
+	if field == "" {
+		field = defaultField
+	}
+	column, ok := columns[field]
+	if !ok {
+		return "", fmt.Errorf("cannot sort by %s: %w", field, models.InvalidEntity)
+	}
+	return column, nil
+}
+
+// pageQuery appends the cursor condition, order and limit of a page to qs. Rows are ordered by the sort column
+// and then by idColumn so a cursor always points between two rows, one more row than the limit is fetched to
+// tell whether another page follows.
+func pageQuery(qs string, args []any, sortColumn, idColumn string, page models.PageRequest) (string, []any) {
+	comparison, direction := ">", "ASC"
+	if page.Sort.Descending {
+		comparison, direction = "<", "DESC"
+	}
+	if page.Cursor != nil {
+		args = append(args, page.Cursor.Value, page.Cursor.ID)
+		qs += fmt.Sprintf(" AND (%s, %s) %s ($%d, $%d)", sortColumn, idColumn, comparison, len(args)-1, len(args))
+	}
+	args = append(args, page.Limit+1)
+	qs += fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT $%d", sortColumn, direction, idColumn, direction, len(args))
+	return qs, args
+}
+
+// pageQueryError reports a cursor whose value can't be compared with the sort column as InvalidEntity, other
+// errors are returned as they are
+func pageQueryError(err error, page models.PageRequest) error {
+	var pqErr *pq.Error
+	if page.Cursor != nil && errors.As(err, &pqErr) && pqErr.Code.Class() == dataExceptionClass {
+		return fmt.Errorf("cursor is not valid: %w", models.InvalidEntity)
+	}
+	return err
+}
+
+// newPage drops the extra row fetched by pageQuery and points the next cursor at the last item kept
+func newPage[T any](items []T, cursors []models.Cursor, page models.PageRequest) *models.Page[T] {
+	if len(items) <= page.Limit {
+		return &models.Page[T]{Items: items}
+	}
+	cursor := cursors[page.Limit-1]
+	cursor.Sort = page.Sort
+	return &models.Page[T]{
+		Items:      items[:page.Limit],
+		NextCursor: &cursor,
+	}
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode
+}
+
+func scanVocabularyTerm(rows *sql.Rows) (*models.VocabularyTerm, error) {
+	term := models.VocabularyTerm{}
+	soundsLike := []byte{}
+	sourceStr := ""
+	if err := rows.Scan(&term.ID, &term.Phrase, &soundsLike, &sourceStr); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(soundsLike, &term.SoundsLike); err != nil {
+		return nil, err
+	}
+	source, err := models.VocabularySourceFromString(sourceStr)
+	if err != nil {
+		return nil, err
+	}
+	term.Source = source
+	return &term, nil
+}
+
+// scanTranscript scans a transcript followed by any extra columns the query selected into extra
+func scanTranscript(rows *sql.Rows, extra ...any) (*models.Transcript, error) {
+	transcript := models.Transcript{}
+	statusStr := ""
+	audioFormatStr := ""
+	jobStateStr := ""
+	dest := append([]any{&transcript.JobID, &transcript.AudioLocation, &audioFormatStr, &transcript.TranscriptLocation, &transcript.SummaryLocation, &statusStr, &transcript.Version, &transcript.AudioSHA256, &transcript.AudioSize, &transcript.Provider, &transcript.Language, &transcript.DetectedLanguage, &jobStateStr, &transcript.FailureReason, &transcript.Attempts, &transcript.CreatedAt, &transcript.UpdatedAt}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	status, err := models.TranscriptStatusFromString(statusStr)
+	if err != nil {
+		return nil, err
+	}
+	audioFormat, err := models.AudioFormatFromString(audioFormatStr)
+	if err != nil {
+		return nil, err
+	}
+	jobState, err := models.JobStateFromString(jobStateStr)
 	if err != nil {
 		return nil, err
 	}
 	transcript.Status = status
 	transcript.AudioFormat = audioFormat
+	transcript.JobState = jobState
 
 	return &transcript, nil
 }
+
+func webhookEventTypeStrings(eventTypes []models.WebhookEventType) []string {
+	strs := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		strs = append(strs, eventType.String())
+	}
+	return strs
+}
+
+func scanWebhookSubscription(rows *sql.Rows) (*models.WebhookSubscription, error) {
+	subscription := models.WebhookSubscription{}
+	eventTypes := []byte{}
+	if err := rows.Scan(&subscription.ID, &subscription.URL, &subscription.Secret, &eventTypes, &subscription.CreatedAt); err != nil {
+		return nil, err
+	}
+	eventTypeStrs := []string{}
+	if err := json.Unmarshal(eventTypes, &eventTypeStrs); err != nil {
+		return nil, err
+	}
+	for _, eventTypeStr := range eventTypeStrs {
+		eventType, err := models.WebhookEventTypeFromString(eventTypeStr)
+		if err != nil {
+			return nil, err
+		}
+		subscription.EventTypes = append(subscription.EventTypes, eventType)
+	}
+	return &subscription, nil
+}
+
+// scanWebhookDelivery scans a delivery followed by any extra columns the query selected into extra
+func scanWebhookDelivery(rows *sql.Rows, extra ...any) (*models.WebhookDelivery, error) {
+	delivery := models.WebhookDelivery{}
+	event := []byte{}
+	statusStr := ""
+	dest := append([]any{&delivery.ID, &delivery.SubscriptionID, &event, &statusStr, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.ResponseStatus, &delivery.LastError, &delivery.CreatedAt, &delivery.DeliveredAt}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(event, &delivery.Event); err != nil {
+		return nil, err
+	}
+	status, err := models.WebhookDeliveryStatusFromString(statusStr)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Status = status
+	return &delivery, nil
+}
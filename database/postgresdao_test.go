@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDbEnv names the connection string of a Postgres database the DAO tests create their tables in.
+// The tests are skipped when it isn't set.
+const testDbEnv = "DRAGONSPEAK_TEST_DB"
+
+// newTestDao creates the service's tables in a schema of their own and returns a dao using it, the schema
+// is dropped when the test ends.
+func newTestDao(t *testing.T) *PostgresDao {
+	connStr := os.Getenv(testDbEnv)
+	if connStr == "" {
+		t.Skipf("%s is not set", testDbEnv)
+	}
+	admin, err := sql.Open("postgres", connStr)
+	require.NoError(t, err)
+	t.Cleanup(func() { admin.Close() })
+
+	schema := "dao_test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	require.NoError(t, err)
+	t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	db, err := sql.Open("postgres", connStr+" search_path="+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	script, err := os.ReadFile("../scripts/dragonspeak-db.sql")
+	require.NoError(t, err)
+	ddl := strings.TrimPrefix(string(script), "use dragonspeak-db;")
+	_, err = db.Exec(ddl)
+	require.NoError(t, err)
+	return &PostgresDao{db: db}
+}
+
+func TestSetTranscriptSpeakersKeepsOutboxMessage(t *testing.T) {
+	dao := newTestDao(t)
+	ctx := context.Background()
+
+	user, err := dao.AddNewUser(ctx, models.User{Handle: "gm", Email: "gm@example.com"})
+	require.NoError(t, err)
+	campaign, err := dao.AddCampaign(ctx, user.ID, models.Campaign{Name: "Curse of Strahd"})
+	require.NoError(t, err)
+	session, err := dao.AddSession(ctx, campaign.ID, models.Session{SessionDate: time.Now(), Title: "Session 1"})
+	require.NoError(t, err)
+	player, err := dao.AddNewPlayer(ctx, campaign.ID, models.Player{Name: "Ireena", Type: models.StandardPlayer})
+	require.NoError(t, err)
+
+	jobID := uuid.NewString()
+	transcript := models.Transcript{
+		JobID:       jobID,
+		AudioFormat: models.MP3,
+		Status:      models.Summarizing,
+		JobState:    models.JobCompleted,
+	}
+	message := models.OutboxMessage{
+		JobID:         jobID,
+		Step:          models.SummarizeStep,
+		NextAttemptAt: time.Now().Add(-time.Minute),
+	}
+	_, err = dao.AddTranscriptToSession(ctx, session.ID, transcript, message)
+	require.NoError(t, err)
+
+	err = dao.SetTranscriptSpeakers(ctx, jobID, []models.SpeakerAssignment{{SpeakerLabel: "spk_0", PlayerID: player.ID}})
+	require.NoError(t, err)
+
+	now := time.Now()
+	claimed, err := dao.ClaimOutboxMessages(ctx, now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, jobID, claimed[0].JobID)
+	assert.Equal(t, models.OutboxStep(models.SummarizeStep), claimed[0].Step)
+}
+
+func TestPageQueryError(t *testing.T) {
+	invalidValue := &pq.Error{Code: "22P02"}
+	cursor := &models.Cursor{Value: "Summarizing", ID: "job-1"}
+	cases := []struct {
+		description   string
+		err           error
+		page          models.PageRequest
+		expectedError error
+	}{
+		{
+			description:   "cursor value the sort column can't be compared with, InvalidEntity returned",
+			err:           invalidValue,
+			page:          models.PageRequest{Cursor: cursor},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "no cursor, error returned as is",
+			err:           invalidValue,
+			expectedError: invalidValue,
+		},
+		{
+			description:   "other database error, error returned as is",
+			err:           &pq.Error{Code: uniqueViolationCode},
+			page:          models.PageRequest{Cursor: cursor},
+			expectedError: &pq.Error{Code: uniqueViolationCode},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := pageQueryError(c.err, c.page)
+			if errors.Is(c.expectedError, models.InvalidEntity) {
+				assert.ErrorIs(t, err, models.InvalidEntity)
+				return
+			}
+			assert.Equal(t, c.expectedError, err)
+		})
+	}
+}
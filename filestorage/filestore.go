@@ -0,0 +1,24 @@
+package filestorage
+
+import (
+	"io"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// Filestore keeps files in buckets under keys. S3Filestore and LocalFilestore behave the same way so one can
+// stand in for the other.
+type Filestore interface {
+	UploadData(bucket, fileKey string, body io.Reader) error
+	DownloadData(bucket, fileKey string, w io.WriterAt) (int64, error)
+	DeleteData(bucket, fileKey string) error
+	StartMultipartUpload(bucket, fileKey string) (string, error)
+	UploadPart(bucket, fileKey, uploadID string, partNumber int, body io.Reader) (string, error)
+	CompleteMultipartUpload(bucket, fileKey, uploadID string, parts []models.UploadPart) error
+	AbortMultipartUpload(bucket, fileKey, uploadID string) error
+	PresignUpload(bucket, fileKey string, constraints models.UploadConstraints, expiry time.Duration) (*models.PresignedURL, error)
+	PresignDownload(bucket, fileKey string, expiry time.Duration) (*models.PresignedURL, error)
+	StatData(bucket, fileKey string) (*models.StoredFile, error)
+	ReadHead(bucket, fileKey string, length int64) ([]byte, error)
+}
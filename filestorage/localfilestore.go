@@ -0,0 +1,544 @@
+package filestorage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+const (
+	// defaultContentType is what S3 reports for files uploaded without a content type
+	defaultContentType = "binary/octet-stream"
+	maxPartNumber      = 10000
+	uploadKeyFile      = "key"
+)
+
+// LocalFilestore keeps files in a directory so the service can run without AWS. It is laid out as
+//
+//	root/objects/<bucket>/<key>          the files
+//	root/metadata/<bucket>/<key>.json    their content type and SHA-256
+//	root/uploads/<bucket>/<uploadID>/    parts of unfinished multipart uploads
+//
+// Every file is written to a temporary file and renamed into place, so a reader never sees half a file.
+// Presigned URLs point back at the store itself, which serves them as an http.Handler mounted at baseURL.
+type LocalFilestore struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+// fileMetadata is what S3 keeps alongside an object that a plain file can't hold
+type fileMetadata struct {
+	ContentType string `json:"contentType"`
+	SHA256      string `json:"sha256"`
+}
+
+// NewLocalFilestore creates a LocalFilestore rooted at root. Presigned URLs are signed with secret, a random
+// one is used when it is empty, which invalidates outstanding URLs whenever the service restarts.
+func NewLocalFilestore(root, baseURL string, secret []byte) (*LocalFilestore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+	return &LocalFilestore{
+		root:    root,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  secret,
+	}, nil
+}
+
+func (f *LocalFilestore) UploadData(bucket, fileKey string, body io.Reader) error {
+	_, err := f.writeObject(bucket, fileKey, body, defaultContentType, nil)
+	return err
+}
+
+func (f *LocalFilestore) DownloadData(bucket, fileKey string, w io.WriterAt) (int64, error) {
+	objectPath, err := f.objectPath(bucket, fileKey)
+	if err != nil {
+		return 0, err
+	}
+	file, err := os.Open(objectPath)
+	if err != nil {
+		return 0, notFoundError(bucket, fileKey, err)
+	}
+	defer file.Close()
+	return io.Copy(io.NewOffsetWriter(w, 0), file)
+}
+
+// DeleteData removes a file from the bucket, deleting a file that doesn't exist is not an error
+func (f *LocalFilestore) DeleteData(bucket, fileKey string) error {
+	objectPath, err := f.objectPath(bucket, fileKey)
+	if err != nil {
+		return err
+	}
+	metadataPath, _ := f.metadataPath(bucket, fileKey)
+	for _, path := range []string{objectPath, metadataPath} {
+		if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartMultipartUpload begins assembling a file from parts and returns the ID the parts are uploaded under
+func (f *LocalFilestore) StartMultipartUpload(bucket, fileKey string) (string, error) {
+	if _, err := f.objectPath(bucket, fileKey); err != nil {
+		return "", err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	uploadID := hex.EncodeToString(id)
+	uploadDir := filepath.Join(f.root, "uploads", bucket, uploadID)
+	if err := os.MkdirAll(uploadDir, 0o755); err != nil {
+		return "", err
+	}
+	// the key is kept with the parts so they can't be completed into a different file
+	if err := writeFileAtomic(filepath.Join(uploadDir, uploadKeyFile), strings.NewReader(fileKey)); err != nil {
+		return "", err
+	}
+	return uploadID, nil
+}
+
+// UploadPart writes one part of a multipart upload and returns its ETag, the quoted MD5 of the part as S3
+// gives. Uploading a part number again replaces it.
+func (f *LocalFilestore) UploadPart(bucket, fileKey, uploadID string, partNumber int, body io.Reader) (string, error) {
+	uploadDir, err := f.uploadDir(bucket, fileKey, uploadID)
+	if err != nil {
+		return "", err
+	}
+	if partNumber < 1 || partNumber > maxPartNumber {
+		return "", fmt.Errorf("part number %d is not between 1 and %d: %w", partNumber, maxPartNumber, models.InvalidEntity)
+	}
+	part, err := createPendingFile(filepath.Join(uploadDir, strconv.Itoa(partNumber)))
+	if err != nil {
+		return "", err
+	}
+	defer part.discard()
+	sum := md5.New()
+	if _, err = io.Copy(io.MultiWriter(part, sum), body); err != nil {
+		return "", err
+	}
+	if err = part.commit(); err != nil {
+		return "", err
+	}
+	return partETag(sum), nil
+}
+
+// CompleteMultipartUpload joins the uploaded parts, in the order given, into the file. Parts have to be listed
+// in ascending order with the ETags they were uploaded with.
+func (f *LocalFilestore) CompleteMultipartUpload(bucket, fileKey, uploadID string, parts []models.UploadPart) error {
+	uploadDir, err := f.uploadDir(bucket, fileKey, uploadID)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("upload %s has no parts: %w", uploadID, models.InvalidEntity)
+	}
+	readers := make([]io.Reader, 0, len(parts))
+	for i, part := range parts {
+		if i > 0 && part.Number <= parts[i-1].Number {
+			return fmt.Errorf("parts of upload %s are not in ascending order: %w", uploadID, models.InvalidEntity)
+		}
+		file, err := os.Open(filepath.Join(uploadDir, strconv.Itoa(part.Number)))
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("part %d of upload %s was not uploaded: %w", part.Number, uploadID, models.InvalidEntity)
+		}
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		sum := md5.New()
+		if _, err = io.Copy(sum, file); err != nil {
+			return err
+		}
+		if partETag(sum) != part.ETag {
+			return fmt.Errorf("part %d of upload %s does not match its ETag: %w", part.Number, uploadID, models.InvalidEntity)
+		}
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		readers = append(readers, file)
+	}
+	if _, err = f.writeObject(bucket, fileKey, io.MultiReader(readers...), defaultContentType, nil); err != nil {
+		return err
+	}
+	return os.RemoveAll(uploadDir)
+}
+
+// AbortMultipartUpload discards a multipart upload along with the parts uploaded so far
+func (f *LocalFilestore) AbortMultipartUpload(bucket, fileKey, uploadID string) error {
+	uploadDir, err := f.uploadDir(bucket, fileKey, uploadID)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(uploadDir)
+}
+
+// PresignUpload returns a URL a client can PUT the file to until the expiry passes. The content type, length
+// and, when given, the SHA-256 are signed so the store rejects uploads that don't match them.
+func (f *LocalFilestore) PresignUpload(bucket, fileKey string, constraints models.UploadConstraints, expiry time.Duration) (*models.PresignedURL, error) {
+	if constraints.SHA256 != "" {
+		if _, err := hex.DecodeString(constraints.SHA256); err != nil {
+			return nil, err
+		}
+	}
+	query := url.Values{}
+	query.Set("contentType", constraints.ContentType)
+	query.Set("length", strconv.FormatInt(constraints.Length, 10))
+	if constraints.SHA256 != "" {
+		query.Set("sha256", strings.ToLower(constraints.SHA256))
+	}
+	return f.presign(http.MethodPut, bucket, fileKey, query, expiry, map[string]string{
+		"Content-Type": constraints.ContentType,
+	})
+}
+
+// PresignDownload returns a URL a client can GET the file from until the expiry passes
+func (f *LocalFilestore) PresignDownload(bucket, fileKey string, expiry time.Duration) (*models.PresignedURL, error) {
+	return f.presign(http.MethodGet, bucket, fileKey, url.Values{}, expiry, map[string]string{})
+}
+
+// StatData describes a file in the bucket, EntityNotFound is returned when there is no such file
+func (f *LocalFilestore) StatData(bucket, fileKey string) (*models.StoredFile, error) {
+	objectPath, err := f.objectPath(bucket, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(objectPath)
+	if err != nil {
+		return nil, notFoundError(bucket, fileKey, err)
+	}
+	metadata := f.readMetadata(bucket, fileKey)
+	return &models.StoredFile{
+		Size:        info.Size(),
+		ContentType: metadata.ContentType,
+		SHA256:      metadata.SHA256,
+	}, nil
+}
+
+// ReadHead returns up to the first length bytes of a file
+func (f *LocalFilestore) ReadHead(bucket, fileKey string, length int64) ([]byte, error) {
+	objectPath, err := f.objectPath(bucket, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(objectPath)
+	if err != nil {
+		return nil, notFoundError(bucket, fileKey, err)
+	}
+	defer file.Close()
+	return io.ReadAll(io.LimitReader(file, length))
+}
+
+// ServeHTTP serves the presigned URLs handed out by the store. Requests are expected with the path after
+// baseURL, for example /<bucket>/<key>, so the handler should be mounted behind http.StripPrefix.
+func (f *LocalFilestore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, fileKey, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	method := r.Method
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || !hmac.Equal([]byte(query.Get("signature")), []byte(f.signature(method, bucket, fileKey, query))) {
+		http.Error(w, "signature does not match", http.StatusForbidden)
+		return
+	}
+	if time.Now().Unix() > expires {
+		http.Error(w, "request has expired", http.StatusForbidden)
+		return
+	}
+
+	switch method {
+	case http.MethodGet:
+		f.serveDownload(w, r, bucket, fileKey)
+	case http.MethodPut:
+		f.serveUpload(w, r, bucket, fileKey, query)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *LocalFilestore) serveDownload(w http.ResponseWriter, r *http.Request, bucket, fileKey string) {
+	objectPath, err := f.objectPath(bucket, fileKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	file, err := os.Open(objectPath)
+	if err != nil {
+		http.Error(w, "file not found", http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", f.readMetadata(bucket, fileKey).ContentType)
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
+
+func (f *LocalFilestore) serveUpload(w http.ResponseWriter, r *http.Request, bucket, fileKey string, query url.Values) {
+	contentType := query.Get("contentType")
+	if r.Header.Get("Content-Type") != contentType {
+		http.Error(w, "Content-Type does not match the signed content type", http.StatusForbidden)
+		return
+	}
+	length, _ := strconv.ParseInt(query.Get("length"), 10, 64)
+	if r.ContentLength != length {
+		http.Error(w, "Content-Length does not match the signed length", http.StatusForbidden)
+		return
+	}
+	// LimitReader keeps a client that lied about its length from writing more than it signed for
+	body := io.LimitReader(r.Body, length+1)
+	expected := &expectedContent{length: length, sha256: query.Get("sha256")}
+	sha, err := f.writeObject(bucket, fileKey, body, contentType, expected.check)
+	if errors.Is(err, models.InvalidEntity) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("ETag", fmt.Sprintf("%q", sha))
+	w.WriteHeader(http.StatusOK)
+}
+
+// expectedContent checks an upload is the length and, when set, SHA-256 it was signed for
+type expectedContent struct {
+	length int64
+	sha256 string
+}
+
+func (e *expectedContent) check(size int64, sha string) error {
+	if size != e.length {
+		return fmt.Errorf("received %d bytes, expected %d: %w", size, e.length, models.InvalidEntity)
+	}
+	if e.sha256 != "" && sha != e.sha256 {
+		return fmt.Errorf("SHA-256 %s does not match the signed %s: %w", sha, e.sha256, models.InvalidEntity)
+	}
+	return nil
+}
+
+func (f *LocalFilestore) presign(method, bucket, fileKey string, query url.Values, expiry time.Duration, headers map[string]string) (*models.PresignedURL, error) {
+	if _, err := f.objectPath(bucket, fileKey); err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(expiry)
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", f.signature(method, bucket, fileKey, query))
+	segments := []string{f.baseURL, url.PathEscape(bucket)}
+	for _, segment := range strings.Split(fileKey, "/") {
+		segments = append(segments, url.PathEscape(segment))
+	}
+	return &models.PresignedURL{
+		URL:       strings.Join(segments, "/") + "?" + query.Encode(),
+		Method:    method,
+		Headers:   headers,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// signature is the HMAC of everything a presigned URL grants, so none of it can be changed by the client
+func (f *LocalFilestore) signature(method, bucket, fileKey string, query url.Values) string {
+	mac := hmac.New(sha256.New, f.secret)
+	for _, value := range []string{method, bucket, fileKey, query.Get("expires"), query.Get("contentType"), query.Get("length"), query.Get("sha256")} {
+		mac.Write([]byte(value))
+		mac.Write([]byte{0})
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// writeObject stores body under the key along with its metadata and returns its SHA-256. When check is given
+// the file is only put in place if check accepts its size and SHA-256.
+func (f *LocalFilestore) writeObject(bucket, fileKey string, body io.Reader, contentType string, check func(size int64, sha string) error) (string, error) {
+	objectPath, err := f.objectPath(bucket, fileKey)
+	if err != nil {
+		return "", err
+	}
+	metadataPath, _ := f.metadataPath(bucket, fileKey)
+	object, err := createPendingFile(objectPath)
+	if err != nil {
+		return "", err
+	}
+	defer object.discard()
+	sum := sha256.New()
+	size, err := io.Copy(io.MultiWriter(object, sum), body)
+	if err != nil {
+		return "", err
+	}
+	sha := hex.EncodeToString(sum.Sum(nil))
+	if check != nil {
+		if err = check(size, sha); err != nil {
+			return "", err
+		}
+	}
+
+	metadata, err := json.Marshal(fileMetadata{ContentType: contentType, SHA256: sha})
+	if err != nil {
+		return "", err
+	}
+	// the metadata goes first so a file is never seen with the metadata of the one it replaced
+	if err = writeFileAtomic(metadataPath, bytes.NewReader(metadata)); err != nil {
+		return "", err
+	}
+	return sha, object.commit()
+}
+
+// readMetadata returns the metadata of a file, files put in the directory by hand have none so they are
+// described the way S3 describes files uploaded without any
+func (f *LocalFilestore) readMetadata(bucket, fileKey string) fileMetadata {
+	metadata := fileMetadata{ContentType: defaultContentType}
+	metadataPath, err := f.metadataPath(bucket, fileKey)
+	if err != nil {
+		return metadata
+	}
+	b, err := os.ReadFile(metadataPath)
+	if err == nil {
+		json.Unmarshal(b, &metadata)
+	}
+	return metadata
+}
+
+func (f *LocalFilestore) objectPath(bucket, fileKey string) (string, error) {
+	return f.keyPath("objects", bucket, fileKey, "")
+}
+
+func (f *LocalFilestore) metadataPath(bucket, fileKey string) (string, error) {
+	return f.keyPath("metadata", bucket, fileKey, ".json")
+}
+
+// uploadDir returns the directory holding the parts of a multipart upload of the key, EntityNotFound is
+// returned when there is no such upload
+func (f *LocalFilestore) uploadDir(bucket, fileKey, uploadID string) (string, error) {
+	if _, err := f.objectPath(bucket, fileKey); err != nil {
+		return "", err
+	}
+	if _, err := hex.DecodeString(uploadID); err != nil || uploadID == "" {
+		return "", fmt.Errorf("upload %s: %w", uploadID, models.EntityNotFound)
+	}
+	uploadDir := filepath.Join(f.root, "uploads", bucket, uploadID)
+	key, err := os.ReadFile(filepath.Join(uploadDir, uploadKeyFile))
+	if err != nil || string(key) != fileKey {
+		return "", fmt.Errorf("upload %s of %s/%s: %w", uploadID, bucket, fileKey, models.EntityNotFound)
+	}
+	return uploadDir, nil
+}
+
+// keyPath maps a key to a path under root/<area>/<bucket>. Keys are "/" separated like S3 keys, but segments
+// that would climb out of the bucket or that a filesystem can't hold are rejected with InvalidEntity.
+func (f *LocalFilestore) keyPath(area, bucket, fileKey, suffix string) (string, error) {
+	if !validSegment(bucket) {
+		return "", fmt.Errorf("bucket %q is not a valid bucket name: %w", bucket, models.InvalidEntity)
+	}
+	segments := strings.Split(fileKey, "/")
+	for _, segment := range segments {
+		if !validSegment(segment) {
+			return "", fmt.Errorf("key %q is not a valid file key: %w", fileKey, models.InvalidEntity)
+		}
+	}
+	// IsLocal also catches names that are special to the operating system, such as NUL on Windows
+	relative := filepath.Join(append([]string{bucket}, segments...)...)
+	if !filepath.IsLocal(relative) {
+		return "", fmt.Errorf("key %q is not a valid file key: %w", fileKey, models.InvalidEntity)
+	}
+	return filepath.Join(f.root, area, relative) + suffix, nil
+}
+
+func validSegment(segment string) bool {
+	return segment != "" && segment != "." && segment != ".." && !strings.ContainsAny(segment, "\\\x00") &&
+		!strings.HasPrefix(segment, pendingFilePrefix)
+}
+
+func notFoundError(bucket, fileKey string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s/%s: %w", bucket, fileKey, models.EntityNotFound)
+	}
+	return err
+}
+
+func partETag(sum hash.Hash) string {
+	return fmt.Sprintf("%q", hex.EncodeToString(sum.Sum(nil)))
+}
+
+// pendingFilePrefix marks files being written, keys can't use it so they never clash with one
+const pendingFilePrefix = ".pending-"
+
+// pendingFile is written beside its destination and renamed over it on commit, so the destination either
+// keeps its old content or has all of the new content
+type pendingFile struct {
+	*os.File
+	path      string
+	committed bool
+}
+
+func createPendingFile(path string) (*pendingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), pendingFilePrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	return &pendingFile{File: file, path: path}, nil
+}
+
+func (p *pendingFile) commit() error {
+	if err := p.Sync(); err != nil {
+		return err
+	}
+	if err := p.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(p.Name(), p.path); err != nil {
+		return err
+	}
+	p.committed = true
+	return nil
+}
+
+// discard removes the pending file unless it was committed, it is safe to defer straight after creating it
+func (p *pendingFile) discard() {
+	if p.committed {
+		return
+	}
+	p.Close()
+	os.Remove(p.Name())
+}
+
+func writeFileAtomic(path string, body io.Reader) error {
+	file, err := createPendingFile(path)
+	if err != nil {
+		return err
+	}
+	defer file.discard()
+	if _, err = io.Copy(file, body); err != nil {
+		return err
+	}
+	return file.commit()
+}
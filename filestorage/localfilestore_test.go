@@ -0,0 +1,348 @@
+package filestorage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBucket = "testBucket"
+
+func newTestLocalFilestore(t *testing.T) *LocalFilestore {
+	store, err := NewLocalFilestore(t.TempDir(), "http://files.test", []byte("test-secret"))
+	if err != nil {
+		t.Fatalf("unexpected error creating the filestore: %s", err)
+	}
+	return store
+}
+
+func TestLocalFilestoreKeys(t *testing.T) {
+	cases := []struct {
+		description   string
+		bucket        string
+		fileKey       string
+		expectedError error
+	}{
+		{
+			description: "plain key stored",
+			bucket:      testBucket,
+			fileKey:     "audio-abc",
+		},
+		{
+			description: "key with directories stored under them",
+			bucket:      testBucket,
+			fileKey:     "sessions/abc/audio",
+		},
+		{
+			description:   "key climbing out of the bucket, InvalidEntity returned",
+			bucket:        testBucket,
+			fileKey:       "../../etc/passwd",
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "key climbing out part way through, InvalidEntity returned",
+			bucket:        testBucket,
+			fileKey:       "sessions/../../other/audio",
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "absolute key, InvalidEntity returned",
+			bucket:        testBucket,
+			fileKey:       "/etc/passwd",
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "key with a backslash, InvalidEntity returned",
+			bucket:        testBucket,
+			fileKey:       "..\\audio",
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "empty key, InvalidEntity returned",
+			bucket:        testBucket,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "bucket climbing out of the root, InvalidEntity returned",
+			bucket:        "..",
+			fileKey:       "audio",
+			expectedError: models.InvalidEntity,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			store := newTestLocalFilestore(t)
+
+			err := store.UploadData(c.bucket, c.fileKey, strings.NewReader("content"))
+			if c.expectedError != nil {
+				assert.ErrorIs(t, err, c.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			w := aws.NewWriteAtBuffer([]byte{})
+			n, err := store.DownloadData(c.bucket, c.fileKey, w)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(7), n)
+			assert.Equal(t, "content", string(w.Bytes()))
+		})
+	}
+}
+
+func TestLocalFilestoreFiles(t *testing.T) {
+	store := newTestLocalFilestore(t)
+
+	_, err := store.DownloadData(testBucket, "audio", aws.NewWriteAtBuffer([]byte{}))
+	assert.ErrorIs(t, err, models.EntityNotFound)
+	_, err = store.StatData(testBucket, "audio")
+	assert.ErrorIs(t, err, models.EntityNotFound)
+	_, err = store.ReadHead(testBucket, "audio", 4)
+	assert.ErrorIs(t, err, models.EntityNotFound)
+
+	assert.NoError(t, store.UploadData(testBucket, "audio", strings.NewReader("first")))
+	assert.NoError(t, store.UploadData(testBucket, "audio", strings.NewReader("replaced")))
+	sum := sha256.Sum256([]byte("replaced"))
+	file, err := store.StatData(testBucket, "audio")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.StoredFile{Size: 8, ContentType: defaultContentType, SHA256: hex.EncodeToString(sum[:])}, file)
+	head, err := store.ReadHead(testBucket, "audio", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "repl", string(head))
+
+	entries, err := os.ReadDir(filepath.Join(store.root, "objects", testBucket))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "no pending files should be left behind")
+
+	assert.NoError(t, store.DeleteData(testBucket, "audio"))
+	assert.NoError(t, store.DeleteData(testBucket, "audio"), "deleting a missing file is not an error")
+	_, err = store.StatData(testBucket, "audio")
+	assert.ErrorIs(t, err, models.EntityNotFound)
+}
+
+func TestLocalFilestoreMultipartUpload(t *testing.T) {
+	cases := []struct {
+		description     string
+		parts           func(etags []string) []models.UploadPart
+		expectedContent string
+		expectedError   error
+	}{
+		{
+			description: "parts joined in order",
+			parts: func(etags []string) []models.UploadPart {
+				return []models.UploadPart{{Number: 1, ETag: etags[0]}, {Number: 2, ETag: etags[1]}}
+			},
+			expectedContent: "firstsecond",
+		},
+		{
+			description: "part with the wrong ETag, InvalidEntity returned",
+			parts: func(etags []string) []models.UploadPart {
+				return []models.UploadPart{{Number: 1, ETag: etags[1]}, {Number: 2, ETag: etags[1]}}
+			},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description: "parts out of order, InvalidEntity returned",
+			parts: func(etags []string) []models.UploadPart {
+				return []models.UploadPart{{Number: 2, ETag: etags[1]}, {Number: 1, ETag: etags[0]}}
+			},
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description: "part never uploaded, InvalidEntity returned",
+			parts: func(etags []string) []models.UploadPart {
+				return []models.UploadPart{{Number: 1, ETag: etags[0]}, {Number: 3, ETag: etags[1]}}
+			},
+			expectedError: models.InvalidEntity,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			store := newTestLocalFilestore(t)
+			uploadID, err := store.StartMultipartUpload(testBucket, "audio")
+			assert.NoError(t, err)
+			etags := []string{}
+			for i, part := range []string{"first", "second"} {
+				etag, err := store.UploadPart(testBucket, "audio", uploadID, i+1, strings.NewReader(part))
+				assert.NoError(t, err)
+				etags = append(etags, etag)
+			}
+
+			err = store.CompleteMultipartUpload(testBucket, "audio", uploadID, c.parts(etags))
+			if c.expectedError != nil {
+				assert.ErrorIs(t, err, c.expectedError)
+				_, err = store.StatData(testBucket, "audio")
+				assert.ErrorIs(t, err, models.EntityNotFound)
+				return
+			}
+			assert.NoError(t, err)
+			w := aws.NewWriteAtBuffer([]byte{})
+			_, err = store.DownloadData(testBucket, "audio", w)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedContent, string(w.Bytes()))
+			_, err = store.UploadPart(testBucket, "audio", uploadID, 3, strings.NewReader("late"))
+			assert.ErrorIs(t, err, models.EntityNotFound, "a completed upload should be gone")
+		})
+	}
+}
+
+func TestLocalFilestoreMultipartUploadOfAnotherKey(t *testing.T) {
+	store := newTestLocalFilestore(t)
+	uploadID, err := store.StartMultipartUpload(testBucket, "audio")
+	assert.NoError(t, err)
+
+	_, err = store.UploadPart(testBucket, "other", uploadID, 1, strings.NewReader("part"))
+	assert.ErrorIs(t, err, models.EntityNotFound)
+	assert.NoError(t, store.AbortMultipartUpload(testBucket, "audio", uploadID))
+	_, err = store.UploadPart(testBucket, "audio", uploadID, 1, strings.NewReader("part"))
+	assert.ErrorIs(t, err, models.EntityNotFound)
+}
+
+func TestLocalFilestorePresignedUpload(t *testing.T) {
+	content := "ID3audio"
+	sum := sha256.Sum256([]byte(content))
+	contentSHA256 := hex.EncodeToString(sum[:])
+	cases := []struct {
+		description        string
+		fileKey            string
+		constraints        models.UploadConstraints
+		expiry             time.Duration
+		body               string
+		contentType        string
+		tamper             func(url string) string
+		expectedStatusCode int
+	}{
+		{
+			description:        "upload matching the constraints stored",
+			fileKey:            "sessions/abc/audio",
+			constraints:        models.UploadConstraints{ContentType: "audio/mpeg", Length: 8, SHA256: contentSHA256},
+			expiry:             time.Minute,
+			body:               content,
+			contentType:        "audio/mpeg",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "upload without a signed SHA-256 stored",
+			fileKey:            "audio",
+			constraints:        models.UploadConstraints{ContentType: "audio/mpeg", Length: 8},
+			expiry:             time.Minute,
+			body:               content,
+			contentType:        "audio/mpeg",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "content type differs from the signed one, Forbidden returned",
+			fileKey:            "audio",
+			constraints:        models.UploadConstraints{ContentType: "audio/mpeg", Length: 8},
+			expiry:             time.Minute,
+			body:               content,
+			contentType:        "audio/ogg",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			description:        "length differs from the signed one, Forbidden returned",
+			fileKey:            "audio",
+			constraints:        models.UploadConstraints{ContentType: "audio/mpeg", Length: 100},
+			expiry:             time.Minute,
+			body:               content,
+			contentType:        "audio/mpeg",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			description:        "content differs from the signed SHA-256, Bad Request returned",
+			fileKey:            "audio",
+			constraints:        models.UploadConstraints{ContentType: "audio/mpeg", Length: 8, SHA256: contentSHA256},
+			expiry:             time.Minute,
+			body:               "ID3other",
+			contentType:        "audio/mpeg",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:        "url has expired, Forbidden returned",
+			fileKey:            "audio",
+			constraints:        models.UploadConstraints{ContentType: "audio/mpeg", Length: 8},
+			expiry:             -time.Minute,
+			body:               content,
+			contentType:        "audio/mpeg",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			description:        "signed length was changed, Forbidden returned",
+			fileKey:            "audio",
+			constraints:        models.UploadConstraints{ContentType: "audio/mpeg", Length: 8},
+			expiry:             time.Minute,
+			body:               content + "more",
+			contentType:        "audio/mpeg",
+			tamper:             func(url string) string { return strings.Replace(url, "length=8", "length=12", 1) },
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			description:        "url was moved to another key, Forbidden returned",
+			fileKey:            "audio",
+			constraints:        models.UploadConstraints{ContentType: "audio/mpeg", Length: 8},
+			expiry:             time.Minute,
+			body:               content,
+			contentType:        "audio/mpeg",
+			tamper:             func(url string) string { return strings.Replace(url, "/audio?", "/other?", 1) },
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			store := newTestLocalFilestore(t)
+			presigned, err := store.PresignUpload(testBucket, c.fileKey, c.constraints, c.expiry)
+			assert.NoError(t, err)
+			assert.Equal(t, http.MethodPut, presigned.Method)
+			url := presigned.URL
+			if c.tamper != nil {
+				url = c.tamper(url)
+			}
+
+			req := httptest.NewRequest(presigned.Method, strings.TrimPrefix(url, "http://files.test"), strings.NewReader(c.body))
+			req.Header.Set("Content-Type", c.contentType)
+			w := httptest.NewRecorder()
+			store.ServeHTTP(w, req)
+
+			assert.Equal(t, c.expectedStatusCode, w.Code)
+			file, err := store.StatData(testBucket, c.fileKey)
+			if c.expectedStatusCode != http.StatusOK {
+				assert.ErrorIs(t, err, models.EntityNotFound, "a rejected upload should not be stored")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &models.StoredFile{Size: 8, ContentType: "audio/mpeg", SHA256: contentSHA256}, file)
+		})
+	}
+}
+
+func TestLocalFilestorePresignedDownload(t *testing.T) {
+	store := newTestLocalFilestore(t)
+	presigned, err := store.PresignUpload(testBucket, "audio", models.UploadConstraints{ContentType: "audio/mpeg", Length: 8}, time.Minute)
+	assert.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPut, strings.TrimPrefix(presigned.URL, "http://files.test"), strings.NewReader("ID3audio"))
+	req.Header.Set("Content-Type", "audio/mpeg")
+	store.ServeHTTP(httptest.NewRecorder(), req)
+
+	download, err := store.PresignDownload(testBucket, "audio", time.Minute)
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	store.ServeHTTP(w, httptest.NewRequest(http.MethodGet, strings.TrimPrefix(download.URL, "http://files.test"), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ID3audio", w.Body.String())
+	assert.Equal(t, "audio/mpeg", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	store.ServeHTTP(w, httptest.NewRequest(http.MethodPut, strings.TrimPrefix(download.URL, "http://files.test"), strings.NewReader("replaced")))
+	assert.Equal(t, http.StatusForbidden, w.Code, "a download url should not allow uploads")
+}
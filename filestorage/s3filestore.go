@@ -1,10 +1,19 @@
 package filestorage
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
-	"path/filepath"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -12,6 +21,7 @@ import (
 
 // Define a struct to hold the S3 uploader
 type S3Filestore struct {
+	client     *s3.S3
 	uploader   *s3manager.Uploader
 	downloader *s3manager.Downloader
 }
@@ -19,6 +29,7 @@ type S3Filestore struct {
 // NewS3Uploader creates a new S3 Uploader instance
 func NewS3Filestore(sess *session.Session) *S3Filestore {
 	return &S3Filestore{
+		client:     s3.New(sess),
 		uploader:   s3manager.NewUploader(sess),
 		downloader: s3manager.NewDownloader(sess),
 	}
@@ -27,13 +38,14 @@ func NewS3Filestore(sess *session.Session) *S3Filestore {
 func (f *S3Filestore) UploadData(bucket, fileKey string, body io.Reader) error {
 	_, err := f.uploader.Upload(&s3manager.UploadInput{
 		Bucket: aws.String(bucket),
-		Key:    aws.String(filepath.Base(fileKey)),
+		Key:    aws.String(fileKey),
 		Body:   body,
 	})
 
 	return err
 }
 
+// DownloadData writes a file in the bucket to w, EntityNotFound is returned when there is no such file
 func (f *S3Filestore) DownloadData(bucket, fileKey string, w io.WriterAt) (int64, error) {
 
 	// Download the item from the bucket. If an error occurs, log it and exit.
@@ -43,8 +55,189 @@ func (f *S3Filestore) DownloadData(bucket, fileKey string, w io.WriterAt) (int64
 			Key:    aws.String(fileKey),
 		})
 	if err != nil {
-		return 0, err
+		return 0, s3NotFoundError(bucket, fileKey, err)
 	}
 
 	return numBytes, nil
 }
+
+// DeleteData removes a file from the bucket, deleting a file that doesn't exist is not an error
+func (f *S3Filestore) DeleteData(bucket, fileKey string) error {
+	_, err := f.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(fileKey),
+	})
+	return err
+}
+
+// StartMultipartUpload begins assembling a file from parts and returns the ID the parts are uploaded under
+func (f *S3Filestore) StartMultipartUpload(bucket, fileKey string) (string, error) {
+	output, err := f.client.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(fileKey),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(output.UploadId), nil
+}
+
+// UploadPart writes one part of a multipart upload and returns its ETag. Uploading a part number again replaces
+// it. The part is buffered to a temporary file first since S3 needs to know its length and may retry the request.
+func (f *S3Filestore) UploadPart(bucket, fileKey, uploadID string, partNumber int, body io.Reader) (string, error) {
+	buffer, err := os.CreateTemp("", "upload-part-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(buffer.Name())
+	defer buffer.Close()
+	if _, err = io.Copy(buffer, body); err != nil {
+		return "", err
+	}
+	if _, err = buffer.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	output, err := f.client.UploadPart(&s3.UploadPartInput{
+		Bucket:     aws.String(bucket),
+		Key:        aws.String(fileKey),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int64(int64(partNumber)),
+		Body:       buffer,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(output.ETag), nil
+}
+
+// CompleteMultipartUpload joins the uploaded parts, in the order given, into the file
+func (f *S3Filestore) CompleteMultipartUpload(bucket, fileKey, uploadID string, parts []models.UploadPart) error {
+	completedParts := make([]*s3.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completedParts = append(completedParts, &s3.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int64(int64(part.Number)),
+		})
+	}
+	_, err := f.client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(fileKey),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completedParts},
+	})
+	return err
+}
+
+// AbortMultipartUpload discards a multipart upload along with the parts uploaded so far
+func (f *S3Filestore) AbortMultipartUpload(bucket, fileKey, uploadID string) error {
+	_, err := f.client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(fileKey),
+		UploadId: aws.String(uploadID),
+	})
+	return err
+}
+
+// PresignUpload returns a URL a client can PUT the file to until the expiry passes. The content type, length
+// and, when given, the SHA-256 are signed so S3 rejects uploads that don't match them.
+func (f *S3Filestore) PresignUpload(bucket, fileKey string, constraints models.UploadConstraints, expiry time.Duration) (*models.PresignedURL, error) {
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(bucket),
+		Key:           aws.String(fileKey),
+		ContentType:   aws.String(constraints.ContentType),
+		ContentLength: aws.Int64(constraints.Length),
+	}
+	headers := map[string]string{
+		"Content-Type": constraints.ContentType,
+	}
+	if constraints.SHA256 != "" {
+		sum, err := hex.DecodeString(constraints.SHA256)
+		if err != nil {
+			return nil, err
+		}
+		checksum := base64.StdEncoding.EncodeToString(sum)
+		input.ChecksumSHA256 = aws.String(checksum)
+		headers["x-amz-checksum-sha256"] = checksum
+	}
+	request, _ := f.client.PutObjectRequest(input)
+	url, err := request.Presign(expiry)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PresignedURL{
+		URL:       url,
+		Method:    http.MethodPut,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(expiry),
+	}, nil
+}
+
+// PresignDownload returns a URL a client can GET the file from until the expiry passes
+func (f *S3Filestore) PresignDownload(bucket, fileKey string, expiry time.Duration) (*models.PresignedURL, error) {
+	request, _ := f.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(fileKey),
+	})
+	url, err := request.Presign(expiry)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PresignedURL{
+		URL:       url,
+		Method:    http.MethodGet,
+		Headers:   map[string]string{},
+		ExpiresAt: time.Now().Add(expiry),
+	}, nil
+}
+
+// StatData describes a file in the bucket, EntityNotFound is returned when there is no such file
+func (f *S3Filestore) StatData(bucket, fileKey string) (*models.StoredFile, error) {
+	output, err := f.client.HeadObject(&s3.HeadObjectInput{
+		Bucket:       aws.String(bucket),
+		Key:          aws.String(fileKey),
+		ChecksumMode: aws.String(s3.ChecksumModeEnabled),
+	})
+	if err != nil {
+		return nil, s3NotFoundError(bucket, fileKey, err)
+	}
+	file := &models.StoredFile{
+		Size:        aws.Int64Value(output.ContentLength),
+		ContentType: aws.StringValue(output.ContentType),
+	}
+	if output.ChecksumSHA256 != nil {
+		sum, err := base64.StdEncoding.DecodeString(*output.ChecksumSHA256)
+		if err == nil {
+			file.SHA256 = hex.EncodeToString(sum)
+		}
+	}
+	return file, nil
+}
+
+// ReadHead returns up to the first length bytes of a file
+func (f *S3Filestore) ReadHead(bucket, fileKey string, length int64) ([]byte, error) {
+	output, err := f.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(fileKey),
+		Range:  aws.String(fmt.Sprintf("bytes=0-%d", length-1)),
+	})
+	if err != nil {
+		return nil, s3NotFoundError(bucket, fileKey, err)
+	}
+	defer output.Body.Close()
+	return io.ReadAll(io.LimitReader(output.Body, length))
+}
+
+// s3NotFoundError returns EntityNotFound for the error S3 answers a request for a missing file with, which is
+// NoSuchKey for a read and a bare 404 for a head
+func s3NotFoundError(bucket, fileKey string, err error) error {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && (awsErr.Code() == s3.ErrCodeNoSuchKey || awsErr.Code() == "NotFound") {
+		return fmt.Errorf("%s/%s: %w", bucket, fileKey, models.EntityNotFound)
+	}
+	var requestErr awserr.RequestFailure
+	if errors.As(err, &requestErr) && requestErr.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%s/%s: %w", bucket, fileKey, models.EntityNotFound)
+	}
+	return err
+}
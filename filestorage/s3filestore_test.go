@@ -0,0 +1,49 @@
+package filestorage
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3NotFoundError(t *testing.T) {
+	accessDenied := awserr.NewRequestFailure(awserr.New("AccessDenied", "Access Denied", nil), http.StatusForbidden, "req-1")
+	cases := []struct {
+		description   string
+		err           error
+		expectedError error
+	}{
+		{
+			description:   "download of a missing key, EntityNotFound returned",
+			err:           awserr.NewRequestFailure(awserr.New(s3.ErrCodeNoSuchKey, "The specified key does not exist.", nil), http.StatusNotFound, "req-1"),
+			expectedError: models.EntityNotFound,
+		},
+		{
+			description:   "head of a missing key, EntityNotFound returned",
+			err:           awserr.NewRequestFailure(awserr.New("NotFound", "Not Found", nil), http.StatusNotFound, "req-1"),
+			expectedError: models.EntityNotFound,
+		},
+		{
+			description:   "missing key without a status, EntityNotFound returned",
+			err:           awserr.New(s3.ErrCodeNoSuchKey, "The specified key does not exist.", nil),
+			expectedError: models.EntityNotFound,
+		},
+		{
+			description:   "access denied, error returned as it is",
+			err:           accessDenied,
+			expectedError: accessDenied,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := s3NotFoundError(testBucket, "transcript-1", c.err)
+			assert.True(t, errors.Is(err, c.expectedError), "expected %v got %v", c.expectedError, err)
+		})
+	}
+}
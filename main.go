@@ -1,12 +1,24 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/EdgarH78/dragonspeak-service/app"
+	"github.com/EdgarH78/dragonspeak-service/auth"
 	"github.com/EdgarH78/dragonspeak-service/database"
 	"github.com/EdgarH78/dragonspeak-service/filestorage"
 	"github.com/EdgarH78/dragonspeak-service/presentation"
+	"github.com/EdgarH78/dragonspeak-service/summarization"
 	"github.com/EdgarH78/dragonspeak-service/transcription"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -14,16 +26,140 @@ import (
 )
 
 var (
-	awsRegion  = os.Getenv("AWS_REGION")
-	s3Bucket   = os.Getenv("S3_BUCKET")
-	openAiKey  = os.Getenv("OPEN_AI_KEY")
-	dbUser     = os.Getenv("DB_USER")
-	dbPassword = os.Getenv("DB_PASSWORD")
-	dbHost     = os.Getenv("DB_HOST")
-	dbPort     = os.Getenv("DB_PORT")
-	dbName     = os.Getenv("DB_NAME")
+	awsRegion   = os.Getenv("AWS_REGION")
+	s3Bucket    = os.Getenv("S3_BUCKET")
+	openAiKey   = os.Getenv("OPEN_AI_KEY")
+	openAiUrl   = os.Getenv("OPEN_AI_BASE_URL")
+	openAiModel = os.Getenv("OPEN_AI_MODEL")
+	dbUser      = os.Getenv("DB_USER")
+	dbPassword  = os.Getenv("DB_PASSWORD")
+	dbHost      = os.Getenv("DB_HOST")
+	dbPort      = os.Getenv("DB_PORT")
+	dbName      = os.Getenv("DB_NAME")
+
+	transcriptionPollInterval         = os.Getenv("TRANSCRIPTION_POLL_INTERVAL")
+	transcriptionFallbackPollInterval = os.Getenv("TRANSCRIPTION_FALLBACK_POLL_INTERVAL")
+	transcriptionWebhookSecret        = os.Getenv("TRANSCRIPTION_WEBHOOK_SECRET")
+	maxAudioUploadBytes               = os.Getenv("MAX_AUDIO_UPLOAD_BYTES")
+	webhookDispatchInterval           = os.Getenv("WEBHOOK_DISPATCH_INTERVAL")
+
+	transcriptionProvider   = os.Getenv("TRANSCRIPTION_PROVIDER")
+	whisperUrl              = os.Getenv("WHISPER_URL")
+	whisperApiKey           = os.Getenv("WHISPER_API_KEY")
+	whisperModel            = os.Getenv("WHISPER_MODEL")
+	enableFakeTranscription = os.Getenv("ENABLE_FAKE_TRANSCRIPTION")
+
+	localFilestoreDir    = os.Getenv("LOCAL_FILESTORE_DIR")
+	localFilestoreUrl    = os.Getenv("LOCAL_FILESTORE_URL")
+	localFilestoreSecret = os.Getenv("LOCAL_FILESTORE_SECRET")
+
+	authJwksUrl      = os.Getenv("AUTH_JWKS_URL")
+	authHmacSecret   = os.Getenv("AUTH_HMAC_SECRET")
+	authIssuer       = os.Getenv("AUTH_ISSUER")
+	authAudience     = os.Getenv("AUTH_AUDIENCE")
+	authStaticTokens = os.Getenv("AUTH_STATIC_TOKENS")
+)
+
+const (
+	defaultTranscriptionPollInterval         = 30 * time.Second
+	defaultTranscriptionFallbackPollInterval = 15 * time.Minute
+	defaultWebhookDispatchInterval           = 10 * time.Second
+	defaultMaxAudioUploadBytes               = 1 << 30
+	defaultLocalFilestoreUrl                 = "http://localhost:8080/local-files"
+	defaultLocalBucket                       = "dragonspeak"
+	defaultTranscriptionProvider             = "amazon"
+	defaultWhisperModel                      = "whisper-1"
 )
 
+func durationOrDefault(value string, defaultDuration time.Duration) time.Duration {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return defaultDuration
+	}
+	return duration
+}
+
+func int64OrDefault(value string, defaultValue int64) int64 {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return defaultValue
+	}
+	return n
+}
+
+// newAuthenticator picks JWKS, HMAC or static token authentication based on which settings are present
+func newAuthenticator() (auth.Authenticator, error) {
+	if authJwksUrl != "" {
+		return auth.NewJWKSAuthenticator(authJwksUrl, authIssuer, authAudience), nil
+	}
+	if authHmacSecret != "" {
+		return auth.NewHMACAuthenticator([]byte(authHmacSecret), authIssuer, authAudience), nil
+	}
+	if authStaticTokens != "" {
+		tokens, err := auth.ParseStaticTokens(authStaticTokens)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("using static token authentication, this is meant for local development only")
+		return auth.NewStaticTokenAuthenticator(tokens), nil
+	}
+	return nil, errors.New("no authentication configured: set AUTH_JWKS_URL, AUTH_HMAC_SECRET or AUTH_STATIC_TOKENS")
+}
+
+// newFilestore uses a LocalFilestore when LOCAL_FILESTORE_DIR is set and S3 otherwise. The local store serves
+// its presigned URLs itself, so it is mounted on the engine.
+func newFilestore(engine *gin.Engine, sess *session.Session) (filestorage.Filestore, error) {
+	if localFilestoreDir == "" {
+		return filestorage.NewS3Filestore(sess), nil
+	}
+	baseUrl := localFilestoreUrl
+	if baseUrl == "" {
+		baseUrl = defaultLocalFilestoreUrl
+	}
+	parsedUrl, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, err
+	}
+	localFilestore, err := filestorage.NewLocalFilestore(localFilestoreDir, baseUrl, []byte(localFilestoreSecret))
+	if err != nil {
+		return nil, err
+	}
+	mountPath := strings.TrimSuffix(parsedUrl.Path, "/")
+	engine.Any(mountPath+"/*fileKey", gin.WrapH(http.StripPrefix(mountPath, localFilestore)))
+	log.Printf("storing files in %s, this is meant for local development only", localFilestoreDir)
+	return localFilestore, nil
+}
+
+// newTranscriptionProviders registers every transcription provider that is configured. Amazon Transcribe reads
+// the audio straight from S3 so it is left out when files are kept locally. WHISPER_URL points at an OpenAI
+// compatible API such as https://api.openai.com/v1 or a whisper.cpp server started with
+// --inference-path /v1/audio/transcriptions.
+func newTranscriptionProviders(sess *session.Session, filestore filestorage.Filestore, bucket string) (*app.TranscriptionProviders, error) {
+	defaultProvider := strings.ToLower(transcriptionProvider)
+	if defaultProvider == "" {
+		defaultProvider = defaultTranscriptionProvider
+	}
+	providers := app.NewTranscriptionProviders(defaultProvider)
+	if localFilestoreDir == "" {
+		providers.Register("amazon", transcription.NewAmazonTranscription(sess, bucket, filestore))
+	}
+	if whisperUrl != "" {
+		model := whisperModel
+		if model == "" {
+			model = defaultWhisperModel
+		}
+		providers.Register("whisper", transcription.NewWhisperTranscription(whisperUrl, whisperApiKey, model, filestore, bucket))
+	}
+	if enableFakeTranscription == "true" || defaultProvider == "fake" {
+		log.Printf("fake transcription is enabled, this is meant for tests and local development only")
+		providers.Register("fake", transcription.NewFakeTranscription(filestore, bucket, nil))
+	}
+	if !slices.Contains(providers.Names(), defaultProvider) {
+		return nil, fmt.Errorf("transcription provider %q is not configured, configured providers are %v", defaultProvider, providers.Names())
+	}
+	return providers, nil
+}
+
 func main() {
 	sqlConfig := database.SQLConfig{
 		User:         dbUser,
@@ -40,14 +176,49 @@ func main() {
 	sess, err := session.NewSession(&aws.Config{
 		Region: aws.String(awsRegion), // Set your preferred region
 	})
-	s3Filestore := filestorage.NewS3Filestore(sess)
-	amzTranscription := transcription.NewAmazonTranscription(sess, s3Bucket)
-	campaignManager := app.NewCampaignManager(postgresDao)
-	sessionManager := app.NewSessionManager(postgresDao)
-	transciptionManager := app.NewTranscriptionManager(s3Bucket, amzTranscription, s3Filestore, postgresDao, &app.DefaultUUIDProvider{})
+	engine := gin.Default()
+	filestore, err := newFilestore(engine, sess)
+	if err != nil {
+		panic(err)
+	}
+	bucket := s3Bucket
+	if bucket == "" && localFilestoreDir != "" {
+		bucket = defaultLocalBucket
+	}
+	transcriptionProviders, err := newTranscriptionProviders(sess, filestore, bucket)
+	if err != nil {
+		panic(err)
+	}
+	var summarizer summarization.Summarizer = summarization.NewStubSummarizer()
+	if openAiKey != "" {
+		summarizer = summarization.NewOpenAISummarizer(openAiKey, openAiUrl, openAiModel)
+	}
+	transciptionManager := app.NewTranscriptionManager(bucket, transcriptionProviders, filestore, postgresDao, &app.DefaultUUIDProvider{}, summarizer, int64OrDefault(maxAudioUploadBytes, defaultMaxAudioUploadBytes))
+	uploadManager := app.NewUploadManager(bucket, filestore, postgresDao, &app.DefaultUUIDProvider{}, transciptionManager, int64OrDefault(maxAudioUploadBytes, defaultMaxAudioUploadBytes))
+	campaignManager := app.NewCampaignManager(postgresDao, transciptionManager)
+	sessionManager := app.NewSessionManager(postgresDao, transciptionManager)
 	userManager := app.NewUserManager(postgresDao)
+	playerManager := app.NewPlayerManager(postgresDao)
+	characterManager := app.NewCharacterManager(postgresDao)
+	accessManager := app.NewAccessManager(postgresDao)
+	webhookManager := app.NewWebhookManager(postgresDao)
+	authenticator, err := newAuthenticator()
+	if err != nil {
+		panic(err)
+	}
 
-	engine := gin.Default()
-	api := presentation.NewHttpAPI(engine, userManager, campaignManager, sessionManager, transciptionManager)
+	// with the webhook enabled jobs advance on the events providers send, polling only catches the missed ones
+	dispatchInterval := durationOrDefault(transcriptionPollInterval, defaultTranscriptionPollInterval)
+	pollInterval := dispatchInterval
+	if transcriptionWebhookSecret != "" {
+		presentation.NewTranscriptionWebhook(engine, transciptionManager, []byte(transcriptionWebhookSecret))
+		pollInterval = durationOrDefault(transcriptionFallbackPollInterval, defaultTranscriptionFallbackPollInterval)
+	}
+	transcriptionPoller := app.NewTranscriptionPoller(transciptionManager, dispatchInterval, pollInterval)
+	go transcriptionPoller.Run(context.Background())
+	webhookDispatcher := app.NewWebhookDispatcher(webhookManager, durationOrDefault(webhookDispatchInterval, defaultWebhookDispatchInterval))
+	go webhookDispatcher.Run(context.Background())
+
+	api := presentation.NewHttpAPI(engine, userManager, campaignManager, sessionManager, transciptionManager, uploadManager, playerManager, characterManager, webhookManager, authenticator, accessManager)
 	api.Run()
 }
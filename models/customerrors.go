@@ -7,4 +7,7 @@ var (
 	EntityAlreadyExists = errors.New("Entity already exists")
 	InvalidEntity       = errors.New("Entity Is Invalid")
 	Conflicted          = errors.New("Conflicted")
+	Unauthorized        = errors.New("Unauthorized")
+	Forbidden           = errors.New("Forbidden")
+	TooLarge            = errors.New("Too Large")
 )
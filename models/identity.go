@@ -0,0 +1,21 @@
+package models
+
+import "context"
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	UserID string
+}
+
+type identityKey struct{}
+
+// ContextWithIdentity returns a copy of ctx carrying the caller identity
+func ContextWithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the caller identity stored in ctx, if any
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
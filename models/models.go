@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"time"
 )
@@ -12,11 +13,93 @@ type User struct {
 	Email  string
 }
 
-// Campaign represents a campaign in the system.
+type CampaignRole int
+
+const (
+	CampaignOwner CampaignRole = iota
+	CampaignGM
+	CampaignPlayer
+)
+
+var campaignRoleStrings = []string{"Owner", "GM", "Player"}
+
+func (c CampaignRole) String() string {
+	return campaignRoleStrings[c]
+}
+
+// CampaignRoleFromString converts a string to a CampaignRole
+func CampaignRoleFromString(str string) (CampaignRole, error) {
+	for i, s := range campaignRoleStrings {
+		if strings.EqualFold(s, str) {
+			return CampaignRole(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid CampaignRole: %s %w", str, InvalidEntity)
+}
+
+// CampaignAccess is what a request does with a campaign. Any member can read a campaign, only its owner and GM
+// can manage it.
+type CampaignAccess int
+
+const (
+	ReadAccess CampaignAccess = iota
+	ManageAccess
+)
+
+// Campaign represents a campaign in the system. Role is the role of the user the campaign was loaded for.
 type Campaign struct {
 	ID   string
 	Name string
 	Link string
+	// DefaultLanguage is the language the campaign's recordings are transcribed in when a submission doesn't
+	// name one, empty for the service wide default
+	DefaultLanguage string
+	Role            CampaignRole
+	Version         int
+}
+
+// CampaignUpdate holds the fields of a campaign to change, nil fields are left as they are.
+// Version is the version the caller last read, the update is rejected if the campaign changed since.
+type CampaignUpdate struct {
+	Name            *string
+	Link            *string
+	DefaultLanguage *string
+	Version         int
+}
+
+// CampaignInvite lets another user join a campaign as a player. When PlayerID is set the accepting user
+// takes over that existing player, otherwise a new player of PlayerType is created.
+type CampaignInvite struct {
+	Code       string
+	CampaignID string
+	PlayerID   string
+	PlayerType PlayerType
+	ExpiresAt  time.Time
+	AcceptedAt *time.Time
+}
+
+type CharacterStatus int
+
+const (
+	CharacterActive CharacterStatus = iota
+	CharacterDead
+	CharacterRetired
+)
+
+var characterStatusStrings = []string{"Active", "Dead", "Retired"}
+
+func (c CharacterStatus) String() string {
+	return characterStatusStrings[c]
+}
+
+// CharacterStatusFromString converts a string to a CharacterStatus
+func CharacterStatusFromString(str string) (CharacterStatus, error) {
+	for i, s := range characterStatusStrings {
+		if strings.EqualFold(s, str) {
+			return CharacterStatus(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid CharacterStatus: %s %w", str, InvalidEntity)
 }
 
 // Character represents a character in the system.
@@ -25,6 +108,13 @@ type Character struct {
 	ID      string
 	Name    string
 	Link    string
+	Status  CharacterStatus
+}
+
+// CharacterUpdate holds the fields of a character to change, nil fields are left as they are.
+type CharacterUpdate struct {
+	Name *string
+	Link *string
 }
 
 // Session represents a session in the system.
@@ -32,6 +122,15 @@ type Session struct {
 	ID          string
 	SessionDate time.Time
 	Title       string
+	Version     int
+}
+
+// SessionUpdate holds the fields of a session to change, nil fields are left as they are.
+// Version is the version the caller last read, the update is rejected if the session changed since.
+type SessionUpdate struct {
+	SessionDate *time.Time
+	Title       *string
+	Version     int
 }
 
 type PlayerType int
@@ -55,14 +154,33 @@ func PlayerTypeFromString(str string) (PlayerType, error) {
 			return PlayerType(i), nil
 		}
 	}
-	return 0, fmt.Errorf("invalid PlayerType: %s", str)
+	return 0, fmt.Errorf("invalid PlayerType: %s %w", str, InvalidEntity)
 }
 
 // Player represents a player in the system.
 type Player struct {
-	ID   string
-	Name string
-	Type PlayerType // Could be a foreign key to a PlayerType table
+	ID     string
+	Name   string
+	Type   PlayerType // Could be a foreign key to a PlayerType table
+	UserID string     // the user playing, empty until someone accepts an invite for this player
+}
+
+// PlayerUpdate holds the fields of a player to change, nil fields are left as they are.
+type PlayerUpdate struct {
+	Name *string
+	Type *PlayerType
+}
+
+// PlayerAttendance is the number of sessions of a campaign a player attended.
+type PlayerAttendance struct {
+	Player           Player
+	SessionsAttended int
+}
+
+// AttendanceReport summarizes attendance across every session of a campaign.
+type AttendanceReport struct {
+	TotalSessions int
+	Players       []PlayerAttendance
 }
 
 type TranscriptStatus int
@@ -126,4 +244,491 @@ type Transcript struct {
 	TranscriptLocation string
 	SummaryLocation    string
 	Status             TranscriptStatus
+	Version            int
+	// AudioSHA256 is the hex encoded SHA-256 of the uploaded audio and AudioSize its length in bytes
+	AudioSHA256 string
+	AudioSize   int64
+	// Provider names the transcription provider the job was started with
+	Provider string
+	// Language is the language the job was started with, AutoLanguage when the provider identifies it.
+	// DetectedLanguage is the language the provider reported once the job completed.
+	Language         string
+	DetectedLanguage string
+	// JobState is the step the job has reached. FailureReason is why it ended in TranscriptionFailed or
+	// SummarizingFailed, empty otherwise.
+	JobState      JobState
+	FailureReason string
+	// Attempts counts the times the job was run, a retry of a failed job adds one. CreatedAt is when the
+	// transcript was submitted and UpdatedAt when it last changed.
+	Attempts  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// JobState is the step a transcription job has reached. Status is what clients see, JobState records which
+// steps behind it are done so a step that failed can be retried without repeating the ones before it.
+type JobState int
+
+const (
+	JobPendingUpload = iota
+	JobUploaded
+	JobProviderStarted
+	JobCompleted
+	JobFailed
+)
+
+var jobStateStrings = []string{"PendingUpload", "Uploaded", "ProviderStarted", "Completed", "Failed"}
+
+func (j JobState) String() string {
+	return jobStateStrings[j]
+}
+
+func JobStateFromString(str string) (JobState, error) {
+	for i, s := range jobStateStrings {
+		if strings.EqualFold(s, str) {
+			return JobState(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid JobState: %s", str)
+}
+
+// OutboxStep is the step of a transcription job an outbox message is waiting to take.
+type OutboxStep int
+
+const (
+	// StartTranscriptionStep starts the provider job of uploaded audio
+	StartTranscriptionStep = iota
+	// ExpireUploadStep fails a job whose audio upload never completed
+	ExpireUploadStep
+	// SummarizeStep summarizes a transcript again after its summary failed
+	SummarizeStep
+)
+
+var outboxStepStrings = []string{"StartTranscription", "ExpireUpload", "Summarize"}
+
+func (o OutboxStep) String() string {
+	return outboxStepStrings[o]
+}
+
+func OutboxStepFromString(str string) (OutboxStep, error) {
+	for i, s := range outboxStepStrings {
+		if strings.EqualFold(s, str) {
+			return OutboxStep(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid OutboxStep: %s", str)
+}
+
+// OutboxMessage is the next step of a transcription job. It is written in the same transaction as the change
+// to the job that made the step due, so the step is taken even when the service stops right after the change.
+// A job has at most one message.
+type OutboxMessage struct {
+	JobID string
+	Step  OutboxStep
+	// Options are the settings the provider job is started with
+	Options TranscriptionOptions
+	// Attempts counts the times the step was taken, NextAttemptAt is when it is due again
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+// WebhookEventType is the kind of transcript change a webhook subscription is notified of.
+type WebhookEventType int
+
+const (
+	// TranscriptDoneEvent is raised when the provider finished transcribing a transcript
+	TranscriptDoneEvent WebhookEventType = iota
+	// SummaryDoneEvent is raised when the summary of a transcript is ready
+	SummaryDoneEvent
+	// TranscriptFailedEvent is raised when a transcript failed transcribing or summarizing
+	TranscriptFailedEvent
+)
+
+var webhookEventTypeStrings = []string{"transcript.done", "summary.done", "transcript.failed"}
+
+func (w WebhookEventType) String() string {
+	return webhookEventTypeStrings[w]
+}
+
+func WebhookEventTypeFromString(str string) (WebhookEventType, error) {
+	for i, s := range webhookEventTypeStrings {
+		if strings.EqualFold(s, str) {
+			return WebhookEventType(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid WebhookEventType: %s %w", str, InvalidEntity)
+}
+
+// TranscriptEvent returns the webhook event raised by a transcript that moved from the from job state into its
+// current status. A summary that is retried doesn't raise TranscriptDoneEvent again.
+func TranscriptEvent(from JobState, transcript Transcript) (WebhookEventType, bool) {
+	switch transcript.Status {
+	case Summarizing:
+		return TranscriptDoneEvent, from == JobProviderStarted
+	case Done:
+		return SummaryDoneEvent, true
+	case TranscriptionFailed, SummarizingFailed:
+		return TranscriptFailedEvent, true
+	}
+	return 0, false
+}
+
+// WebhookSubscription is a URL of a campaign that is notified of the events it subscribed to. Every
+// notification is signed with the secret.
+type WebhookSubscription struct {
+	ID         string
+	URL        string
+	Secret     string
+	EventTypes []WebhookEventType
+	CreatedAt  time.Time
+}
+
+// WebhookEvent is a change to a transcript as it is sent to webhook subscriptions. ID stays the same across
+// redeliveries, so receivers can drop an event they already handled.
+type WebhookEvent struct {
+	ID            string
+	Type          WebhookEventType
+	CampaignID    string
+	SessionID     string
+	JobID         string
+	Status        TranscriptStatus
+	FailureReason string
+	OccurredAt    time.Time
+}
+
+// WebhookDeliveryStatus is where the delivery of an event to a webhook subscription stands.
+type WebhookDeliveryStatus int
+
+const (
+	// DeliveryPending is a delivery that is waiting for its first or next attempt
+	DeliveryPending WebhookDeliveryStatus = iota
+	// DeliveryDelivered is a delivery the subscription accepted
+	DeliveryDelivered
+	// DeliveryFailed is a delivery that was given up on
+	DeliveryFailed
+)
+
+var webhookDeliveryStatusStrings = []string{"Pending", "Delivered", "Failed"}
+
+func (w WebhookDeliveryStatus) String() string {
+	return webhookDeliveryStatusStrings[w]
+}
+
+func WebhookDeliveryStatusFromString(str string) (WebhookDeliveryStatus, error) {
+	for i, s := range webhookDeliveryStatusStrings {
+		if strings.EqualFold(s, str) {
+			return WebhookDeliveryStatus(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid WebhookDeliveryStatus: %s", str)
+}
+
+// WebhookDelivery is an attempt to notify a webhook subscription of an event, kept as the subscription's
+// delivery log. ResponseStatus is the HTTP status of the last attempt, 0 when it got no response.
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	Event          WebhookEvent
+	Status         WebhookDeliveryStatus
+	// Attempts counts the times the event was sent, NextAttemptAt is when a pending delivery is sent again
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseStatus int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// AudioUpload is an audio recording streamed in to be transcribed. Size is the length the client declared,
+// -1 when it wasn't known up front.
+type AudioUpload struct {
+	Format AudioFormat
+	Body   io.Reader
+	Size   int64
+}
+
+// MinUploadChunkBytes is the smallest chunk a resumable upload accepts other than its last one, the storage
+// behind uploads can't assemble smaller parts.
+const MinUploadChunkBytes = 5 << 20
+
+// StoredAudio is a recording already written to the file store under Location and ready to be transcribed.
+type StoredAudio struct {
+	Location string
+	Format   AudioFormat
+	SHA256   string
+	Size     int64
+}
+
+// UploadPart is a chunk of a resumable upload written to the file store, ETag identifies it when the parts
+// are assembled.
+type UploadPart struct {
+	Number int
+	ETag   string
+}
+
+// ResumableUpload is a recording sent in chunks so a dropped connection only costs the chunk in flight.
+// Offset is the number of bytes received so far and HashState the SHA-256 state over them. Assembled is set
+// once the parts were joined into the audio file and TranscriptJobID once the upload was handed off to be
+// transcribed.
+type ResumableUpload struct {
+	ID              string
+	SessionID       string
+	AudioFormat     AudioFormat
+	Length          int64
+	Offset          int64
+	AudioLocation   string
+	StorageUploadID string
+	Parts           []UploadPart
+	HashState       []byte
+	Options         TranscriptionOptions
+	Assembled       bool
+	TranscriptJobID string
+	CreatedAt       time.Time
+}
+
+// DirectUpload is a recording the client uploads straight to the file store with a presigned URL instead of
+// through the API. It's transcribed once the client confirms the upload finished.
+type DirectUpload struct {
+	ID              string
+	SessionID       string
+	AudioFormat     AudioFormat
+	ContentType     string
+	Length          int64
+	SHA256          string
+	AudioLocation   string
+	Options         TranscriptionOptions
+	UploadURL       *PresignedURL
+	TranscriptJobID string
+	CreatedAt       time.Time
+}
+
+// PresignedURL lets a client read or write a file in the file store directly until ExpiresAt. Headers are part
+// of the signature and have to be sent with the request as they are.
+type PresignedURL struct {
+	URL       string
+	Method    string
+	Headers   map[string]string
+	ExpiresAt time.Time
+}
+
+// UploadConstraints are signed into a presigned upload URL, the file store rejects uploads that don't match
+// them. SHA256 is hex encoded and optional.
+type UploadConstraints struct {
+	ContentType string
+	Length      int64
+	SHA256      string
+}
+
+// StoredFile describes a file in the file store. SHA256 is hex encoded and empty when the store doesn't
+// know the checksum.
+type StoredFile struct {
+	Size        int64
+	ContentType string
+	SHA256      string
+}
+
+// TranscriptFile names one of the files kept for a transcript
+type TranscriptFile int
+
+const (
+	AudioFile TranscriptFile = iota
+	TranscriptDataFile
+	SummaryFile
+)
+
+var transcriptFileStrings = []string{"audio", "transcript", "summary"}
+
+func (t TranscriptFile) String() string {
+	return transcriptFileStrings[t]
+}
+
+func TranscriptFileFromString(str string) (TranscriptFile, error) {
+	for i, s := range transcriptFileStrings {
+		if s == str {
+			return TranscriptFile(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid TranscriptFile: %s", str)
+}
+
+// TranscriptUpdate holds the fields of a transcript to change, nil fields are left as they are.
+// SessionID moves the transcript to another session of the same campaign. Version is the version the
+// caller last read, the update is rejected if the transcript changed since.
+type TranscriptUpdate struct {
+	SessionID *string
+	Version   int
+}
+
+// TranscriptionJobStatus is the state of a job as reported by a transcription provider.
+type TranscriptionJobStatus int
+
+const (
+	TranscriptionJobQueued TranscriptionJobStatus = iota
+	TranscriptionJobInProgress
+	TranscriptionJobFailed
+	TranscriptionJobCompleted
+)
+
+var transcriptionJobStatusStrings = []string{"Queued", "InProgress", "Failed", "Completed"}
+
+func (t TranscriptionJobStatus) String() string {
+	return transcriptionJobStatusStrings[t]
+}
+
+// TranscriptionJobEvent is a job state change a transcription provider notified the service of. JobName is the
+// name the provider job was started under.
+type TranscriptionJobEvent struct {
+	JobName       string
+	Status        TranscriptionJobStatus
+	FailureReason string
+}
+
+// MaxSpeakerLabels is the largest number of speakers a transcription job can distinguish.
+const MaxSpeakerLabels = 30
+
+// TranscriptionOptions are the settings a transcription job is started with.
+type TranscriptionOptions struct {
+	// MaxSpeakers is the number of speakers to distinguish, diarization is disabled when it is 1
+	MaxSpeakers int
+	// Provider names the transcription provider to use, the deployment's default when empty
+	Provider string
+	// Language is the language code of the audio, or AutoLanguage to have the provider identify it. The
+	// campaign's default language is used when it is empty.
+	Language string
+	// Vocabulary lists the names and phrases the provider should recognize in the audio
+	Vocabulary []VocabularyTerm
+}
+
+const (
+	// AutoLanguage asks the transcription provider to identify the language of the audio
+	AutoLanguage = "auto"
+	// DefaultLanguage is the language used when neither the submission nor the campaign names one
+	DefaultLanguage = "en-US"
+)
+
+// supportedLanguages are the language codes audio can be transcribed in
+var supportedLanguages = []string{
+	"da-DK", "de-DE", "en-AU", "en-GB", "en-IN", "en-US", "es-ES", "es-US", "fr-CA", "fr-FR", "it-IT",
+	"ja-JP", "ko-KR", "nl-NL", "pl-PL", "pt-BR", "pt-PT", "ru-RU", "sv-SE", "zh-CN",
+}
+
+// SupportedLanguages returns the language codes audio can be transcribed in
+func SupportedLanguages() []string {
+	return append([]string{}, supportedLanguages...)
+}
+
+// LanguageFromString converts a string to a supported language code or AutoLanguage
+func LanguageFromString(str string) (string, error) {
+	if strings.EqualFold(str, AutoLanguage) {
+		return AutoLanguage, nil
+	}
+	for _, language := range supportedLanguages {
+		if strings.EqualFold(language, str) {
+			return language, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported language: %s %w", str, InvalidEntity)
+}
+
+type VocabularySource int
+
+const (
+	VocabularyCustom VocabularySource = iota
+	VocabularyCampaign
+	VocabularyPlayer
+	VocabularyCharacter
+)
+
+var vocabularySourceStrings = []string{"Custom", "Campaign", "Player", "Character"}
+
+func (v VocabularySource) String() string {
+	return vocabularySourceStrings[v]
+}
+
+// VocabularySourceFromString converts a string to a VocabularySource
+func VocabularySourceFromString(str string) (VocabularySource, error) {
+	for i, s := range vocabularySourceStrings {
+		if strings.EqualFold(s, str) {
+			return VocabularySource(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid VocabularySource: %s %w", str, InvalidEntity)
+}
+
+// VocabularyTerm is a name or phrase of a campaign that speech recognition should pick up, like "Tiamat" or
+// "Neverwinter". Each SoundsLike hint spells out a pronunciation with hyphens between the syllables, for
+// example "tee-ah-mat". Custom terms are managed by the campaign's members and have an ID, the other sources
+// are seeded from the names of the campaign, its players and their characters.
+type VocabularyTerm struct {
+	ID         string
+	Phrase     string
+	SoundsLike []string
+	Source     VocabularySource
+}
+
+// VocabularyTermUpdate holds the fields of a custom vocabulary term to change, nil fields are left as they are.
+type VocabularyTermUpdate struct {
+	Phrase     *string
+	SoundsLike *[]string
+}
+
+// SpeakerAssignment maps a speaker label produced by diarization to a player of the campaign.
+type SpeakerAssignment struct {
+	SpeakerLabel string
+	PlayerID     string
+	PlayerName   string
+}
+
+// TranscriptSegment is a contiguous span of speech by a single speaker.
+type TranscriptSegment struct {
+	StartTime  time.Duration
+	EndTime    time.Duration
+	Speaker    string
+	PlayerID   string
+	PlayerName string
+	Text       string
+	Confidence float64
+}
+
+// SpeakerName is the name of the player assigned to the segment's speaker, or the speaker label when unassigned.
+func (s TranscriptSegment) SpeakerName() string {
+	if s.PlayerName != "" {
+		return s.PlayerName
+	}
+	return s.Speaker
+}
+
+// TranscriptDocument is the provider independent, structured form of a transcript.
+type TranscriptDocument struct {
+	Segments []TranscriptSegment
+	// Language is the language code the provider reports the audio is in, empty when it doesn't say
+	Language string
+}
+
+// Text renders the document as plain text with one line per segment, prefixed by the speaker when known.
+func (d TranscriptDocument) Text() string {
+	lines := []string{}
+	for _, segment := range d.Segments {
+		if speaker := segment.SpeakerName(); speaker != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", speaker, segment.Text))
+		} else {
+			lines = append(lines, segment.Text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// AssignSpeakers sets the player of every segment whose speaker label has been assigned.
+func (d *TranscriptDocument) AssignSpeakers(assignments []SpeakerAssignment) {
+	assignmentsByLabel := map[string]SpeakerAssignment{}
+	for _, assignment := range assignments {
+		assignmentsByLabel[assignment.SpeakerLabel] = assignment
+	}
+	for i, segment := range d.Segments {
+		if assignment, ok := assignmentsByLabel[segment.Speaker]; ok {
+			d.Segments[i].PlayerID = assignment.PlayerID
+			d.Segments[i].PlayerName = assignment.PlayerName
+		}
+	}
 }
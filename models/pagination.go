@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+const (
+	// DefaultPageLimit is the page size used when a list request doesn't ask for one
+	DefaultPageLimit = 50
+	// MaxPageLimit is the largest page a list request can ask for
+	MaxPageLimit = 200
+)
+
+// Cursor marks the last item of a page by the value it was sorted on and its ID, the next page starts after it.
+// Sort is the order of the list the cursor was taken from, it only continues a list in that order.
+type Cursor struct {
+	Value string
+	ID    string
+	Sort  Sort
+}
+
+// Sort orders a list by one of its sortable fields, an empty Field uses the list's default order.
+type Sort struct {
+	Field      string
+	Descending bool
+}
+
+// PageRequest asks for up to Limit items following Cursor, a nil Cursor starts at the first item.
+type PageRequest struct {
+	Limit  int
+	Cursor *Cursor
+	Sort   Sort
+}
+
+// Page is one page of a list, NextCursor is nil on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor *Cursor
+}
+
+// SessionFilter narrows sessions to those held within a date range, nil bounds are left open.
+type SessionFilter struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// TranscriptFilter narrows transcripts to those in Status when it is set.
+type TranscriptFilter struct {
+	Status *TranscriptStatus
+}
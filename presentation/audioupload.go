@@ -0,0 +1,116 @@
+package presentation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// audioFormField is the multipart part holding the audio of a transcription job
+	audioFormField = "audio"
+	// maxFormFieldBytes caps the metadata fields sent along with the audio
+	maxFormFieldBytes = 1024
+)
+
+var errMissingAudioPart = errors.New("multipart body has no audio part")
+
+// optionFormFields are the fields read ahead of the audio part, other parts are skipped unread
+var optionFormFields = map[string]bool{"provider": true, "language": true, "maxSpeakers": true}
+
+// audioContentTypes maps the media types, including common aliases, accepted for each audio format
+var audioContentTypes = map[string]models.AudioFormat{
+	"audio/mpeg":      models.MP3,
+	"audio/mp3":       models.MP3,
+	"audio/mpeg3":     models.MP3,
+	"audio/x-mpeg-3":  models.MP3,
+	"audio/mp4":       models.MP4,
+	"audio/m4a":       models.MP4,
+	"audio/x-m4a":     models.MP4,
+	"video/mp4":       models.MP4,
+	"audio/wav":       models.WAV,
+	"audio/wave":      models.WAV,
+	"audio/x-wav":     models.WAV,
+	"audio/vnd.wave":  models.WAV,
+	"audio/flac":      models.FLAC,
+	"audio/x-flac":    models.FLAC,
+	"audio/amr":       models.AMR,
+	"audio/amr-wb":    models.AMR,
+	"audio/ogg":       models.OGG,
+	"audio/opus":      models.OGG,
+	"application/ogg": models.OGG,
+	"audio/webm":      models.WebM,
+	"video/webm":      models.WebM,
+}
+
+// supportedContentTypes lists one media type per audio format for error messages
+var supportedContentTypes = []string{"audio/mpeg", "audio/mp4", "audio/wav", "audio/flac", "audio/amr", "audio/ogg", "audio/webm"}
+
+// contentTypeToAudioType returns the audio format of a media type, parameters such as codecs are ignored
+func contentTypeToAudioType(contentType string) (models.AudioFormat, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return 0, fmt.Errorf("content type %q: %w", contentType, models.InvalidEntity)
+	}
+	audioFormat, ok := audioContentTypes[mediaType]
+	if !ok {
+		return 0, fmt.Errorf("content type %q is not audio: %w", contentType, models.InvalidEntity)
+	}
+	return audioFormat, nil
+}
+
+func unsupportedContentTypeResponse(contentType string) ErrorResponse {
+	return ErrorResponse{
+		ErrorMessage: fmt.Sprintf("Content-Type %q is not supported. Supported types are %s", contentType, strings.Join(supportedContentTypes, ", ")),
+	}
+}
+
+// formFieldOrQuery returns a field sent ahead of the audio in a multipart upload, falling back on the query
+// parameter of the same name
+func formFieldOrQuery(c *gin.Context, formFields map[string]string, name string) string {
+	if value, ok := formFields[name]; ok {
+		return value
+	}
+	return c.Query(name)
+}
+
+// readMultipartAudio reads the form fields sent ahead of the audio part of a multipart upload and returns the
+// audio part unread so it can be streamed to storage. Metadata has to come before the audio, anything after it
+// is ignored, as are fields that aren't transcription options.
+func readMultipartAudio(r *http.Request) (*multipart.Part, map[string]string, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := map[string]string{}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errMissingAudioPart
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if part.FormName() == audioFormField {
+			return part, fields, nil
+		}
+		if !optionFormFields[part.FormName()] {
+			continue
+		}
+		value, err := io.ReadAll(io.LimitReader(part, maxFormFieldBytes+1))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(value) > maxFormFieldBytes {
+			return nil, nil, fmt.Errorf("form field %s is longer than %d bytes", part.FormName(), maxFormFieldBytes)
+		}
+		fields[part.FormName()] = string(value)
+	}
+}
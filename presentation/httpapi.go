@@ -6,6 +6,8 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/EdgarH78/dragonspeak-service/models"
@@ -40,28 +42,206 @@ func UserResponseFromUser(user *models.User) UserResponse {
 }
 
 type CreateCampaignRequest struct {
-	Name string `json:"name"`
-	Link string `json:"link"`
+	Name            string `json:"name"`
+	Link            string `json:"link"`
+	DefaultLanguage string `json:"defaultLanguage"`
 }
 
 func (c CreateCampaignRequest) toCampaign() models.Campaign {
 	return models.Campaign{
-		Name: c.Name,
-		Link: c.Link,
+		Name:            c.Name,
+		Link:            c.Link,
+		DefaultLanguage: c.DefaultLanguage,
 	}
 }
 
 type CampaignResponse struct {
-	Name string `json:"name"`
-	Link string `json:"link"`
-	ID   string `json:"id"`
+	Name            string `json:"name"`
+	Link            string `json:"link"`
+	DefaultLanguage string `json:"defaultLanguage,omitempty"`
+	ID              string `json:"id"`
+	Role            string `json:"role"`
 }
 
 func CampaignResponseFromCampaign(campaign *models.Campaign) CampaignResponse {
 	return CampaignResponse{
-		Name: campaign.Name,
-		Link: campaign.Link,
-		ID:   campaign.ID,
+		Name:            campaign.Name,
+		Link:            campaign.Link,
+		DefaultLanguage: campaign.DefaultLanguage,
+		ID:              campaign.ID,
+		Role:            campaign.Role.String(),
+	}
+}
+
+type UpdateCampaignRequest struct {
+	Name            *string `json:"name"`
+	Link            *string `json:"link"`
+	DefaultLanguage *string `json:"defaultLanguage"`
+}
+
+func (u UpdateCampaignRequest) toCampaignUpdate(version int) models.CampaignUpdate {
+	return models.CampaignUpdate{
+		Name:            u.Name,
+		Link:            u.Link,
+		DefaultLanguage: u.DefaultLanguage,
+		Version:         version,
+	}
+}
+
+type CreateVocabularyTermRequest struct {
+	Phrase     string   `json:"phrase"`
+	SoundsLike []string `json:"soundsLike"`
+}
+
+func (c CreateVocabularyTermRequest) toVocabularyTerm() models.VocabularyTerm {
+	return models.VocabularyTerm{
+		Phrase:     c.Phrase,
+		SoundsLike: c.SoundsLike,
+	}
+}
+
+type UpdateVocabularyTermRequest struct {
+	Phrase     *string   `json:"phrase"`
+	SoundsLike *[]string `json:"soundsLike"`
+}
+
+func (u UpdateVocabularyTermRequest) toVocabularyTermUpdate() models.VocabularyTermUpdate {
+	return models.VocabularyTermUpdate{
+		Phrase:     u.Phrase,
+		SoundsLike: u.SoundsLike,
+	}
+}
+
+type VocabularyTermResponse struct {
+	ID         string   `json:"id,omitempty"`
+	Phrase     string   `json:"phrase"`
+	SoundsLike []string `json:"soundsLike"`
+	Source     string   `json:"source"`
+}
+
+func VocabularyTermResponseFromTerm(term *models.VocabularyTerm) VocabularyTermResponse {
+	soundsLike := term.SoundsLike
+	if soundsLike == nil {
+		soundsLike = []string{}
+	}
+	return VocabularyTermResponse{
+		ID:         term.ID,
+		Phrase:     term.Phrase,
+		SoundsLike: soundsLike,
+		Source:     term.Source.String(),
+	}
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"eventTypes"`
+}
+
+func (c CreateWebhookRequest) toWebhookSubscription() (models.WebhookSubscription, error) {
+	eventTypes := []models.WebhookEventType{}
+	for _, eventTypeStr := range c.EventTypes {
+		eventType, err := models.WebhookEventTypeFromString(eventTypeStr)
+		if err != nil {
+			return models.WebhookSubscription{}, err
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+	return models.WebhookSubscription{
+		URL:        c.URL,
+		Secret:     c.Secret,
+		EventTypes: eventTypes,
+	}, nil
+}
+
+// WebhookResponse leaves out the secret, it is only ever sent by the client
+type WebhookResponse struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func WebhookResponseFromSubscription(subscription *models.WebhookSubscription) WebhookResponse {
+	eventTypes := []string{}
+	for _, eventType := range subscription.EventTypes {
+		eventTypes = append(eventTypes, eventType.String())
+	}
+	return WebhookResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: eventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+type WebhookDeliveryResponse struct {
+	ID             string     `json:"id"`
+	WebhookID      string     `json:"webhookId"`
+	EventID        string     `json:"eventId"`
+	EventType      string     `json:"eventType"`
+	TranscriptID   string     `json:"transcriptId"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"responseStatus,omitempty"`
+	LastError      string     `json:"lastError,omitempty"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+}
+
+func WebhookDeliveryResponseFromDelivery(delivery *models.WebhookDelivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.SubscriptionID,
+		EventID:        delivery.Event.ID,
+		EventType:      delivery.Event.Type.String(),
+		TranscriptID:   delivery.Event.JobID,
+		Status:         delivery.Status.String(),
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+}
+
+type CreateInviteRequest struct {
+	PlayerID   string `json:"playerId"`
+	PlayerType string `json:"playerType"`
+}
+
+func (c CreateInviteRequest) toInvite() (models.CampaignInvite, error) {
+	playerType := models.StandardPlayer
+	if c.PlayerType != "" {
+		var err error
+		playerType, err = models.PlayerTypeFromString(c.PlayerType)
+		if err != nil {
+			return models.CampaignInvite{}, err
+		}
+	}
+	return models.CampaignInvite{
+		PlayerID:   c.PlayerID,
+		PlayerType: playerType,
+	}, nil
+}
+
+type InviteResponse struct {
+	Code       string    `json:"code"`
+	CampaignID string    `json:"campaignId"`
+	PlayerID   string    `json:"playerId,omitempty"`
+	PlayerType string    `json:"playerType"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+func InviteResponseFromInvite(invite *models.CampaignInvite) InviteResponse {
+	return InviteResponse{
+		Code:       invite.Code,
+		CampaignID: invite.CampaignID,
+		PlayerID:   invite.PlayerID,
+		PlayerType: invite.PlayerType.String(),
+		ExpiresAt:  invite.ExpiresAt,
 	}
 }
 
@@ -77,6 +257,19 @@ func (c CreateSessionRequest) toSession() models.Session {
 	}
 }
 
+type UpdateSessionRequest struct {
+	SessionDate *time.Time `json:"sessionDate"`
+	Title       *string    `json:"title"`
+}
+
+func (u UpdateSessionRequest) toSessionUpdate(version int) models.SessionUpdate {
+	return models.SessionUpdate{
+		SessionDate: u.SessionDate,
+		Title:       u.Title,
+		Version:     version,
+	}
+}
+
 type SessionResponse struct {
 	ID          string    `json:"id"`
 	SessionDate time.Time `json:"sessionDate"`
@@ -91,22 +284,348 @@ func SessionResponseFromSession(session *models.Session) SessionResponse {
 	}
 }
 
-type TranscriptResponse struct {
+type CreatePlayerRequest struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func (c CreatePlayerRequest) toPlayer() (models.Player, error) {
+	playerType := models.StandardPlayer
+	if c.Type != "" {
+		var err error
+		playerType, err = models.PlayerTypeFromString(c.Type)
+		if err != nil {
+			return models.Player{}, err
+		}
+	}
+	return models.Player{
+		Name: c.Name,
+		Type: playerType,
+	}, nil
+}
+
+type UpdatePlayerRequest struct {
+	Name *string `json:"name"`
+	Type *string `json:"type"`
+}
+
+func (u UpdatePlayerRequest) toPlayerUpdate() (models.PlayerUpdate, error) {
+	update := models.PlayerUpdate{
+		Name: u.Name,
+	}
+	if u.Type != nil {
+		playerType, err := models.PlayerTypeFromString(*u.Type)
+		if err != nil {
+			return models.PlayerUpdate{}, err
+		}
+		update.Type = &playerType
+	}
+	return update, nil
+}
+
+type PlayerResponse struct {
 	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	UserID string `json:"userId,omitempty"`
+}
+
+func PlayerResponseFromPlayer(player *models.Player) PlayerResponse {
+	return PlayerResponse{
+		ID:     player.ID,
+		Name:   player.Name,
+		Type:   player.Type.String(),
+		UserID: player.UserID,
+	}
+}
+
+type CreateCharacterRequest struct {
+	Name string `json:"name"`
+	Link string `json:"link"`
+}
+
+func (c CreateCharacterRequest) toCharacter() models.Character {
+	return models.Character{
+		Name: c.Name,
+		Link: c.Link,
+	}
+}
+
+type UpdateCharacterRequest struct {
+	Name *string `json:"name"`
+	Link *string `json:"link"`
+}
+
+func (u UpdateCharacterRequest) toCharacterUpdate() models.CharacterUpdate {
+	return models.CharacterUpdate{
+		Name: u.Name,
+		Link: u.Link,
+	}
+}
+
+type RetireCharacterRequest struct {
 	Status string `json:"status"`
 }
 
+type CharacterResponse struct {
+	ID       string `json:"id"`
+	PlayerID string `json:"playerId"`
+	Name     string `json:"name"`
+	Link     string `json:"link"`
+	Status   string `json:"status"`
+}
+
+func CharacterResponseFromCharacter(character *models.Character) CharacterResponse {
+	return CharacterResponse{
+		ID:       character.ID,
+		PlayerID: character.OwnerID,
+		Name:     character.Name,
+		Link:     character.Link,
+		Status:   character.Status.String(),
+	}
+}
+
+type SetAttendanceRequest struct {
+	PlayerIDs []string `json:"playerIds"`
+}
+
+type AttendanceResponse struct {
+	Players []PlayerResponse `json:"players"`
+}
+
+func AttendanceResponseFromPlayers(players []models.Player) AttendanceResponse {
+	response := AttendanceResponse{
+		Players: []PlayerResponse{},
+	}
+	for _, player := range players {
+		response.Players = append(response.Players, PlayerResponseFromPlayer(&player))
+	}
+	return response
+}
+
+type PlayerAttendanceResponse struct {
+	PlayerResponse
+	SessionsAttended int `json:"sessionsAttended"`
+}
+
+type AttendanceReportResponse struct {
+	TotalSessions int                        `json:"totalSessions"`
+	Players       []PlayerAttendanceResponse `json:"players"`
+}
+
+func AttendanceReportResponseFromReport(report *models.AttendanceReport) AttendanceReportResponse {
+	response := AttendanceReportResponse{
+		TotalSessions: report.TotalSessions,
+		Players:       []PlayerAttendanceResponse{},
+	}
+	for _, attendance := range report.Players {
+		response.Players = append(response.Players, PlayerAttendanceResponse{
+			PlayerResponse:   PlayerResponseFromPlayer(&attendance.Player),
+			SessionsAttended: attendance.SessionsAttended,
+		})
+	}
+	return response
+}
+
+type UpdateTranscriptRequest struct {
+	SessionID *string `json:"sessionId"`
+}
+
+func (u UpdateTranscriptRequest) toTranscriptUpdate(version int) models.TranscriptUpdate {
+	return models.TranscriptUpdate{
+		SessionID: u.SessionID,
+		Version:   version,
+	}
+}
+
+type TranscriptResponse struct {
+	ID               string    `json:"id"`
+	Status           string    `json:"status"`
+	AudioSHA256      string    `json:"audioSha256,omitempty"`
+	AudioSize        int64     `json:"audioSize,omitempty"`
+	Provider         string    `json:"provider,omitempty"`
+	Language         string    `json:"language,omitempty"`
+	DetectedLanguage string    `json:"detectedLanguage,omitempty"`
+	FailureReason    string    `json:"failureReason,omitempty"`
+	Attempts         int       `json:"attempts"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+type TranscriptSegmentResponse struct {
+	StartTime  float64 `json:"startTime"`
+	EndTime    float64 `json:"endTime"`
+	Speaker    string  `json:"speaker,omitempty"`
+	PlayerID   string  `json:"playerId,omitempty"`
+	PlayerName string  `json:"playerName,omitempty"`
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+}
+
+type TranscriptDocumentResponse struct {
+	ID       string                      `json:"id"`
+	Segments []TranscriptSegmentResponse `json:"segments"`
+}
+
+func TranscriptDocumentResponseFromDocument(jobID string, document *models.TranscriptDocument) TranscriptDocumentResponse {
+	segments := []TranscriptSegmentResponse{}
+	for _, segment := range document.Segments {
+		segments = append(segments, TranscriptSegmentResponse{
+			StartTime:  segment.StartTime.Seconds(),
+			EndTime:    segment.EndTime.Seconds(),
+			Speaker:    segment.Speaker,
+			PlayerID:   segment.PlayerID,
+			PlayerName: segment.PlayerName,
+			Text:       segment.Text,
+			Confidence: segment.Confidence,
+		})
+	}
+	return TranscriptDocumentResponse{
+		ID:       jobID,
+		Segments: segments,
+	}
+}
+
+type SpeakerAssignmentRequest struct {
+	SpeakerLabel string `json:"speakerLabel"`
+	PlayerID     string `json:"playerId"`
+}
+
+type SetSpeakersRequest struct {
+	Speakers []SpeakerAssignmentRequest `json:"speakers"`
+}
+
+func (s SetSpeakersRequest) toSpeakerAssignments() []models.SpeakerAssignment {
+	assignments := []models.SpeakerAssignment{}
+	for _, speaker := range s.Speakers {
+		assignments = append(assignments, models.SpeakerAssignment{
+			SpeakerLabel: speaker.SpeakerLabel,
+			PlayerID:     speaker.PlayerID,
+		})
+	}
+	return assignments
+}
+
+type SpeakerAssignmentResponse struct {
+	SpeakerLabel string `json:"speakerLabel"`
+	PlayerID     string `json:"playerId"`
+	PlayerName   string `json:"playerName"`
+}
+
+type SpeakersResponse struct {
+	Speakers []SpeakerAssignmentResponse `json:"speakers"`
+}
+
+func SpeakersResponseFromAssignments(assignments []models.SpeakerAssignment) SpeakersResponse {
+	speakers := []SpeakerAssignmentResponse{}
+	for _, assignment := range assignments {
+		speakers = append(speakers, SpeakerAssignmentResponse{
+			SpeakerLabel: assignment.SpeakerLabel,
+			PlayerID:     assignment.PlayerID,
+			PlayerName:   assignment.PlayerName,
+		})
+	}
+	return SpeakersResponse{
+		Speakers: speakers,
+	}
+}
+
+type SummaryResponse struct {
+	ID      string `json:"id"`
+	Summary string `json:"summary"`
+}
+
 type ErrorResponse struct {
 	ErrorMessage string `json:"errorMessage"`
 }
 
 func TranscriptResponseFromTranscript(transcript *models.Transcript) TranscriptResponse {
 	return TranscriptResponse{
-		ID:     transcript.JobID,
-		Status: transcript.Status.String(),
+		ID:               transcript.JobID,
+		Status:           transcript.Status.String(),
+		AudioSHA256:      transcript.AudioSHA256,
+		AudioSize:        transcript.AudioSize,
+		Provider:         transcript.Provider,
+		Language:         transcript.Language,
+		DetectedLanguage: transcript.DetectedLanguage,
+		FailureReason:    transcript.FailureReason,
+		Attempts:         transcript.Attempts,
+		CreatedAt:        transcript.CreatedAt,
+		UpdatedAt:        transcript.UpdatedAt,
+	}
+}
+
+type CreateUploadRequest struct {
+	ContentType string `json:"contentType"`
+	Length      int64  `json:"length"`
+	MaxSpeakers int    `json:"maxSpeakers"`
+	Provider    string `json:"provider"`
+	Language    string `json:"language"`
+}
+
+type UploadResponse struct {
+	ID           string `json:"id"`
+	Offset       int64  `json:"offset"`
+	Length       int64  `json:"length"`
+	TranscriptID string `json:"transcriptId,omitempty"`
+}
+
+func UploadResponseFromUpload(upload *models.ResumableUpload) UploadResponse {
+	return UploadResponse{
+		ID:           upload.ID,
+		Offset:       upload.Offset,
+		Length:       upload.Length,
+		TranscriptID: upload.TranscriptJobID,
+	}
+}
+
+type CreateDirectUploadRequest struct {
+	ContentType string `json:"contentType"`
+	Length      int64  `json:"length"`
+	SHA256      string `json:"sha256"`
+	MaxSpeakers int    `json:"maxSpeakers"`
+	Provider    string `json:"provider"`
+	Language    string `json:"language"`
+}
+
+type PresignedURLResponse struct {
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
+
+func PresignedURLResponseFromURL(url *models.PresignedURL) PresignedURLResponse {
+	return PresignedURLResponse{
+		URL:       url.URL,
+		Method:    url.Method,
+		Headers:   url.Headers,
+		ExpiresAt: url.ExpiresAt,
+	}
+}
+
+type DirectUploadResponse struct {
+	ID        string               `json:"id"`
+	UploadURL PresignedURLResponse `json:"uploadUrl"`
+}
+
+func DirectUploadResponseFromUpload(upload *models.DirectUpload) DirectUploadResponse {
+	return DirectUploadResponse{
+		ID:        upload.ID,
+		UploadURL: PresignedURLResponseFromURL(upload.UploadURL),
 	}
 }
 
+type authenticator interface {
+	Authenticate(ctx context.Context, token string) (models.Identity, error)
+}
+
+type accessManager interface {
+	AuthorizeCampaign(ctx context.Context, campaignID string, access models.CampaignAccess) error
+	AuthorizeSession(ctx context.Context, campaignID, sessionID string, access models.CampaignAccess) error
+	AuthorizeTranscript(ctx context.Context, campaignID, sessionID, jobID string, access models.CampaignAccess) error
+}
+
 type userManager interface {
 	AddNewUser(ctx context.Context, user models.User) (*models.User, error)
 	GetUserByID(ctx context.Context, email string) (*models.User, error)
@@ -114,24 +633,86 @@ type userManager interface {
 
 type campaignManager interface {
 	AddCampaign(ctx context.Context, ownerID string, campaign models.Campaign) (*models.Campaign, error)
-	GetCampaignsForUser(ctx context.Context, ownerID string) ([]models.Campaign, error)
+	GetCampaignsForUser(ctx context.Context, userID string, page models.PageRequest) (*models.Page[models.Campaign], error)
+	CreateInvite(ctx context.Context, campaignID string, invite models.CampaignInvite) (*models.CampaignInvite, error)
+	AcceptInvite(ctx context.Context, userID, code string) (*models.Campaign, error)
+	GetCampaign(ctx context.Context, userID, campaignID string) (*models.Campaign, error)
+	UpdateCampaign(ctx context.Context, campaignID string, update models.CampaignUpdate) (*models.Campaign, error)
+	DeleteCampaign(ctx context.Context, campaignID string) error
+	GetVocabulary(ctx context.Context, campaignID string) ([]models.VocabularyTerm, error)
+	GetVocabularyTerm(ctx context.Context, campaignID, termID string) (*models.VocabularyTerm, error)
+	AddVocabularyTerm(ctx context.Context, campaignID string, term models.VocabularyTerm) (*models.VocabularyTerm, error)
+	UpdateVocabularyTerm(ctx context.Context, campaignID, termID string, update models.VocabularyTermUpdate) (*models.VocabularyTerm, error)
+	DeleteVocabularyTerm(ctx context.Context, campaignID, termID string) error
 }
 
 type sessionManager interface {
 	AddSession(ctx context.Context, campaignID string, session models.Session) (*models.Session, error)
-	GetSessionsForCampaign(ctx context.Context, campaignID string) ([]models.Session, error)
+	GetSessionsForCampaign(ctx context.Context, campaignID string, filter models.SessionFilter, page models.PageRequest) (*models.Page[models.Session], error)
+	SetAttendance(ctx context.Context, campaignID, sessionID string, playerIDs []string) ([]models.Player, error)
+	GetAttendance(ctx context.Context, campaignID, sessionID string) ([]models.Player, error)
+	GetAttendanceReport(ctx context.Context, campaignID string) (*models.AttendanceReport, error)
+	GetSession(ctx context.Context, campaignID, sessionID string) (*models.Session, error)
+	UpdateSession(ctx context.Context, campaignID, sessionID string, update models.SessionUpdate) (*models.Session, error)
+	DeleteSession(ctx context.Context, campaignID, sessionID string) error
+}
+
+type playerManager interface {
+	AddPlayer(ctx context.Context, campaignID string, player models.Player) (*models.Player, error)
+	GetPlayersForCampaign(ctx context.Context, campaignID string) ([]models.Player, error)
+	GetPlayer(ctx context.Context, campaignID, playerID string) (*models.Player, error)
+	UpdatePlayer(ctx context.Context, campaignID, playerID string, update models.PlayerUpdate) (*models.Player, error)
+	DeletePlayer(ctx context.Context, campaignID, playerID string) error
+}
+
+type characterManager interface {
+	AddCharacter(ctx context.Context, campaignID, playerID string, character models.Character) (*models.Character, error)
+	GetCharactersForPlayer(ctx context.Context, campaignID, playerID string) ([]models.Character, error)
+	GetCharacter(ctx context.Context, campaignID, playerID, characterID string) (*models.Character, error)
+	UpdateCharacter(ctx context.Context, campaignID, playerID, characterID string, update models.CharacterUpdate) (*models.Character, error)
+	RetireCharacter(ctx context.Context, campaignID, playerID, characterID string, status models.CharacterStatus) (*models.Character, error)
+}
+
+type webhookManager interface {
+	AddSubscription(ctx context.Context, campaignID string, subscription models.WebhookSubscription) (*models.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context, campaignID string) ([]models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, campaignID, subscriptionID string) (*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, campaignID, subscriptionID string) error
+	GetDeliveries(ctx context.Context, campaignID, subscriptionID string, page models.PageRequest) (*models.Page[models.WebhookDelivery], error)
+	Redeliver(ctx context.Context, campaignID, subscriptionID, deliveryID string) (*models.WebhookDelivery, error)
 }
 
 type transcriptionManager interface {
-	SubmitTranscriptionJob(ctx context.Context, userID, campaignID, sessionID string, audioFormat models.AudioFormat, audioFile io.Reader) (*models.Transcript, error)
+	SubmitTranscriptionJob(ctx context.Context, userID, campaignID, sessionID string, audio models.AudioUpload, options models.TranscriptionOptions) (*models.Transcript, error)
 	GetTranscriptJob(ctx context.Context, jobID string) (*models.Transcript, error)
-	GetTranscriptsForSession(ctx context.Context, sessionID string) ([]models.Transcript, error)
-	DownloadTranscript(ctx context.Context, jobID string, w io.WriterAt) (int64, error)
+	GetTranscriptsForSession(ctx context.Context, sessionID string, filter models.TranscriptFilter, page models.PageRequest) (*models.Page[models.Transcript], error)
+	GetTranscriptDocument(ctx context.Context, jobID string) (*models.TranscriptDocument, error)
+	DownloadSummary(ctx context.Context, jobID string, w io.WriterAt) (int64, error)
+	SetSpeakerAssignments(ctx context.Context, jobID string, assignments []models.SpeakerAssignment) ([]models.SpeakerAssignment, error)
+	GetSpeakerAssignments(ctx context.Context, jobID string) ([]models.SpeakerAssignment, error)
+	UpdateTranscript(ctx context.Context, campaignID, jobID string, update models.TranscriptUpdate) (*models.Transcript, error)
+	DeleteTranscript(ctx context.Context, jobID string) error
+	RetryTranscript(ctx context.Context, sessionID, jobID string) (*models.Transcript, error)
+	GetTranscriptFileURL(ctx context.Context, jobID string, file models.TranscriptFile) (*models.PresignedURL, error)
+}
+
+type uploadManager interface {
+	CreateUpload(ctx context.Context, sessionID string, audioFormat models.AudioFormat, length int64, options models.TranscriptionOptions) (*models.ResumableUpload, error)
+	GetUpload(ctx context.Context, sessionID, uploadID string) (*models.ResumableUpload, error)
+	AppendChunk(ctx context.Context, sessionID, uploadID string, offset int64, chunk io.Reader, chunkLength int64) (*models.ResumableUpload, error)
+	FinalizeUpload(ctx context.Context, sessionID, uploadID string) (*models.Transcript, error)
+	DeleteUpload(ctx context.Context, sessionID, uploadID string) error
+	CreateDirectUpload(ctx context.Context, sessionID string, audioFormat models.AudioFormat, constraints models.UploadConstraints, options models.TranscriptionOptions) (*models.DirectUpload, error)
+	ConfirmDirectUpload(ctx context.Context, sessionID, uploadID string) (*models.Transcript, error)
 }
 
 var (
 	baseUrl             = "dragonspeak-service"
 	maxFileDownloadSize = 10 * 1024 * 1024
+	markdownContentType = "text/markdown"
+	chunkContentType    = "application/offset+octet-stream"
+	uploadOffsetHeader  = "Upload-Offset"
+	uploadLengthHeader  = "Upload-Length"
 )
 
 type HttpAPI struct {
@@ -139,16 +720,28 @@ type HttpAPI struct {
 	campaignManager      campaignManager
 	sessionManager       sessionManager
 	transcriptionManager transcriptionManager
+	uploadManager        uploadManager
+	playerManager        playerManager
+	characterManager     characterManager
+	webhookManager       webhookManager
+	authenticator        authenticator
+	accessManager        accessManager
 	engine               *gin.Engine
 }
 
-func NewHttpAPI(engine *gin.Engine, userManager userManager, campaignManager campaignManager, sessionManager sessionManager, transcriptionManager transcriptionManager) *HttpAPI {
+func NewHttpAPI(engine *gin.Engine, userManager userManager, campaignManager campaignManager, sessionManager sessionManager, transcriptionManager transcriptionManager, uploadManager uploadManager, playerManager playerManager, characterManager characterManager, webhookManager webhookManager, authenticator authenticator, accessManager accessManager) *HttpAPI {
 	api := &HttpAPI{
 		engine:               engine,
 		userManager:          userManager,
 		campaignManager:      campaignManager,
 		sessionManager:       sessionManager,
 		transcriptionManager: transcriptionManager,
+		uploadManager:        uploadManager,
+		playerManager:        playerManager,
+		characterManager:     characterManager,
+		webhookManager:       webhookManager,
+		authenticator:        authenticator,
+		accessManager:        accessManager,
 	}
 	api.registerHandlers()
 
@@ -160,16 +753,64 @@ func (api *HttpAPI) Run() {
 }
 
 func (api *HttpAPI) registerHandlers() {
-	api.engine.POST(baseUrl+"/v1/users", api.AddUser)
-	api.engine.GET(baseUrl+"/v1/users/:userId", api.GetUserByID)
-	api.engine.POST(baseUrl+"/v1/users/:userId/campaigns", api.AddCampaign)
-	api.engine.GET(baseUrl+"/v1/users/:userId/campaigns", api.GetCampaigns)
-	api.engine.POST(baseUrl+"/v1/users/:userId/campaigns/:campaignId/sessions", api.AddSession)
-	api.engine.GET(baseUrl+"/v1/users/:userId/campaigns/:campaignId/sessions", api.GetSessions)
-	api.engine.POST(baseUrl+"/v1/users/:userId/campaigns/:campaignId/sessions/:sessionId/transcripts", api.SubmitTranscriptionJob)
-	api.engine.GET(baseUrl+"/v1/users/:userId/campaigns/:campaignId/sessions/:sessionId/transcripts", api.GetTranscriptJobs)
-	api.engine.GET(baseUrl+"/v1/users/:userId/campaigns/:campaignId/sessions/:sessionId/transcripts/:jobId", api.GetTranscriptJob)
-	api.engine.GET(baseUrl+"/v1/users/:userId/campaigns/:campaignId/sessions/:sessionId/transcripts/:jobId/fulltext", api.GetTranscriptFullText)
+	api.engine.POST(baseUrl+"/v1/users", api.authenticate, api.AddUser)
+
+	user := api.engine.Group(baseUrl+"/v1/users/:userId", api.authenticate, api.authorize)
+	user.GET("", api.GetUserByID)
+	user.POST("/campaigns", api.AddCampaign)
+	user.GET("/campaigns", api.GetCampaigns)
+	user.GET("/campaigns/:campaignId", api.GetCampaign)
+	user.PATCH("/campaigns/:campaignId", api.UpdateCampaign)
+	user.DELETE("/campaigns/:campaignId", api.DeleteCampaign)
+	user.POST("/campaigns/:campaignId/invites", api.CreateInvite)
+	user.GET("/campaigns/:campaignId/vocabulary", api.GetVocabulary)
+	user.POST("/campaigns/:campaignId/vocabulary", api.AddVocabularyTerm)
+	user.GET("/campaigns/:campaignId/vocabulary/:termId", api.GetVocabularyTerm)
+	user.PATCH("/campaigns/:campaignId/vocabulary/:termId", api.UpdateVocabularyTerm)
+	user.DELETE("/campaigns/:campaignId/vocabulary/:termId", api.DeleteVocabularyTerm)
+	user.POST("/campaigns/:campaignId/webhooks", api.AddWebhook)
+	user.GET("/campaigns/:campaignId/webhooks", api.GetWebhooks)
+	user.GET("/campaigns/:campaignId/webhooks/:webhookId", api.GetWebhook)
+	user.DELETE("/campaigns/:campaignId/webhooks/:webhookId", api.DeleteWebhook)
+	user.GET("/campaigns/:campaignId/webhooks/:webhookId/deliveries", api.GetWebhookDeliveries)
+	user.POST("/campaigns/:campaignId/webhooks/:webhookId/deliveries/:deliveryId/redeliver", api.RedeliverWebhook)
+	user.POST("/invites/:code/accept", api.AcceptInvite)
+	user.POST("/campaigns/:campaignId/players", api.AddPlayer)
+	user.GET("/campaigns/:campaignId/players", api.GetPlayers)
+	user.GET("/campaigns/:campaignId/players/:playerId", api.GetPlayer)
+	user.PATCH("/campaigns/:campaignId/players/:playerId", api.UpdatePlayer)
+	user.DELETE("/campaigns/:campaignId/players/:playerId", api.DeletePlayer)
+	user.POST("/campaigns/:campaignId/players/:playerId/characters", api.AddCharacter)
+	user.GET("/campaigns/:campaignId/players/:playerId/characters", api.GetCharacters)
+	user.GET("/campaigns/:campaignId/players/:playerId/characters/:characterId", api.GetCharacter)
+	user.PATCH("/campaigns/:campaignId/players/:playerId/characters/:characterId", api.UpdateCharacter)
+	user.POST("/campaigns/:campaignId/players/:playerId/characters/:characterId/retire", api.RetireCharacter)
+	user.POST("/campaigns/:campaignId/sessions", api.AddSession)
+	user.GET("/campaigns/:campaignId/sessions", api.GetSessions)
+	user.GET("/campaigns/:campaignId/sessions/:sessionId", api.GetSession)
+	user.PATCH("/campaigns/:campaignId/sessions/:sessionId", api.UpdateSession)
+	user.DELETE("/campaigns/:campaignId/sessions/:sessionId", api.DeleteSession)
+	user.PUT("/campaigns/:campaignId/sessions/:sessionId/attendance", api.SetAttendance)
+	user.GET("/campaigns/:campaignId/sessions/:sessionId/attendance", api.GetAttendance)
+	user.GET("/campaigns/:campaignId/attendance", api.GetAttendanceReport)
+	user.POST("/campaigns/:campaignId/sessions/:sessionId/transcripts", api.SubmitTranscriptionJob)
+	user.GET("/campaigns/:campaignId/sessions/:sessionId/transcripts", api.GetTranscriptJobs)
+	user.POST("/campaigns/:campaignId/sessions/:sessionId/uploads", api.CreateUpload)
+	user.HEAD("/campaigns/:campaignId/sessions/:sessionId/uploads/:uploadId", api.GetUploadOffset)
+	user.PATCH("/campaigns/:campaignId/sessions/:sessionId/uploads/:uploadId", api.AppendUploadChunk)
+	user.DELETE("/campaigns/:campaignId/sessions/:sessionId/uploads/:uploadId", api.DeleteUpload)
+	user.POST("/campaigns/:campaignId/sessions/:sessionId/uploads/:uploadId/finalize", api.FinalizeUpload)
+	user.POST("/campaigns/:campaignId/sessions/:sessionId/direct-uploads", api.CreateDirectUpload)
+	user.POST("/campaigns/:campaignId/sessions/:sessionId/direct-uploads/:uploadId/confirm", api.ConfirmDirectUpload)
+	user.GET("/campaigns/:campaignId/sessions/:sessionId/transcripts/:jobId", api.GetTranscriptJob)
+	user.PATCH("/campaigns/:campaignId/sessions/:sessionId/transcripts/:jobId", api.UpdateTranscript)
+	user.DELETE("/campaigns/:campaignId/sessions/:sessionId/transcripts/:jobId", api.DeleteTranscript)
+	user.POST("/campaigns/:campaignId/sessions/:sessionId/transcripts/:jobId/retry", api.RetryTranscript)
+	user.GET("/campaigns/:campaignId/sessions/:sessionId/transcripts/:jobId/fulltext", api.GetTranscriptFullText)
+	user.GET("/campaigns/:campaignId/sessions/:sessionId/transcripts/:jobId/summary", api.GetTranscriptSummary)
+	user.GET("/campaigns/:campaignId/sessions/:sessionId/transcripts/:jobId/files/:file", api.GetTranscriptFileURL)
+	user.PUT("/campaigns/:campaignId/sessions/:sessionId/transcripts/:jobId/speakers", api.SetTranscriptSpeakers)
+	user.GET("/campaigns/:campaignId/sessions/:sessionId/transcripts/:jobId/speakers", api.GetTranscriptSpeakers)
 }
 
 func (api *HttpAPI) AddUser(c *gin.Context) {
@@ -181,7 +822,10 @@ func (api *HttpAPI) AddUser(c *gin.Context) {
 		})
 		return
 	}
-	addedUser, err := api.userManager.AddNewUser(c.Request.Context(), user.toUser())
+	identity, _ := models.IdentityFromContext(c.Request.Context())
+	newUser := user.toUser()
+	newUser.ID = identity.UserID
+	addedUser, err := api.userManager.AddNewUser(c.Request.Context(), newUser)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -219,121 +863,1006 @@ func (api *HttpAPI) AddCampaign(c *gin.Context) {
 
 func (api *HttpAPI) GetCampaigns(c *gin.Context) {
 	userID := c.Param("userId")
-	campaigns, err := api.campaignManager.GetCampaignsForUser(c.Request.Context(), userID)
+	page, ok := pageRequestFromQuery(c)
+	if !ok {
+		return
+	}
+	campaigns, err := api.campaignManager.GetCampaignsForUser(c.Request.Context(), userID, page)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
-	response := []CampaignResponse{}
-	for _, campaign := range campaigns {
-		response = append(response, CampaignResponseFromCampaign(&campaign))
+	c.JSON(http.StatusOK, listResponseFromPage(campaigns, CampaignResponseFromCampaign))
+}
+
+func (api *HttpAPI) GetCampaign(c *gin.Context) {
+	userID := c.Param("userId")
+	campaignID := c.Param("campaignId")
+	campaign, err := api.campaignManager.GetCampaign(c.Request.Context(), userID, campaignID)
+	if err != nil {
+		handleError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, response)
+	setETag(c, campaign.Version)
+	c.JSON(http.StatusOK, CampaignResponseFromCampaign(campaign))
 }
 
-func (api *HttpAPI) AddSession(c *gin.Context) {
+func (api *HttpAPI) UpdateCampaign(c *gin.Context) {
 	campaignID := c.Param("campaignId")
-	var session CreateSessionRequest
-	err := json.NewDecoder(c.Request.Body).Decode(&session)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	var request UpdateCampaignRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
 	if err != nil {
 		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
 			ErrorMessage: "Request body is in the incorrect format",
 		})
+		return
 	}
-	addedSession, err := api.sessionManager.AddSession(c.Request.Context(), campaignID, session.toSession())
+	campaign, err := api.campaignManager.UpdateCampaign(c.Request.Context(), campaignID, request.toCampaignUpdate(version))
 	if err != nil {
 		handleError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, SessionResponseFromSession(addedSession))
+	setETag(c, campaign.Version)
+	c.JSON(http.StatusOK, CampaignResponseFromCampaign(campaign))
 }
 
-func (api *HttpAPI) GetSessions(c *gin.Context) {
+func (api *HttpAPI) DeleteCampaign(c *gin.Context) {
 	campaignID := c.Param("campaignId")
-	sessions, err := api.sessionManager.GetSessionsForCampaign(c.Request.Context(), campaignID)
+	err := api.campaignManager.DeleteCampaign(c.Request.Context(), campaignID)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
-	response := []SessionResponse{}
-	for _, session := range sessions {
-		response = append(response, SessionResponseFromSession(&session))
+	c.Status(http.StatusNoContent)
+}
+
+// GetVocabulary lists the custom terms of a campaign followed by the names it is seeded with
+func (api *HttpAPI) GetVocabulary(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	terms, err := api.campaignManager.GetVocabulary(c.Request.Context(), campaignID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	response := []VocabularyTermResponse{}
+	for _, term := range terms {
+		response = append(response, VocabularyTermResponseFromTerm(&term))
 	}
 	c.JSON(http.StatusOK, response)
 }
 
-func (api *HttpAPI) SubmitTranscriptionJob(c *gin.Context) {
-	userID := c.Param("userId")
+func (api *HttpAPI) AddVocabularyTerm(c *gin.Context) {
 	campaignID := c.Param("campaignId")
-	sessionID := c.Param("sessionId")
-	fileType := c.Request.Header.Get("Content-Type")
-	audioFormat, err := contentTypeToAudioType(fileType)
+	var request CreateVocabularyTermRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
 	if err != nil {
 		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
-			ErrorMessage: "Unprocessable Entity. Content-Type: %s not supported. Supported types are \"audio/mpeg\", \"audio/webm\", \"audio/ogg\"",
+			ErrorMessage: "Request body is in the incorrect format",
 		})
 		return
 	}
-	job, err := api.transcriptionManager.SubmitTranscriptionJob(c.Request.Context(), userID, campaignID, sessionID, audioFormat, c.Request.Body)
+	term, err := api.campaignManager.AddVocabularyTerm(c.Request.Context(), campaignID, request.toVocabularyTerm())
 	if err != nil {
 		handleError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, TranscriptResponseFromTranscript(job))
+	c.JSON(http.StatusCreated, VocabularyTermResponseFromTerm(term))
 }
 
-func (api *HttpAPI) GetTranscriptJob(c *gin.Context) {
-	jobID := c.Param("jobId")
-	job, err := api.transcriptionManager.GetTranscriptJob(c.Request.Context(), jobID)
+func (api *HttpAPI) GetVocabularyTerm(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	termID := c.Param("termId")
+	term, err := api.campaignManager.GetVocabularyTerm(c.Request.Context(), campaignID, termID)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, TranscriptResponseFromTranscript(job))
+	c.JSON(http.StatusOK, VocabularyTermResponseFromTerm(term))
 }
 
-func (api *HttpAPI) GetTranscriptJobs(c *gin.Context) {
-	sessionID := c.Param("sessionId")
-	transcripts, err := api.transcriptionManager.GetTranscriptsForSession(c.Request.Context(), sessionID)
+func (api *HttpAPI) UpdateVocabularyTerm(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	termID := c.Param("termId")
+	var request UpdateVocabularyTermRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
 	if err != nil {
-		handleError(c, err)
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
 		return
 	}
-	response := []TranscriptResponse{}
-	for _, transcript := range transcripts {
-		response = append(response, TranscriptResponseFromTranscript(&transcript))
+	term, err := api.campaignManager.UpdateVocabularyTerm(c.Request.Context(), campaignID, termID, request.toVocabularyTermUpdate())
+	if err != nil {
+		handleError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, VocabularyTermResponseFromTerm(term))
 }
 
-func (api *HttpAPI) GetTranscriptFullText(c *gin.Context) {
-	jobID := c.Param("jobId")
-
-	buf := make([]byte, 100)
-	writeBuffer := aws.NewWriteAtBuffer(buf)
-	bytesWritten, err := api.transcriptionManager.DownloadTranscript(c.Request.Context(), jobID, writeBuffer)
+func (api *HttpAPI) DeleteVocabularyTerm(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	termID := c.Param("termId")
+	err := api.campaignManager.DeleteVocabularyTerm(c.Request.Context(), campaignID, termID)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
+	c.Status(http.StatusNoContent)
+}
 
-	c.String(http.StatusOK, string(writeBuffer.Bytes()[:bytesWritten]))
+func (api *HttpAPI) AddWebhook(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	var request CreateWebhookRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	subscription, err := request.toWebhookSubscription()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	created, err := api.webhookManager.AddSubscription(c.Request.Context(), campaignID, subscription)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, WebhookResponseFromSubscription(created))
 }
 
-func contentTypeToAudioType(contentType string) (models.AudioFormat, error) {
-	switch contentType {
-	case "audio/mpeg":
-		return models.MP3, nil
-	case "audio/webm":
-		return models.WebM, nil
-	case "audio/ogg":
-		return models.OGG, nil
+func (api *HttpAPI) GetWebhooks(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	subscriptions, err := api.webhookManager.GetSubscriptions(c.Request.Context(), campaignID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	response := []WebhookResponse{}
+	for _, subscription := range subscriptions {
+		response = append(response, WebhookResponseFromSubscription(&subscription))
 	}
-	return 0, models.InvalidEntity
+	c.JSON(http.StatusOK, response)
 }
 
-func handleError(c *gin.Context, err error) {
-	if errors.Is(err, models.EntityNotFound) {
+func (api *HttpAPI) GetWebhook(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	webhookID := c.Param("webhookId")
+	subscription, err := api.webhookManager.GetSubscription(c.Request.Context(), campaignID, webhookID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, WebhookResponseFromSubscription(subscription))
+}
+
+func (api *HttpAPI) DeleteWebhook(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	webhookID := c.Param("webhookId")
+	err := api.webhookManager.DeleteSubscription(c.Request.Context(), campaignID, webhookID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries pages through the deliveries of a webhook, newest first unless sorted otherwise
+func (api *HttpAPI) GetWebhookDeliveries(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	webhookID := c.Param("webhookId")
+	page, ok := pageRequestFromQuery(c)
+	if !ok {
+		return
+	}
+	deliveries, err := api.webhookManager.GetDeliveries(c.Request.Context(), campaignID, webhookID, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, listResponseFromPage(deliveries, WebhookDeliveryResponseFromDelivery))
+}
+
+// RedeliverWebhook queues the event of a delivery to be sent again, the new delivery is sent in the background
+func (api *HttpAPI) RedeliverWebhook(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	webhookID := c.Param("webhookId")
+	deliveryID := c.Param("deliveryId")
+	delivery, err := api.webhookManager.Redeliver(c.Request.Context(), campaignID, webhookID, deliveryID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, WebhookDeliveryResponseFromDelivery(delivery))
+}
+
+func (api *HttpAPI) CreateInvite(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	var request CreateInviteRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	invite, err := request.toInvite()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	createdInvite, err := api.campaignManager.CreateInvite(c.Request.Context(), campaignID, invite)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, InviteResponseFromInvite(createdInvite))
+}
+
+func (api *HttpAPI) AcceptInvite(c *gin.Context) {
+	userID := c.Param("userId")
+	code := c.Param("code")
+	campaign, err := api.campaignManager.AcceptInvite(c.Request.Context(), userID, code)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CampaignResponseFromCampaign(campaign))
+}
+
+func (api *HttpAPI) AddPlayer(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	var request CreatePlayerRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	player, err := request.toPlayer()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	addedPlayer, err := api.playerManager.AddPlayer(c.Request.Context(), campaignID, player)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, PlayerResponseFromPlayer(addedPlayer))
+}
+
+func (api *HttpAPI) GetPlayers(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	players, err := api.playerManager.GetPlayersForCampaign(c.Request.Context(), campaignID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	response := []PlayerResponse{}
+	for _, player := range players {
+		response = append(response, PlayerResponseFromPlayer(&player))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (api *HttpAPI) GetPlayer(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	playerID := c.Param("playerId")
+	player, err := api.playerManager.GetPlayer(c.Request.Context(), campaignID, playerID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, PlayerResponseFromPlayer(player))
+}
+
+func (api *HttpAPI) UpdatePlayer(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	playerID := c.Param("playerId")
+	var request UpdatePlayerRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	update, err := request.toPlayerUpdate()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	player, err := api.playerManager.UpdatePlayer(c.Request.Context(), campaignID, playerID, update)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, PlayerResponseFromPlayer(player))
+}
+
+func (api *HttpAPI) DeletePlayer(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	playerID := c.Param("playerId")
+	err := api.playerManager.DeletePlayer(c.Request.Context(), campaignID, playerID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (api *HttpAPI) AddCharacter(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	playerID := c.Param("playerId")
+	var request CreateCharacterRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	character, err := api.characterManager.AddCharacter(c.Request.Context(), campaignID, playerID, request.toCharacter())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, CharacterResponseFromCharacter(character))
+}
+
+func (api *HttpAPI) GetCharacters(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	playerID := c.Param("playerId")
+	characters, err := api.characterManager.GetCharactersForPlayer(c.Request.Context(), campaignID, playerID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	response := []CharacterResponse{}
+	for _, character := range characters {
+		response = append(response, CharacterResponseFromCharacter(&character))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (api *HttpAPI) GetCharacter(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	playerID := c.Param("playerId")
+	characterID := c.Param("characterId")
+	character, err := api.characterManager.GetCharacter(c.Request.Context(), campaignID, playerID, characterID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CharacterResponseFromCharacter(character))
+}
+
+func (api *HttpAPI) UpdateCharacter(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	playerID := c.Param("playerId")
+	characterID := c.Param("characterId")
+	var request UpdateCharacterRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	character, err := api.characterManager.UpdateCharacter(c.Request.Context(), campaignID, playerID, characterID, request.toCharacterUpdate())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CharacterResponseFromCharacter(character))
+}
+
+// RetireCharacter marks a character as Retired, or as Dead when the body asks for it
+func (api *HttpAPI) RetireCharacter(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	playerID := c.Param("playerId")
+	characterID := c.Param("characterId")
+	request := RetireCharacterRequest{}
+	if c.Request.ContentLength != 0 {
+		if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				ErrorMessage: "Request body is in the incorrect format",
+			})
+			return
+		}
+	}
+	status := models.CharacterRetired
+	if request.Status != "" {
+		var err error
+		status, err = models.CharacterStatusFromString(request.Status)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+	}
+	character, err := api.characterManager.RetireCharacter(c.Request.Context(), campaignID, playerID, characterID, status)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CharacterResponseFromCharacter(character))
+}
+
+func (api *HttpAPI) AddSession(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	var session CreateSessionRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&session)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+	}
+	addedSession, err := api.sessionManager.AddSession(c.Request.Context(), campaignID, session.toSession())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, SessionResponseFromSession(addedSession))
+}
+
+func (api *HttpAPI) GetSessions(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	filter, ok := sessionFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	page, ok := pageRequestFromQuery(c)
+	if !ok {
+		return
+	}
+	sessions, err := api.sessionManager.GetSessionsForCampaign(c.Request.Context(), campaignID, filter, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, listResponseFromPage(sessions, SessionResponseFromSession))
+}
+
+func (api *HttpAPI) GetSession(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	sessionID := c.Param("sessionId")
+	session, err := api.sessionManager.GetSession(c.Request.Context(), campaignID, sessionID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	setETag(c, session.Version)
+	c.JSON(http.StatusOK, SessionResponseFromSession(session))
+}
+
+func (api *HttpAPI) UpdateSession(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	sessionID := c.Param("sessionId")
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	var request UpdateSessionRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	session, err := api.sessionManager.UpdateSession(c.Request.Context(), campaignID, sessionID, request.toSessionUpdate(version))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	setETag(c, session.Version)
+	c.JSON(http.StatusOK, SessionResponseFromSession(session))
+}
+
+func (api *HttpAPI) DeleteSession(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	sessionID := c.Param("sessionId")
+	err := api.sessionManager.DeleteSession(c.Request.Context(), campaignID, sessionID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (api *HttpAPI) SetAttendance(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	sessionID := c.Param("sessionId")
+	var request SetAttendanceRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	players, err := api.sessionManager.SetAttendance(c.Request.Context(), campaignID, sessionID, request.PlayerIDs)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, AttendanceResponseFromPlayers(players))
+}
+
+func (api *HttpAPI) GetAttendance(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	sessionID := c.Param("sessionId")
+	players, err := api.sessionManager.GetAttendance(c.Request.Context(), campaignID, sessionID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, AttendanceResponseFromPlayers(players))
+}
+
+func (api *HttpAPI) GetAttendanceReport(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	report, err := api.sessionManager.GetAttendanceReport(c.Request.Context(), campaignID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, AttendanceReportResponseFromReport(report))
+}
+
+func (api *HttpAPI) SubmitTranscriptionJob(c *gin.Context) {
+	userID := c.Param("userId")
+	campaignID := c.Param("campaignId")
+	sessionID := c.Param("sessionId")
+	fileType := c.ContentType()
+	audioBody := io.Reader(c.Request.Body)
+	audioSize := c.Request.ContentLength
+	formFields := map[string]string{}
+	if fileType == gin.MIMEMultipartPOSTForm {
+		part, fields, err := readMultipartAudio(c.Request)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				ErrorMessage: "multipart body must hold the recording in an \"audio\" part after any other fields",
+			})
+			return
+		}
+		fileType = part.Header.Get("Content-Type")
+		audioBody = part
+		audioSize = -1
+		formFields = fields
+	}
+	audioFormat, err := contentTypeToAudioType(fileType)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, unsupportedContentTypeResponse(fileType))
+		return
+	}
+	options := models.TranscriptionOptions{
+		Provider: formFieldOrQuery(c, formFields, "provider"),
+		Language: formFieldOrQuery(c, formFields, "language"),
+	}
+	maxSpeakers := formFieldOrQuery(c, formFields, "maxSpeakers")
+	if maxSpeakers != "" {
+		options.MaxSpeakers, err = strconv.Atoi(maxSpeakers)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				ErrorMessage: "maxSpeakers must be a number",
+			})
+			return
+		}
+	}
+	audio := models.AudioUpload{
+		Format: audioFormat,
+		Body:   audioBody,
+		Size:   audioSize,
+	}
+	job, err := api.transcriptionManager.SubmitTranscriptionJob(c.Request.Context(), userID, campaignID, sessionID, audio, options)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, TranscriptResponseFromTranscript(job))
+}
+
+// CreateUpload starts a resumable upload of a session recording, the chunks are then sent with AppendUploadChunk
+func (api *HttpAPI) CreateUpload(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+	var request CreateUploadRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	audioFormat, err := contentTypeToAudioType(request.ContentType)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, unsupportedContentTypeResponse(request.ContentType))
+		return
+	}
+	options := models.TranscriptionOptions{MaxSpeakers: request.MaxSpeakers, Provider: request.Provider, Language: request.Language}
+	upload, err := api.uploadManager.CreateUpload(c.Request.Context(), sessionID, audioFormat, request.Length, options)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Header("Location", c.Request.URL.Path+"/"+upload.ID)
+	c.Header(uploadOffsetHeader, strconv.FormatInt(upload.Offset, 10))
+	c.JSON(http.StatusCreated, UploadResponseFromUpload(upload))
+}
+
+// GetUploadOffset tells a client resuming an upload where its next chunk has to start
+func (api *HttpAPI) GetUploadOffset(c *gin.Context) {
+	upload, err := api.uploadManager.GetUpload(c.Request.Context(), c.Param("sessionId"), c.Param("uploadId"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header(uploadOffsetHeader, strconv.FormatInt(upload.Offset, 10))
+	c.Header(uploadLengthHeader, strconv.FormatInt(upload.Length, 10))
+	c.Status(http.StatusOK)
+}
+
+// AppendUploadChunk writes the request body to an upload at the offset given in the Upload-Offset header
+func (api *HttpAPI) AppendUploadChunk(c *gin.Context) {
+	if c.ContentType() != chunkContentType {
+		c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{
+			ErrorMessage: "chunks must be sent as " + chunkContentType,
+		})
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader(uploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: uploadOffsetHeader + " header must be the byte offset of the chunk",
+		})
+		return
+	}
+	if c.Request.ContentLength < 0 {
+		c.JSON(http.StatusLengthRequired, ErrorResponse{
+			ErrorMessage: "Content-Length header is required",
+		})
+		return
+	}
+	upload, err := api.uploadManager.AppendChunk(c.Request.Context(), c.Param("sessionId"), c.Param("uploadId"), offset, c.Request.Body, c.Request.ContentLength)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Header(uploadOffsetHeader, strconv.FormatInt(upload.Offset, 10))
+	c.Status(http.StatusNoContent)
+}
+
+// FinalizeUpload starts transcribing a fully received upload
+func (api *HttpAPI) FinalizeUpload(c *gin.Context) {
+	job, err := api.uploadManager.FinalizeUpload(c.Request.Context(), c.Param("sessionId"), c.Param("uploadId"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, TranscriptResponseFromTranscript(job))
+}
+
+func (api *HttpAPI) DeleteUpload(c *gin.Context) {
+	err := api.uploadManager.DeleteUpload(c.Request.Context(), c.Param("sessionId"), c.Param("uploadId"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CreateDirectUpload issues a presigned URL the client uploads a session recording to without going through
+// the API, the upload is then confirmed with ConfirmDirectUpload
+func (api *HttpAPI) CreateDirectUpload(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+	var request CreateDirectUploadRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	audioFormat, err := contentTypeToAudioType(request.ContentType)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, unsupportedContentTypeResponse(request.ContentType))
+		return
+	}
+	constraints := models.UploadConstraints{
+		ContentType: request.ContentType,
+		Length:      request.Length,
+		SHA256:      request.SHA256,
+	}
+	options := models.TranscriptionOptions{MaxSpeakers: request.MaxSpeakers, Provider: request.Provider, Language: request.Language}
+	upload, err := api.uploadManager.CreateDirectUpload(c.Request.Context(), sessionID, audioFormat, constraints, options)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, DirectUploadResponseFromUpload(upload))
+}
+
+// ConfirmDirectUpload starts transcribing a recording the client finished uploading with a presigned URL
+func (api *HttpAPI) ConfirmDirectUpload(c *gin.Context) {
+	job, err := api.uploadManager.ConfirmDirectUpload(c.Request.Context(), c.Param("sessionId"), c.Param("uploadId"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, TranscriptResponseFromTranscript(job))
+}
+
+func (api *HttpAPI) GetTranscriptJob(c *gin.Context) {
+	jobID := c.Param("jobId")
+	job, err := api.transcriptionManager.GetTranscriptJob(c.Request.Context(), jobID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	setETag(c, job.Version)
+	c.JSON(http.StatusOK, TranscriptResponseFromTranscript(job))
+}
+
+func (api *HttpAPI) GetTranscriptJobs(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+	filter, ok := transcriptFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	page, ok := pageRequestFromQuery(c)
+	if !ok {
+		return
+	}
+	transcripts, err := api.transcriptionManager.GetTranscriptsForSession(c.Request.Context(), sessionID, filter, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, listResponseFromPage(transcripts, TranscriptResponseFromTranscript))
+}
+
+func (api *HttpAPI) UpdateTranscript(c *gin.Context) {
+	campaignID := c.Param("campaignId")
+	jobID := c.Param("jobId")
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	var request UpdateTranscriptRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&request)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	transcript, err := api.transcriptionManager.UpdateTranscript(c.Request.Context(), campaignID, jobID, request.toTranscriptUpdate(version))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	setETag(c, transcript.Version)
+	c.JSON(http.StatusOK, TranscriptResponseFromTranscript(transcript))
+}
+
+func (api *HttpAPI) DeleteTranscript(c *gin.Context) {
+	jobID := c.Param("jobId")
+	err := api.transcriptionManager.DeleteTranscript(c.Request.Context(), jobID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RetryTranscript reruns the stage a failed transcript failed at, the transcript is returned in the status the
+// retry left it in
+func (api *HttpAPI) RetryTranscript(c *gin.Context) {
+	job, err := api.transcriptionManager.RetryTranscript(c.Request.Context(), c.Param("sessionId"), c.Param("jobId"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	setETag(c, job.Version)
+	c.JSON(http.StatusAccepted, TranscriptResponseFromTranscript(job))
+}
+
+// GetTranscriptFileURL returns a presigned URL the audio, transcript or summary file can be downloaded from
+func (api *HttpAPI) GetTranscriptFileURL(c *gin.Context) {
+	file, err := models.TranscriptFileFromString(c.Param("file"))
+	if err != nil {
+		handleError(c, models.EntityNotFound)
+		return
+	}
+	url, err := api.transcriptionManager.GetTranscriptFileURL(c.Request.Context(), c.Param("jobId"), file)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, PresignedURLResponseFromURL(url))
+}
+
+func (api *HttpAPI) GetTranscriptFullText(c *gin.Context) {
+	jobID := c.Param("jobId")
+	format, err := negotiateTranscriptFormat(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	document, err := api.transcriptionManager.GetTranscriptDocument(c.Request.Context(), jobID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	switch format {
+	case jsonTranscriptFormat:
+		c.JSON(http.StatusOK, TranscriptDocumentResponseFromDocument(jobID, document))
+	case srtTranscriptFormat:
+		c.Data(http.StatusOK, srtContentType+"; charset=utf-8", []byte(formatSRT(document)))
+	case vttTranscriptFormat:
+		c.Data(http.StatusOK, vttContentType+"; charset=utf-8", []byte(formatVTT(document)))
+	default:
+		c.String(http.StatusOK, document.Text())
+	}
+}
+
+func (api *HttpAPI) GetTranscriptSummary(c *gin.Context) {
+	jobID := c.Param("jobId")
+
+	writeBuffer := aws.NewWriteAtBuffer([]byte{})
+	bytesWritten, err := api.transcriptionManager.DownloadSummary(c.Request.Context(), jobID, writeBuffer)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	summary := writeBuffer.Bytes()[:bytesWritten]
+
+	switch c.NegotiateFormat(markdownContentType, gin.MIMEJSON) {
+	case gin.MIMEJSON:
+		c.JSON(http.StatusOK, SummaryResponse{
+			ID:      jobID,
+			Summary: string(summary),
+		})
+	default:
+		c.Data(http.StatusOK, markdownContentType+"; charset=utf-8", summary)
+	}
+}
+
+func (api *HttpAPI) SetTranscriptSpeakers(c *gin.Context) {
+	jobID := c.Param("jobId")
+	var speakers SetSpeakersRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&speakers)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			ErrorMessage: "Request body is in the incorrect format",
+		})
+		return
+	}
+	assignments, err := api.transcriptionManager.SetSpeakerAssignments(c.Request.Context(), jobID, speakers.toSpeakerAssignments())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SpeakersResponseFromAssignments(assignments))
+}
+
+func (api *HttpAPI) GetTranscriptSpeakers(c *gin.Context) {
+	jobID := c.Param("jobId")
+	assignments, err := api.transcriptionManager.GetSpeakerAssignments(c.Request.Context(), jobID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, SpeakersResponseFromAssignments(assignments))
+}
+
+// authenticate verifies the bearer token of the request and stores the caller identity in the request context
+func (api *HttpAPI) authenticate(c *gin.Context) {
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || token == "" {
+		handleError(c, models.Unauthorized)
+		c.Abort()
+		return
+	}
+	identity, err := api.authenticator.Authenticate(c.Request.Context(), token)
+	if err != nil {
+		handleError(c, err)
+		c.Abort()
+		return
+	}
+	c.Request = c.Request.WithContext(models.ContextWithIdentity(c.Request.Context(), identity))
+	c.Next()
+}
+
+// authorize checks that the caller is the user in the path and may access the campaign, session and
+// transcript the path refers to
+func (api *HttpAPI) authorize(c *gin.Context) {
+	identity, ok := models.IdentityFromContext(c.Request.Context())
+	if !ok {
+		handleError(c, models.Unauthorized)
+		c.Abort()
+		return
+	}
+	if identity.UserID != c.Param("userId") {
+		handleError(c, models.Forbidden)
+		c.Abort()
+		return
+	}
+
+	ctx := c.Request.Context()
+	campaignID := c.Param("campaignId")
+	sessionID := c.Param("sessionId")
+	jobID := c.Param("jobId")
+	access := campaignAccess(c.Request.Method)
+	var err error
+	switch {
+	case jobID != "":
+		err = api.accessManager.AuthorizeTranscript(ctx, campaignID, sessionID, jobID, access)
+	case sessionID != "":
+		err = api.accessManager.AuthorizeSession(ctx, campaignID, sessionID, access)
+	case campaignID != "":
+		err = api.accessManager.AuthorizeCampaign(ctx, campaignID, access)
+	}
+	if err != nil {
+		handleError(c, err)
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// campaignAccess returns the access a request with method needs, reads are open to every member of the
+// campaign and anything else changes it
+func campaignAccess(method string) models.CampaignAccess {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return models.ReadAccess
+	}
+	return models.ManageAccess
+}
+
+// setETag sets the ETag header to the version of the resource in the response
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion reads the version the client is updating from the If-Match header. A 428 is written when
+// the header is missing and a 409 when it doesn't hold one of our ETags, in both cases false is returned.
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		c.JSON(http.StatusPreconditionRequired, ErrorResponse{
+			ErrorMessage: "If-Match header is required",
+		})
+		return 0, false
+	}
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`))
+	if err != nil {
+		handleError(c, models.Conflicted)
+		return 0, false
+	}
+	return version, true
+}
+
+func handleError(c *gin.Context, err error) {
+	if errors.Is(err, models.Unauthorized) {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			ErrorMessage: "Unauthorized",
+		})
+	} else if errors.Is(err, models.Forbidden) {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			ErrorMessage: "Forbidden",
+		})
+	} else if errors.Is(err, models.EntityNotFound) {
 		c.JSON(http.StatusNotFound, ErrorResponse{
 			ErrorMessage: "Not Found",
 		})
@@ -345,6 +1874,10 @@ func handleError(c *gin.Context, err error) {
 		c.JSON(http.StatusConflict, ErrorResponse{
 			ErrorMessage: "Already Exists",
 		})
+	} else if errors.Is(err, models.TooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+			ErrorMessage: "Payload Too Large",
+		})
 	} else if errors.Is(err, models.InvalidEntity) {
 		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
 			ErrorMessage: "Invalid Request",
@@ -6,8 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +20,55 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+// tokenIsUserAuthenticator treats the bearer token as the caller's user ID
+type tokenIsUserAuthenticator struct{}
+
+func (a *tokenIsUserAuthenticator) Authenticate(ctx context.Context, token string) (models.Identity, error) {
+	return models.Identity{UserID: token}, nil
+}
+
+type allowAllAccessManager struct{}
+
+func (a *allowAllAccessManager) AuthorizeCampaign(ctx context.Context, campaignID string, access models.CampaignAccess) error {
+	return nil
+}
+
+func (a *allowAllAccessManager) AuthorizeSession(ctx context.Context, campaignID, sessionID string, access models.CampaignAccess) error {
+	return nil
+}
+
+func (a *allowAllAccessManager) AuthorizeTranscript(ctx context.Context, campaignID, sessionID, jobID string, access models.CampaignAccess) error {
+	return nil
+}
+
+type MockAuthenticator struct {
+	mock.Mock
+}
+
+func (m *MockAuthenticator) Authenticate(ctx context.Context, token string) (models.Identity, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(models.Identity), args.Error(1)
+}
+
+type MockAccessManager struct {
+	mock.Mock
+}
+
+func (m *MockAccessManager) AuthorizeCampaign(ctx context.Context, campaignID string, access models.CampaignAccess) error {
+	args := m.Called(ctx, campaignID, access)
+	return args.Error(0)
+}
+
+func (m *MockAccessManager) AuthorizeSession(ctx context.Context, campaignID, sessionID string, access models.CampaignAccess) error {
+	args := m.Called(ctx, campaignID, sessionID, access)
+	return args.Error(0)
+}
+
+func (m *MockAccessManager) AuthorizeTranscript(ctx context.Context, campaignID, sessionID, jobID string, access models.CampaignAccess) error {
+	args := m.Called(ctx, campaignID, sessionID, jobID, access)
+	return args.Error(0)
+}
+
 type MockUserManager struct {
 	mock.Mock
 }
@@ -49,245 +101,622 @@ func (m *MockCampaignManager) AddCampaign(ctx context.Context, ownerID string, c
 	return args.Get(0).(*models.Campaign), nil
 }
 
-func (m *MockCampaignManager) GetCampaignsForUser(ctx context.Context, ownerID string) ([]models.Campaign, error) {
-	args := m.Called(ctx, ownerID)
+func (m *MockCampaignManager) GetCampaignsForUser(ctx context.Context, ownerID string, page models.PageRequest) (*models.Page[models.Campaign], error) {
+	args := m.Called(ctx, ownerID, page)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]models.Campaign), nil
+	return args.Get(0).(*models.Page[models.Campaign]), nil
 }
 
-type MockSessionManager struct {
-	mock.Mock
+func (m *MockCampaignManager) CreateInvite(ctx context.Context, campaignID string, invite models.CampaignInvite) (*models.CampaignInvite, error) {
+	args := m.Called(ctx, campaignID, invite)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CampaignInvite), nil
 }
 
-func (m *MockSessionManager) AddSession(ctx context.Context, campaignID string, session models.Session) (*models.Session, error) {
-	args := m.Called(ctx, campaignID, session)
+func (m *MockCampaignManager) AcceptInvite(ctx context.Context, userID, code string) (*models.Campaign, error) {
+	args := m.Called(ctx, userID, code)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Session), nil
+	return args.Get(0).(*models.Campaign), nil
 }
 
-func (m *MockSessionManager) GetSessionsForCampaign(ctx context.Context, campaignID string) ([]models.Session, error) {
-	args := m.Called(ctx, campaignID)
+func (m *MockCampaignManager) GetCampaign(ctx context.Context, userID, campaignID string) (*models.Campaign, error) {
+	args := m.Called(ctx, userID, campaignID)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]models.Session), nil
+	return args.Get(0).(*models.Campaign), nil
 }
 
-type MockTranscriptionManager struct {
-	mock.Mock
+func (m *MockCampaignManager) UpdateCampaign(ctx context.Context, campaignID string, update models.CampaignUpdate) (*models.Campaign, error) {
+	args := m.Called(ctx, campaignID, update)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Campaign), nil
+}
+
+func (m *MockCampaignManager) DeleteCampaign(ctx context.Context, campaignID string) error {
+	args := m.Called(ctx, campaignID)
+	return args.Error(0)
 }
 
-func (m *MockTranscriptionManager) SubmitTranscriptionJob(ctx context.Context, userID, campaignID, sessionID string, audioFormat models.AudioFormat, audioFile io.Reader) (*models.Transcript, error) {
-	args := m.Called(ctx, userID, campaignID, sessionID, audioFormat, audioFile)
+func (m *MockCampaignManager) GetVocabulary(ctx context.Context, campaignID string) ([]models.VocabularyTerm, error) {
+	args := m.Called(ctx, campaignID)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Transcript), nil
+	return args.Get(0).([]models.VocabularyTerm), nil
 }
 
-func (m *MockTranscriptionManager) GetTranscriptJob(ctx context.Context, jobID string) (*models.Transcript, error) {
-	args := m.Called(ctx, jobID)
+func (m *MockCampaignManager) GetVocabularyTerm(ctx context.Context, campaignID, termID string) (*models.VocabularyTerm, error) {
+	args := m.Called(ctx, campaignID, termID)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Transcript), nil
+	return args.Get(0).(*models.VocabularyTerm), nil
 }
 
-func (m *MockTranscriptionManager) GetTranscriptsForSession(ctx context.Context, sessionID string) ([]models.Transcript, error) {
-	args := m.Called(ctx, sessionID)
+func (m *MockCampaignManager) AddVocabularyTerm(ctx context.Context, campaignID string, term models.VocabularyTerm) (*models.VocabularyTerm, error) {
+	args := m.Called(ctx, campaignID, term)
 	if args.Error(1) != nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]models.Transcript), nil
+	return args.Get(0).(*models.VocabularyTerm), nil
 }
 
-func (m *MockTranscriptionManager) DownloadTranscript(ctx context.Context, jobID string, w io.WriterAt) (int64, error) {
-	args := m.Called(ctx, jobID, w)
+func (m *MockCampaignManager) UpdateVocabularyTerm(ctx context.Context, campaignID, termID string, update models.VocabularyTermUpdate) (*models.VocabularyTerm, error) {
+	args := m.Called(ctx, campaignID, termID, update)
 	if args.Error(1) != nil {
-		return 0, args.Error(1)
+		return nil, args.Error(1)
 	}
-	return args.Get(0).(int64), nil
+	return args.Get(0).(*models.VocabularyTerm), nil
 }
 
-func TestAddUser(t *testing.T) {
-	cases := []struct {
-		description           string
-		crateUserRequuest     CreateUserRequest
-		managerUserResponse   *models.User
-		managerError          error
-		expectedUserReponse   *UserResponse
-		expectedErrorResponse *ErrorResponse
-		expectedStatusCode    int
-	}{
-		{
-			description: "user added request, user is added",
-			crateUserRequuest: CreateUserRequest{
-				Handle: "test",
-				Email:  "test@test.com",
-			},
-			managerUserResponse: &models.User{
-				Handle: "test",
-				Email:  "test@test.com",
-				ID:     "abc123",
-			},
-			expectedUserReponse: &UserResponse{
-				Handle: "test",
-				Email:  "test@test.com",
-				ID:     "abc123",
-			},
-			expectedStatusCode: http.StatusCreated,
-		},
-		{
-			description: "user added request, EntityAlreadyExist error returned",
-			crateUserRequuest: CreateUserRequest{
-				Handle: "test",
-				Email:  "test@test.com",
-			},
-			managerError: models.EntityAlreadyExists,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Already Exists",
-			},
-			expectedStatusCode: http.StatusConflict,
-		},
-		{
-			description: "user added request, database error error returned",
-			crateUserRequuest: CreateUserRequest{
-				Handle: "test",
-				Email:  "test@test.com",
-			},
-			managerError: fmt.Errorf("failed to connect to database host: abc"),
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Internal Server Error",
-			},
-			expectedStatusCode: http.StatusInternalServerError,
-		},
+func (m *MockCampaignManager) DeleteVocabularyTerm(ctx context.Context, campaignID, termID string) error {
+	args := m.Called(ctx, campaignID, termID)
+	return args.Error(0)
+}
+
+type MockPlayerManager struct {
+	mock.Mock
+}
+
+func (m *MockPlayerManager) AddPlayer(ctx context.Context, campaignID string, player models.Player) (*models.Player, error) {
+	args := m.Called(ctx, campaignID, player)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*models.Player), nil
+}
 
-	for _, c := range cases {
-		t.Run(c.description, func(t *testing.T) {
-			r := gin.Default()
-			userManager := &MockUserManager{}
-			campaignManager := &MockCampaignManager{}
-			sessionManager := &MockSessionManager{}
-			transcriptionManager := &MockTranscriptionManager{}
+func (m *MockPlayerManager) GetPlayersForCampaign(ctx context.Context, campaignID string) ([]models.Player, error) {
+	args := m.Called(ctx, campaignID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Player), nil
+}
 
-			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager)
-			if c.managerUserResponse != nil {
-				userManager.On("AddNewUser", mock.Anything, mock.Anything).Return(c.managerUserResponse, nil)
-			} else if c.managerError != nil {
-				userManager.On("AddNewUser", mock.Anything, mock.Anything).Return(nil, c.managerError)
-			}
+func (m *MockPlayerManager) GetPlayer(ctx context.Context, campaignID, playerID string) (*models.Player, error) {
+	args := m.Called(ctx, campaignID, playerID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Player), nil
+}
 
-			userBody, err := json.Marshal(c.crateUserRequuest)
-			if err != nil {
-				t.Errorf("unexpected error marshaling cage to json: %s", err)
-				return
-			}
+func (m *MockPlayerManager) UpdatePlayer(ctx context.Context, campaignID, playerID string, update models.PlayerUpdate) (*models.Player, error) {
+	args := m.Called(ctx, campaignID, playerID, update)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Player), nil
+}
 
-			w := httptest.NewRecorder()
+func (m *MockPlayerManager) DeletePlayer(ctx context.Context, campaignID, playerID string) error {
+	args := m.Called(ctx, campaignID, playerID)
+	return args.Error(0)
+}
 
-			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users", bytes.NewReader(userBody))
-			r.ServeHTTP(w, req)
+type MockCharacterManager struct {
+	mock.Mock
+}
 
-			if w.Code != c.expectedStatusCode {
-				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
-			}
-			if c.expectedUserReponse != nil {
-				var actualUserResponse UserResponse
-				err = json.Unmarshal(w.Body.Bytes(), &actualUserResponse)
-				if err != nil {
-					t.Fatalf("unexpected error when unmarshalling response: %s", err)
-					return
-				}
+func (m *MockCharacterManager) AddCharacter(ctx context.Context, campaignID, playerID string, character models.Character) (*models.Character, error) {
+	args := m.Called(ctx, campaignID, playerID, character)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Character), nil
+}
 
-				assert.Equal(t, c.expectedUserReponse.Email, actualUserResponse.Email)
-				assert.Equal(t, c.expectedUserReponse.Handle, actualUserResponse.Handle)
-				assert.Equal(t, c.expectedUserReponse.ID, actualUserResponse.ID)
-			} else if c.expectedErrorResponse != nil {
-				var actualErrorResponse ErrorResponse
-				err = json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
-				if err != nil {
-					t.Fatalf("unexpected error when unmarshalling response: %s", err)
-					return
-				}
-				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
-			}
-		})
+func (m *MockCharacterManager) GetCharactersForPlayer(ctx context.Context, campaignID, playerID string) ([]models.Character, error) {
+	args := m.Called(ctx, campaignID, playerID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).([]models.Character), nil
 }
 
-func TestGetUserByID(t *testing.T) {
-	cases := []struct {
-		description           string
-		userID                string
-		managerUserResponse   *models.User
-		managerError          error
-		expectedUserReponse   *UserResponse
-		expectedErrorResponse *ErrorResponse
-		expectedStatusCode    int
-	}{
-		{
-			description: "user returned",
-			userID:      "testUID",
-			managerUserResponse: &models.User{
-				ID:     "testUID",
-				Handle: "testuser",
-				Email:  "test@test.com",
-			},
-			expectedUserReponse: &UserResponse{
-				Handle: "testuser",
-				Email:  "test@test.com",
-				ID:     "testUID",
-			},
-			expectedStatusCode: http.StatusOK,
-		},
-		{
-			description:        "user not found",
-			userID:             "testUID",
-			managerError:       models.EntityNotFound,
-			expectedStatusCode: http.StatusNotFound,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Not Found",
-			},
-		},
-		{
-			description:        "internal server error",
-			userID:             "testUID",
-			managerError:       fmt.Errorf("could not connect to database"),
-			expectedStatusCode: http.StatusInternalServerError,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Internal Server Error",
-			},
-		},
+func (m *MockCharacterManager) GetCharacter(ctx context.Context, campaignID, playerID, characterID string) (*models.Character, error) {
+	args := m.Called(ctx, campaignID, playerID, characterID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*models.Character), nil
+}
 
-	for _, c := range cases {
-		t.Run(c.description, func(t *testing.T) {
-			r := gin.Default()
-			userManager := &MockUserManager{}
-			campaignManager := &MockCampaignManager{}
-			sessionManager := &MockSessionManager{}
-			transcriptionManager := &MockTranscriptionManager{}
+func (m *MockCharacterManager) UpdateCharacter(ctx context.Context, campaignID, playerID, characterID string, update models.CharacterUpdate) (*models.Character, error) {
+	args := m.Called(ctx, campaignID, playerID, characterID, update)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Character), nil
+}
 
-			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager)
-			if c.managerUserResponse != nil {
-				userManager.On("GetUserByID", mock.Anything, c.userID).Return(c.managerUserResponse, nil)
-			} else if c.managerError != nil {
-				userManager.On("GetUserByID", mock.Anything, mock.Anything).Return(nil, c.managerError)
-			}
+func (m *MockCharacterManager) RetireCharacter(ctx context.Context, campaignID, playerID, characterID string, status models.CharacterStatus) (*models.Character, error) {
+	args := m.Called(ctx, campaignID, playerID, characterID, status)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Character), nil
+}
 
-			w := httptest.NewRecorder()
+type MockWebhookManager struct {
+	mock.Mock
+}
 
-			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/%s", c.userID), nil)
-			r.ServeHTTP(w, req)
+func (m *MockWebhookManager) AddSubscription(ctx context.Context, campaignID string, subscription models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, campaignID, subscription)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), nil
+}
 
-			if w.Code != c.expectedStatusCode {
-				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
-			}
+func (m *MockWebhookManager) GetSubscriptions(ctx context.Context, campaignID string) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx, campaignID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookSubscription), nil
+}
+
+func (m *MockWebhookManager) GetSubscription(ctx context.Context, campaignID, subscriptionID string) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, campaignID, subscriptionID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookSubscription), nil
+}
+
+func (m *MockWebhookManager) DeleteSubscription(ctx context.Context, campaignID, subscriptionID string) error {
+	args := m.Called(ctx, campaignID, subscriptionID)
+	return args.Error(0)
+}
+
+func (m *MockWebhookManager) GetDeliveries(ctx context.Context, campaignID, subscriptionID string, page models.PageRequest) (*models.Page[models.WebhookDelivery], error) {
+	args := m.Called(ctx, campaignID, subscriptionID, page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.WebhookDelivery]), nil
+}
+
+func (m *MockWebhookManager) Redeliver(ctx context.Context, campaignID, subscriptionID, deliveryID string) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, campaignID, subscriptionID, deliveryID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), nil
+}
+
+type MockSessionManager struct {
+	mock.Mock
+}
+
+func (m *MockSessionManager) AddSession(ctx context.Context, campaignID string, session models.Session) (*models.Session, error) {
+	args := m.Called(ctx, campaignID, session)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), nil
+}
+
+func (m *MockSessionManager) GetSessionsForCampaign(ctx context.Context, campaignID string, filter models.SessionFilter, page models.PageRequest) (*models.Page[models.Session], error) {
+	args := m.Called(ctx, campaignID, filter, page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Session]), nil
+}
+
+func (m *MockSessionManager) SetAttendance(ctx context.Context, campaignID, sessionID string, playerIDs []string) ([]models.Player, error) {
+	args := m.Called(ctx, campaignID, sessionID, playerIDs)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Player), nil
+}
+
+func (m *MockSessionManager) GetAttendance(ctx context.Context, campaignID, sessionID string) ([]models.Player, error) {
+	args := m.Called(ctx, campaignID, sessionID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Player), nil
+}
+
+func (m *MockSessionManager) GetAttendanceReport(ctx context.Context, campaignID string) (*models.AttendanceReport, error) {
+	args := m.Called(ctx, campaignID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AttendanceReport), nil
+}
+
+func (m *MockSessionManager) GetSession(ctx context.Context, campaignID, sessionID string) (*models.Session, error) {
+	args := m.Called(ctx, campaignID, sessionID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), nil
+}
+
+func (m *MockSessionManager) UpdateSession(ctx context.Context, campaignID, sessionID string, update models.SessionUpdate) (*models.Session, error) {
+	args := m.Called(ctx, campaignID, sessionID, update)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), nil
+}
+
+func (m *MockSessionManager) DeleteSession(ctx context.Context, campaignID, sessionID string) error {
+	args := m.Called(ctx, campaignID, sessionID)
+	return args.Error(0)
+}
+
+type MockTranscriptionManager struct {
+	mock.Mock
+}
+
+func (m *MockTranscriptionManager) SubmitTranscriptionJob(ctx context.Context, userID, campaignID, sessionID string, audio models.AudioUpload, options models.TranscriptionOptions) (*models.Transcript, error) {
+	content, err := io.ReadAll(audio.Body)
+	if err != nil {
+		return nil, err
+	}
+	args := m.Called(ctx, userID, campaignID, sessionID, audio.Format, string(content), audio.Size, options)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transcript), nil
+}
+
+func (m *MockTranscriptionManager) GetTranscriptJob(ctx context.Context, jobID string) (*models.Transcript, error) {
+	args := m.Called(ctx, jobID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transcript), nil
+}
+
+func (m *MockTranscriptionManager) GetTranscriptsForSession(ctx context.Context, sessionID string, filter models.TranscriptFilter, page models.PageRequest) (*models.Page[models.Transcript], error) {
+	args := m.Called(ctx, sessionID, filter, page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Transcript]), nil
+}
+
+func (m *MockTranscriptionManager) GetTranscriptDocument(ctx context.Context, jobID string) (*models.TranscriptDocument, error) {
+	args := m.Called(ctx, jobID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TranscriptDocument), nil
+}
+
+func (m *MockTranscriptionManager) DownloadSummary(ctx context.Context, jobID string, w io.WriterAt) (int64, error) {
+	args := m.Called(ctx, jobID, w)
+	if args.Error(1) != nil {
+		return 0, args.Error(1)
+	}
+	return args.Get(0).(int64), nil
+}
+
+func (m *MockTranscriptionManager) SetSpeakerAssignments(ctx context.Context, jobID string, assignments []models.SpeakerAssignment) ([]models.SpeakerAssignment, error) {
+	args := m.Called(ctx, jobID, assignments)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SpeakerAssignment), nil
+}
+
+func (m *MockTranscriptionManager) GetSpeakerAssignments(ctx context.Context, jobID string) ([]models.SpeakerAssignment, error) {
+	args := m.Called(ctx, jobID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SpeakerAssignment), nil
+}
+
+func (m *MockTranscriptionManager) UpdateTranscript(ctx context.Context, campaignID, jobID string, update models.TranscriptUpdate) (*models.Transcript, error) {
+	args := m.Called(ctx, campaignID, jobID, update)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transcript), nil
+}
+
+func (m *MockTranscriptionManager) DeleteTranscript(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+func (m *MockTranscriptionManager) RetryTranscript(ctx context.Context, sessionID, jobID string) (*models.Transcript, error) {
+	args := m.Called(ctx, sessionID, jobID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transcript), nil
+}
+
+func (m *MockTranscriptionManager) GetTranscriptFileURL(ctx context.Context, jobID string, file models.TranscriptFile) (*models.PresignedURL, error) {
+	args := m.Called(ctx, jobID, file)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PresignedURL), nil
+}
+
+type MockUploadManager struct {
+	mock.Mock
+}
+
+func (m *MockUploadManager) CreateUpload(ctx context.Context, sessionID string, audioFormat models.AudioFormat, length int64, options models.TranscriptionOptions) (*models.ResumableUpload, error) {
+	args := m.Called(ctx, sessionID, audioFormat, length, options)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ResumableUpload), nil
+}
+
+func (m *MockUploadManager) GetUpload(ctx context.Context, sessionID, uploadID string) (*models.ResumableUpload, error) {
+	args := m.Called(ctx, sessionID, uploadID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ResumableUpload), nil
+}
+
+func (m *MockUploadManager) AppendChunk(ctx context.Context, sessionID, uploadID string, offset int64, chunk io.Reader, chunkLength int64) (*models.ResumableUpload, error) {
+	content, err := io.ReadAll(chunk)
+	if err != nil {
+		return nil, err
+	}
+	args := m.Called(ctx, sessionID, uploadID, offset, string(content), chunkLength)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ResumableUpload), nil
+}
+
+func (m *MockUploadManager) FinalizeUpload(ctx context.Context, sessionID, uploadID string) (*models.Transcript, error) {
+	args := m.Called(ctx, sessionID, uploadID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transcript), nil
+}
+
+func (m *MockUploadManager) DeleteUpload(ctx context.Context, sessionID, uploadID string) error {
+	args := m.Called(ctx, sessionID, uploadID)
+	return args.Error(0)
+}
+
+func (m *MockUploadManager) CreateDirectUpload(ctx context.Context, sessionID string, audioFormat models.AudioFormat, constraints models.UploadConstraints, options models.TranscriptionOptions) (*models.DirectUpload, error) {
+	args := m.Called(ctx, sessionID, audioFormat, constraints, options)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DirectUpload), nil
+}
+
+func (m *MockUploadManager) ConfirmDirectUpload(ctx context.Context, sessionID, uploadID string) (*models.Transcript, error) {
+	args := m.Called(ctx, sessionID, uploadID)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transcript), nil
+}
+
+func TestAddUser(t *testing.T) {
+	cases := []struct {
+		description           string
+		crateUserRequuest     CreateUserRequest
+		managerUserResponse   *models.User
+		managerError          error
+		expectedUserReponse   *UserResponse
+		expectedErrorResponse *ErrorResponse
+		expectedStatusCode    int
+	}{
+		{
+			description: "user added request, user is added",
+			crateUserRequuest: CreateUserRequest{
+				Handle: "test",
+				Email:  "test@test.com",
+			},
+			managerUserResponse: &models.User{
+				Handle: "test",
+				Email:  "test@test.com",
+				ID:     "abc123",
+			},
+			expectedUserReponse: &UserResponse{
+				Handle: "test",
+				Email:  "test@test.com",
+				ID:     "abc123",
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description: "user added request, EntityAlreadyExist error returned",
+			crateUserRequuest: CreateUserRequest{
+				Handle: "test",
+				Email:  "test@test.com",
+			},
+			managerError: models.EntityAlreadyExists,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Already Exists",
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			description: "user added request, database error error returned",
+			crateUserRequuest: CreateUserRequest{
+				Handle: "test",
+				Email:  "test@test.com",
+			},
+			managerError: fmt.Errorf("failed to connect to database host: abc"),
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Internal Server Error",
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerUserResponse != nil {
+				userManager.On("AddNewUser", mock.Anything, mock.Anything).Return(c.managerUserResponse, nil)
+			} else if c.managerError != nil {
+				userManager.On("AddNewUser", mock.Anything, mock.Anything).Return(nil, c.managerError)
+			}
+
+			userBody, err := json.Marshal(c.crateUserRequuest)
+			if err != nil {
+				t.Errorf("unexpected error marshaling cage to json: %s", err)
+				return
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users", bytes.NewReader(userBody))
+			req.Header.Set("Authorization", "Bearer abc123")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+			}
+			if c.expectedUserReponse != nil {
+				var actualUserResponse UserResponse
+				err = json.Unmarshal(w.Body.Bytes(), &actualUserResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+
+				assert.Equal(t, c.expectedUserReponse.Email, actualUserResponse.Email)
+				assert.Equal(t, c.expectedUserReponse.Handle, actualUserResponse.Handle)
+				assert.Equal(t, c.expectedUserReponse.ID, actualUserResponse.ID)
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err = json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestGetUserByID(t *testing.T) {
+	cases := []struct {
+		description           string
+		userID                string
+		managerUserResponse   *models.User
+		managerError          error
+		expectedUserReponse   *UserResponse
+		expectedErrorResponse *ErrorResponse
+		expectedStatusCode    int
+	}{
+		{
+			description: "user returned",
+			userID:      "testUID",
+			managerUserResponse: &models.User{
+				ID:     "testUID",
+				Handle: "testuser",
+				Email:  "test@test.com",
+			},
+			expectedUserReponse: &UserResponse{
+				Handle: "testuser",
+				Email:  "test@test.com",
+				ID:     "testUID",
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "user not found",
+			userID:             "testUID",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Not Found",
+			},
+		},
+		{
+			description:        "internal server error",
+			userID:             "testUID",
+			managerError:       fmt.Errorf("could not connect to database"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Internal Server Error",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerUserResponse != nil {
+				userManager.On("GetUserByID", mock.Anything, c.userID).Return(c.managerUserResponse, nil)
+			} else if c.managerError != nil {
+				userManager.On("GetUserByID", mock.Anything, mock.Anything).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/%s", c.userID), nil)
+			req.Header.Set("Authorization", "Bearer "+c.userID)
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+			}
 			if c.expectedUserReponse != nil {
 				var actualUserResponse UserResponse
 				err := json.Unmarshal(w.Body.Bytes(), &actualUserResponse)
@@ -296,80 +725,2860 @@ func TestGetUserByID(t *testing.T) {
 					return
 				}
 
-				assert.Equal(t, c.expectedUserReponse.Email, actualUserResponse.Email)
-				assert.Equal(t, c.expectedUserReponse.Handle, actualUserResponse.Handle)
-				assert.Equal(t, c.expectedUserReponse.ID, actualUserResponse.ID)
-			} else if c.expectedErrorResponse != nil {
-				var actualErrorResponse ErrorResponse
-				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				assert.Equal(t, c.expectedUserReponse.Email, actualUserResponse.Email)
+				assert.Equal(t, c.expectedUserReponse.Handle, actualUserResponse.Handle)
+				assert.Equal(t, c.expectedUserReponse.ID, actualUserResponse.ID)
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestCreateCampaign(t *testing.T) {
+	cases := []struct {
+		description              string
+		userID                   string
+		campaign                 *models.Campaign
+		managerCampaignResponse  *models.Campaign
+		managerError             error
+		expectedCampaignResponse *CampaignResponse
+		expectedErrorResponse    *ErrorResponse
+		expectedStatusCode       int
+	}{
+		{
+			description: "campaign created",
+			userID:      "abc123",
+			campaign: &models.Campaign{
+				ID:   "cmp123",
+				Name: "one-shot",
+				Link: "http://abc.com",
+			},
+			managerCampaignResponse: &models.Campaign{
+				ID:   "cmp123",
+				Name: "one-shot",
+				Link: "http://abc.com",
+			},
+			expectedCampaignResponse: &CampaignResponse{
+				ID:   "cmp123",
+				Name: "one-shot",
+				Link: "http://abc.com",
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description: "database error returned",
+			userID:      "abc123",
+			campaign: &models.Campaign{
+				ID:   "cmp123",
+				Name: "one-shot",
+				Link: "http://abc.com",
+			},
+			managerError: fmt.Errorf("something went wrong"),
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Internal Server Error",
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			description: "conflicted error returned",
+			userID:      "abc123",
+			campaign: &models.Campaign{
+				ID:   "cmp123",
+				Name: "one-shot",
+				Link: "http://abc.com",
+			},
+			managerError: models.Conflicted,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Conflict",
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.expectedCampaignResponse != nil {
+				campaignManager.On("AddCampaign", mock.Anything, c.userID, mock.Anything).Return(c.managerCampaignResponse, nil)
+			} else if c.managerError != nil {
+				campaignManager.On("AddCampaign", mock.Anything, mock.Anything, mock.Anything).Return(nil, c.managerError)
+			}
+
+			campaignBody, err := json.Marshal(c.campaign)
+			if err != nil {
+				t.Errorf("unexpected error marshaling cage to json: %s", err)
+				return
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("POST", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns", c.userID), bytes.NewReader(campaignBody))
+			req.Header.Set("Authorization", "Bearer "+c.userID)
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+			}
+			if c.expectedCampaignResponse != nil {
+				var actualCampaignResponse CampaignResponse
+				err = json.Unmarshal(w.Body.Bytes(), &actualCampaignResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+
+				assert.Equal(t, c.expectedCampaignResponse.Name, actualCampaignResponse.Name)
+				assert.Equal(t, c.expectedCampaignResponse.Link, actualCampaignResponse.Link)
+				assert.Equal(t, c.expectedCampaignResponse.ID, actualCampaignResponse.ID)
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err = json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestGetCampaigns(t *testing.T) {
+	cases := []struct {
+		description               string
+		userID                    string
+		managerCampaignsResponse  []models.Campaign
+		managerError              error
+		expectedCampaignsResponse []CampaignResponse
+		expectedErrorResponse     *ErrorResponse
+		expectedStatusCode        int
+	}{
+		{
+			description: "campaigns returned",
+			userID:      "abc123",
+			managerCampaignsResponse: []models.Campaign{
+				{
+					ID:   "cmp123",
+					Name: "one-shot",
+					Link: "http://campaign1",
+					Role: models.CampaignOwner,
+				},
+				{
+					ID:   "cmp456",
+					Name: "two-shot",
+					Link: "http://campaign2",
+					Role: models.CampaignPlayer,
+				},
+			},
+			expectedCampaignsResponse: []CampaignResponse{
+				{
+					ID:   "cmp123",
+					Name: "one-shot",
+					Link: "http://campaign1",
+					Role: "Owner",
+				},
+				{
+					ID:   "cmp456",
+					Name: "two-shot",
+					Link: "http://campaign2",
+					Role: "Player",
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "campaigns not found",
+			userID:             "abc123",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Not Found",
+			},
+		},
+		{
+			description:        "internal server error",
+			userID:             "abc123",
+			managerError:       fmt.Errorf("an error has occurred"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Internal Server Error",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.expectedCampaignsResponse != nil {
+				campaignManager.On("GetCampaignsForUser", mock.Anything, c.userID, models.PageRequest{}).Return(&models.Page[models.Campaign]{Items: c.managerCampaignsResponse}, nil)
+			} else if c.managerError != nil {
+				campaignManager.On("GetCampaignsForUser", mock.Anything, mock.Anything, mock.Anything).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns", c.userID), nil)
+			req.Header.Set("Authorization", "Bearer "+c.userID)
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedCampaignsResponse != nil {
+				var actualCampaignsPage ListResponse[CampaignResponse]
+				err := json.Unmarshal(w.Body.Bytes(), &actualCampaignsPage)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				actualCampaignsResponse := actualCampaignsPage.Items
+				if len(c.expectedCampaignsResponse) != len(actualCampaignsResponse) {
+					t.Errorf("expected %d campaigns got %d", len(c.expectedCampaignsResponse), len(actualCampaignsResponse))
+					return
+				}
+
+				for i, expected := range c.expectedCampaignsResponse {
+					actual := actualCampaignsResponse[i]
+					assert.Equal(t, expected.Name, actual.Name)
+					assert.Equal(t, expected.Link, actual.Link)
+					assert.Equal(t, expected.ID, actual.ID)
+					assert.Equal(t, expected.Role, actual.Role)
+				}
+
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestAddSession(t *testing.T) {
+	cases := []struct {
+		description             string
+		userID                  string
+		campaignID              string
+		session                 *models.Session
+		managerSessionResponse  *models.Session
+		managerError            error
+		expectedSessionResponse *SessionResponse
+		expectedErrorResponse   *ErrorResponse
+		expectedStatusCode      int
+	}{
+		{
+			description: "session added",
+			userID:      "abc123",
+			campaignID:  "efg456",
+			session: &models.Session{
+				Title:       "session-0",
+				SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
+			},
+			managerSessionResponse: &models.Session{
+				ID:          "sesUUID",
+				Title:       "session-0",
+				SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
+			},
+			expectedSessionResponse: &SessionResponse{
+				ID:          "sesUUID",
+				Title:       "session-0",
+				SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description: "session manager returns error unprocessable entity",
+			userID:      "abc123",
+			campaignID:  "efg456",
+			session: &models.Session{
+				Title:       "session-0",
+				SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
+			},
+			managerError: models.InvalidEntity,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Invalid Request",
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description: "session manager returns generic error, Internal Server error returned",
+			userID:      "abc123",
+			campaignID:  "efg456",
+			session: &models.Session{
+				Title:       "session-0",
+				SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
+			},
+			managerError: fmt.Errorf("database connection failed"),
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Internal Server Error",
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.expectedSessionResponse != nil {
+				sessionManager.On("AddSession", mock.Anything, c.campaignID, mock.Anything).Return(c.managerSessionResponse, nil)
+			} else if c.managerError != nil {
+				sessionManager.On("AddSession", mock.Anything, mock.Anything, mock.Anything).Return(nil, c.managerError)
+			}
+
+			sessionBody, err := json.Marshal(c.session)
+			if err != nil {
+				t.Errorf("unexpected error marshaling cage to json: %s", err)
+				return
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("POST", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions", c.userID, c.campaignID), bytes.NewReader(sessionBody))
+			req.Header.Set("Authorization", "Bearer "+c.userID)
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedSessionResponse != nil {
+				var actualSessionResponse SessionResponse
+				err = json.Unmarshal(w.Body.Bytes(), &actualSessionResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+
+				assert.Equal(t, c.expectedSessionResponse.Title, actualSessionResponse.Title)
+				assert.Equal(t, c.expectedSessionResponse.SessionDate, actualSessionResponse.SessionDate)
+				assert.Equal(t, c.expectedSessionResponse.ID, actualSessionResponse.ID)
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err = json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestGetSessionsForCampaign(t *testing.T) {
+	cases := []struct {
+		description              string
+		userID                   string
+		campaignID               string
+		managerSessionssResponse []models.Session
+		managerError             error
+		expectedSessionsResponse []SessionResponse
+		expectedErrorResponse    *ErrorResponse
+		expectedStatusCode       int
+	}{
+		{
+			description: "sessions returned",
+			userID:      "abc123",
+			campaignID:  "cmp123",
+			managerSessionssResponse: []models.Session{
+				{
+					ID:          "ses123",
+					Title:       "sesion0",
+					SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
+				},
+				{
+					ID:          "ses456",
+					Title:       "session1",
+					SessionDate: time.Date(2024, time.January, 21, 19, 0, 0, 0, time.UTC),
+				},
+			},
+			expectedSessionsResponse: []SessionResponse{
+				{
+					ID:          "ses123",
+					Title:       "sesion0",
+					SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
+				},
+				{
+					ID:          "ses456",
+					Title:       "session1",
+					SessionDate: time.Date(2024, time.January, 21, 19, 0, 0, 0, time.UTC),
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "sessions not found",
+			userID:             "abc123",
+			campaignID:         "cmp123",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Not Found",
+			},
+		},
+		{
+			description:        "internal server error",
+			userID:             "abc123",
+			campaignID:         "cmp123",
+			managerError:       fmt.Errorf("an error has occurred"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Internal Server Error",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.expectedSessionsResponse != nil {
+				sessionManager.On("GetSessionsForCampaign", mock.Anything, c.campaignID, models.SessionFilter{}, models.PageRequest{}).Return(&models.Page[models.Session]{Items: c.managerSessionssResponse}, nil)
+			} else if c.managerError != nil {
+				sessionManager.On("GetSessionsForCampaign", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions", c.userID, c.campaignID), nil)
+			req.Header.Set("Authorization", "Bearer "+c.userID)
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedSessionsResponse != nil {
+				var actualSessionsPage ListResponse[SessionResponse]
+				err := json.Unmarshal(w.Body.Bytes(), &actualSessionsPage)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				actualSessionsResponse := actualSessionsPage.Items
+				if len(c.expectedSessionsResponse) != len(actualSessionsResponse) {
+					t.Errorf("expected %d campaigns got %d", len(c.expectedSessionsResponse), len(actualSessionsResponse))
+					return
+				}
+
+				for i, expected := range c.expectedSessionsResponse {
+					actual := actualSessionsResponse[i]
+					assert.Equal(t, expected.Title, actual.Title)
+					assert.Equal(t, expected.SessionDate, actual.SessionDate)
+					assert.Equal(t, expected.ID, actual.ID)
+				}
+
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestSubmitTranscriptJob(t *testing.T) {
+	cases := []struct {
+		description                string
+		userID                     string
+		campaignID                 string
+		sessionID                  string
+		audioFile                  []byte
+		contentType                string
+		multipartFields            map[string]string
+		query                      string
+		expectedSize               int64
+		expectedOptions            models.TranscriptionOptions
+		managerTranscriptResponse  *models.Transcript
+		managerError               error
+		expectedTranscriptResponse *TranscriptResponse
+		expectedErrorResponse      *ErrorResponse
+		expectedStatusCode         int
+	}{
+		{
+			description: "session added",
+			userID:      "abc123",
+			campaignID:  "efg456",
+			sessionID:   "ses123",
+			audioFile:   []byte("test audio"),
+			contentType: "audio/mpeg",
+			managerTranscriptResponse: &models.Transcript{
+				JobID:              "ts123",
+				AudioLocation:      "audio.mp3",
+				AudioFormat:        models.MP3,
+				TranscriptLocation: "tx.text",
+				SummaryLocation:    "",
+				Status:             models.NotStarted,
+			},
+			expectedTranscriptResponse: &TranscriptResponse{
+				ID:     "ts123",
+				Status: "NotStarted",
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:     "max speakers passed to the manager",
+			userID:          "abc123",
+			campaignID:      "efg456",
+			sessionID:       "ses123",
+			audioFile:       []byte("test audio"),
+			contentType:     "audio/mpeg",
+			query:           "?maxSpeakers=4",
+			expectedOptions: models.TranscriptionOptions{MaxSpeakers: 4},
+			managerTranscriptResponse: &models.Transcript{
+				JobID:  "ts123",
+				Status: models.Transcribing,
+			},
+			expectedTranscriptResponse: &TranscriptResponse{
+				ID:     "ts123",
+				Status: "Transcribing",
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:     "provider passed to the manager and returned",
+			userID:          "abc123",
+			campaignID:      "efg456",
+			sessionID:       "ses123",
+			audioFile:       []byte("test audio"),
+			contentType:     "audio/mpeg",
+			query:           "?provider=whisper",
+			expectedOptions: models.TranscriptionOptions{Provider: "whisper"},
+			managerTranscriptResponse: &models.Transcript{
+				JobID:    "ts123",
+				Status:   models.Transcribing,
+				Provider: "whisper",
+			},
+			expectedTranscriptResponse: &TranscriptResponse{
+				ID:       "ts123",
+				Status:   "Transcribing",
+				Provider: "whisper",
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:     "language passed to the manager and the detected language returned",
+			userID:          "abc123",
+			campaignID:      "efg456",
+			sessionID:       "ses123",
+			audioFile:       []byte("test audio"),
+			contentType:     "audio/mpeg",
+			query:           "?language=auto",
+			expectedOptions: models.TranscriptionOptions{Language: "auto"},
+			managerTranscriptResponse: &models.Transcript{
+				JobID:            "ts123",
+				Status:           models.Done,
+				Language:         models.AutoLanguage,
+				DetectedLanguage: "es-ES",
+			},
+			expectedTranscriptResponse: &TranscriptResponse{
+				ID:               "ts123",
+				Status:           "Done",
+				Language:         "auto",
+				DetectedLanguage: "es-ES",
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:        "language is not supported, 422 returned",
+			userID:             "abc123",
+			campaignID:         "efg456",
+			sessionID:          "ses123",
+			audioFile:          []byte("test audio"),
+			contentType:        "audio/mpeg",
+			query:              "?language=tlh-KL",
+			expectedOptions:    models.TranscriptionOptions{Language: "tlh-KL"},
+			managerError:       models.InvalidEntity,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description: "max speakers is not a number",
+			userID:      "abc123",
+			campaignID:  "efg456",
+			sessionID:   "ses123",
+			audioFile:   []byte("test audio"),
+			contentType: "audio/mpeg",
+			query:       "?maxSpeakers=many",
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "maxSpeakers must be a number",
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:  "transcript manager returns error unprocessable entity",
+			userID:       "abc123",
+			campaignID:   "efg456",
+			audioFile:    []byte("test audio"),
+			contentType:  "audio/mpeg",
+			managerError: models.InvalidEntity,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Invalid Request",
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:  "session manager returns generic error, Internal Server error returned",
+			userID:       "abc123",
+			campaignID:   "efg456",
+			audioFile:    []byte("test audio"),
+			contentType:  "audio/mpeg",
+			managerError: fmt.Errorf("database connection failed"),
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Internal Server Error",
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			description:  "audio is over the size limit, Payload Too Large returned",
+			userID:       "abc123",
+			campaignID:   "efg456",
+			audioFile:    []byte("test audio"),
+			contentType:  "audio/mpeg",
+			managerError: models.TooLarge,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Payload Too Large",
+			},
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			description:     "multipart upload streams the audio part with its fields",
+			userID:          "abc123",
+			campaignID:      "efg456",
+			sessionID:       "ses123",
+			audioFile:       []byte("test audio"),
+			contentType:     "audio/mpeg",
+			multipartFields: map[string]string{"maxSpeakers": "3", "provider": "fake", "language": "de-DE"},
+			query:           "?maxSpeakers=4",
+			expectedSize:    -1,
+			expectedOptions: models.TranscriptionOptions{MaxSpeakers: 3, Provider: "fake", Language: "de-DE"},
+			managerTranscriptResponse: &models.Transcript{
+				JobID:       "ts123",
+				Status:      models.Transcribing,
+				AudioSHA256: "abc",
+				AudioSize:   10,
+			},
+			expectedTranscriptResponse: &TranscriptResponse{
+				ID:          "ts123",
+				Status:      "Transcribing",
+				AudioSHA256: "abc",
+				AudioSize:   10,
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:     "multipart fields other than the job options are skipped",
+			userID:          "abc123",
+			campaignID:      "efg456",
+			sessionID:       "ses123",
+			audioFile:       []byte("test audio"),
+			contentType:     "audio/mpeg",
+			multipartFields: map[string]string{"maxSpeakers": "3", "notes": strings.Repeat("a", maxFormFieldBytes+1)},
+			query:           "?maxSpeakers=4",
+			expectedSize:    -1,
+			expectedOptions: models.TranscriptionOptions{MaxSpeakers: 3},
+			managerTranscriptResponse: &models.Transcript{
+				JobID:       "ts123",
+				Status:      models.Transcribing,
+				AudioSHA256: "abc",
+				AudioSize:   10,
+			},
+			expectedTranscriptResponse: &TranscriptResponse{
+				ID:          "ts123",
+				Status:      "Transcribing",
+				AudioSHA256: "abc",
+				AudioSize:   10,
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description: "content type alias with parameters accepted",
+			userID:      "abc123",
+			campaignID:  "efg456",
+			sessionID:   "ses123",
+			audioFile:   []byte("test audio"),
+			contentType: "audio/x-mpeg-3; charset=binary",
+			managerTranscriptResponse: &models.Transcript{
+				JobID:  "ts123",
+				Status: models.Transcribing,
+			},
+			expectedTranscriptResponse: &TranscriptResponse{
+				ID:     "ts123",
+				Status: "Transcribing",
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description: "content type is not supported, supported types listed",
+			userID:      "abc123",
+			campaignID:  "efg456",
+			sessionID:   "ses123",
+			audioFile:   []byte("test audio"),
+			contentType: "text/plain",
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Content-Type \"text/plain\" is not supported. Supported types are audio/mpeg, audio/mp4, audio/wav, audio/flac, audio/amr, audio/ogg, audio/webm",
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "multipart upload without an audio part",
+			userID:          "abc123",
+			campaignID:      "efg456",
+			sessionID:       "ses123",
+			multipartFields: map[string]string{"maxSpeakers": "3"},
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "multipart body must hold the recording in an \"audio\" part after any other fields",
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			expectedSize := int64(len(c.audioFile))
+			if c.expectedSize != 0 {
+				expectedSize = c.expectedSize
+			}
+			if c.managerTranscriptResponse != nil {
+				transcriptionManager.On("SubmitTranscriptionJob", mock.Anything, c.userID, c.campaignID, c.sessionID, models.AudioFormat(models.MP3), string(c.audioFile), expectedSize, c.expectedOptions).Return(c.managerTranscriptResponse, nil)
+			} else if c.managerError != nil {
+				transcriptionManager.On("SubmitTranscriptionJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			body := bytes.NewBuffer(c.audioFile)
+			contentType := c.contentType
+			if c.multipartFields != nil {
+				body = &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				for name, value := range c.multipartFields {
+					writer.WriteField(name, value)
+				}
+				if c.audioFile != nil {
+					header := textproto.MIMEHeader{}
+					header.Set("Content-Disposition", `form-data; name="audio"; filename="session.mp3"`)
+					header.Set("Content-Type", c.contentType)
+					part, _ := writer.CreatePart(header)
+					part.Write(c.audioFile)
+				}
+				writer.Close()
+				contentType = writer.FormDataContentType()
+			}
+			req, _ := http.NewRequest("POST", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions/%s/transcripts%s", c.userID, c.campaignID, c.sessionID, c.query), body)
+			req.Header.Set("Authorization", "Bearer "+c.userID)
+			req.Header.Set("Content-Type", contentType)
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedTranscriptResponse != nil {
+				var actualTranscriptResponse TranscriptResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualTranscriptResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+
+				assert.Equal(t, c.expectedTranscriptResponse.Status, actualTranscriptResponse.Status)
+				assert.Equal(t, c.expectedTranscriptResponse.ID, actualTranscriptResponse.ID)
+				assert.Equal(t, c.expectedTranscriptResponse.AudioSHA256, actualTranscriptResponse.AudioSHA256)
+				assert.Equal(t, c.expectedTranscriptResponse.AudioSize, actualTranscriptResponse.AudioSize)
+				assert.Equal(t, c.expectedTranscriptResponse.Provider, actualTranscriptResponse.Provider)
+				assert.Equal(t, c.expectedTranscriptResponse.Language, actualTranscriptResponse.Language)
+				assert.Equal(t, c.expectedTranscriptResponse.DetectedLanguage, actualTranscriptResponse.DetectedLanguage)
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestCreateUpload(t *testing.T) {
+	cases := []struct {
+		description            string
+		body                   string
+		expectedLength         int64
+		expectedOptions        models.TranscriptionOptions
+		managerUpload          *models.ResumableUpload
+		managerError           error
+		expectedUploadResponse *UploadResponse
+		expectedStatusCode     int
+	}{
+		{
+			description:     "upload created",
+			body:            `{"contentType": "audio/mpeg", "length": 12000000, "maxSpeakers": 4, "provider": "whisper", "language": "en-GB"}`,
+			expectedLength:  12000000,
+			expectedOptions: models.TranscriptionOptions{MaxSpeakers: 4, Provider: "whisper", Language: "en-GB"},
+			managerUpload:   &models.ResumableUpload{ID: "upl123", Length: 12000000},
+			expectedUploadResponse: &UploadResponse{
+				ID:     "upl123",
+				Length: 12000000,
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:        "content type is not audio, 422 returned",
+			body:               `{"contentType": "text/plain", "length": 100}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "upload is over the size limit, 413 returned",
+			body:               `{"contentType": "audio/mpeg", "length": 12000000}`,
+			expectedLength:     12000000,
+			managerError:       models.TooLarge,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			uploadManager := &MockUploadManager{}
+
+			NewHttpAPI(r, &MockUserManager{}, &MockCampaignManager{}, &MockSessionManager{}, &MockTranscriptionManager{}, uploadManager, &MockPlayerManager{}, &MockCharacterManager{}, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerUpload != nil || c.managerError != nil {
+				uploadManager.On("CreateUpload", mock.Anything, "ses123", models.AudioFormat(models.MP3), c.expectedLength, c.expectedOptions).Return(c.managerUpload, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/uploads", strings.NewReader(c.body))
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedUploadResponse != nil {
+				var actualUploadResponse UploadResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &actualUploadResponse); err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+				}
+				assert.Equal(t, *c.expectedUploadResponse, actualUploadResponse)
+				assert.Equal(t, "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/uploads/upl123", w.Header().Get("Location"))
+				assert.Equal(t, "0", w.Header().Get("Upload-Offset"))
+			}
+			uploadManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAppendUploadChunk(t *testing.T) {
+	cases := []struct {
+		description        string
+		contentType        string
+		offsetHeader       string
+		chunk              string
+		expectManagerCall  bool
+		managerUpload      *models.ResumableUpload
+		managerError       error
+		expectedOffset     string
+		expectedStatusCode int
+	}{
+		{
+			description:        "chunk appended, new offset returned",
+			contentType:        "application/offset+octet-stream",
+			offsetHeader:       "5",
+			chunk:              "audio",
+			expectManagerCall:  true,
+			managerUpload:      &models.ResumableUpload{ID: "upl123", Offset: 10, Length: 10},
+			expectedOffset:     "10",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "offset does not match the upload, 409 returned",
+			contentType:        "application/offset+octet-stream",
+			offsetHeader:       "5",
+			chunk:              "audio",
+			expectManagerCall:  true,
+			managerError:       models.Conflicted,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			description:        "offset header missing, 422 returned",
+			contentType:        "application/offset+octet-stream",
+			chunk:              "audio",
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "chunk has the wrong content type, 415 returned",
+			contentType:        "audio/mpeg",
+			offsetHeader:       "5",
+			chunk:              "audio",
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			uploadManager := &MockUploadManager{}
+
+			NewHttpAPI(r, &MockUserManager{}, &MockCampaignManager{}, &MockSessionManager{}, &MockTranscriptionManager{}, uploadManager, &MockPlayerManager{}, &MockCharacterManager{}, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.expectManagerCall {
+				uploadManager.On("AppendChunk", mock.Anything, "ses123", "upl123", int64(5), c.chunk, int64(len(c.chunk))).Return(c.managerUpload, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("PATCH", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/uploads/upl123", strings.NewReader(c.chunk))
+			req.Header.Set("Authorization", "Bearer testUID")
+			req.Header.Set("Content-Type", c.contentType)
+			if c.offsetHeader != "" {
+				req.Header.Set("Upload-Offset", c.offsetHeader)
+			}
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			assert.Equal(t, c.expectedOffset, w.Header().Get("Upload-Offset"))
+			uploadManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetUploadOffset(t *testing.T) {
+	r := gin.Default()
+	uploadManager := &MockUploadManager{}
+
+	NewHttpAPI(r, &MockUserManager{}, &MockCampaignManager{}, &MockSessionManager{}, &MockTranscriptionManager{}, uploadManager, &MockPlayerManager{}, &MockCharacterManager{}, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+	uploadManager.On("GetUpload", mock.Anything, "ses123", "upl123").Return(&models.ResumableUpload{ID: "upl123", Offset: 5242880, Length: 12000000}, nil)
+
+	w := httptest.NewRecorder()
+
+	req, _ := http.NewRequest("HEAD", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/uploads/upl123", nil)
+	req.Header.Set("Authorization", "Bearer testUID")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "5242880", w.Header().Get("Upload-Offset"))
+	assert.Equal(t, "12000000", w.Header().Get("Upload-Length"))
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+}
+
+func TestFinalizeUpload(t *testing.T) {
+	cases := []struct {
+		description                string
+		managerTranscript          *models.Transcript
+		managerError               error
+		expectedTranscriptResponse *TranscriptResponse
+		expectedStatusCode         int
+	}{
+		{
+			description:       "upload finalized, transcript returned",
+			managerTranscript: &models.Transcript{JobID: "ts123", Status: models.Transcribing, AudioSHA256: "abc", AudioSize: 12000000},
+			expectedTranscriptResponse: &TranscriptResponse{
+				ID:          "ts123",
+				Status:      "Transcribing",
+				AudioSHA256: "abc",
+				AudioSize:   12000000,
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:        "upload is incomplete, 422 returned",
+			managerError:       models.InvalidEntity,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "upload not found, 404 returned",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			uploadManager := &MockUploadManager{}
+
+			NewHttpAPI(r, &MockUserManager{}, &MockCampaignManager{}, &MockSessionManager{}, &MockTranscriptionManager{}, uploadManager, &MockPlayerManager{}, &MockCharacterManager{}, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			uploadManager.On("FinalizeUpload", mock.Anything, "ses123", "upl123").Return(c.managerTranscript, c.managerError)
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/uploads/upl123/finalize", nil)
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedTranscriptResponse != nil {
+				var actualTranscriptResponse TranscriptResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &actualTranscriptResponse); err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+				}
+				assert.Equal(t, *c.expectedTranscriptResponse, actualTranscriptResponse)
+			}
+		})
+	}
+}
+
+func TestCreateDirectUpload(t *testing.T) {
+	expiresAt := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		description                  string
+		body                         string
+		expectedConstraints          models.UploadConstraints
+		managerUpload                *models.DirectUpload
+		managerError                 error
+		expectedDirectUploadResponse *DirectUploadResponse
+		expectedStatusCode           int
+	}{
+		{
+			description:         "upload URL issued",
+			body:                `{"contentType": "audio/ogg", "length": 100, "sha256": "abc"}`,
+			expectedConstraints: models.UploadConstraints{ContentType: "audio/ogg", Length: 100, SHA256: "abc"},
+			managerUpload: &models.DirectUpload{
+				ID: "upl123",
+				UploadURL: &models.PresignedURL{
+					URL:       "https://bucket.test/audio-1",
+					Method:    "PUT",
+					Headers:   map[string]string{"Content-Type": "audio/ogg"},
+					ExpiresAt: expiresAt,
+				},
+			},
+			expectedDirectUploadResponse: &DirectUploadResponse{
+				ID: "upl123",
+				UploadURL: PresignedURLResponse{
+					URL:       "https://bucket.test/audio-1",
+					Method:    "PUT",
+					Headers:   map[string]string{"Content-Type": "audio/ogg"},
+					ExpiresAt: expiresAt,
+				},
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:         "upload is over the size limit, 413 returned",
+			body:                `{"contentType": "audio/ogg", "length": 100}`,
+			expectedConstraints: models.UploadConstraints{ContentType: "audio/ogg", Length: 100},
+			managerError:        models.TooLarge,
+			expectedStatusCode:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			description:        "content type is not audio, 422 returned",
+			body:               `{"contentType": "image/png", "length": 100}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			uploadManager := &MockUploadManager{}
+
+			NewHttpAPI(r, &MockUserManager{}, &MockCampaignManager{}, &MockSessionManager{}, &MockTranscriptionManager{}, uploadManager, &MockPlayerManager{}, &MockCharacterManager{}, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerUpload != nil || c.managerError != nil {
+				uploadManager.On("CreateDirectUpload", mock.Anything, "ses123", models.AudioFormat(models.OGG), c.expectedConstraints, models.TranscriptionOptions{}).Return(c.managerUpload, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/direct-uploads", strings.NewReader(c.body))
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedDirectUploadResponse != nil {
+				var actualResponse DirectUploadResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &actualResponse); err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+				}
+				assert.Equal(t, *c.expectedDirectUploadResponse, actualResponse)
+			}
+			uploadManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConfirmDirectUpload(t *testing.T) {
+	cases := []struct {
+		description        string
+		managerTranscript  *models.Transcript
+		managerError       error
+		expectedStatusCode int
+	}{
+		{
+			description:        "upload confirmed",
+			managerTranscript:  &models.Transcript{JobID: "ts123", Status: models.Transcribing},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:        "audio was not uploaded, 422 returned",
+			managerError:       models.InvalidEntity,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "upload already confirmed, 409 returned",
+			managerError:       models.Conflicted,
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			uploadManager := &MockUploadManager{}
+
+			NewHttpAPI(r, &MockUserManager{}, &MockCampaignManager{}, &MockSessionManager{}, &MockTranscriptionManager{}, uploadManager, &MockPlayerManager{}, &MockCharacterManager{}, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			uploadManager.On("ConfirmDirectUpload", mock.Anything, "ses123", "upl123").Return(c.managerTranscript, c.managerError)
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/direct-uploads/upl123/confirm", nil)
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.managerTranscript != nil {
+				var actualTranscriptResponse TranscriptResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &actualTranscriptResponse); err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+				}
+				assert.Equal(t, "ts123", actualTranscriptResponse.ID)
+			}
+		})
+	}
+}
+
+func TestGetTranscriptFileURL(t *testing.T) {
+	expiresAt := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		description        string
+		file               string
+		expectedFile       models.TranscriptFile
+		managerURL         *models.PresignedURL
+		managerError       error
+		expectedStatusCode int
+	}{
+		{
+			description:        "summary URL returned",
+			file:               "summary",
+			expectedFile:       models.SummaryFile,
+			managerURL:         &models.PresignedURL{URL: "https://bucket.test/summary-1", Method: "GET", ExpiresAt: expiresAt},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "transcript still transcribing, 409 returned",
+			file:               "transcript",
+			expectedFile:       models.TranscriptDataFile,
+			managerError:       models.Conflicted,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			description:        "unknown file, 404 returned",
+			file:               "video",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			transcriptionManager := &MockTranscriptionManager{}
+
+			NewHttpAPI(r, &MockUserManager{}, &MockCampaignManager{}, &MockSessionManager{}, transcriptionManager, &MockUploadManager{}, &MockPlayerManager{}, &MockCharacterManager{}, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerURL != nil || c.managerError != nil {
+				transcriptionManager.On("GetTranscriptFileURL", mock.Anything, "ts123", c.expectedFile).Return(c.managerURL, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/transcripts/ts123/files/"+c.file, nil)
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.managerURL != nil {
+				var actualResponse PresignedURLResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &actualResponse); err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+				}
+				assert.Equal(t, c.managerURL.URL, actualResponse.URL)
+				assert.Equal(t, expiresAt, actualResponse.ExpiresAt)
+			}
+			transcriptionManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetTranscriptJob(t *testing.T) {
+	cases := []struct {
+		description               string
+		userID                    string
+		campaignID                string
+		sessionID                 string
+		jobID                     string
+		managerTranscriptResponse *models.Transcript
+		managerError              error
+		expectedTranscriptReponse *TranscriptResponse
+		expectedErrorResponse     *ErrorResponse
+		expectedStatusCode        int
+	}{
+		{
+			description: "transcript job returned",
+			userID:      "testUID",
+			campaignID:  "cmp123",
+			sessionID:   "ses123",
+			jobID:       "job123",
+			managerTranscriptResponse: &models.Transcript{
+				JobID:              "ts123",
+				AudioLocation:      "audio.mp3",
+				AudioFormat:        models.MP3,
+				TranscriptLocation: "tx.text",
+				SummaryLocation:    "",
+				Status:             models.Summarizing,
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "transcript not found",
+			userID:             "testUID",
+			campaignID:         "cmp123",
+			sessionID:          "ses123",
+			jobID:              "job123",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Not Found",
+			},
+		},
+		{
+			description:        "internal server error",
+			userID:             "testUID",
+			campaignID:         "cmp123",
+			sessionID:          "ses123",
+			jobID:              "job123",
+			managerError:       fmt.Errorf("could not connect to database"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Internal Server Error",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerTranscriptResponse != nil {
+				transcriptionManager.On("GetTranscriptJob", mock.Anything, c.jobID).Return(c.managerTranscriptResponse, nil)
+			} else if c.managerError != nil {
+				transcriptionManager.On("GetTranscriptJob", mock.Anything, mock.Anything).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions/%s/transcripts/%s", c.userID, c.campaignID, c.sessionID, c.jobID), nil)
+			req.Header.Set("Authorization", "Bearer "+c.userID)
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedTranscriptReponse != nil {
+				var actualTranscriptResponse TranscriptResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualTranscriptResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+
+				assert.Equal(t, c.expectedTranscriptReponse.Status, actualTranscriptResponse.Status)
+				assert.Equal(t, c.expectedTranscriptReponse.ID, actualTranscriptResponse.ID)
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestGetJobsForSession(t *testing.T) {
+	cases := []struct {
+		description                 string
+		userID                      string
+		campaignID                  string
+		sessionID                   string
+		managerTranscriptsResponse  []models.Transcript
+		managerError                error
+		expectedTranscriptsResponse []TranscriptResponse
+		expectedErrorResponse       *ErrorResponse
+		expectedStatusCode          int
+	}{
+		{
+			description: "sessions returned",
+			userID:      "abc123",
+			campaignID:  "cmp123",
+			sessionID:   "ses123",
+			managerTranscriptsResponse: []models.Transcript{
+				{
+					JobID:              "ts123",
+					AudioLocation:      "audio.mp3",
+					AudioFormat:        models.MP3,
+					TranscriptLocation: "tx.text",
+					SummaryLocation:    "",
+					Status:             models.Summarizing,
+				},
+				{
+					JobID:              "ts456",
+					AudioLocation:      "audio.mp3",
+					AudioFormat:        models.MP3,
+					TranscriptLocation: "tx.text",
+					SummaryLocation:    "",
+					Status:             models.Done,
+				},
+			},
+			expectedTranscriptsResponse: []TranscriptResponse{
+				{
+					ID:     "ts123",
+					Status: "Summarizing",
+				},
+				{
+					ID:     "ts456",
+					Status: "Done",
+				},
+			},
+
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "sessions not found",
+			userID:             "abc123",
+			campaignID:         "cmp123",
+			sessionID:          "ses123",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Not Found",
+			},
+		},
+		{
+			description:        "internal server error",
+			userID:             "abc123",
+			campaignID:         "cmp123",
+			sessionID:          "ses123",
+			managerError:       fmt.Errorf("an error has occurred"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Internal Server Error",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerTranscriptsResponse != nil {
+				transcriptionManager.On("GetTranscriptsForSession", mock.Anything, c.sessionID, models.TranscriptFilter{}, models.PageRequest{}).Return(&models.Page[models.Transcript]{Items: c.managerTranscriptsResponse}, nil)
+			} else if c.managerError != nil {
+				transcriptionManager.On("GetTranscriptsForSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions/%s/transcripts", c.userID, c.campaignID, c.sessionID), nil)
+			req.Header.Set("Authorization", "Bearer "+c.userID)
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedTranscriptsResponse != nil {
+				var actualTranscriptsPage ListResponse[TranscriptResponse]
+				err := json.Unmarshal(w.Body.Bytes(), &actualTranscriptsPage)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				actualTranscriptsResponse := actualTranscriptsPage.Items
+				if len(c.expectedTranscriptsResponse) != len(actualTranscriptsResponse) {
+					t.Errorf("expected %d campaigns got %d", len(c.expectedTranscriptsResponse), len(actualTranscriptsResponse))
+					return
+				}
+
+				for i, expected := range c.expectedTranscriptsResponse {
+					actual := actualTranscriptsResponse[i]
+					assert.Equal(t, expected.Status, actual.Status)
+					assert.Equal(t, expected.ID, actual.ID)
+				}
+
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestRetryTranscript(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	cases := []struct {
+		description        string
+		managerTranscript  *models.Transcript
+		managerError       error
+		expectedResponse   *TranscriptResponse
+		expectedStatusCode int
+	}{
+		{
+			description: "failed transcript is retried",
+			managerTranscript: &models.Transcript{
+				JobID:     "job123",
+				Status:    models.Transcribing,
+				Attempts:  2,
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt.Add(time.Hour),
+			},
+			expectedResponse: &TranscriptResponse{
+				ID:        "job123",
+				Status:    "Transcribing",
+				Attempts:  2,
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt.Add(time.Hour),
+			},
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			description:        "transcript did not fail",
+			managerError:       models.Conflicted,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			description:        "audio was never uploaded",
+			managerError:       models.InvalidEntity,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "transcript not found",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			transcriptionManager := &MockTranscriptionManager{}
+			NewHttpAPI(r, &MockUserManager{}, &MockCampaignManager{}, &MockSessionManager{}, transcriptionManager, &MockUploadManager{}, &MockPlayerManager{}, &MockCharacterManager{}, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			transcriptionManager.On("RetryTranscript", mock.Anything, "ses123", "job123").Return(c.managerTranscript, c.managerError)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/transcripts/job123/retry", nil)
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, c.expectedStatusCode, w.Code)
+			if c.expectedResponse != nil {
+				var response TranscriptResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, *c.expectedResponse, response)
+			}
+		})
+	}
+}
+
+func TestGetTranscript(t *testing.T) {
+	document := &models.TranscriptDocument{
+		Segments: []models.TranscriptSegment{
+			{
+				StartTime:  0,
+				EndTime:    1500 * time.Millisecond,
+				Speaker:    "spk_0",
+				Text:       "Welcome to dnd.",
+				Confidence: 0.9,
+			},
+			{
+				StartTime:  3723 * time.Second,
+				EndTime:    3724*time.Second + 250*time.Millisecond,
+				Speaker:    "spk_1",
+				Text:       "I roll for initiative.",
+				Confidence: 0.8,
+			},
+		},
+	}
+
+	cases := []struct {
+		description              string
+		userID                   string
+		campaignID               string
+		sessionID                string
+		jobID                    string
+		format                   string
+		accept                   string
+		managerDocument          *models.TranscriptDocument
+		managerError             error
+		expectedContentType      string
+		expectedTranscriptText   string
+		expectedDocumentResponse *TranscriptDocumentResponse
+		expectedErrorResponse    *ErrorResponse
+		expectedStatusCode       int
+	}{
+		{
+			description:            "transcript text returned",
+			userID:                 "testUID",
+			campaignID:             "cmp123",
+			sessionID:              "ses123",
+			jobID:                  "job123",
+			managerDocument:        document,
+			expectedContentType:    "text/plain; charset=utf-8",
+			expectedTranscriptText: "spk_0: Welcome to dnd.\nspk_1: I roll for initiative.",
+			expectedStatusCode:     http.StatusOK,
+		},
+		{
+			description:     "structured transcript returned for json format",
+			userID:          "testUID",
+			campaignID:      "cmp123",
+			sessionID:       "ses123",
+			jobID:           "job123",
+			format:          "json",
+			managerDocument: document,
+			expectedDocumentResponse: &TranscriptDocumentResponse{
+				ID: "job123",
+				Segments: []TranscriptSegmentResponse{
+					{StartTime: 0, EndTime: 1.5, Speaker: "spk_0", Text: "Welcome to dnd.", Confidence: 0.9},
+					{StartTime: 3723, EndTime: 3724.25, Speaker: "spk_1", Text: "I roll for initiative.", Confidence: 0.8},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:              "structured transcript returned for json accept header",
+			userID:                   "testUID",
+			campaignID:               "cmp123",
+			sessionID:                "ses123",
+			jobID:                    "job123",
+			accept:                   "application/json",
+			managerDocument:          &models.TranscriptDocument{Segments: []models.TranscriptSegment{}},
+			expectedDocumentResponse: &TranscriptDocumentResponse{ID: "job123", Segments: []TranscriptSegmentResponse{}},
+			expectedStatusCode:       http.StatusOK,
+		},
+		{
+			description:            "srt subtitles returned",
+			userID:                 "testUID",
+			campaignID:             "cmp123",
+			sessionID:              "ses123",
+			jobID:                  "job123",
+			format:                 "srt",
+			managerDocument:        document,
+			expectedContentType:    "application/x-subrip; charset=utf-8",
+			expectedTranscriptText: "1\n00:00:00,000 --> 00:00:01,500\nspk_0: Welcome to dnd.\n\n2\n01:02:03,000 --> 01:02:04,250\nspk_1: I roll for initiative.\n\n",
+			expectedStatusCode:     http.StatusOK,
+		},
+		{
+			description:            "webvtt captions returned",
+			userID:                 "testUID",
+			campaignID:             "cmp123",
+			sessionID:              "ses123",
+			jobID:                  "job123",
+			accept:                 "text/vtt",
+			managerDocument:        document,
+			expectedContentType:    "text/vtt; charset=utf-8",
+			expectedTranscriptText: "WEBVTT\n\n00:00:00.000 --> 00:00:01.500\n<v spk_0>Welcome to dnd.\n\n01:02:03.000 --> 01:02:04.250\n<v spk_1>I roll for initiative.\n\n",
+			expectedStatusCode:     http.StatusOK,
+		},
+		{
+			description:        "unsupported format",
+			userID:             "testUID",
+			campaignID:         "cmp123",
+			sessionID:          "ses123",
+			jobID:              "job123",
+			format:             "docx",
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Invalid Request",
+			},
+		},
+		{
+			description:        "transcript not found",
+			userID:             "testUID",
+			campaignID:         "cmp123",
+			sessionID:          "ses123",
+			jobID:              "job123",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Not Found",
+			},
+		},
+		{
+			description:        "internal server error",
+			userID:             "testUID",
+			campaignID:         "cmp123",
+			sessionID:          "ses123",
+			jobID:              "job123",
+			managerError:       fmt.Errorf("could not connect to database"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Internal Server Error",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerDocument != nil {
+				transcriptionManager.On("GetTranscriptDocument", mock.Anything, c.jobID).Return(c.managerDocument, nil)
+			} else if c.managerError != nil {
+				transcriptionManager.On("GetTranscriptDocument", mock.Anything, mock.Anything).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions/%s/transcripts/%s/fulltext", c.userID, c.campaignID, c.sessionID, c.jobID)
+			if c.format != "" {
+				url += "?format=" + c.format
+			}
+			req, _ := http.NewRequest("GET", url, nil)
+			req.Header.Set("Authorization", "Bearer "+c.userID)
+			if c.accept != "" {
+				req.Header.Set("Accept", c.accept)
+			}
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedTranscriptText != "" {
+				assert.Equal(t, c.expectedContentType, w.Header().Get("Content-Type"))
+				actualText := string(w.Body.Bytes())
+				assert.Equal(t, c.expectedTranscriptText, actualText)
+			} else if c.expectedDocumentResponse != nil {
+				var actualDocumentResponse TranscriptDocumentResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualDocumentResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, *c.expectedDocumentResponse, actualDocumentResponse)
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestGetTranscriptSummary(t *testing.T) {
+	cases := []struct {
+		description             string
+		jobID                   string
+		accept                  string
+		managerSummary          string
+		managerError            error
+		expectedContentType     string
+		expectedBody            string
+		expectedSummaryResponse *SummaryResponse
+		expectedErrorResponse   *ErrorResponse
+		expectedStatusCode      int
+	}{
+		{
+			description:         "summary returned as markdown by default",
+			jobID:               "job123",
+			managerSummary:      "# the party met in a tavern",
+			expectedContentType: "text/markdown; charset=utf-8",
+			expectedBody:        "# the party met in a tavern",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			description:         "summary returned as markdown",
+			jobID:               "job123",
+			accept:              "text/markdown",
+			managerSummary:      "# the party met in a tavern",
+			expectedContentType: "text/markdown; charset=utf-8",
+			expectedBody:        "# the party met in a tavern",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			description:    "summary returned as json",
+			jobID:          "job123",
+			accept:         "application/json",
+			managerSummary: "# the party met in a tavern",
+			expectedSummaryResponse: &SummaryResponse{
+				ID:      "job123",
+				Summary: "# the party met in a tavern",
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "transcript still summarizing",
+			jobID:              "job123",
+			managerError:       models.Conflicted,
+			expectedStatusCode: http.StatusConflict,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Conflict",
+			},
+		},
+		{
+			description:        "summary not found",
+			jobID:              "job123",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Not Found",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerSummary != "" {
+				transcriptionManager.On("DownloadSummary", mock.Anything, c.jobID, mock.Anything).Run(func(args mock.Arguments) {
+					w := args.Get(2).(io.WriterAt)
+					w.WriteAt([]byte(c.managerSummary), 0)
+				}).Return(int64(len(c.managerSummary)), nil)
+			} else if c.managerError != nil {
+				transcriptionManager.On("DownloadSummary", mock.Anything, mock.Anything, mock.Anything).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/transcripts/%s/summary", c.jobID), nil)
+			req.Header.Set("Authorization", "Bearer testUID")
+			if c.accept != "" {
+				req.Header.Set("Accept", c.accept)
+			}
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedBody != "" {
+				assert.Equal(t, c.expectedContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, c.expectedBody, w.Body.String())
+			} else if c.expectedSummaryResponse != nil {
+				var actualSummaryResponse SummaryResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualSummaryResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, *c.expectedSummaryResponse, actualSummaryResponse)
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestSetTranscriptSpeakers(t *testing.T) {
+	cases := []struct {
+		description              string
+		jobID                    string
+		body                     string
+		expectedAssignments      []models.SpeakerAssignment
+		managerAssignments       []models.SpeakerAssignment
+		managerError             error
+		expectedSpeakersResponse *SpeakersResponse
+		expectedErrorResponse    *ErrorResponse
+		expectedStatusCode       int
+	}{
+		{
+			description:         "speakers assigned",
+			jobID:               "job123",
+			body:                `{"speakers": [{"speakerLabel": "spk_0", "playerId": "player-1"}]}`,
+			expectedAssignments: []models.SpeakerAssignment{{SpeakerLabel: "spk_0", PlayerID: "player-1"}},
+			managerAssignments:  []models.SpeakerAssignment{{SpeakerLabel: "spk_0", PlayerID: "player-1", PlayerName: "Mercer"}},
+			expectedSpeakersResponse: &SpeakersResponse{
+				Speakers: []SpeakerAssignmentResponse{{SpeakerLabel: "spk_0", PlayerID: "player-1", PlayerName: "Mercer"}},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "malformed body",
+			jobID:              "job123",
+			body:               `{"speakers": `,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Request body is in the incorrect format",
+			},
+		},
+		{
+			description:         "player not in campaign",
+			jobID:               "job123",
+			body:                `{"speakers": [{"speakerLabel": "spk_0", "playerId": "player-9"}]}`,
+			expectedAssignments: []models.SpeakerAssignment{{SpeakerLabel: "spk_0", PlayerID: "player-9"}},
+			managerError:        models.InvalidEntity,
+			expectedStatusCode:  http.StatusUnprocessableEntity,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Invalid Request",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerAssignments != nil {
+				transcriptionManager.On("SetSpeakerAssignments", mock.Anything, c.jobID, c.expectedAssignments).Return(c.managerAssignments, nil)
+			} else if c.managerError != nil {
+				transcriptionManager.On("SetSpeakerAssignments", mock.Anything, c.jobID, c.expectedAssignments).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("PUT", fmt.Sprintf("/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/transcripts/%s/speakers", c.jobID), bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedSpeakersResponse != nil {
+				var actualSpeakersResponse SpeakersResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualSpeakersResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, *c.expectedSpeakersResponse, actualSpeakersResponse)
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestGetTranscriptSpeakers(t *testing.T) {
+	cases := []struct {
+		description              string
+		jobID                    string
+		managerAssignments       []models.SpeakerAssignment
+		managerError             error
+		expectedSpeakersResponse *SpeakersResponse
+		expectedStatusCode       int
+	}{
+		{
+			description:        "speakers returned",
+			jobID:              "job123",
+			managerAssignments: []models.SpeakerAssignment{{SpeakerLabel: "spk_0", PlayerID: "player-1", PlayerName: "Mercer"}},
+			expectedSpeakersResponse: &SpeakersResponse{
+				Speakers: []SpeakerAssignmentResponse{{SpeakerLabel: "spk_0", PlayerID: "player-1", PlayerName: "Mercer"}},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:              "no speakers assigned",
+			jobID:                    "job123",
+			managerAssignments:       []models.SpeakerAssignment{},
+			expectedSpeakersResponse: &SpeakersResponse{Speakers: []SpeakerAssignmentResponse{}},
+			expectedStatusCode:       http.StatusOK,
+		},
+		{
+			description:        "transcript not found",
+			jobID:              "job123",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerAssignments != nil {
+				transcriptionManager.On("GetSpeakerAssignments", mock.Anything, c.jobID).Return(c.managerAssignments, nil)
+			} else if c.managerError != nil {
+				transcriptionManager.On("GetSpeakerAssignments", mock.Anything, c.jobID).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/transcripts/%s/speakers", c.jobID), nil)
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedSpeakersResponse != nil {
+				var actualSpeakersResponse SpeakersResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualSpeakersResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, *c.expectedSpeakersResponse, actualSpeakersResponse)
+			}
+		})
+	}
+}
+
+func TestAddPlayer(t *testing.T) {
+	cases := []struct {
+		description            string
+		body                   string
+		expectedPlayer         models.Player
+		managerPlayer          *models.Player
+		managerError           error
+		expectedPlayerResponse *PlayerResponse
+		expectedErrorResponse  *ErrorResponse
+		expectedStatusCode     int
+	}{
+		{
+			description:    "player added",
+			body:           `{"name": "Mercer", "type": "GM"}`,
+			expectedPlayer: models.Player{Name: "Mercer", Type: models.GM},
+			managerPlayer:  &models.Player{ID: "player-1", Name: "Mercer", Type: models.GM},
+			expectedPlayerResponse: &PlayerResponse{
+				ID:   "player-1",
+				Name: "Mercer",
+				Type: "GM",
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:    "player type omitted, player is added as a standard player",
+			body:           `{"name": "Laura"}`,
+			expectedPlayer: models.Player{Name: "Laura", Type: models.StandardPlayer},
+			managerPlayer:  &models.Player{ID: "player-2", Name: "Laura", Type: models.StandardPlayer},
+			expectedPlayerResponse: &PlayerResponse{
+				ID:   "player-2",
+				Name: "Laura",
+				Type: "StandardPlayer",
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description: "unknown player type, 422 returned",
+			body:        `{"name": "Laura", "type": "Wizard"}`,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Invalid Request",
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description: "malformed body, 422 returned",
+			body:        `{"name": `,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Request body is in the incorrect format",
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:    "campaign already has a GM, 409 returned",
+			body:           `{"name": "Sam", "type": "GM"}`,
+			expectedPlayer: models.Player{Name: "Sam", Type: models.GM},
+			managerError:   models.Conflicted,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Conflict",
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			description:    "campaign does not exist, 404 returned",
+			body:           `{"name": "Sam"}`,
+			expectedPlayer: models.Player{Name: "Sam", Type: models.StandardPlayer},
+			managerError:   models.EntityNotFound,
+			expectedErrorResponse: &ErrorResponse{
+				ErrorMessage: "Not Found",
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerPlayer != nil {
+				playerManager.On("AddPlayer", mock.Anything, "cmp123", c.expectedPlayer).Return(c.managerPlayer, nil)
+			} else if c.managerError != nil {
+				playerManager.On("AddPlayer", mock.Anything, "cmp123", c.expectedPlayer).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/players", bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedPlayerResponse != nil {
+				var actualPlayerResponse PlayerResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualPlayerResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, *c.expectedPlayerResponse, actualPlayerResponse)
+			} else if c.expectedErrorResponse != nil {
+				var actualErrorResponse ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestUpdatePlayer(t *testing.T) {
+	newName := "Matt"
+	gm := models.GM
+	cases := []struct {
+		description            string
+		playerID               string
+		body                   string
+		expectedUpdate         models.PlayerUpdate
+		managerPlayer          *models.Player
+		managerError           error
+		expectedPlayerResponse *PlayerResponse
+		expectedStatusCode     int
+	}{
+		{
+			description:    "name and type updated",
+			playerID:       "player-1",
+			body:           `{"name": "Matt", "type": "gm"}`,
+			expectedUpdate: models.PlayerUpdate{Name: &newName, Type: &gm},
+			managerPlayer:  &models.Player{ID: "player-1", Name: "Matt", Type: models.GM},
+			expectedPlayerResponse: &PlayerResponse{
+				ID:   "player-1",
+				Name: "Matt",
+				Type: "GM",
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:    "only name updated",
+			playerID:       "player-1",
+			body:           `{"name": "Matt"}`,
+			expectedUpdate: models.PlayerUpdate{Name: &newName},
+			managerPlayer:  &models.Player{ID: "player-1", Name: "Matt", Type: models.StandardPlayer},
+			expectedPlayerResponse: &PlayerResponse{
+				ID:   "player-1",
+				Name: "Matt",
+				Type: "StandardPlayer",
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "unknown player type, 422 returned",
+			playerID:           "player-1",
+			body:               `{"type": "Wizard"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "player does not exist, 404 returned",
+			playerID:           "missing",
+			body:               `{"name": "Matt"}`,
+			expectedUpdate:     models.PlayerUpdate{Name: &newName},
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerPlayer != nil {
+				playerManager.On("UpdatePlayer", mock.Anything, "cmp123", c.playerID, c.expectedUpdate).Return(c.managerPlayer, nil)
+			} else if c.managerError != nil {
+				playerManager.On("UpdatePlayer", mock.Anything, "cmp123", c.playerID, c.expectedUpdate).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("PATCH", fmt.Sprintf("/dragonspeak-service/v1/users/testUID/campaigns/cmp123/players/%s", c.playerID), bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedPlayerResponse != nil {
+				var actualPlayerResponse PlayerResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualPlayerResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, *c.expectedPlayerResponse, actualPlayerResponse)
+			}
+		})
+	}
+}
+
+func TestDeletePlayer(t *testing.T) {
+	cases := []struct {
+		description        string
+		playerID           string
+		managerError       error
+		expectedStatusCode int
+	}{
+		{
+			description:        "player deleted",
+			playerID:           "player-1",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "player does not exist, 404 returned",
+			playerID:           "missing",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			playerManager.On("DeletePlayer", mock.Anything, "cmp123", c.playerID).Return(c.managerError)
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("DELETE", fmt.Sprintf("/dragonspeak-service/v1/users/testUID/campaigns/cmp123/players/%s", c.playerID), nil)
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+			}
+			playerManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetCharacters(t *testing.T) {
+	cases := []struct {
+		description                string
+		managerCharacters          []models.Character
+		managerError               error
+		expectedCharacterResponses []CharacterResponse
+		expectedStatusCode         int
+	}{
+		{
+			description: "active and retired characters returned",
+			managerCharacters: []models.Character{
+				{ID: "chr-1", OwnerID: "player-1", Name: "Vex", Status: models.CharacterActive},
+				{ID: "chr-2", OwnerID: "player-1", Name: "Mollymauk", Status: models.CharacterDead},
+			},
+			expectedCharacterResponses: []CharacterResponse{
+				{ID: "chr-1", PlayerID: "player-1", Name: "Vex", Status: "Active"},
+				{ID: "chr-2", PlayerID: "player-1", Name: "Mollymauk", Status: "Dead"},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:                "player has no characters",
+			managerCharacters:          []models.Character{},
+			expectedCharacterResponses: []CharacterResponse{},
+			expectedStatusCode:         http.StatusOK,
+		},
+		{
+			description:        "database error, 500 returned",
+			managerError:       fmt.Errorf("failed to connect to database host: abc"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerError != nil {
+				characterManager.On("GetCharactersForPlayer", mock.Anything, "cmp123", "player-1").Return(nil, c.managerError)
+			} else {
+				characterManager.On("GetCharactersForPlayer", mock.Anything, "cmp123", "player-1").Return(c.managerCharacters, nil)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/players/player-1/characters", nil)
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedCharacterResponses != nil {
+				var actualCharacterResponses []CharacterResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualCharacterResponses)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, c.expectedCharacterResponses, actualCharacterResponses)
+			}
+		})
+	}
+}
+
+func TestRetireCharacter(t *testing.T) {
+	cases := []struct {
+		description               string
+		body                      string
+		expectedStatus            models.CharacterStatus
+		managerCharacter          *models.Character
+		managerError              error
+		expectedCharacterResponse *CharacterResponse
+		expectedStatusCode        int
+	}{
+		{
+			description:      "no body, character retired",
+			expectedStatus:   models.CharacterRetired,
+			managerCharacter: &models.Character{ID: "chr-1", OwnerID: "player-1", Name: "Vex", Status: models.CharacterRetired},
+			expectedCharacterResponse: &CharacterResponse{
+				ID: "chr-1", PlayerID: "player-1", Name: "Vex", Status: "Retired",
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:      "character marked dead",
+			body:             `{"status": "dead"}`,
+			expectedStatus:   models.CharacterDead,
+			managerCharacter: &models.Character{ID: "chr-1", OwnerID: "player-1", Name: "Vex", Status: models.CharacterDead},
+			expectedCharacterResponse: &CharacterResponse{
+				ID: "chr-1", PlayerID: "player-1", Name: "Vex", Status: "Dead",
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "unknown status, 422 returned",
+			body:               `{"status": "Sleeping"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "character already retired, 409 returned",
+			expectedStatus:     models.CharacterRetired,
+			managerError:       models.Conflicted,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			description:        "character does not exist, 404 returned",
+			expectedStatus:     models.CharacterRetired,
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerCharacter != nil {
+				characterManager.On("RetireCharacter", mock.Anything, "cmp123", "player-1", "chr-1", c.expectedStatus).Return(c.managerCharacter, nil)
+			} else if c.managerError != nil {
+				characterManager.On("RetireCharacter", mock.Anything, "cmp123", "player-1", "chr-1", c.expectedStatus).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/players/player-1/characters/chr-1/retire", bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedCharacterResponse != nil {
+				var actualCharacterResponse CharacterResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualCharacterResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, *c.expectedCharacterResponse, actualCharacterResponse)
+			}
+		})
+	}
+}
+
+func TestSetAttendance(t *testing.T) {
+	cases := []struct {
+		description                string
+		body                       string
+		expectedPlayerIDs          []string
+		managerPlayers             []models.Player
+		managerError               error
+		expectedAttendanceResponse *AttendanceResponse
+		expectedStatusCode         int
+	}{
+		{
+			description:       "attendance recorded",
+			body:              `{"playerIds": ["player-1", "player-2"]}`,
+			expectedPlayerIDs: []string{"player-1", "player-2"},
+			managerPlayers: []models.Player{
+				{ID: "player-1", Name: "Laura", Type: models.StandardPlayer},
+				{ID: "player-2", Name: "Matt", Type: models.GM},
+			},
+			expectedAttendanceResponse: &AttendanceResponse{
+				Players: []PlayerResponse{
+					{ID: "player-1", Name: "Laura", Type: "StandardPlayer"},
+					{ID: "player-2", Name: "Matt", Type: "GM"},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "malformed body, 422 returned",
+			body:               `{"playerIds": "player-1"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "player not in campaign, 422 returned",
+			body:               `{"playerIds": ["stranger"]}`,
+			expectedPlayerIDs:  []string{"stranger"},
+			managerError:       models.InvalidEntity,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "session does not exist, 404 returned",
+			body:               `{"playerIds": ["player-1"]}`,
+			expectedPlayerIDs:  []string{"player-1"},
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerPlayers != nil {
+				sessionManager.On("SetAttendance", mock.Anything, "cmp123", "ses123", c.expectedPlayerIDs).Return(c.managerPlayers, nil)
+			} else if c.managerError != nil {
+				sessionManager.On("SetAttendance", mock.Anything, "cmp123", "ses123", c.expectedPlayerIDs).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("PUT", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/attendance", bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedAttendanceResponse != nil {
+				var actualAttendanceResponse AttendanceResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualAttendanceResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, *c.expectedAttendanceResponse, actualAttendanceResponse)
+			}
+		})
+	}
+}
+
+func TestGetAttendanceReport(t *testing.T) {
+	cases := []struct {
+		description            string
+		managerReport          *models.AttendanceReport
+		managerError           error
+		expectedReportResponse *AttendanceReportResponse
+		expectedStatusCode     int
+	}{
+		{
+			description: "report returned",
+			managerReport: &models.AttendanceReport{
+				TotalSessions: 3,
+				Players: []models.PlayerAttendance{
+					{Player: models.Player{ID: "player-1", Name: "Laura", Type: models.StandardPlayer}, SessionsAttended: 2},
+					{Player: models.Player{ID: "player-2", Name: "Matt", Type: models.GM}, SessionsAttended: 3},
+				},
+			},
+			expectedReportResponse: &AttendanceReportResponse{
+				TotalSessions: 3,
+				Players: []PlayerAttendanceResponse{
+					{PlayerResponse: PlayerResponse{ID: "player-1", Name: "Laura", Type: "StandardPlayer"}, SessionsAttended: 2},
+					{PlayerResponse: PlayerResponse{ID: "player-2", Name: "Matt", Type: "GM"}, SessionsAttended: 3},
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "database error, 500 returned",
+			managerError:       fmt.Errorf("failed to connect to database host: abc"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerError != nil {
+				sessionManager.On("GetAttendanceReport", mock.Anything, "cmp123").Return(nil, c.managerError)
+			} else {
+				sessionManager.On("GetAttendanceReport", mock.Anything, "cmp123").Return(c.managerReport, nil)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("GET", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/attendance", nil)
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedReportResponse != nil {
+				var actualReportResponse AttendanceReportResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualReportResponse)
+				if err != nil {
+					t.Fatalf("unexpected error when unmarshalling response: %s", err)
+					return
+				}
+				assert.Equal(t, *c.expectedReportResponse, actualReportResponse)
+			}
+		})
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	cases := []struct {
+		description        string
+		method             string
+		url                string
+		authorization      string
+		authError          error
+		accessCall         []interface{}
+		accessError        error
+		expectedStatusCode int
+	}{
+		{
+			description:        "missing authorization header, 401 returned",
+			method:             "GET",
+			url:                "/dragonspeak-service/v1/users/testUID/campaigns",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			description:        "non bearer authorization header, 401 returned",
+			method:             "GET",
+			url:                "/dragonspeak-service/v1/users/testUID/campaigns",
+			authorization:      "Basic dGVzdDp0ZXN0",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			description:        "invalid token, 401 returned",
+			method:             "GET",
+			url:                "/dragonspeak-service/v1/users/testUID/campaigns",
+			authorization:      "Bearer bad-token",
+			authError:          models.Unauthorized,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			description:        "caller is a different user, 403 returned",
+			method:             "GET",
+			url:                "/dragonspeak-service/v1/users/otherUser/campaigns",
+			authorization:      "Bearer good-token",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			description:        "caller has no access to the campaign, 403 returned",
+			method:             "GET",
+			url:                "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions",
+			authorization:      "Bearer good-token",
+			accessCall:         []interface{}{"AuthorizeCampaign", mock.Anything, "cmp123", models.ReadAccess},
+			accessError:        models.Forbidden,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			description:        "session is not part of the campaign, 404 returned",
+			method:             "GET",
+			url:                "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses999/attendance",
+			authorization:      "Bearer good-token",
+			accessCall:         []interface{}{"AuthorizeSession", mock.Anything, "cmp123", "ses999", models.ReadAccess},
+			accessError:        models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			description:        "transcript is not part of the session, 404 returned",
+			method:             "GET",
+			url:                "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/transcripts/job999",
+			authorization:      "Bearer good-token",
+			accessCall:         []interface{}{"AuthorizeTranscript", mock.Anything, "cmp123", "ses123", "job999", models.ReadAccess},
+			accessError:        models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			description:        "player deletes a session, 403 returned",
+			method:             "DELETE",
+			url:                "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123",
+			authorization:      "Bearer good-token",
+			accessCall:         []interface{}{"AuthorizeSession", mock.Anything, "cmp123", "ses123", models.ManageAccess},
+			accessError:        models.Forbidden,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			description:        "player retries a transcript, 403 returned",
+			method:             "POST",
+			url:                "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/transcripts/job123/retry",
+			authorization:      "Bearer good-token",
+			accessCall:         []interface{}{"AuthorizeTranscript", mock.Anything, "cmp123", "ses123", "job123", models.ManageAccess},
+			accessError:        models.Forbidden,
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+			authenticator := &MockAuthenticator{}
+			accessManager := &MockAccessManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, authenticator, accessManager)
+			if c.authError != nil {
+				authenticator.On("Authenticate", mock.Anything, mock.Anything).Return(models.Identity{}, c.authError)
+			} else {
+				authenticator.On("Authenticate", mock.Anything, "good-token").Return(models.Identity{UserID: "testUID"}, nil)
+			}
+			if c.accessCall != nil {
+				accessManager.On(c.accessCall[0].(string), c.accessCall[1:]...).Return(c.accessError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(c.method, c.url, nil)
+			if c.authorization != "" {
+				req.Header.Set("Authorization", c.authorization)
+			}
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+			}
+			accessManager.AssertExpectations(t)
+			campaignManager.AssertNotCalled(t, "GetCampaignsForUser", mock.Anything, mock.Anything)
+			sessionManager.AssertNotCalled(t, "GetAttendance", mock.Anything, mock.Anything, mock.Anything)
+			transcriptionManager.AssertNotCalled(t, "GetTranscriptJob", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateInvite(t *testing.T) {
+	expiresAt := time.Date(2024, 1, 9, 3, 4, 5, 0, time.UTC)
+	cases := []struct {
+		description            string
+		body                   string
+		expectedInvite         models.CampaignInvite
+		managerInvite          *models.CampaignInvite
+		managerError           error
+		expectedInviteResponse *InviteResponse
+		expectedStatusCode     int
+	}{
+		{
+			description:    "invite created",
+			body:           `{"playerType": "StandardPlayer"}`,
+			expectedInvite: models.CampaignInvite{PlayerType: models.StandardPlayer},
+			managerInvite:  &models.CampaignInvite{Code: "code-1", CampaignID: "cmp123", PlayerType: models.StandardPlayer, ExpiresAt: expiresAt},
+			expectedInviteResponse: &InviteResponse{
+				Code:       "code-1",
+				CampaignID: "cmp123",
+				PlayerType: "StandardPlayer",
+				ExpiresAt:  expiresAt,
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:        "unknown player type, 422 returned",
+			body:               `{"playerType": "Wizard"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "caller is not the owner, 403 returned",
+			body:               `{}`,
+			expectedInvite:     models.CampaignInvite{PlayerType: models.StandardPlayer},
+			managerError:       models.Forbidden,
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerInvite != nil {
+				campaignManager.On("CreateInvite", mock.Anything, "cmp123", c.expectedInvite).Return(c.managerInvite, nil)
+			} else if c.managerError != nil {
+				campaignManager.On("CreateInvite", mock.Anything, "cmp123", c.expectedInvite).Return(nil, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/invites", bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
+			}
+			if c.expectedInviteResponse != nil {
+				var actualInviteResponse InviteResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualInviteResponse)
 				if err != nil {
 					t.Fatalf("unexpected error when unmarshalling response: %s", err)
 					return
 				}
-				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+				assert.Equal(t, *c.expectedInviteResponse, actualInviteResponse)
 			}
 		})
 	}
 }
 
-func TestCreateCampaign(t *testing.T) {
+func TestAcceptInvite(t *testing.T) {
 	cases := []struct {
 		description              string
-		userID                   string
-		campaign                 *models.Campaign
-		managerCampaignResponse  *models.Campaign
+		managerCampaign          *models.Campaign
 		managerError             error
 		expectedCampaignResponse *CampaignResponse
-		expectedErrorResponse    *ErrorResponse
 		expectedStatusCode       int
 	}{
 		{
-			description: "campaign created",
-			userID:      "abc123",
-			campaign: &models.Campaign{
-				ID:   "cmp123",
-				Name: "one-shot",
-				Link: "http://abc.com",
-			},
-			managerCampaignResponse: &models.Campaign{
-				ID:   "cmp123",
-				Name: "one-shot",
-				Link: "http://abc.com",
-			},
+			description:     "invite accepted, campaign returned with the player role",
+			managerCampaign: &models.Campaign{ID: "cmp123", Name: "Vox Machina", Role: models.CampaignPlayer},
 			expectedCampaignResponse: &CampaignResponse{
 				ID:   "cmp123",
-				Name: "one-shot",
-				Link: "http://abc.com",
+				Name: "Vox Machina",
+				Role: "Player",
 			},
-			expectedStatusCode: http.StatusCreated,
+			expectedStatusCode: http.StatusOK,
 		},
 		{
-			description: "database error returned",
-			userID:      "abc123",
-			campaign: &models.Campaign{
-				ID:   "cmp123",
-				Name: "one-shot",
-				Link: "http://abc.com",
-			},
-			managerError: fmt.Errorf("something went wrong"),
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Internal Server Error",
-			},
-			expectedStatusCode: http.StatusInternalServerError,
+			description:        "invite already used, 409 returned",
+			managerError:       models.Conflicted,
+			expectedStatusCode: http.StatusConflict,
 		},
 		{
-			description: "conflicted error returned",
-			userID:      "abc123",
-			campaign: &models.Campaign{
-				ID:   "cmp123",
-				Name: "one-shot",
-				Link: "http://abc.com",
-			},
-			managerError: models.Conflicted,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Conflict",
-			},
-			expectedStatusCode: http.StatusConflict,
+			description:        "invite does not exist, 404 returned",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
 		},
 	}
 
@@ -380,108 +3589,105 @@ func TestCreateCampaign(t *testing.T) {
 			campaignManager := &MockCampaignManager{}
 			sessionManager := &MockSessionManager{}
 			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
 
-			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager)
-			if c.expectedCampaignResponse != nil {
-				campaignManager.On("AddCampaign", mock.Anything, c.userID, mock.Anything).Return(c.managerCampaignResponse, nil)
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerCampaign != nil {
+				campaignManager.On("AcceptInvite", mock.Anything, "testUID", "code-1").Return(c.managerCampaign, nil)
 			} else if c.managerError != nil {
-				campaignManager.On("AddCampaign", mock.Anything, mock.Anything, mock.Anything).Return(nil, c.managerError)
-			}
-
-			campaignBody, err := json.Marshal(c.campaign)
-			if err != nil {
-				t.Errorf("unexpected error marshaling cage to json: %s", err)
-				return
+				campaignManager.On("AcceptInvite", mock.Anything, "testUID", "code-1").Return(nil, c.managerError)
 			}
 
 			w := httptest.NewRecorder()
 
-			req, _ := http.NewRequest("POST", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns", c.userID), bytes.NewReader(campaignBody))
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/invites/code-1/accept", nil)
+			req.Header.Set("Authorization", "Bearer testUID")
 			r.ServeHTTP(w, req)
 
 			if w.Code != c.expectedStatusCode {
 				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+				return
 			}
 			if c.expectedCampaignResponse != nil {
 				var actualCampaignResponse CampaignResponse
-				err = json.Unmarshal(w.Body.Bytes(), &actualCampaignResponse)
-				if err != nil {
-					t.Fatalf("unexpected error when unmarshalling response: %s", err)
-					return
-				}
-
-				assert.Equal(t, c.expectedCampaignResponse.Name, actualCampaignResponse.Name)
-				assert.Equal(t, c.expectedCampaignResponse.Link, actualCampaignResponse.Link)
-				assert.Equal(t, c.expectedCampaignResponse.ID, actualCampaignResponse.ID)
-			} else if c.expectedErrorResponse != nil {
-				var actualErrorResponse ErrorResponse
-				err = json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+				err := json.Unmarshal(w.Body.Bytes(), &actualCampaignResponse)
 				if err != nil {
 					t.Fatalf("unexpected error when unmarshalling response: %s", err)
 					return
 				}
-				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+				assert.Equal(t, *c.expectedCampaignResponse, actualCampaignResponse)
 			}
 		})
 	}
 }
 
-func TestGetCampaigns(t *testing.T) {
+func TestUpdateCampaign(t *testing.T) {
+	newName := "Critical Role"
+	newLanguage := "auto"
 	cases := []struct {
-		description               string
-		userID                    string
-		managerCampaignsResponse  []models.Campaign
-		managerError              error
-		expectedCampaignsResponse []CampaignResponse
-		expectedErrorResponse     *ErrorResponse
-		expectedStatusCode        int
+		description              string
+		ifMatch                  string
+		body                     string
+		expectedUpdate           models.CampaignUpdate
+		managerCampaign          *models.Campaign
+		managerError             error
+		expectedCampaignResponse *CampaignResponse
+		expectedStatusCode       int
 	}{
 		{
-			description: "campaigns returned",
-			userID:      "abc123",
-			managerCampaignsResponse: []models.Campaign{
-				{
-					ID:   "cmp123",
-					Name: "one-shot",
-					Link: "http://campaign1",
-				},
-				{
-					ID:   "cmp456",
-					Name: "two-shot",
-					Link: "http://campaign2",
-				},
-			},
-			expectedCampaignsResponse: []CampaignResponse{
-				{
-					ID:   "cmp123",
-					Name: "one-shot",
-					Link: "http://campaign1",
-				},
-				{
-					ID:   "cmp456",
-					Name: "two-shot",
-					Link: "http://campaign2",
-				},
+			description:     "name updated",
+			ifMatch:         `"3"`,
+			body:            `{"name": "Critical Role"}`,
+			expectedUpdate:  models.CampaignUpdate{Name: &newName, Version: 3},
+			managerCampaign: &models.Campaign{ID: "cmp123", Name: newName, Role: models.CampaignOwner, Version: 4},
+			expectedCampaignResponse: &CampaignResponse{
+				ID:   "cmp123",
+				Name: newName,
+				Role: "Owner",
 			},
 			expectedStatusCode: http.StatusOK,
 		},
 		{
-			description:        "campaigns not found",
-			userID:             "abc123",
-			managerError:       models.EntityNotFound,
-			expectedStatusCode: http.StatusNotFound,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Not Found",
+			description:     "default language updated",
+			ifMatch:         `"3"`,
+			body:            `{"defaultLanguage": "auto"}`,
+			expectedUpdate:  models.CampaignUpdate{DefaultLanguage: &newLanguage, Version: 3},
+			managerCampaign: &models.Campaign{ID: "cmp123", Name: "Vox Machina", DefaultLanguage: models.AutoLanguage, Role: models.CampaignOwner, Version: 4},
+			expectedCampaignResponse: &CampaignResponse{
+				ID:              "cmp123",
+				Name:            "Vox Machina",
+				DefaultLanguage: "auto",
+				Role:            "Owner",
 			},
+			expectedStatusCode: http.StatusOK,
 		},
 		{
-			description:        "internal server error",
-			userID:             "abc123",
-			managerError:       fmt.Errorf("an error has occurred"),
-			expectedStatusCode: http.StatusInternalServerError,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Internal Server Error",
-			},
+			description:        "malformed body, 422 returned",
+			ifMatch:            `"3"`,
+			body:               `{"name":`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "caller is not the owner, 403 returned",
+			ifMatch:            `"3"`,
+			body:               `{"name": "Critical Role"}`,
+			expectedUpdate:     models.CampaignUpdate{Name: &newName, Version: 3},
+			managerError:       models.Forbidden,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			description:        "campaign changed since it was read, 409 returned",
+			ifMatch:            `"2"`,
+			body:               `{"name": "Critical Role"}`,
+			expectedUpdate:     models.CampaignUpdate{Name: &newName, Version: 2},
+			managerError:       models.Conflicted,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			description:        "If-Match missing, 428 returned",
+			body:               `{"name": "Critical Role"}`,
+			expectedStatusCode: http.StatusPreconditionRequired,
 		},
 	}
 
@@ -492,348 +3698,251 @@ func TestGetCampaigns(t *testing.T) {
 			campaignManager := &MockCampaignManager{}
 			sessionManager := &MockSessionManager{}
 			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
 
-			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager)
-			if c.expectedCampaignsResponse != nil {
-				campaignManager.On("GetCampaignsForUser", mock.Anything, c.userID).Return(c.managerCampaignsResponse, nil)
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerCampaign != nil {
+				campaignManager.On("UpdateCampaign", mock.Anything, "cmp123", c.expectedUpdate).Return(c.managerCampaign, nil)
 			} else if c.managerError != nil {
-				campaignManager.On("GetCampaignsForUser", mock.Anything, mock.Anything).Return(nil, c.managerError)
+				campaignManager.On("UpdateCampaign", mock.Anything, "cmp123", c.expectedUpdate).Return(nil, c.managerError)
 			}
 
 			w := httptest.NewRecorder()
 
-			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns", c.userID), nil)
+			req, _ := http.NewRequest("PATCH", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123", bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
+			if c.ifMatch != "" {
+				req.Header.Set("If-Match", c.ifMatch)
+			}
 			r.ServeHTTP(w, req)
 
 			if w.Code != c.expectedStatusCode {
 				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
 				return
 			}
-			if c.expectedCampaignsResponse != nil {
-				var actualCampaignsResponse []CampaignResponse
-				err := json.Unmarshal(w.Body.Bytes(), &actualCampaignsResponse)
-				if err != nil {
-					t.Fatalf("unexpected error when unmarshalling response: %s", err)
-					return
-				}
-				if len(c.expectedCampaignsResponse) != len(actualCampaignsResponse) {
-					t.Errorf("expected %d campaigns got %d", len(c.expectedCampaignsResponse), len(actualCampaignsResponse))
-					return
-				}
-
-				for i, expected := range c.expectedCampaignsResponse {
-					actual := actualCampaignsResponse[i]
-					assert.Equal(t, expected.Name, actual.Name)
-					assert.Equal(t, expected.Link, actual.Link)
-					assert.Equal(t, expected.ID, actual.ID)
-				}
-
-			} else if c.expectedErrorResponse != nil {
-				var actualErrorResponse ErrorResponse
-				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+			if c.expectedCampaignResponse != nil {
+				var actualCampaignResponse CampaignResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualCampaignResponse)
 				if err != nil {
 					t.Fatalf("unexpected error when unmarshalling response: %s", err)
 					return
 				}
-				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+				assert.Equal(t, *c.expectedCampaignResponse, actualCampaignResponse)
+				assert.Equal(t, `"4"`, w.Header().Get("ETag"))
 			}
 		})
 	}
 }
 
-func TestAddSession(t *testing.T) {
+func TestDeleteCampaign(t *testing.T) {
 	cases := []struct {
-		description             string
-		userID                  string
-		campaignID              string
-		session                 *models.Session
-		managerSessionResponse  *models.Session
-		managerError            error
-		expectedSessionResponse *SessionResponse
-		expectedErrorResponse   *ErrorResponse
-		expectedStatusCode      int
+		description        string
+		managerError       error
+		expectedStatusCode int
 	}{
 		{
-			description: "session added",
-			userID:      "abc123",
-			campaignID:  "efg456",
-			session: &models.Session{
-				Title:       "session-0",
-				SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
-			},
-			managerSessionResponse: &models.Session{
-				ID:          "sesUUID",
-				Title:       "session-0",
-				SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
-			},
-			expectedSessionResponse: &SessionResponse{
-				ID:          "sesUUID",
-				Title:       "session-0",
-				SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
-			},
-			expectedStatusCode: http.StatusCreated,
+			description:        "campaign deleted",
+			expectedStatusCode: http.StatusNoContent,
 		},
 		{
-			description: "session manager returns error unprocessable entity",
-			userID:      "abc123",
-			campaignID:  "efg456",
-			session: &models.Session{
-				Title:       "session-0",
-				SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
-			},
-			managerError: models.InvalidEntity,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Invalid Request",
-			},
+			description:        "caller is not the owner, 403 returned",
+			managerError:       models.Forbidden,
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			userManager := &MockUserManager{}
+			campaignManager := &MockCampaignManager{}
+			sessionManager := &MockSessionManager{}
+			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
+
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			campaignManager.On("DeleteCampaign", mock.Anything, "cmp123").Return(c.managerError)
+
+			w := httptest.NewRecorder()
+
+			req, _ := http.NewRequest("DELETE", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123", nil)
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.expectedStatusCode {
+				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
+			}
+			campaignManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetVocabulary(t *testing.T) {
+	r := gin.Default()
+	campaignManager := &MockCampaignManager{}
+	NewHttpAPI(r, &MockUserManager{}, campaignManager, &MockSessionManager{}, &MockTranscriptionManager{}, &MockUploadManager{}, &MockPlayerManager{}, &MockCharacterManager{}, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+	campaignManager.On("GetVocabulary", mock.Anything, "cmp123").Return([]models.VocabularyTerm{
+		{ID: "term-1", Phrase: "Tiamat", SoundsLike: []string{"tee-ah-mat"}, Source: models.VocabularyCustom},
+		{Phrase: "Grog Strongjaw", Source: models.VocabularyCharacter},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/vocabulary", nil)
+	req.Header.Set("Authorization", "Bearer testUID")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[
+		{"id": "term-1", "phrase": "Tiamat", "soundsLike": ["tee-ah-mat"], "source": "Custom"},
+		{"phrase": "Grog Strongjaw", "soundsLike": [], "source": "Character"}]`, w.Body.String())
+}
+
+func TestAddVocabularyTerm(t *testing.T) {
+	cases := []struct {
+		description        string
+		body               string
+		expectedTerm       models.VocabularyTerm
+		managerTerm        *models.VocabularyTerm
+		managerError       error
+		expectedResponse   string
+		expectedStatusCode int
+	}{
+		{
+			description:        "term added",
+			body:               `{"phrase": "Tiamat", "soundsLike": ["tee-ah-mat"]}`,
+			expectedTerm:       models.VocabularyTerm{Phrase: "Tiamat", SoundsLike: []string{"tee-ah-mat"}},
+			managerTerm:        &models.VocabularyTerm{ID: "term-1", Phrase: "Tiamat", SoundsLike: []string{"tee-ah-mat"}, Source: models.VocabularyCustom},
+			expectedResponse:   `{"id": "term-1", "phrase": "Tiamat", "soundsLike": ["tee-ah-mat"], "source": "Custom"}`,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:        "malformed body, 422 returned",
+			body:               `{"phrase":`,
 			expectedStatusCode: http.StatusUnprocessableEntity,
 		},
 		{
-			description: "session manager returns generic error, Internal Server error returned",
-			userID:      "abc123",
-			campaignID:  "efg456",
-			session: &models.Session{
-				Title:       "session-0",
-				SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
-			},
-			managerError: fmt.Errorf("database connection failed"),
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Internal Server Error",
-			},
-			expectedStatusCode: http.StatusInternalServerError,
+			description:        "phrase is invalid, 422 returned",
+			body:               `{"phrase": "Tiamat!"}`,
+			expectedTerm:       models.VocabularyTerm{Phrase: "Tiamat!"},
+			managerError:       models.InvalidEntity,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "campaign has the term already, 409 returned",
+			body:               `{"phrase": "Tiamat"}`,
+			expectedTerm:       models.VocabularyTerm{Phrase: "Tiamat"},
+			managerError:       models.EntityAlreadyExists,
+			expectedStatusCode: http.StatusConflict,
 		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.description, func(t *testing.T) {
 			r := gin.Default()
-			userManager := &MockUserManager{}
 			campaignManager := &MockCampaignManager{}
-			sessionManager := &MockSessionManager{}
-			transcriptionManager := &MockTranscriptionManager{}
-
-			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager)
-			if c.expectedSessionResponse != nil {
-				sessionManager.On("AddSession", mock.Anything, c.campaignID, mock.Anything).Return(c.managerSessionResponse, nil)
-			} else if c.managerError != nil {
-				sessionManager.On("AddSession", mock.Anything, mock.Anything, mock.Anything).Return(nil, c.managerError)
-			}
-
-			sessionBody, err := json.Marshal(c.session)
-			if err != nil {
-				t.Errorf("unexpected error marshaling cage to json: %s", err)
-				return
-			}
+			NewHttpAPI(r, &MockUserManager{}, campaignManager, &MockSessionManager{}, &MockTranscriptionManager{}, &MockUploadManager{}, &MockPlayerManager{}, &MockCharacterManager{}, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			campaignManager.On("AddVocabularyTerm", mock.Anything, "cmp123", c.expectedTerm).Return(c.managerTerm, c.managerError)
 
 			w := httptest.NewRecorder()
-
-			req, _ := http.NewRequest("POST", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions", c.userID, c.campaignID), bytes.NewReader(sessionBody))
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/vocabulary", bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
 			r.ServeHTTP(w, req)
 
-			if w.Code != c.expectedStatusCode {
-				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
-				return
-			}
-			if c.expectedSessionResponse != nil {
-				var actualSessionResponse SessionResponse
-				err = json.Unmarshal(w.Body.Bytes(), &actualSessionResponse)
-				if err != nil {
-					t.Fatalf("unexpected error when unmarshalling response: %s", err)
-					return
-				}
-
-				assert.Equal(t, c.expectedSessionResponse.Title, actualSessionResponse.Title)
-				assert.Equal(t, c.expectedSessionResponse.SessionDate, actualSessionResponse.SessionDate)
-				assert.Equal(t, c.expectedSessionResponse.ID, actualSessionResponse.ID)
-			} else if c.expectedErrorResponse != nil {
-				var actualErrorResponse ErrorResponse
-				err = json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
-				if err != nil {
-					t.Fatalf("unexpected error when unmarshalling response: %s", err)
-					return
-				}
-				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			assert.Equal(t, c.expectedStatusCode, w.Code)
+			if c.expectedResponse != "" {
+				assert.JSONEq(t, c.expectedResponse, w.Body.String())
 			}
 		})
 	}
 }
 
-func TestGetSessionsForCampaign(t *testing.T) {
+func TestUpdateVocabularyTerm(t *testing.T) {
+	soundsLike := []string{"tya-mat"}
 	cases := []struct {
-		description              string
-		userID                   string
-		campaignID               string
-		managerSessionssResponse []models.Session
-		managerError             error
-		expectedSessionsResponse []SessionResponse
-		expectedErrorResponse    *ErrorResponse
-		expectedStatusCode       int
+		description        string
+		body               string
+		expectedUpdate     models.VocabularyTermUpdate
+		managerTerm        *models.VocabularyTerm
+		managerError       error
+		expectedStatusCode int
 	}{
 		{
-			description: "sessions returned",
-			userID:      "abc123",
-			campaignID:  "cmp123",
-			managerSessionssResponse: []models.Session{
-				{
-					ID:          "ses123",
-					Title:       "sesion0",
-					SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
-				},
-				{
-					ID:          "ses456",
-					Title:       "session1",
-					SessionDate: time.Date(2024, time.January, 21, 19, 0, 0, 0, time.UTC),
-				},
-			},
-			expectedSessionsResponse: []SessionResponse{
-				{
-					ID:          "ses123",
-					Title:       "sesion0",
-					SessionDate: time.Date(2024, time.January, 14, 19, 0, 0, 0, time.UTC),
-				},
-				{
-					ID:          "ses456",
-					Title:       "session1",
-					SessionDate: time.Date(2024, time.January, 21, 19, 0, 0, 0, time.UTC),
-				},
-			},
+			description:        "sounds like hints updated",
+			body:               `{"soundsLike": ["tya-mat"]}`,
+			expectedUpdate:     models.VocabularyTermUpdate{SoundsLike: &soundsLike},
+			managerTerm:        &models.VocabularyTerm{ID: "term-1", Phrase: "Tiamat", SoundsLike: soundsLike, Source: models.VocabularyCustom},
 			expectedStatusCode: http.StatusOK,
 		},
 		{
-			description:        "sessions not found",
-			userID:             "abc123",
-			campaignID:         "cmp123",
+			description:        "term does not exist, 404 returned",
+			body:               `{"soundsLike": ["tya-mat"]}`,
+			expectedUpdate:     models.VocabularyTermUpdate{SoundsLike: &soundsLike},
 			managerError:       models.EntityNotFound,
 			expectedStatusCode: http.StatusNotFound,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Not Found",
-			},
-		},
-		{
-			description:        "internal server error",
-			userID:             "abc123",
-			campaignID:         "cmp123",
-			managerError:       fmt.Errorf("an error has occurred"),
-			expectedStatusCode: http.StatusInternalServerError,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Internal Server Error",
-			},
 		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.description, func(t *testing.T) {
 			r := gin.Default()
-			userManager := &MockUserManager{}
 			campaignManager := &MockCampaignManager{}
-			sessionManager := &MockSessionManager{}
-			transcriptionManager := &MockTranscriptionManager{}
-
-			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager)
-			if c.expectedSessionsResponse != nil {
-				sessionManager.On("GetSessionsForCampaign", mock.Anything, c.campaignID).Return(c.managerSessionssResponse, nil)
-			} else if c.managerError != nil {
-				sessionManager.On("GetSessionsForCampaign", mock.Anything, mock.Anything).Return(nil, c.managerError)
-			}
+			NewHttpAPI(r, &MockUserManager{}, campaignManager, &MockSessionManager{}, &MockTranscriptionManager{}, &MockUploadManager{}, &MockPlayerManager{}, &MockCharacterManager{}, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			campaignManager.On("UpdateVocabularyTerm", mock.Anything, "cmp123", "term-1", c.expectedUpdate).Return(c.managerTerm, c.managerError)
 
 			w := httptest.NewRecorder()
-
-			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions", c.userID, c.campaignID), nil)
+			req, _ := http.NewRequest("PATCH", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/vocabulary/term-1", bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
 			r.ServeHTTP(w, req)
 
-			if w.Code != c.expectedStatusCode {
-				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
-				return
-			}
-			if c.expectedSessionsResponse != nil {
-				var actualSessionsResponse []SessionResponse
-				err := json.Unmarshal(w.Body.Bytes(), &actualSessionsResponse)
-				if err != nil {
-					t.Fatalf("unexpected error when unmarshalling response: %s", err)
-					return
-				}
-				if len(c.expectedSessionsResponse) != len(actualSessionsResponse) {
-					t.Errorf("expected %d campaigns got %d", len(c.expectedSessionsResponse), len(actualSessionsResponse))
-					return
-				}
-
-				for i, expected := range c.expectedSessionsResponse {
-					actual := actualSessionsResponse[i]
-					assert.Equal(t, expected.Title, actual.Title)
-					assert.Equal(t, expected.SessionDate, actual.SessionDate)
-					assert.Equal(t, expected.ID, actual.ID)
-				}
-
-			} else if c.expectedErrorResponse != nil {
-				var actualErrorResponse ErrorResponse
-				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
-				if err != nil {
-					t.Fatalf("unexpected error when unmarshalling response: %s", err)
-					return
-				}
-				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+			assert.Equal(t, c.expectedStatusCode, w.Code)
+			if c.managerTerm != nil {
+				var response VocabularyTermResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, VocabularyTermResponseFromTerm(c.managerTerm), response)
 			}
 		})
 	}
 }
 
-func TestSubmitTranscriptJob(t *testing.T) {
+func TestUpdateSession(t *testing.T) {
+	newTitle := "The Great Heist"
+	sessionDate := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
 	cases := []struct {
-		description                string
-		userID                     string
-		campaignID                 string
-		sessionID                  string
-		audioFile                  []byte
-		contentType                string
-		managerTranscriptResponse  *models.Transcript
-		managerError               error
-		expectedTranscriptResponse *TranscriptResponse
-		expectedErrorResponse      *ErrorResponse
-		expectedStatusCode         int
+		description             string
+		body                    string
+		expectedUpdate          models.SessionUpdate
+		managerSession          *models.Session
+		managerError            error
+		expectedSessionResponse *SessionResponse
+		expectedStatusCode      int
 	}{
 		{
-			description: "session added",
-			userID:      "abc123",
-			campaignID:  "efg456",
-			sessionID:   "ses123",
-			audioFile:   []byte("test audio"),
-			contentType: "audio/mpeg",
-			managerTranscriptResponse: &models.Transcript{
-				JobID:              "ts123",
-				AudioLocation:      "audio.mp3",
-				AudioFormat:        models.MP3,
-				TranscriptLocation: "tx.text",
-				SummaryLocation:    "",
-				Status:             models.NotStarted,
-			},
-			expectedTranscriptResponse: &TranscriptResponse{
-				ID:     "ts123",
-				Status: "NotStarted",
+			description:    "title updated",
+			body:           `{"title": "The Great Heist"}`,
+			expectedUpdate: models.SessionUpdate{Title: &newTitle, Version: 1},
+			managerSession: &models.Session{ID: "ses123", Title: newTitle, SessionDate: sessionDate, Version: 2},
+			expectedSessionResponse: &SessionResponse{
+				ID:          "ses123",
+				Title:       newTitle,
+				SessionDate: sessionDate,
 			},
-			expectedStatusCode: http.StatusCreated,
+			expectedStatusCode: http.StatusOK,
 		},
 		{
-			description:  "transcript manager returns error unprocessable entity",
-			userID:       "abc123",
-			campaignID:   "efg456",
-			audioFile:    []byte("test audio"),
-			contentType:  "audio/mpeg",
-			managerError: models.InvalidEntity,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Invalid Request",
-			},
+			description:        "title cleared, 422 returned",
+			body:               `{"title": ""}`,
+			expectedUpdate:     models.SessionUpdate{Title: new(string), Version: 1},
+			managerError:       models.InvalidEntity,
 			expectedStatusCode: http.StatusUnprocessableEntity,
 		},
 		{
-			description:  "session manager returns generic error, Internal Server error returned",
-			userID:       "abc123",
-			campaignID:   "efg456",
-			audioFile:    []byte("test audio"),
-			contentType:  "audio/mpeg",
-			managerError: fmt.Errorf("database connection failed"),
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Internal Server Error",
-			},
-			expectedStatusCode: http.StatusInternalServerError,
+			description:        "session does not exist, 404 returned",
+			body:               `{"title": "The Great Heist"}`,
+			expectedUpdate:     models.SessionUpdate{Title: &newTitle, Version: 1},
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
 		},
 	}
 
@@ -844,100 +3953,66 @@ func TestSubmitTranscriptJob(t *testing.T) {
 			campaignManager := &MockCampaignManager{}
 			sessionManager := &MockSessionManager{}
 			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
 
-			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager)
-			//SubmitTranscriptionJob(ctx context.Context, userID, campaignID, sessionID string, audioFormat models.AudioFormat, audioFile io.Reader) (*models.Transcript, error)
-			if c.managerTranscriptResponse != nil {
-				transcriptionManager.On("SubmitTranscriptionJob", mock.Anything, c.userID, c.campaignID, c.sessionID, mock.Anything, mock.Anything).Return(c.managerTranscriptResponse, nil)
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerSession != nil {
+				sessionManager.On("UpdateSession", mock.Anything, "cmp123", "ses123", c.expectedUpdate).Return(c.managerSession, nil)
 			} else if c.managerError != nil {
-				transcriptionManager.On("SubmitTranscriptionJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, c.managerError)
+				sessionManager.On("UpdateSession", mock.Anything, "cmp123", "ses123", c.expectedUpdate).Return(nil, c.managerError)
 			}
 
 			w := httptest.NewRecorder()
 
-			req, _ := http.NewRequest("POST", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions/%s/transcripts", c.userID, c.campaignID, c.sessionID), bytes.NewReader(c.audioFile))
-			req.Header.Set("Content-Type", c.contentType)
+			req, _ := http.NewRequest("PATCH", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123", bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
+			req.Header.Set("If-Match", `W/"1"`)
 			r.ServeHTTP(w, req)
 
 			if w.Code != c.expectedStatusCode {
 				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
 				return
 			}
-			if c.expectedTranscriptResponse != nil {
-				var actualTranscriptResponse TranscriptResponse
-				err := json.Unmarshal(w.Body.Bytes(), &actualTranscriptResponse)
-				if err != nil {
-					t.Fatalf("unexpected error when unmarshalling response: %s", err)
-					return
-				}
-
-				assert.Equal(t, c.expectedTranscriptResponse.Status, actualTranscriptResponse.Status)
-				assert.Equal(t, c.expectedTranscriptResponse.ID, actualTranscriptResponse.ID)
-			} else if c.expectedErrorResponse != nil {
-				var actualErrorResponse ErrorResponse
-				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+			if c.expectedSessionResponse != nil {
+				var actualSessionResponse SessionResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualSessionResponse)
 				if err != nil {
 					t.Fatalf("unexpected error when unmarshalling response: %s", err)
 					return
 				}
-				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+				assert.Equal(t, *c.expectedSessionResponse, actualSessionResponse)
+				assert.Equal(t, `"2"`, w.Header().Get("ETag"))
 			}
 		})
-	}
-}
-
-func TestGetTranscriptJob(t *testing.T) {
-	cases := []struct {
-		description               string
-		userID                    string
-		campaignID                string
-		sessionID                 string
-		jobID                     string
-		managerTranscriptResponse *models.Transcript
-		managerError              error
-		expectedTranscriptReponse *TranscriptResponse
-		expectedErrorResponse     *ErrorResponse
-		expectedStatusCode        int
-	}{
-		{
-			description: "transcript job returned",
-			userID:      "testUID",
-			campaignID:  "cmp123",
-			sessionID:   "ses123",
-			jobID:       "job123",
-			managerTranscriptResponse: &models.Transcript{
-				JobID:              "ts123",
-				AudioLocation:      "audio.mp3",
-				AudioFormat:        models.MP3,
-				TranscriptLocation: "tx.text",
-				SummaryLocation:    "",
-				Status:             models.Summarizing,
-			},
-			expectedStatusCode: http.StatusOK,
-		},
+	}
+}
+
+func TestUpdateTranscript(t *testing.T) {
+	newSessionID := "ses456"
+	cases := []struct {
+		description                string
+		body                       string
+		managerTranscript          *models.Transcript
+		managerError               error
+		expectedTranscriptResponse *TranscriptResponse
+		expectedStatusCode         int
+	}{
 		{
-			description:        "transcript not found",
-			userID:             "testUID",
-			campaignID:         "cmp123",
-			sessionID:          "ses123",
-			jobID:              "job123",
-			managerError:       models.EntityNotFound,
-			expectedStatusCode: http.StatusNotFound,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Not Found",
+			description:       "transcript moved to another session",
+			body:              `{"sessionId": "ses456"}`,
+			managerTranscript: &models.Transcript{JobID: "job123", Status: models.Done},
+			expectedTranscriptResponse: &TranscriptResponse{
+				ID:     "job123",
+				Status: "Done",
 			},
+			expectedStatusCode: http.StatusOK,
 		},
 		{
-			description:        "internal server error",
-			userID:             "testUID",
-			campaignID:         "cmp123",
-			sessionID:          "ses123",
-			jobID:              "job123",
-			managerError:       fmt.Errorf("could not connect to database"),
-			expectedStatusCode: http.StatusInternalServerError,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Internal Server Error",
-			},
+			description:        "target session is not in the campaign, 422 returned",
+			body:               `{"sessionId": "ses456"}`,
+			managerError:       models.InvalidEntity,
+			expectedStatusCode: http.StatusUnprocessableEntity,
 		},
 	}
 
@@ -948,115 +4023,66 @@ func TestGetTranscriptJob(t *testing.T) {
 			campaignManager := &MockCampaignManager{}
 			sessionManager := &MockSessionManager{}
 			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
 
-			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager)
-			if c.managerTranscriptResponse != nil {
-				transcriptionManager.On("GetTranscriptJob", mock.Anything, c.jobID).Return(c.managerTranscriptResponse, nil)
-			} else if c.managerError != nil {
-				transcriptionManager.On("GetTranscriptJob", mock.Anything, mock.Anything).Return(nil, c.managerError)
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			expectedUpdate := models.TranscriptUpdate{SessionID: &newSessionID, Version: 5}
+			if c.managerTranscript != nil {
+				transcriptionManager.On("UpdateTranscript", mock.Anything, "cmp123", "job123", expectedUpdate).Return(c.managerTranscript, nil)
+			} else {
+				transcriptionManager.On("UpdateTranscript", mock.Anything, "cmp123", "job123", expectedUpdate).Return(nil, c.managerError)
 			}
 
 			w := httptest.NewRecorder()
 
-			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions/%s/transcripts/%s", c.userID, c.campaignID, c.sessionID, c.jobID), nil)
+			req, _ := http.NewRequest("PATCH", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123/transcripts/job123", bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
+			req.Header.Set("If-Match", `"5"`)
 			r.ServeHTTP(w, req)
 
 			if w.Code != c.expectedStatusCode {
 				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
 				return
 			}
-			if c.expectedTranscriptReponse != nil {
+			if c.expectedTranscriptResponse != nil {
 				var actualTranscriptResponse TranscriptResponse
 				err := json.Unmarshal(w.Body.Bytes(), &actualTranscriptResponse)
 				if err != nil {
 					t.Fatalf("unexpected error when unmarshalling response: %s", err)
 					return
 				}
-
-				assert.Equal(t, c.expectedTranscriptReponse.Status, actualTranscriptResponse.Status)
-				assert.Equal(t, c.expectedTranscriptReponse.ID, actualTranscriptResponse.ID)
-			} else if c.expectedErrorResponse != nil {
-				var actualErrorResponse ErrorResponse
-				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
-				if err != nil {
-					t.Fatalf("unexpected error when unmarshalling response: %s", err)
-					return
-				}
-				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+				assert.Equal(t, *c.expectedTranscriptResponse, actualTranscriptResponse)
 			}
 		})
 	}
 }
 
-func TestGetJobsForSession(t *testing.T) {
+func TestGetSession(t *testing.T) {
+	sessionDate := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
 	cases := []struct {
-		description                 string
-		userID                      string
-		campaignID                  string
-		sessionID                   string
-		managerTranscriptsResponse  []models.Transcript
-		managerError                error
-		expectedTranscriptsResponse []TranscriptResponse
-		expectedErrorResponse       *ErrorResponse
-		expectedStatusCode          int
+		description             string
+		managerSession          *models.Session
+		managerError            error
+		expectedSessionResponse *SessionResponse
+		expectedETag            string
+		expectedStatusCode      int
 	}{
 		{
-			description: "sessions returned",
-			userID:      "abc123",
-			campaignID:  "cmp123",
-			sessionID:   "ses123",
-			managerTranscriptsResponse: []models.Transcript{
-				{
-					JobID:              "ts123",
-					AudioLocation:      "audio.mp3",
-					AudioFormat:        models.MP3,
-					TranscriptLocation: "tx.text",
-					SummaryLocation:    "",
-					Status:             models.Summarizing,
-				},
-				{
-					JobID:              "ts456",
-					AudioLocation:      "audio.mp3",
-					AudioFormat:        models.MP3,
-					TranscriptLocation: "tx.text",
-					SummaryLocation:    "",
-					Status:             models.Done,
-				},
-			},
-			expectedTranscriptsResponse: []TranscriptResponse{
-				{
-					ID:     "ts123",
-					Status: "Summarizing",
-				},
-				{
-					ID:     "ts456",
-					Status: "Done",
-				},
+			description:    "session returned with its version as the ETag",
+			managerSession: &models.Session{ID: "ses123", Title: "The Heist", SessionDate: sessionDate, Version: 7},
+			expectedSessionResponse: &SessionResponse{
+				ID:          "ses123",
+				Title:       "The Heist",
+				SessionDate: sessionDate,
 			},
-
+			expectedETag:       `"7"`,
 			expectedStatusCode: http.StatusOK,
 		},
 		{
-			description:        "sessions not found",
-			userID:             "abc123",
-			campaignID:         "cmp123",
-			sessionID:          "ses123",
+			description:        "session does not exist, 404 returned",
 			managerError:       models.EntityNotFound,
 			expectedStatusCode: http.StatusNotFound,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Not Found",
-			},
-		},
-		{
-			description:        "internal server error",
-			userID:             "abc123",
-			campaignID:         "cmp123",
-			sessionID:          "ses123",
-			managerError:       fmt.Errorf("an error has occurred"),
-			expectedStatusCode: http.StatusInternalServerError,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Internal Server Error",
-			},
 		},
 	}
 
@@ -1067,100 +4093,91 @@ func TestGetJobsForSession(t *testing.T) {
 			campaignManager := &MockCampaignManager{}
 			sessionManager := &MockSessionManager{}
 			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
 
-			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager)
-			if c.managerTranscriptsResponse != nil {
-				transcriptionManager.On("GetTranscriptsForSession", mock.Anything, c.sessionID).Return(c.managerTranscriptsResponse, nil)
-			} else if c.managerError != nil {
-				transcriptionManager.On("GetTranscriptsForSession", mock.Anything, mock.Anything).Return(nil, c.managerError)
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerSession != nil {
+				sessionManager.On("GetSession", mock.Anything, "cmp123", "ses123").Return(c.managerSession, nil)
+			} else {
+				sessionManager.On("GetSession", mock.Anything, "cmp123", "ses123").Return(nil, c.managerError)
 			}
 
 			w := httptest.NewRecorder()
 
-			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions/%s/transcripts", c.userID, c.campaignID, c.sessionID), nil)
+			req, _ := http.NewRequest("GET", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions/ses123", nil)
+			req.Header.Set("Authorization", "Bearer testUID")
 			r.ServeHTTP(w, req)
 
 			if w.Code != c.expectedStatusCode {
 				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
 				return
 			}
-			if c.expectedTranscriptsResponse != nil {
-				var actualTranscriptsResponse []TranscriptResponse
-				err := json.Unmarshal(w.Body.Bytes(), &actualTranscriptsResponse)
-				if err != nil {
-					t.Fatalf("unexpected error when unmarshalling response: %s", err)
-					return
-				}
-				if len(c.expectedTranscriptsResponse) != len(actualTranscriptsResponse) {
-					t.Errorf("expected %d campaigns got %d", len(c.expectedTranscriptsResponse), len(actualTranscriptsResponse))
-					return
-				}
-
-				for i, expected := range c.expectedTranscriptsResponse {
-					actual := actualTranscriptsResponse[i]
-					assert.Equal(t, expected.Status, actual.Status)
-					assert.Equal(t, expected.ID, actual.ID)
-				}
-
-			} else if c.expectedErrorResponse != nil {
-				var actualErrorResponse ErrorResponse
-				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+			assert.Equal(t, c.expectedETag, w.Header().Get("ETag"))
+			if c.expectedSessionResponse != nil {
+				var actualSessionResponse SessionResponse
+				err := json.Unmarshal(w.Body.Bytes(), &actualSessionResponse)
 				if err != nil {
 					t.Fatalf("unexpected error when unmarshalling response: %s", err)
 					return
 				}
-				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+				assert.Equal(t, *c.expectedSessionResponse, actualSessionResponse)
 			}
 		})
 	}
 }
 
-func TestGetTranscript(t *testing.T) {
+func TestListPagination(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	sessionDate := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	cursor := encodeCursor(&models.Cursor{Value: "2024-02-24", ID: "ses100"})
 	cases := []struct {
-		description            string
-		userID                 string
-		campaignID             string
-		sessionID              string
-		jobID                  string
-		managerTranscriptText  string
-		managerError           error
-		expectedTranscriptText string
-		expectedErrorResponse  *ErrorResponse
-		expectedStatusCode     int
+		description        string
+		query              string
+		expectedFilter     models.SessionFilter
+		expectedPage       models.PageRequest
+		managerPage        *models.Page[models.Session]
+		expectedNextCursor string
+		expectedStatusCode int
 	}{
 		{
-			description:            "transcript text returned",
-			userID:                 "testUID",
-			campaignID:             "cmp123",
-			sessionID:              "ses123",
-			jobID:                  "job123",
-			managerTranscriptText:  "welcome to dnd",
-			expectedTranscriptText: "welcome to dnd",
-			expectedStatusCode:     http.StatusOK,
+			description:        "first page has a cursor to the next",
+			query:              "?limit=1&sort=-sessionDate",
+			expectedPage:       models.PageRequest{Limit: 1, Sort: models.Sort{Field: "sessionDate", Descending: true}},
+			managerPage:        &models.Page[models.Session]{Items: []models.Session{{ID: "ses123", SessionDate: sessionDate}}, NextCursor: &models.Cursor{Value: "2024-03-02", ID: "ses123"}},
+			expectedNextCursor: encodeCursor(&models.Cursor{Value: "2024-03-02", ID: "ses123"}),
+			expectedStatusCode: http.StatusOK,
 		},
 		{
-			description:        "transcript not found",
-			userID:             "testUID",
-			campaignID:         "cmp123",
-			sessionID:          "ses123",
-			jobID:              "job123",
-			managerError:       models.EntityNotFound,
-			expectedStatusCode: http.StatusNotFound,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Not Found",
-			},
+			description:        "cursor and date range passed to the manager, last page has no cursor",
+			query:              "?from=2024-01-01&to=2024-06-30&cursor=" + cursor,
+			expectedFilter:     models.SessionFilter{From: &from, To: &to},
+			expectedPage:       models.PageRequest{Cursor: &models.Cursor{Value: "2024-02-24", ID: "ses100"}},
+			managerPage:        &models.Page[models.Session]{Items: []models.Session{{ID: "ses123", SessionDate: sessionDate}}},
+			expectedStatusCode: http.StatusOK,
 		},
 		{
-			description:        "internal server error",
-			userID:             "testUID",
-			campaignID:         "cmp123",
-			sessionID:          "ses123",
-			jobID:              "job123",
-			managerError:       fmt.Errorf("could not connect to database"),
-			expectedStatusCode: http.StatusInternalServerError,
-			expectedErrorResponse: &ErrorResponse{
-				ErrorMessage: "Internal Server Error",
-			},
+			description:        "cursor keeps the order of the list it was taken from",
+			query:              "?sort=-title&cursor=" + encodeCursor(&models.Cursor{Value: "Ambush", ID: "ses100", Sort: models.Sort{Field: "title", Descending: true}}),
+			expectedPage:       models.PageRequest{Cursor: &models.Cursor{Value: "Ambush", ID: "ses100", Sort: models.Sort{Field: "title", Descending: true}}, Sort: models.Sort{Field: "title", Descending: true}},
+			managerPage:        &models.Page[models.Session]{Items: []models.Session{{ID: "ses123", SessionDate: sessionDate}}},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:        "malformed cursor, 422 returned",
+			query:              "?cursor=not-a-cursor",
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "limit is not a number, 422 returned",
+			query:              "?limit=ten",
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "malformed date, 422 returned",
+			query:              "?from=01/01/2024",
+			expectedStatusCode: http.StatusUnprocessableEntity,
 		},
 	}
 
@@ -1171,39 +4188,211 @@ func TestGetTranscript(t *testing.T) {
 			campaignManager := &MockCampaignManager{}
 			sessionManager := &MockSessionManager{}
 			transcriptionManager := &MockTranscriptionManager{}
+			playerManager := &MockPlayerManager{}
+			characterManager := &MockCharacterManager{}
 
-			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager)
-			if c.managerTranscriptText != "" {
-				transcriptionManager.On("DownloadTranscript", mock.Anything, c.jobID, mock.Anything).Run(func(args mock.Arguments) {
-					w := args.Get(2).(io.WriterAt)
-					w.WriteAt([]byte(c.managerTranscriptText), 0)
-				}).Return(int64(len(c.managerTranscriptText)), nil)
-			} else if c.managerError != nil {
-				transcriptionManager.On("DownloadTranscript", mock.Anything, mock.Anything, mock.Anything).Return(nil, c.managerError)
+			NewHttpAPI(r, userManager, campaignManager, sessionManager, transcriptionManager, &MockUploadManager{}, playerManager, characterManager, &MockWebhookManager{}, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.managerPage != nil {
+				sessionManager.On("GetSessionsForCampaign", mock.Anything, "cmp123", c.expectedFilter, c.expectedPage).Return(c.managerPage, nil)
 			}
 
 			w := httptest.NewRecorder()
 
-			req, _ := http.NewRequest("GET", fmt.Sprintf("/dragonspeak-service/v1/users/%s/campaigns/%s/sessions/%s/transcripts/%s/fulltext", c.userID, c.campaignID, c.sessionID, c.jobID), nil)
+			req, _ := http.NewRequest("GET", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/sessions"+c.query, nil)
+			req.Header.Set("Authorization", "Bearer testUID")
 			r.ServeHTTP(w, req)
 
 			if w.Code != c.expectedStatusCode {
 				t.Errorf("expected status code %d got %d", c.expectedStatusCode, w.Code)
 				return
 			}
-			if c.expectedTranscriptText != "" {
-				actualText := string(w.Body.Bytes())
-				assert.Equal(t, c.expectedTranscriptText, actualText)
-
-			} else if c.expectedErrorResponse != nil {
-				var actualErrorResponse ErrorResponse
-				err := json.Unmarshal(w.Body.Bytes(), &actualErrorResponse)
+			if c.managerPage != nil {
+				var actualPage ListResponse[SessionResponse]
+				err := json.Unmarshal(w.Body.Bytes(), &actualPage)
 				if err != nil {
 					t.Fatalf("unexpected error when unmarshalling response: %s", err)
 					return
 				}
-				assert.Equal(t, c.expectedErrorResponse.ErrorMessage, actualErrorResponse.ErrorMessage)
+				assert.Len(t, actualPage.Items, len(c.managerPage.Items))
+				assert.Equal(t, c.expectedNextCursor, actualPage.NextCursor)
 			}
 		})
 	}
 }
+
+func TestAddWebhook(t *testing.T) {
+	createdAt := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		description          string
+		body                 string
+		expectedSubscription *models.WebhookSubscription
+		managerSubscription  *models.WebhookSubscription
+		managerError         error
+		expectedResponse     string
+		expectedStatusCode   int
+	}{
+		{
+			description: "webhook added, secret left out of the response",
+			body:        `{"url": "https://bot.example.com/hooks", "secret": "0123456789abcdef", "eventTypes": ["transcript.done", "summary.done"]}`,
+			expectedSubscription: &models.WebhookSubscription{
+				URL:        "https://bot.example.com/hooks",
+				Secret:     "0123456789abcdef",
+				EventTypes: []models.WebhookEventType{models.TranscriptDoneEvent, models.SummaryDoneEvent},
+			},
+			managerSubscription: &models.WebhookSubscription{
+				ID:         "whk123",
+				URL:        "https://bot.example.com/hooks",
+				Secret:     "0123456789abcdef",
+				EventTypes: []models.WebhookEventType{models.TranscriptDoneEvent, models.SummaryDoneEvent},
+				CreatedAt:  createdAt,
+			},
+			expectedResponse:   `{"id": "whk123", "url": "https://bot.example.com/hooks", "eventTypes": ["transcript.done", "summary.done"], "createdAt": "2024-03-02T12:00:00Z"}`,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:        "unknown event type, 422 returned",
+			body:               `{"url": "https://bot.example.com/hooks", "secret": "0123456789abcdef", "eventTypes": ["session.done"]}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "malformed body, 422 returned",
+			body:               `{"url":`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description: "caller doesn't own the campaign, 403 returned",
+			body:        `{"url": "https://bot.example.com/hooks", "secret": "0123456789abcdef", "eventTypes": ["transcript.failed"]}`,
+			expectedSubscription: &models.WebhookSubscription{
+				URL:        "https://bot.example.com/hooks",
+				Secret:     "0123456789abcdef",
+				EventTypes: []models.WebhookEventType{models.TranscriptFailedEvent},
+			},
+			managerError:       models.Forbidden,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			description: "campaign has as many webhooks as allowed, 409 returned",
+			body:        `{"url": "https://bot.example.com/hooks", "secret": "0123456789abcdef", "eventTypes": ["transcript.failed"]}`,
+			expectedSubscription: &models.WebhookSubscription{
+				URL:        "https://bot.example.com/hooks",
+				Secret:     "0123456789abcdef",
+				EventTypes: []models.WebhookEventType{models.TranscriptFailedEvent},
+			},
+			managerError:       models.Conflicted,
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			webhookManager := &MockWebhookManager{}
+			NewHttpAPI(r, &MockUserManager{}, &MockCampaignManager{}, &MockSessionManager{}, &MockTranscriptionManager{}, &MockUploadManager{}, &MockPlayerManager{}, &MockCharacterManager{}, webhookManager, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			if c.expectedSubscription != nil {
+				webhookManager.On("AddSubscription", mock.Anything, "cmp123", *c.expectedSubscription).Return(c.managerSubscription, c.managerError)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/webhooks", bytes.NewReader([]byte(c.body)))
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, c.expectedStatusCode, w.Code)
+			if c.expectedResponse != "" {
+				assert.JSONEq(t, c.expectedResponse, w.Body.String())
+			}
+			webhookManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	createdAt := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	deliveredAt := createdAt.Add(time.Second)
+	r := gin.Default()
+	webhookManager := &MockWebhookManager{}
+	NewHttpAPI(r, &MockUserManager{}, &MockCampaignManager{}, &MockSessionManager{}, &MockTranscriptionManager{}, &MockUploadManager{}, &MockPlayerManager{}, &MockCharacterManager{}, webhookManager, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+	webhookManager.On("GetDeliveries", mock.Anything, "cmp123", "whk123", models.PageRequest{Limit: 2}).Return(&models.Page[models.WebhookDelivery]{
+		Items: []models.WebhookDelivery{
+			{
+				ID:             "dlv2",
+				SubscriptionID: "whk123",
+				Event:          models.WebhookEvent{ID: "evt2", Type: models.TranscriptFailedEvent, JobID: "job456"},
+				Status:         models.DeliveryPending,
+				Attempts:       2,
+				ResponseStatus: http.StatusBadGateway,
+				LastError:      "webhook responded 502",
+				NextAttemptAt:  createdAt.Add(time.Minute),
+				CreatedAt:      createdAt,
+			},
+			{
+				ID:             "dlv1",
+				SubscriptionID: "whk123",
+				Event:          models.WebhookEvent{ID: "evt1", Type: models.TranscriptDoneEvent, JobID: "job123"},
+				Status:         models.DeliveryDelivered,
+				Attempts:       1,
+				ResponseStatus: http.StatusNoContent,
+				NextAttemptAt:  createdAt,
+				CreatedAt:      createdAt,
+				DeliveredAt:    &deliveredAt,
+			},
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/webhooks/whk123/deliveries?limit=2", nil)
+	req.Header.Set("Authorization", "Bearer testUID")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items": [
+		{"id": "dlv2", "webhookId": "whk123", "eventId": "evt2", "eventType": "transcript.failed", "transcriptId": "job456", "status": "Pending",
+			"attempts": 2, "responseStatus": 502, "lastError": "webhook responded 502", "nextAttemptAt": "2024-03-02T12:01:00Z", "createdAt": "2024-03-02T12:00:00Z"},
+		{"id": "dlv1", "webhookId": "whk123", "eventId": "evt1", "eventType": "transcript.done", "transcriptId": "job123", "status": "Delivered",
+			"attempts": 1, "responseStatus": 204, "nextAttemptAt": "2024-03-02T12:00:00Z", "createdAt": "2024-03-02T12:00:00Z", "deliveredAt": "2024-03-02T12:00:01Z"}]}`, w.Body.String())
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	createdAt := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		description        string
+		managerDelivery    *models.WebhookDelivery
+		managerError       error
+		expectedStatusCode int
+	}{
+		{
+			description: "event queued to be sent again, 202 returned",
+			managerDelivery: &models.WebhookDelivery{
+				ID:             "dlv2",
+				SubscriptionID: "whk123",
+				Event:          models.WebhookEvent{ID: "evt1", Type: models.SummaryDoneEvent, JobID: "job123"},
+				Status:         models.DeliveryPending,
+				NextAttemptAt:  createdAt,
+				CreatedAt:      createdAt,
+			},
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			description:        "delivery does not exist, 404 returned",
+			managerError:       models.EntityNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			webhookManager := &MockWebhookManager{}
+			NewHttpAPI(r, &MockUserManager{}, &MockCampaignManager{}, &MockSessionManager{}, &MockTranscriptionManager{}, &MockUploadManager{}, &MockPlayerManager{}, &MockCharacterManager{}, webhookManager, &tokenIsUserAuthenticator{}, &allowAllAccessManager{})
+			webhookManager.On("Redeliver", mock.Anything, "cmp123", "whk123", "dlv1").Return(c.managerDelivery, c.managerError)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/users/testUID/campaigns/cmp123/webhooks/whk123/deliveries/dlv1/redeliver", nil)
+			req.Header.Set("Authorization", "Bearer testUID")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, c.expectedStatusCode, w.Code)
+			webhookManager.AssertExpectations(t)
+		})
+	}
+}
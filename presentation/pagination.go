@@ -0,0 +1,141 @@
+package presentation
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/gin-gonic/gin"
+)
+
+const filterDateLayout = "2006-01-02"
+
+// ListResponse is one page of a list. NextCursor is passed back as the cursor query parameter to fetch the
+// next page and is left out on the last one.
+type ListResponse[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+func listResponseFromPage[T, R any](page *models.Page[T], toResponse func(*T) R) ListResponse[R] {
+	response := ListResponse[R]{
+		Items:      []R{},
+		NextCursor: encodeCursor(page.NextCursor),
+	}
+	for i := range page.Items {
+		response.Items = append(response.Items, toResponse(&page.Items[i]))
+	}
+	return response
+}
+
+// cursorToken is the JSON inside the base64 cursor handed to clients, they should treat it as opaque.
+// SortField and Descending record the order of the list the cursor was taken from.
+type cursorToken struct {
+	Value      string `json:"v"`
+	ID         string `json:"id"`
+	SortField  string `json:"s,omitempty"`
+	Descending bool   `json:"d,omitempty"`
+}
+
+func encodeCursor(cursor *models.Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+	b, _ := json.Marshal(cursorToken{
+		Value:      cursor.Value,
+		ID:         cursor.ID,
+		SortField:  cursor.Sort.Field,
+		Descending: cursor.Sort.Descending,
+	})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(cursor string) (*models.Cursor, bool) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, false
+	}
+	var token cursorToken
+	if err = json.Unmarshal(b, &token); err != nil || token.ID == "" {
+		return nil, false
+	}
+	return &models.Cursor{
+		Value: token.Value,
+		ID:    token.ID,
+		Sort:  models.Sort{Field: token.SortField, Descending: token.Descending},
+	}, true
+}
+
+// pageRequestFromQuery reads the limit, cursor and sort query parameters of a list request. Sort names a field,
+// prefixed with "-" for descending order. A 422 is written and false returned when a parameter is malformed.
+func pageRequestFromQuery(c *gin.Context) (models.PageRequest, bool) {
+	page := models.PageRequest{}
+	if limit := c.Query("limit"); limit != "" {
+		var err error
+		page.Limit, err = strconv.Atoi(limit)
+		if err != nil || page.Limit < 1 {
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				ErrorMessage: "limit must be a positive number",
+			})
+			return page, false
+		}
+	}
+	if cursor := c.Query("cursor"); cursor != "" {
+		var ok bool
+		page.Cursor, ok = decodeCursor(cursor)
+		if !ok {
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				ErrorMessage: "cursor is not valid",
+			})
+			return page, false
+		}
+	}
+	sort := c.Query("sort")
+	page.Sort = models.Sort{
+		Field:      strings.TrimPrefix(sort, "-"),
+		Descending: strings.HasPrefix(sort, "-"),
+	}
+	return page, true
+}
+
+// sessionFilterFromQuery reads the from and to dates sessions are filtered on. A 422 is written and false
+// returned when a date is malformed.
+func sessionFilterFromQuery(c *gin.Context) (models.SessionFilter, bool) {
+	filter := models.SessionFilter{}
+	for param, bound := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse(filterDateLayout, value)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				ErrorMessage: param + " must be a date formatted as YYYY-MM-DD",
+			})
+			return filter, false
+		}
+		*bound = &date
+	}
+	return filter, true
+}
+
+// transcriptFilterFromQuery reads the status transcripts are filtered on. A 422 is written and false returned
+// when the status is unknown.
+func transcriptFilterFromQuery(c *gin.Context) (models.TranscriptFilter, bool) {
+	filter := models.TranscriptFilter{}
+	if status := c.Query("status"); status != "" {
+		transcriptStatus, err := models.TranscriptStatusFromString(status)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				ErrorMessage: "status is not a transcript status",
+			})
+			return filter, false
+		}
+		filter.Status = &transcriptStatus
+	}
+	return filter, true
+}
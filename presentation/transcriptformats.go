@@ -0,0 +1,88 @@
+package presentation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	textTranscriptFormat = "text"
+	jsonTranscriptFormat = "json"
+	srtTranscriptFormat  = "srt"
+	vttTranscriptFormat  = "vtt"
+)
+
+var (
+	srtContentType = "application/x-subrip"
+	vttContentType = "text/vtt"
+
+	transcriptFormatsByContentType = map[string]string{
+		gin.MIMEPlain:  textTranscriptFormat,
+		gin.MIMEJSON:   jsonTranscriptFormat,
+		srtContentType: srtTranscriptFormat,
+		vttContentType: vttTranscriptFormat,
+	}
+)
+
+// negotiateTranscriptFormat picks the transcript format from the format query parameter, falling back to the Accept header.
+// Plain text is used when neither is set.
+func negotiateTranscriptFormat(c *gin.Context) (string, error) {
+	if format := c.Query("format"); format != "" {
+		switch strings.ToLower(format) {
+		case textTranscriptFormat, jsonTranscriptFormat, srtTranscriptFormat, vttTranscriptFormat:
+			return strings.ToLower(format), nil
+		}
+		return "", fmt.Errorf("unsupported transcript format %s: %w", format, models.InvalidEntity)
+	}
+	contentType := c.NegotiateFormat(gin.MIMEPlain, gin.MIMEJSON, srtContentType, vttContentType)
+	if format, ok := transcriptFormatsByContentType[contentType]; ok {
+		return format, nil
+	}
+	return textTranscriptFormat, nil
+}
+
+// formatSRT renders the transcript as SubRip subtitles
+func formatSRT(document *models.TranscriptDocument) string {
+	builder := strings.Builder{}
+	for i, segment := range document.Segments {
+		fmt.Fprintf(&builder, "%d\n%s --> %s\n%s\n\n", i+1, formatTimestamp(segment.StartTime, ","), formatTimestamp(segment.EndTime, ","), captionText(segment))
+	}
+	return builder.String()
+}
+
+// formatVTT renders the transcript as WebVTT captions with the speaker as the cue voice
+func formatVTT(document *models.TranscriptDocument) string {
+	builder := strings.Builder{}
+	builder.WriteString("WEBVTT\n\n")
+	for _, segment := range document.Segments {
+		text := segment.Text
+		if speaker := segment.SpeakerName(); speaker != "" {
+			text = fmt.Sprintf("<v %s>%s", speaker, segment.Text)
+		}
+		fmt.Fprintf(&builder, "%s --> %s\n%s\n\n", formatTimestamp(segment.StartTime, "."), formatTimestamp(segment.EndTime, "."), text)
+	}
+	return builder.String()
+}
+
+func captionText(segment models.TranscriptSegment) string {
+	if segment.SpeakerName() == "" {
+		return segment.Text
+	}
+	return fmt.Sprintf("%s: %s", segment.SpeakerName(), segment.Text)
+}
+
+// formatTimestamp formats a duration as hh:mm:ss followed by the separator and milliseconds
+func formatTimestamp(d time.Duration, millisecondSeparator string) string {
+	milliseconds := d.Milliseconds()
+	hours := milliseconds / int64(time.Hour/time.Millisecond)
+	milliseconds -= hours * int64(time.Hour/time.Millisecond)
+	minutes := milliseconds / int64(time.Minute/time.Millisecond)
+	milliseconds -= minutes * int64(time.Minute/time.Millisecond)
+	seconds := milliseconds / int64(time.Second/time.Millisecond)
+	milliseconds -= seconds * int64(time.Second/time.Millisecond)
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, seconds, millisecondSeparator, milliseconds)
+}
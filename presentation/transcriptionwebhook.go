@@ -0,0 +1,94 @@
+package presentation
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// webhookSecretHeader carries the shared secret, as an EventBridge API destination sends it
+	webhookSecretHeader = "X-Webhook-Secret"
+	// webhookSignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of the body keyed with the secret
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookSignaturePrefix = "sha256="
+	// maxJobEventBytes caps the body of a job event, events are a few hundred bytes
+	maxJobEventBytes = 64 << 10
+)
+
+type transcriptionEventHandler interface {
+	HandleTranscriptionJobEvent(ctx context.Context, providerName string, data []byte) error
+}
+
+// TranscriptionWebhook receives the job state change events transcription providers send, so jobs advance
+// without waiting for them to be polled. Events are accepted when they carry the shared secret or are signed
+// with it.
+type TranscriptionWebhook struct {
+	handler transcriptionEventHandler
+	secret  []byte
+}
+
+// NewTranscriptionWebhook registers the webhook on engine, events of a provider are posted to
+// /v1/webhooks/transcription/<provider>
+func NewTranscriptionWebhook(engine *gin.Engine, handler transcriptionEventHandler, secret []byte) *TranscriptionWebhook {
+	webhook := &TranscriptionWebhook{
+		handler: handler,
+		secret:  secret,
+	}
+	engine.POST(baseUrl+"/v1/webhooks/transcription/:provider", webhook.ReceiveJobEvent)
+	return webhook
+}
+
+func (w *TranscriptionWebhook) ReceiveJobEvent(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxJobEventBytes+1))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if len(body) > maxJobEventBytes {
+		handleError(c, fmt.Errorf("job event is larger than %d bytes: %w", maxJobEventBytes, models.TooLarge))
+		return
+	}
+	if err := w.verify(c.Request.Header, body); err != nil {
+		handleError(c, err)
+		return
+	}
+	if err := w.handler.HandleTranscriptionJobEvent(c.Request.Context(), c.Param("provider"), body); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// verify returns Unauthorized unless the request carries the shared secret or a signature of body made with it
+func (w *TranscriptionWebhook) verify(header http.Header, body []byte) error {
+	if secret := header.Get(webhookSecretHeader); secret != "" {
+		if subtle.ConstantTimeCompare([]byte(secret), w.secret) == 1 {
+			return nil
+		}
+		return fmt.Errorf("wrong webhook secret: %w", models.Unauthorized)
+	}
+	signature, found := strings.CutPrefix(header.Get(webhookSignatureHeader), webhookSignaturePrefix)
+	if !found {
+		return fmt.Errorf("job event is neither signed nor carries the webhook secret: %w", models.Unauthorized)
+	}
+	decoded, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("malformed job event signature: %w", models.Unauthorized)
+	}
+	mac := hmac.New(sha256.New, w.secret)
+	mac.Write(body)
+	if !hmac.Equal(decoded, mac.Sum(nil)) {
+		return fmt.Errorf("wrong job event signature: %w", models.Unauthorized)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package presentation
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTranscriptionEventHandler struct {
+	mock.Mock
+}
+
+func (m *MockTranscriptionEventHandler) HandleTranscriptionJobEvent(ctx context.Context, providerName string, data []byte) error {
+	args := m.Called(ctx, providerName, data)
+	return args.Error(0)
+}
+
+func TestReceiveJobEvent(t *testing.T) {
+	secret := []byte("webhook-secret")
+	event := []byte(`{"detail-type": "Transcribe Job State Change", "detail": {"TranscriptionJobName": "job123", "TranscriptionJobStatus": "COMPLETED"}}`)
+	sign := func(key, body []byte) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(body)
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	cases := []struct {
+		description        string
+		headers            map[string]string
+		body               []byte
+		handlerError       error
+		expectHandled      bool
+		expectedStatusCode int
+	}{
+		{
+			description:        "event carrying the shared secret is handled",
+			headers:            map[string]string{"X-Webhook-Secret": "webhook-secret"},
+			body:               event,
+			expectHandled:      true,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "signed event is handled",
+			headers:            map[string]string{"X-Webhook-Signature": sign(secret, event)},
+			body:               event,
+			expectHandled:      true,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "wrong shared secret, unauthorized",
+			headers:            map[string]string{"X-Webhook-Secret": "guess"},
+			body:               event,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			description:        "event signed with another key, unauthorized",
+			headers:            map[string]string{"X-Webhook-Signature": sign([]byte("other"), event)},
+			body:               event,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			description:        "signed event was altered, unauthorized",
+			headers:            map[string]string{"X-Webhook-Signature": sign(secret, event)},
+			body:               bytes.Replace(event, []byte("COMPLETED"), []byte("FAILED"), 1),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			description:        "event neither signed nor carrying the secret, unauthorized",
+			body:               event,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			description:        "event is larger than any job event, too large",
+			headers:            map[string]string{"X-Webhook-Secret": "webhook-secret"},
+			body:               []byte(strings.Repeat(" ", maxJobEventBytes+1)),
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			description:        "event can't be parsed, invalid",
+			headers:            map[string]string{"X-Webhook-Secret": "webhook-secret"},
+			body:               event,
+			handlerError:       models.InvalidEntity,
+			expectHandled:      true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:        "provider doesn't send events, not found",
+			headers:            map[string]string{"X-Webhook-Secret": "webhook-secret"},
+			body:               event,
+			handlerError:       models.EntityNotFound,
+			expectHandled:      true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gin.Default()
+			handler := &MockTranscriptionEventHandler{}
+			handler.On("HandleTranscriptionJobEvent", mock.Anything, "amazon", c.body).Return(c.handlerError)
+			NewTranscriptionWebhook(r, handler, secret)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/dragonspeak-service/v1/webhooks/transcription/amazon", bytes.NewReader(c.body))
+			for name, value := range c.headers {
+				req.Header.Set(name, value)
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, c.expectedStatusCode, w.Code)
+			if c.expectHandled {
+				handler.AssertExpectations(t)
+			} else {
+				handler.AssertNotCalled(t, "HandleTranscriptionJobEvent", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
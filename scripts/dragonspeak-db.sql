@@ -16,6 +16,8 @@ CREATE TABLE Campaigns(
     OwnerUserId INT NOT NULL,
     CampaignName VARCHAR(24) NOT NULL,
     CampaignLink VARCHAR(255) NULL,
+    DefaultLanguage VARCHAR(16) NULL,
+    Version INT NOT NULL DEFAULT 1,
     FOREIGN KEY (OwnerUserId) REFERENCES Users(UserKey)
 );
 CREATE UNIQUE INDEX campaigns_idx_campaignid ON Campaigns(CampaignId);
@@ -31,16 +33,42 @@ VALUES ('StandardPlayer'),
 CREATE TABLE Players(
     PlayerKey SERIAL PRIMARY KEY,
     PlayerID VARCHAR(64) NOT NULL,
-    UserKey INT NOT NULL,
+    UserKey INT NULL,
     CampaignKey INT NOT NULL,
     PlayerName VARCHAR(24),
     PlayerType VARCHAR(16) NOT NULL,
     FOREIGN KEY (CampaignKey) REFERENCES Campaigns(CampaignKey),
-    FOREIGN KEY (UserKey) REFERENCES User(UserKey),
+    FOREIGN KEY (UserKey) REFERENCES Users(UserKey),
     FOREIGN KEY (PlayerType) REFERENCES PlayerType(PlayerType)
 );
 CREATE UNIQUE INDEX players_idx_playerId ON Players(PlayerID);
 CREATE UNIQUE INDEX players_idx_playerName_campaignKey ON Players(PlayerName, CampaignKey);
+CREATE UNIQUE INDEX players_idx_campaignKey_userKey ON Players(CampaignKey, UserKey);
+
+CREATE TABLE CampaignInvites(
+    InviteKey SERIAL PRIMARY KEY,
+    InviteCode VARCHAR(64) NOT NULL,
+    CampaignKey INT NOT NULL,
+    PlayerKey INT NULL,
+    PlayerType VARCHAR(16) NOT NULL,
+    ExpiresAt TIMESTAMP NOT NULL,
+    AcceptedByUserKey INT NULL,
+    AcceptedAt TIMESTAMP NULL,
+    FOREIGN KEY (CampaignKey) REFERENCES Campaigns(CampaignKey),
+    FOREIGN KEY (PlayerKey) REFERENCES Players(PlayerKey),
+    FOREIGN KEY (PlayerType) REFERENCES PlayerType(PlayerType),
+    FOREIGN KEY (AcceptedByUserKey) REFERENCES Users(UserKey)
+);
+CREATE UNIQUE INDEX campaigninvites_idx_inviteCode ON CampaignInvites(InviteCode);
+
+CREATE TABLE CharacterStatus(
+    CharacterStatus VARCHAR(16) PRIMARY KEY NOT NULL
+);
+
+INSERT INTO CharacterStatus(CharacterStatus)
+VALUES ('Active'),
+       ('Dead'),
+       ('Retired');
 
 CREATE TABLE Characters(
     CharacterKey SERIAL PRIMARY KEY,
@@ -48,27 +76,42 @@ CREATE TABLE Characters(
     PlayerKey INT NOT NULL,
     CharacterName VARCHAR(24) NOT NULL,
     CharacterLink VARCHAR(255) NULL,
-    FOREIGN KEY (PlayerKey) REFERENCES Players(PlayerKey)
+    CharacterStatus VARCHAR(16) NOT NULL DEFAULT 'Active',
+    FOREIGN KEY (PlayerKey) REFERENCES Players(PlayerKey),
+    FOREIGN KEY (CharacterStatus) REFERENCES CharacterStatus(CharacterStatus)
 );
 CREATE UNIQUE INDEX characters_idx_characterId ON Characters(CharacterId);
 
+CREATE TABLE CampaignVocabulary(
+    TermKey SERIAL PRIMARY KEY,
+    TermId VARCHAR(64) NOT NULL,
+    CampaignKey INT NOT NULL,
+    Phrase VARCHAR(256) NOT NULL,
+    SoundsLike JSONB NOT NULL DEFAULT '[]',
+    FOREIGN KEY (CampaignKey) REFERENCES Campaigns(CampaignKey)
+);
+CREATE UNIQUE INDEX campaignvocabulary_idx_termid ON CampaignVocabulary(TermId);
+CREATE UNIQUE INDEX campaignvocabulary_idx_campaignkey_phrase ON CampaignVocabulary(CampaignKey, LOWER(Phrase));
+
 CREATE TABLE Sessions(
     SessionKey SERIAL PRIMARY KEY,
     SessionId VARCHAR(64) NOT NULL,
     CampaignKey INT NOT NULL,
     SessionDate DATE NOT NULL,
     Title VARCHAR(24) NULL,  
+    Version INT NOT NULL DEFAULT 1,
     FOREIGN KEY (CampaignKey) REFERENCES Campaigns(CampaignKey)  
 );
 CREATE UNIQUE INDEX sessions_idx_sessionId ON Sessions(SessionId);
 CREATE INDEX sessions_idx_campaignkey_sessiondate  ON Sessions(CampaignKey, SessionDate);
 
 CREATE TABLE SessionAttendance(
-    SessionKey INT,
-    PlayerKey INT,
+    SessionKey INT NOT NULL,
+    PlayerKey INT NOT NULL,
     FOREIGN KEY(SessionKey) REFERENCES Sessions(SessionKey),
     FOREIGN KEY (PlayerKey) REFERENCES Players(PlayerKey)
 );
+CREATE UNIQUE INDEX sessionattendance_idx_sessionkey_playerkey ON SessionAttendance(SessionKey, PlayerKey);
 
 CREATE TABLE TranscriptionStatus(
     Status VARCHAR(32) PRIMARY KEY
@@ -90,10 +133,112 @@ CREATE TABLE SessionTranscripts(
     AudioFormat VARCHAR(10) NULL,
     TranscriptLocation VARCHAR(128) NULL,
     SummaryLocation VARCHAR(128) NULL,
-    Status VARCHAR(16) NOT NULL,
-    FOREIGN KEY (Status) REFERENCES TranscriptionStatus(Status)
+    Status VARCHAR(32) NOT NULL,
+    Version INT NOT NULL DEFAULT 1,
+    AudioSha256 CHAR(64) NULL,
+    AudioSize BIGINT NULL,
+    Provider VARCHAR(32) NOT NULL DEFAULT 'amazon',
+    Language VARCHAR(16) NOT NULL DEFAULT 'en-US',
+    DetectedLanguage VARCHAR(16) NULL,
+    JobState VARCHAR(32) NOT NULL DEFAULT 'PendingUpload',
+    FailureReason TEXT NULL,
+    Attempts INT NOT NULL DEFAULT 1,
+    CreatedAt TIMESTAMP NOT NULL DEFAULT NOW(),
+    UpdatedAt TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (Status) REFERENCES TranscriptionStatus(Status),
     FOREIGN KEY (SessionId) REFERENCES Sessions(SessionKey)
 );
 CREATE UNIQUE INDEX sessiontrascripts_idx_transcriptionjobid ON SessionTranscripts(TranscriptionJobId);
 CREATE INDEX sessiontranscripts_idx_status ON SessionTranscripts(Status);
 
+CREATE TABLE TranscriptionOutbox(
+    TranscriptKey INT PRIMARY KEY,
+    Step VARCHAR(32) NOT NULL,
+    Options JSONB NOT NULL DEFAULT '{}',
+    Attempts INT NOT NULL DEFAULT 0,
+    NextAttemptAt TIMESTAMP NOT NULL DEFAULT NOW(),
+    LastError TEXT NULL,
+    FOREIGN KEY (TranscriptKey) REFERENCES SessionTranscripts(TranscriptKey)
+);
+CREATE INDEX transcriptionoutbox_idx_nextattemptat ON TranscriptionOutbox(NextAttemptAt);
+
+
+CREATE TABLE AudioUploads(
+    UploadKey SERIAL PRIMARY KEY,
+    UploadId VARCHAR(128) NOT NULL,
+    SessionKey INT NOT NULL,
+    AudioFormat VARCHAR(10) NOT NULL,
+    UploadLength BIGINT NOT NULL,
+    UploadOffset BIGINT NOT NULL DEFAULT 0,
+    AudioLocation VARCHAR(128) NOT NULL,
+    StorageUploadId VARCHAR(1024) NOT NULL,
+    Parts JSONB NOT NULL DEFAULT '[]',
+    HashState BYTEA NULL,
+    MaxSpeakers INT NOT NULL,
+    Provider VARCHAR(32) NOT NULL DEFAULT '',
+    Language VARCHAR(16) NOT NULL DEFAULT '',
+    Assembled BOOLEAN NOT NULL DEFAULT FALSE,
+    TranscriptionJobId VARCHAR(128) NULL,
+    CreatedAt TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (SessionKey) REFERENCES Sessions(SessionKey)
+);
+CREATE UNIQUE INDEX audiouploads_idx_uploadid ON AudioUploads(UploadId);
+
+CREATE TABLE DirectUploads(
+    UploadKey SERIAL PRIMARY KEY,
+    UploadId VARCHAR(128) NOT NULL,
+    SessionKey INT NOT NULL,
+    AudioFormat VARCHAR(10) NOT NULL,
+    ContentType VARCHAR(128) NOT NULL,
+    UploadLength BIGINT NOT NULL,
+    AudioSha256 CHAR(64) NULL,
+    AudioLocation VARCHAR(128) NOT NULL,
+    MaxSpeakers INT NOT NULL,
+    Provider VARCHAR(32) NOT NULL DEFAULT '',
+    Language VARCHAR(16) NOT NULL DEFAULT '',
+    TranscriptionJobId VARCHAR(128) NULL,
+    CreatedAt TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (SessionKey) REFERENCES Sessions(SessionKey)
+);
+CREATE UNIQUE INDEX directuploads_idx_uploadid ON DirectUploads(UploadId);
+
+CREATE TABLE TranscriptSpeakers(
+    TranscriptKey INT NOT NULL,
+    SpeakerLabel VARCHAR(16) NOT NULL,
+    PlayerKey INT NOT NULL,
+    FOREIGN KEY (TranscriptKey) REFERENCES SessionTranscripts(TranscriptKey),
+    FOREIGN KEY (PlayerKey) REFERENCES Players(PlayerKey)
+);
+CREATE UNIQUE INDEX transcriptspeakers_idx_transcriptkey_speakerlabel ON TranscriptSpeakers(TranscriptKey, SpeakerLabel);
+
+CREATE TABLE WebhookSubscriptions(
+    SubscriptionKey SERIAL PRIMARY KEY,
+    SubscriptionId VARCHAR(64) NOT NULL,
+    CampaignKey INT NOT NULL,
+    Url VARCHAR(2048) NOT NULL,
+    Secret VARCHAR(256) NOT NULL,
+    EventTypes JSONB NOT NULL DEFAULT '[]',
+    CreatedAt TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (CampaignKey) REFERENCES Campaigns(CampaignKey)
+);
+CREATE UNIQUE INDEX webhooksubscriptions_idx_subscriptionid ON WebhookSubscriptions(SubscriptionId);
+CREATE INDEX webhooksubscriptions_idx_campaignkey ON WebhookSubscriptions(CampaignKey);
+
+CREATE TABLE WebhookDeliveries(
+    DeliveryKey SERIAL PRIMARY KEY,
+    DeliveryId VARCHAR(64) NOT NULL,
+    SubscriptionKey INT NOT NULL,
+    EventType VARCHAR(32) NOT NULL,
+    Event JSONB NOT NULL,
+    Status VARCHAR(16) NOT NULL DEFAULT 'Pending',
+    Attempts INT NOT NULL DEFAULT 0,
+    NextAttemptAt TIMESTAMP NOT NULL DEFAULT NOW(),
+    ResponseStatus INT NOT NULL DEFAULT 0,
+    LastError TEXT NULL,
+    CreatedAt TIMESTAMP NOT NULL DEFAULT NOW(),
+    DeliveredAt TIMESTAMP NULL,
+    FOREIGN KEY (SubscriptionKey) REFERENCES WebhookSubscriptions(SubscriptionKey)
+);
+CREATE UNIQUE INDEX webhookdeliveries_idx_deliveryid ON WebhookDeliveries(DeliveryId);
+CREATE INDEX webhookdeliveries_idx_status_nextattemptat ON WebhookDeliveries(Status, NextAttemptAt);
+CREATE INDEX webhookdeliveries_idx_subscriptionkey_createdat ON WebhookDeliveries(SubscriptionKey, CreatedAt);
@@ -0,0 +1,20 @@
+#!/bin/sh
+# Posts a signed Transcribe job state change event to a locally running service, standing in for the provider.
+# Usage: TRANSCRIPTION_WEBHOOK_SECRET=... scripts/post-transcription-event.sh <job name> [COMPLETED|FAILED|IN_PROGRESS] [provider]
+# The provider defaults to fake, SERVICE_URL defaults to http://localhost:8080.
+set -eu
+
+job_name=${1:?usage: $0 <job name> [status] [provider]}
+status=${2:-COMPLETED}
+provider=${3:-fake}
+service_url=${SERVICE_URL:-http://localhost:8080}
+secret=${TRANSCRIPTION_WEBHOOK_SECRET:?TRANSCRIPTION_WEBHOOK_SECRET must be set}
+
+body=$(printf '{"version":"0","detail-type":"Transcribe Job State Change","source":"aws.transcribe","detail":{"TranscriptionJobName":"%s","TranscriptionJobStatus":"%s"}}' "$job_name" "$status")
+signature=$(printf '%s' "$body" | openssl dgst -sha256 -hmac "$secret" | sed 's/^.* //')
+
+curl -sS -X POST "$service_url/dragonspeak-service/v1/webhooks/transcription/$provider" \
+	-H "Content-Type: application/json" \
+	-H "X-Webhook-Signature: sha256=$signature" \
+	-d "$body" \
+	-w '%{http_code}\n'
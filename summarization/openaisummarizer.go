@@ -0,0 +1,155 @@
+package summarization
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOpenAIModel   = "gpt-4o-mini"
+	defaultMaxChunkChars = 200000
+	requestTimeout       = 5 * time.Minute
+)
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+type chatCompletionError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// OpenAISummarizer summarizes transcripts with any OpenAI compatible chat completion API.
+type OpenAISummarizer struct {
+	client        *http.Client
+	baseURL       string
+	apiKey        string
+	model         string
+	maxChunkChars int
+}
+
+// NewOpenAISummarizer creates a summarizer for the chat completion API at baseURL. Empty baseURL and model
+// fall back to the OpenAI API and a default model.
+func NewOpenAISummarizer(apiKey, baseURL, model string) *OpenAISummarizer {
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+	return &OpenAISummarizer{
+		client:        &http.Client{Timeout: requestTimeout},
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		apiKey:        apiKey,
+		model:         model,
+		maxChunkChars: defaultMaxChunkChars,
+	}
+}
+
+// Summarize recaps the transcript. Transcripts longer than the chunk size are summarized in parts and the
+// partial recaps are then combined.
+func (o *OpenAISummarizer) Summarize(ctx context.Context, transcript string) (string, error) {
+	if strings.TrimSpace(transcript) == "" {
+		return "", errors.New("transcript is empty")
+	}
+	chunks := splitIntoChunks(transcript, o.maxChunkChars)
+	if len(chunks) == 1 {
+		return o.complete(ctx, systemPrompt, chunks[0])
+	}
+
+	partialSummaries := []string{}
+	for i, chunk := range chunks {
+		summary, err := o.complete(ctx, systemPrompt, chunk)
+		if err != nil {
+			return "", fmt.Errorf("failed to summarize part %d of %d: %w", i+1, len(chunks), err)
+		}
+		partialSummaries = append(partialSummaries, fmt.Sprintf("Part %d:\n%s", i+1, summary))
+	}
+	return o.complete(ctx, combinePrompt, strings.Join(partialSummaries, "\n\n"))
+}
+
+func (o *OpenAISummarizer) complete(ctx context.Context, prompt, content string) (string, error) {
+	body, err := json.Marshal(chatCompletionRequest{
+		Model: o.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: content},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiError := chatCompletionError{}
+		if json.Unmarshal(respBody, &apiError) == nil && apiError.Error.Message != "" {
+			return "", fmt.Errorf("chat completion failed with status %d: %s", resp.StatusCode, apiError.Error.Message)
+		}
+		return "", fmt.Errorf("chat completion failed with status %d", resp.StatusCode)
+	}
+
+	completion := chatCompletionResponse{}
+	if err := json.Unmarshal(respBody, &completion); err != nil {
+		return "", err
+	}
+	if len(completion.Choices) == 0 {
+		return "", errors.New("chat completion returned no choices")
+	}
+	return strings.TrimSpace(completion.Choices[0].Message.Content), nil
+}
+
+// splitIntoChunks splits text into chunks of at most maxChars, preferring to break on line endings.
+func splitIntoChunks(text string, maxChars int) []string {
+	chunks := []string{}
+	for len(text) > maxChars {
+		cut := strings.LastIndex(text[:maxChars], "\n")
+		if cut <= 0 {
+			cut = strings.LastIndex(text[:maxChars], " ")
+		}
+		if cut <= 0 {
+			cut = maxChars
+		}
+		chunks = append(chunks, text[:cut])
+		text = strings.TrimLeft(text[cut:], "\n ")
+	}
+	return append(chunks, text)
+}
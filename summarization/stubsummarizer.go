@@ -0,0 +1,27 @@
+package summarization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+var stubExcerptWords = 50
+
+// StubSummarizer produces a deterministic summary without calling out to a language model. It is intended
+// for tests and local development.
+type StubSummarizer struct {
+}
+
+func NewStubSummarizer() *StubSummarizer {
+	return &StubSummarizer{}
+}
+
+func (s *StubSummarizer) Summarize(ctx context.Context, transcript string) (string, error) {
+	words := strings.Fields(transcript)
+	excerpt := words
+	if len(excerpt) > stubExcerptWords {
+		excerpt = excerpt[:stubExcerptWords]
+	}
+	return fmt.Sprintf("# Session Summary\n\nThe session transcript contains %d words.\n\n> %s\n", len(words), strings.Join(excerpt, " ")), nil
+}
@@ -0,0 +1,16 @@
+package summarization
+
+import "context"
+
+// Summarizer condenses the full text of a session transcript into a markdown recap.
+type Summarizer interface {
+	Summarize(ctx context.Context, transcript string) (string, error)
+}
+
+const systemPrompt = `You are the chronicler of a tabletop role-playing game. You will be given the transcript of a game session.
+Write a recap of the session in markdown for the players and the game master. Include a short overview, the key events in order,
+the notable characters and locations, any loot or rewards, and any unresolved plot threads. Do not invent events that are not in the transcript.`
+
+const combinePrompt = `You are the chronicler of a tabletop role-playing game. You will be given recaps of consecutive parts of a single game session.
+Combine them into one recap of the whole session in markdown with a short overview, the key events in order, the notable characters and locations,
+any loot or rewards, and any unresolved plot threads.`
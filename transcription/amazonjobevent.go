@@ -0,0 +1,70 @@
+package transcription
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+const (
+	// amazonJobEventType is the detail type of the EventBridge event Amazon Transcribe sends when a job changes state
+	amazonJobEventType = "Transcribe Job State Change"
+	snsNotification    = "Notification"
+	snsConfirmation    = "SubscriptionConfirmation"
+)
+
+type amazonJobEvent struct {
+	DetailType string `json:"detail-type"`
+	Detail     struct {
+		TranscriptionJobName   string `json:"TranscriptionJobName"`
+		TranscriptionJobStatus string `json:"TranscriptionJobStatus"`
+		FailureReason          string `json:"FailureReason"`
+	} `json:"detail"`
+}
+
+// snsMessage is the envelope of an event delivered through an SNS topic, the event itself is the Message string
+type snsMessage struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+// ParseAmazonJobEvent converts an Amazon Transcribe job state change event into a models.TranscriptionJobEvent.
+// The event is accepted as EventBridge delivers it and wrapped in an SNS notification. Nil is returned for
+// other events and for SNS subscription confirmations, whose subscribe URL is logged so it can be confirmed.
+// InvalidEntity is returned for a body that isn't an event.
+func ParseAmazonJobEvent(data []byte) (*models.TranscriptionJobEvent, error) {
+	var envelope snsMessage
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse job event: %s: %w", err, models.InvalidEntity)
+	}
+	switch envelope.Type {
+	case snsConfirmation:
+		log.Printf("confirm the SNS subscription for transcription job events by visiting %s", envelope.SubscribeURL)
+		return nil, nil
+	case snsNotification:
+		data = []byte(envelope.Message)
+	}
+
+	var event amazonJobEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse job event: %s: %w", err, models.InvalidEntity)
+	}
+	if event.DetailType != amazonJobEventType {
+		return nil, nil
+	}
+	if event.Detail.TranscriptionJobName == "" {
+		return nil, fmt.Errorf("job event has no TranscriptionJobName: %w", models.InvalidEntity)
+	}
+	status, err := statusFromString(event.Detail.TranscriptionJobStatus)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err, models.InvalidEntity)
+	}
+	return &models.TranscriptionJobEvent{
+		JobName:       event.Detail.TranscriptionJobName,
+		Status:        status,
+		FailureReason: event.Detail.FailureReason,
+	}, nil
+}
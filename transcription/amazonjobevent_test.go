@@ -0,0 +1,73 @@
+package transcription
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmazonJobEvent(t *testing.T) {
+	completed := `{"version": "0", "detail-type": "Transcribe Job State Change", "source": "aws.transcribe",
+		"detail": {"TranscriptionJobName": "job-1", "TranscriptionJobStatus": "COMPLETED"}}`
+
+	cases := []struct {
+		description   string
+		event         string
+		expectedEvent *models.TranscriptionJobEvent
+		expectedError error
+	}{
+		{
+			description:   "EventBridge event of a completed job",
+			event:         completed,
+			expectedEvent: &models.TranscriptionJobEvent{JobName: "job-1", Status: models.TranscriptionJobCompleted},
+		},
+		{
+			description: "EventBridge event of a failed job, failure reason is kept",
+			event: `{"detail-type": "Transcribe Job State Change",
+				"detail": {"TranscriptionJobName": "job-1-2", "TranscriptionJobStatus": "FAILED", "FailureReason": "Unsupported audio format"}}`,
+			expectedEvent: &models.TranscriptionJobEvent{JobName: "job-1-2", Status: models.TranscriptionJobFailed, FailureReason: "Unsupported audio format"},
+		},
+		{
+			description:   "event delivered through an SNS notification",
+			event:         `{"Type": "Notification", "TopicArn": "arn:aws:sns:us-east-1:123:transcribe", "Message": "{\"detail-type\": \"Transcribe Job State Change\", \"detail\": {\"TranscriptionJobName\": \"job-1\", \"TranscriptionJobStatus\": \"IN_PROGRESS\"}}"}`,
+			expectedEvent: &models.TranscriptionJobEvent{JobName: "job-1", Status: models.TranscriptionJobInProgress},
+		},
+		{
+			description: "SNS subscription confirmation, no event",
+			event:       `{"Type": "SubscriptionConfirmation", "SubscribeURL": "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription"}`,
+		},
+		{
+			description: "event of another kind, no event",
+			event:       `{"detail-type": "Transcribe Vocabulary State Change", "detail": {"VocabularyName": "dragonspeak-en-US"}}`,
+		},
+		{
+			description:   "event without a job name, invalid",
+			event:         `{"detail-type": "Transcribe Job State Change", "detail": {"TranscriptionJobStatus": "COMPLETED"}}`,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "event with an unknown status, invalid",
+			event:         `{"detail-type": "Transcribe Job State Change", "detail": {"TranscriptionJobName": "job-1", "TranscriptionJobStatus": "PAUSED"}}`,
+			expectedError: models.InvalidEntity,
+		},
+		{
+			description:   "body that isn't JSON, invalid",
+			event:         `job-1 completed`,
+			expectedError: models.InvalidEntity,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			event, err := ParseAmazonJobEvent([]byte(c.event))
+			if c.expectedError != nil {
+				assert.True(t, errors.Is(err, c.expectedError), "expected error %s got %v", c.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedEvent, event)
+		})
+	}
+}
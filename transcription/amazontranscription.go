@@ -1,39 +1,31 @@
 package transcription
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/EdgarH78/dragonspeak-service/models"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/transcribeservice"
 )
 
-// TranscriptionJobStatus is a custom type for transcription job statuses
-type TranscriptionJobStatus int
-
-// Constants for the transcription job statuses
-const (
-	TranscriptionJobStatusQueued TranscriptionJobStatus = iota
-	TranscriptionJobStatusInProgress
-	TranscriptionJobStatusFailed
-	TranscriptionJobStatusCompleted
-)
-
-// statusStrings maps TranscriptionJobStatusType constants to their string representations
-var statusStrings = []string{"QUEUED", "IN_PROGRESS", "FAILED", "COMPLETED"}
-
-// String returns the string representation of a TranscriptionJobStatusType
-func (s TranscriptionJobStatus) String() string {
-	return statusStrings[s]
+// statusStrings maps the Amazon Transcribe job statuses to their models.TranscriptionJobStatus
+var statusStrings = map[string]models.TranscriptionJobStatus{
+	"QUEUED":      models.TranscriptionJobQueued,
+	"IN_PROGRESS": models.TranscriptionJobInProgress,
+	"FAILED":      models.TranscriptionJobFailed,
+	"COMPLETED":   models.TranscriptionJobCompleted,
 }
 
-// FromString converts a string to a TranscriptionJobStatusType
-func statusFromString(str string) (TranscriptionJobStatus, error) {
-	for i, s := range statusStrings {
+// statusFromString converts an Amazon Transcribe job status to a models.TranscriptionJobStatus
+func statusFromString(str string) (models.TranscriptionJobStatus, error) {
+	for s, status := range statusStrings {
 		if strings.EqualFold(s, str) { // Case insensitive comparison
-			return TranscriptionJobStatus(i), nil
+			return status, nil
 		}
 	}
 	return 0, fmt.Errorf("invalid TranscriptionJobStatusType: %s", str)
@@ -42,35 +34,75 @@ func statusFromString(str string) (TranscriptionJobStatus, error) {
 type AmazonTranscription struct {
 	svc          *transcribeservice.TranscribeService
 	outputBucket string
+	fileStore    fileStore
 }
 
-func NewAmazonTranscription(sess *session.Session, outputBucket string) *AmazonTranscription {
+// NewAmazonTranscription creates an AmazonTranscription writing transcripts to outputBucket, fileStore stores
+// the custom vocabulary tables in the same bucket
+func NewAmazonTranscription(sess *session.Session, outputBucket string, fileStore fileStore) *AmazonTranscription {
 	return &AmazonTranscription{
 		svc:          transcribeservice.New(sess),
 		outputBucket: outputBucket,
+		fileStore:    fileStore,
 	}
 }
 
-func (t *AmazonTranscription) StartTranscriptionJob(jobName, audioLocation, resultLocation string, aduioFormat models.AudioFormat) error {
+var defaultMaxSpeakerLabels = 10
+
+func (t *AmazonTranscription) StartTranscriptionJob(jobName, audioLocation, resultLocation string, aduioFormat models.AudioFormat, options models.TranscriptionOptions) error {
 	// Start transcription job
 	mediaFormat, err := audioFormatToMediaString(aduioFormat)
 	if err != nil {
 		return err
 	}
-	_, err = t.svc.StartTranscriptionJob(&transcribeservice.StartTranscriptionJobInput{
+	settings := &transcribeservice.Settings{
+		ShowAlternatives: aws.Bool(false),
+	}
+	if options.MaxSpeakers != 1 {
+		maxSpeakers := options.MaxSpeakers
+		if maxSpeakers < 2 {
+			maxSpeakers = defaultMaxSpeakerLabels
+		}
+		if maxSpeakers > models.MaxSpeakerLabels {
+			maxSpeakers = models.MaxSpeakerLabels
+		}
+		settings.ShowSpeakerLabels = aws.Bool(true)
+		settings.MaxSpeakerLabels = aws.Int64(int64(maxSpeakers))
+	}
+	input := &transcribeservice.StartTranscriptionJobInput{
 		TranscriptionJobName: aws.String(jobName),
-		LanguageCode:         aws.String("en-US"),     // Set to the language of your audio file
 		MediaFormat:          aws.String(mediaFormat), // Set to the format of your audio file
 		Media: &transcribeservice.Media{
 			MediaFileUri: aws.String(fmt.Sprintf("s3://dragonspeak-files/%s", audioLocation)),
 		},
-		Settings: &transcribeservice.Settings{
-			ShowAlternatives: aws.Bool(false),
-		},
+		Settings:         settings,
 		OutputBucketName: aws.String(t.outputBucket),
 		OutputKey:        &resultLocation,
-	})
-
+	}
+	switch options.Language {
+	case models.AutoLanguage:
+		input.IdentifyLanguage = aws.Bool(true)
+	case "":
+		input.LanguageCode = aws.String(models.DefaultLanguage)
+	default:
+		input.LanguageCode = aws.String(options.Language)
+	}
+	// a custom vocabulary is tied to a language, so it can't be used when the language is identified
+	if len(options.Vocabulary) > 0 && input.LanguageCode != nil {
+		vocabularyName, err := t.vocabulary(*input.LanguageCode, options.Vocabulary)
+		if err != nil {
+			// the vocabulary only improves accuracy, it's not worth failing the job over
+			log.Printf("transcription job %s is started without its custom vocabulary: %s", jobName, err)
+		} else {
+			settings.VocabularyName = aws.String(vocabularyName)
+		}
+	}
+	_, err = t.svc.StartTranscriptionJob(input)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == transcribeservice.ErrCodeConflictException {
+		// the job was started before, its name is unique to the transcript
+		return nil
+	}
 	return err
 }
 
@@ -95,20 +127,29 @@ func audioFormatToMediaString(audioFormat models.AudioFormat) (string, error) {
 	}
 }
 
-func (t *AmazonTranscription) GetTranscriptionJobStatus(jobName string) (TranscriptionJobStatus, error) {
+func (t *AmazonTranscription) GetTranscriptionJobStatus(jobName string) (models.TranscriptionJobStatus, error) {
 	// Query the transcription job
 	result, err := t.svc.GetTranscriptionJob(&transcribeservice.GetTranscriptionJobInput{
 		TranscriptionJobName: &jobName,
 	})
 	if err != nil {
-		return TranscriptionJobStatusFailed, err
+		return models.TranscriptionJobFailed, err
 	}
 
 	status, err := statusFromString(*result.TranscriptionJob.TranscriptionJobStatus)
 	if err != nil {
-		return TranscriptionJobStatusFailed, err
+		return models.TranscriptionJobFailed, err
 	}
 
 	return status, nil
+}
+
+// ParseTranscript converts the JSON document Amazon Transcribe writes to the result location into a models.TranscriptDocument
+func (t *AmazonTranscription) ParseTranscript(data []byte) (*models.TranscriptDocument, error) {
+	return ParseAmazonTranscript(data)
+}
 
+// ParseJobEvent converts a job state change event Amazon Transcribe sent through EventBridge or SNS
+func (t *AmazonTranscription) ParseJobEvent(data []byte) (*models.TranscriptionJobEvent, error) {
+	return ParseAmazonJobEvent(data)
 }
@@ -0,0 +1,278 @@
+package transcription
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+type amazonAlternative struct {
+	Confidence string `json:"confidence"`
+	Content    string `json:"content"`
+}
+
+type amazonItem struct {
+	ID           int                 `json:"id"`
+	StartTime    string              `json:"start_time"`
+	EndTime      string              `json:"end_time"`
+	Type         string              `json:"type"`
+	SpeakerLabel string              `json:"speaker_label"`
+	Alternatives []amazonAlternative `json:"alternatives"`
+}
+
+type amazonSpeakerSegment struct {
+	SpeakerLabel string `json:"speaker_label"`
+	Items        []struct {
+		StartTime    string `json:"start_time"`
+		SpeakerLabel string `json:"speaker_label"`
+	} `json:"items"`
+}
+
+type amazonAudioSegment struct {
+	ID           int    `json:"id"`
+	Transcript   string `json:"transcript"`
+	StartTime    string `json:"start_time"`
+	EndTime      string `json:"end_time"`
+	SpeakerLabel string `json:"speaker_label"`
+	Items        []int  `json:"items"`
+}
+
+type amazonTranscriptDocument struct {
+	Results struct {
+		// LanguageCode is the language the job was transcribed in, identified or as it was requested
+		LanguageCode string `json:"language_code"`
+		Transcripts  []struct {
+			Transcript string `json:"transcript"`
+		} `json:"transcripts"`
+		SpeakerLabels struct {
+			Segments []amazonSpeakerSegment `json:"segments"`
+		} `json:"speaker_labels"`
+		Items         []amazonItem         `json:"items"`
+		AudioSegments []amazonAudioSegment `json:"audio_segments"`
+	} `json:"results"`
+}
+
+const (
+	pronunciationItem = "pronunciation"
+	punctuationItem   = "punctuation"
+)
+
+// ParseAmazonTranscript converts the JSON document written by Amazon Transcribe into a models.TranscriptDocument.
+// Documents with audio segments are segmented the way Transcribe segmented them, otherwise the items are
+// grouped into sentences, splitting whenever the speaker changes.
+func ParseAmazonTranscript(data []byte) (*models.TranscriptDocument, error) {
+	doc := amazonTranscriptDocument{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	document, err := amazonSegments(doc)
+	if err != nil {
+		return nil, err
+	}
+	document.Language = doc.Results.LanguageCode
+	return document, nil
+}
+
+func amazonSegments(doc amazonTranscriptDocument) (*models.TranscriptDocument, error) {
+	items := doc.Results.Items
+	assignSpeakers(items, doc.Results.SpeakerLabels.Segments)
+
+	if len(doc.Results.AudioSegments) > 0 {
+		return segmentsFromAudioSegments(doc.Results.AudioSegments, items)
+	}
+	if len(items) == 0 {
+		// transcripts without item level detail only carry the full text
+		segments := []models.TranscriptSegment{}
+		for _, transcript := range doc.Results.Transcripts {
+			if transcript.Transcript != "" {
+				segments = append(segments, models.TranscriptSegment{Text: transcript.Transcript})
+			}
+		}
+		return &models.TranscriptDocument{Segments: segments}, nil
+	}
+	return segmentsFromItems(items)
+}
+
+// assignSpeakers fills in the speaker of items from the speaker label segments used by older output versions
+func assignSpeakers(items []amazonItem, speakerSegments []amazonSpeakerSegment) {
+	speakerByStartTime := map[string]string{}
+	for _, segment := range speakerSegments {
+		for _, item := range segment.Items {
+			speaker := item.SpeakerLabel
+			if speaker == "" {
+				speaker = segment.SpeakerLabel
+			}
+			speakerByStartTime[item.StartTime] = speaker
+		}
+	}
+	lastSpeaker := ""
+	for i := range items {
+		if items[i].SpeakerLabel == "" {
+			if items[i].Type == punctuationItem {
+				items[i].SpeakerLabel = lastSpeaker
+			} else {
+				items[i].SpeakerLabel = speakerByStartTime[items[i].StartTime]
+			}
+		}
+		lastSpeaker = items[i].SpeakerLabel
+	}
+}
+
+func segmentsFromAudioSegments(audioSegments []amazonAudioSegment, items []amazonItem) (*models.TranscriptDocument, error) {
+	itemsByID := map[int]amazonItem{}
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+
+	segments := []models.TranscriptSegment{}
+	for _, audioSegment := range audioSegments {
+		startTime, err := parseSeconds(audioSegment.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		endTime, err := parseSeconds(audioSegment.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		segmentItems := []amazonItem{}
+		for _, id := range audioSegment.Items {
+			if item, ok := itemsByID[id]; ok {
+				segmentItems = append(segmentItems, item)
+			}
+		}
+		confidence, err := averageConfidence(segmentItems)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, models.TranscriptSegment{
+			StartTime:  startTime,
+			EndTime:    endTime,
+			Speaker:    audioSegment.SpeakerLabel,
+			Text:       strings.TrimSpace(audioSegment.Transcript),
+			Confidence: confidence,
+		})
+	}
+	return &models.TranscriptDocument{Segments: segments}, nil
+}
+
+func segmentsFromItems(items []amazonItem) (*models.TranscriptDocument, error) {
+	segments := []models.TranscriptSegment{}
+	current := []amazonItem{}
+	flush := func() error {
+		if len(current) == 0 {
+			return nil
+		}
+		segment, err := segmentFromItems(current)
+		if err != nil {
+			return err
+		}
+		segments = append(segments, *segment)
+		current = []amazonItem{}
+		return nil
+	}
+
+	for _, item := range items {
+		if item.Type == pronunciationItem && len(current) > 0 && item.SpeakerLabel != current[len(current)-1].SpeakerLabel {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+		current = append(current, item)
+		if item.Type == punctuationItem && endsSentence(content(item)) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return &models.TranscriptDocument{Segments: segments}, nil
+}
+
+func segmentFromItems(items []amazonItem) (*models.TranscriptSegment, error) {
+	segment := models.TranscriptSegment{}
+	text := strings.Builder{}
+	timed := false
+	for _, item := range items {
+		if item.Type == punctuationItem {
+			text.WriteString(content(item))
+			continue
+		}
+		if text.Len() > 0 {
+			text.WriteString(" ")
+		}
+		text.WriteString(content(item))
+		if segment.Speaker == "" {
+			segment.Speaker = item.SpeakerLabel
+		}
+
+		startTime, err := parseSeconds(item.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		endTime, err := parseSeconds(item.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		if !timed {
+			segment.StartTime = startTime
+			timed = true
+		}
+		segment.EndTime = endTime
+	}
+	confidence, err := averageConfidence(items)
+	if err != nil {
+		return nil, err
+	}
+	segment.Text = strings.TrimSpace(text.String())
+	segment.Confidence = confidence
+	return &segment, nil
+}
+
+// averageConfidence is the mean confidence of the pronunciation items, punctuation carries no confidence
+func averageConfidence(items []amazonItem) (float64, error) {
+	total := 0.0
+	count := 0
+	for _, item := range items {
+		if item.Type != pronunciationItem || len(item.Alternatives) == 0 {
+			continue
+		}
+		confidence, err := strconv.ParseFloat(item.Alternatives[0].Confidence, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid confidence %q: %w", item.Alternatives[0].Confidence, err)
+		}
+		total += confidence
+		count++
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return total / float64(count), nil
+}
+
+func content(item amazonItem) string {
+	if len(item.Alternatives) == 0 {
+		return ""
+	}
+	return item.Alternatives[0].Content
+}
+
+func endsSentence(punctuation string) bool {
+	return punctuation == "." || punctuation == "?" || punctuation == "!"
+}
+
+func parseSeconds(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: %w", value, err)
+	}
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond), nil
+}
@@ -0,0 +1,102 @@
+package transcription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmazonTranscript(t *testing.T) {
+	cases := []struct {
+		description      string
+		transcript       string
+		expectedDocument *models.TranscriptDocument
+		expectError      bool
+	}{
+		{
+			description: "items are grouped into sentences and split on speaker changes",
+			transcript: `{"results": {
+				"transcripts": [{"transcript": "Welcome, adventurers. Roll initiative!"}],
+				"speaker_labels": {"speakers": 2, "segments": [
+					{"speaker_label": "spk_0", "start_time": "0.5", "end_time": "1.8", "items": [
+						{"start_time": "0.5", "end_time": "1.0", "speaker_label": "spk_0"},
+						{"start_time": "1.1", "end_time": "1.8", "speaker_label": "spk_0"}]},
+					{"speaker_label": "spk_1", "start_time": "2.0", "end_time": "3.0", "items": [
+						{"start_time": "2.0", "end_time": "2.4", "speaker_label": "spk_1"},
+						{"start_time": "2.5", "end_time": "3.0", "speaker_label": "spk_1"}]}]},
+				"items": [
+					{"start_time": "0.5", "end_time": "1.0", "alternatives": [{"confidence": "0.9", "content": "Welcome"}], "type": "pronunciation"},
+					{"alternatives": [{"confidence": "0.0", "content": ","}], "type": "punctuation"},
+					{"start_time": "1.1", "end_time": "1.8", "alternatives": [{"confidence": "0.7", "content": "adventurers"}], "type": "pronunciation"},
+					{"alternatives": [{"confidence": "0.0", "content": "."}], "type": "punctuation"},
+					{"start_time": "2.0", "end_time": "2.4", "alternatives": [{"confidence": "1.0", "content": "Roll"}], "type": "pronunciation"},
+					{"start_time": "2.5", "end_time": "3.0", "alternatives": [{"confidence": "0.8", "content": "initiative"}], "type": "pronunciation"},
+					{"alternatives": [{"confidence": "0.0", "content": "!"}], "type": "punctuation"}]
+			}}`,
+			expectedDocument: &models.TranscriptDocument{
+				Segments: []models.TranscriptSegment{
+					{StartTime: 500 * time.Millisecond, EndTime: 1800 * time.Millisecond, Speaker: "spk_0", Text: "Welcome, adventurers.", Confidence: 0.8},
+					{StartTime: 2 * time.Second, EndTime: 3 * time.Second, Speaker: "spk_1", Text: "Roll initiative!", Confidence: 0.9},
+				},
+			},
+		},
+		{
+			description: "audio segments are used when present",
+			transcript: `{"results": {
+				"language_code": "en-GB",
+				"transcripts": [{"transcript": "Hello there."}],
+				"items": [
+					{"id": 0, "start_time": "0.0", "end_time": "0.4", "alternatives": [{"confidence": "0.6", "content": "Hello"}], "type": "pronunciation", "speaker_label": "spk_0"},
+					{"id": 1, "start_time": "0.5", "end_time": "0.9", "alternatives": [{"confidence": "0.8", "content": "there"}], "type": "pronunciation", "speaker_label": "spk_0"},
+					{"id": 2, "alternatives": [{"confidence": "0.0", "content": "."}], "type": "punctuation", "speaker_label": "spk_0"}],
+				"audio_segments": [
+					{"id": 0, "transcript": "Hello there.", "start_time": "0.0", "end_time": "0.9", "speaker_label": "spk_0", "items": [0, 1, 2]}]
+			}}`,
+			expectedDocument: &models.TranscriptDocument{
+				Segments: []models.TranscriptSegment{
+					{StartTime: 0, EndTime: 900 * time.Millisecond, Speaker: "spk_0", Text: "Hello there.", Confidence: 0.7},
+				},
+				Language: "en-GB",
+			},
+		},
+		{
+			description: "transcript without items falls back to the full text",
+			transcript:  `{"results": {"transcripts": [{"transcript": "Hello there."}], "items": []}}`,
+			expectedDocument: &models.TranscriptDocument{
+				Segments: []models.TranscriptSegment{{Text: "Hello there."}},
+			},
+		},
+		{
+			description: "invalid json returns an error",
+			transcript:  `not json`,
+			expectError: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			document, err := ParseAmazonTranscript([]byte(c.transcript))
+			if c.expectError {
+				if err == nil {
+					t.Errorf("expected an error got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error returned: %s", err)
+			}
+			assert.Equal(t, c.expectedDocument.Language, document.Language)
+			assert.Equal(t, len(c.expectedDocument.Segments), len(document.Segments))
+			for i, expected := range c.expectedDocument.Segments {
+				actual := document.Segments[i]
+				assert.Equal(t, expected.StartTime, actual.StartTime)
+				assert.Equal(t, expected.EndTime, actual.EndTime)
+				assert.Equal(t, expected.Speaker, actual.Speaker)
+				assert.Equal(t, expected.Text, actual.Text)
+				assert.InDelta(t, expected.Confidence, actual.Confidence, 0.0001)
+			}
+		})
+	}
+}
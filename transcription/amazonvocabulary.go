@@ -0,0 +1,103 @@
+package transcription
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/transcribeservice"
+)
+
+const (
+	// vocabularyReadyTimeout bounds how long a job waits for a new custom vocabulary to be built, the job is
+	// started without it after that
+	vocabularyReadyTimeout = 2 * time.Minute
+	vocabularyPollInterval = 5 * time.Second
+)
+
+// amazonVocabularyTable renders terms as an Amazon Transcribe custom vocabulary table. Phrases are written with
+// hyphens between their words and displayed as they were given, every pronunciation gets a row of its own.
+func amazonVocabularyTable(terms []models.VocabularyTerm) string {
+	rows := []string{"Phrase\tSoundsLike\tIPA\tDisplayAs"}
+	for _, term := range terms {
+		phrase := strings.Join(strings.Fields(term.Phrase), "-")
+		if len(term.SoundsLike) == 0 {
+			rows = append(rows, fmt.Sprintf("%s\t\t\t%s", phrase, term.Phrase))
+		}
+		for _, soundsLike := range term.SoundsLike {
+			rows = append(rows, fmt.Sprintf("%s\t%s\t\t%s", phrase, soundsLike, term.Phrase))
+		}
+	}
+	return strings.Join(rows, "\n") + "\n"
+}
+
+// amazonVocabularyName names a vocabulary after its language and content, so jobs with the same vocabulary
+// share it and a changed vocabulary never affects jobs that are already running
+func amazonVocabularyName(language, table string) string {
+	return fmt.Sprintf("dragonspeak-%s-%x", language, sha256.Sum256([]byte(table)))
+}
+
+// vocabulary returns the name of a ready custom vocabulary holding terms, creating it when it doesn't exist yet
+func (t *AmazonTranscription) vocabulary(language string, terms []models.VocabularyTerm) (string, error) {
+	table := amazonVocabularyTable(terms)
+	name := amazonVocabularyName(language, table)
+	state, err := t.vocabularyState(name)
+	if err != nil {
+		return "", err
+	}
+	if state == "" {
+		if state, err = t.createVocabulary(name, language, table); err != nil {
+			return "", err
+		}
+	}
+	deadline := time.Now().Add(vocabularyReadyTimeout)
+	for state == transcribeservice.VocabularyStatePending && time.Now().Before(deadline) {
+		time.Sleep(vocabularyPollInterval)
+		if state, err = t.vocabularyState(name); err != nil {
+			return "", err
+		}
+	}
+	if state != transcribeservice.VocabularyStateReady {
+		return "", fmt.Errorf("vocabulary %s is %s", name, state)
+	}
+	return name, nil
+}
+
+// vocabularyState returns the state of a custom vocabulary, empty when it doesn't exist
+func (t *AmazonTranscription) vocabularyState(name string) (string, error) {
+	result, err := t.svc.GetVocabulary(&transcribeservice.GetVocabularyInput{VocabularyName: aws.String(name)})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && (awsErr.Code() == transcribeservice.ErrCodeNotFoundException || awsErr.Code() == transcribeservice.ErrCodeBadRequestException) {
+			return "", nil
+		}
+		return "", err
+	}
+	return aws.StringValue(result.VocabularyState), nil
+}
+
+func (t *AmazonTranscription) createVocabulary(name, language, table string) (string, error) {
+	fileKey := fmt.Sprintf("vocabulary/%s.txt", name)
+	if err := t.fileStore.UploadData(t.outputBucket, fileKey, strings.NewReader(table)); err != nil {
+		return "", err
+	}
+	result, err := t.svc.CreateVocabulary(&transcribeservice.CreateVocabularyInput{
+		VocabularyName:    aws.String(name),
+		LanguageCode:      aws.String(language),
+		VocabularyFileUri: aws.String(fmt.Sprintf("s3://%s/%s", t.outputBucket, fileKey)),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == transcribeservice.ErrCodeConflictException {
+			// another job created it in the meantime
+			return transcribeservice.VocabularyStatePending, nil
+		}
+		return "", err
+	}
+	return aws.StringValue(result.VocabularyState), nil
+}
@@ -0,0 +1,24 @@
+package transcription
+
+import (
+	"testing"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmazonVocabularyTable(t *testing.T) {
+	terms := []models.VocabularyTerm{
+		{Phrase: "Bryn Shander"},
+		{Phrase: "Tiamat", SoundsLike: []string{"tee-ah-mat", "tya-mat"}},
+	}
+
+	table := amazonVocabularyTable(terms)
+
+	assert.Equal(t, "Phrase\tSoundsLike\tIPA\tDisplayAs\n"+
+		"Bryn-Shander\t\t\tBryn Shander\n"+
+		"Tiamat\ttee-ah-mat\t\tTiamat\n"+
+		"Tiamat\ttya-mat\t\tTiamat\n", table)
+	assert.Equal(t, amazonVocabularyName("en-US", table), amazonVocabularyName("en-US", amazonVocabularyTable(terms)))
+	assert.NotEqual(t, amazonVocabularyName("en-US", table), amazonVocabularyName("en-GB", table), "vocabularies are per language")
+}
@@ -0,0 +1,97 @@
+package transcription
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+// defaultFakeSegments is the transcript FakeTranscription writes when it isn't given one
+var defaultFakeSegments = []models.TranscriptSegment{
+	{StartTime: 0, EndTime: 3 * time.Second, Speaker: "spk_0", Text: "Welcome back, adventurers. You stand before the gates of the ruined keep.", Confidence: 1},
+	{StartTime: 3 * time.Second, EndTime: 5 * time.Second, Speaker: "spk_1", Text: "I check the gate for traps.", Confidence: 1},
+	{StartTime: 5 * time.Second, EndTime: 7 * time.Second, Speaker: "spk_0", Text: "Roll investigation.", Confidence: 1},
+}
+
+type fakeSegment struct {
+	Start      time.Duration `json:"start"`
+	End        time.Duration `json:"end"`
+	Speaker    string        `json:"speaker"`
+	Text       string        `json:"text"`
+	Confidence float64       `json:"confidence"`
+}
+
+// FakeTranscription completes every job straight away with a canned transcript. It is intended for tests and
+// local development without a speech to text engine.
+type FakeTranscription struct {
+	fileStore fileStore
+	bucket    string
+	segments  []models.TranscriptSegment
+}
+
+// NewFakeTranscription creates a FakeTranscription that answers every job with segments, a short exchange
+// between two speakers is used when segments is empty
+func NewFakeTranscription(fileStore fileStore, bucket string, segments []models.TranscriptSegment) *FakeTranscription {
+	if len(segments) == 0 {
+		segments = defaultFakeSegments
+	}
+	return &FakeTranscription{
+		fileStore: fileStore,
+		bucket:    bucket,
+		segments:  segments,
+	}
+}
+
+// StartTranscriptionJob writes the canned transcript to the result location before returning
+func (t *FakeTranscription) StartTranscriptionJob(jobName, audioLocation, resultLocation string, audioFormat models.AudioFormat, options models.TranscriptionOptions) error {
+	if _, err := audioFormatToMediaString(audioFormat); err != nil {
+		return err
+	}
+	segments := make([]fakeSegment, 0, len(t.segments))
+	for _, segment := range t.segments {
+		segments = append(segments, fakeSegment{
+			Start:      segment.StartTime,
+			End:        segment.EndTime,
+			Speaker:    segment.Speaker,
+			Text:       segment.Text,
+			Confidence: segment.Confidence,
+		})
+	}
+	data, err := json.Marshal(segments)
+	if err != nil {
+		return err
+	}
+	return t.fileStore.UploadData(t.bucket, resultLocation, bytes.NewReader(data))
+}
+
+// GetTranscriptionJobStatus reports every job as completed, since a job that started has written its transcript
+func (t *FakeTranscription) GetTranscriptionJobStatus(jobName string) (models.TranscriptionJobStatus, error) {
+	return models.TranscriptionJobCompleted, nil
+}
+
+// ParseJobEvent accepts job state change events shaped like the ones Amazon Transcribe sends, so a local
+// harness can post them to the webhook in place of a provider
+func (t *FakeTranscription) ParseJobEvent(data []byte) (*models.TranscriptionJobEvent, error) {
+	return ParseAmazonJobEvent(data)
+}
+
+func (t *FakeTranscription) ParseTranscript(data []byte) (*models.TranscriptDocument, error) {
+	fakeSegments := []fakeSegment{}
+	if err := json.Unmarshal(data, &fakeSegments); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	segments := make([]models.TranscriptSegment, 0, len(fakeSegments))
+	for _, segment := range fakeSegments {
+		segments = append(segments, models.TranscriptSegment{
+			StartTime:  segment.Start,
+			EndTime:    segment.End,
+			Speaker:    segment.Speaker,
+			Text:       segment.Text,
+			Confidence: segment.Confidence,
+		})
+	}
+	return &models.TranscriptDocument{Segments: segments}, nil
+}
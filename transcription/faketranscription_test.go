@@ -0,0 +1,26 @@
+package transcription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeTranscription(t *testing.T) {
+	segments := []models.TranscriptSegment{
+		{StartTime: time.Second, EndTime: 2 * time.Second, Speaker: "spk_0", Text: "Roll initiative.", Confidence: 0.9},
+	}
+	fileStore := newMemoryFileStore()
+	provider := NewFakeTranscription(fileStore, "testBucket", segments)
+
+	err := provider.StartTranscriptionJob("job-1", "audio-1", "transcript-1", models.WAV, models.TranscriptionOptions{})
+	assert.NoError(t, err)
+	status, err := provider.GetTranscriptionJobStatus("job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.TranscriptionJobCompleted, status)
+	document, err := provider.ParseTranscript(fileStore.files["testBucket/transcript-1"])
+	assert.NoError(t, err)
+	assert.Equal(t, &models.TranscriptDocument{Segments: segments}, document)
+}
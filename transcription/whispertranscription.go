@@ -0,0 +1,221 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+type fileStore interface {
+	UploadData(bucket, fileKey string, body io.Reader) error
+	DownloadData(bucket, fileKey string, w io.WriterAt) (int64, error)
+}
+
+const (
+	// whisperConcurrency is how many recordings are sent to the server at once, a local whisper.cpp server
+	// works through one at a time anyway
+	whisperConcurrency = 2
+	// whisperJobTimeout bounds a single transcription, long sessions on a laptop can take hours
+	whisperJobTimeout = 6 * time.Hour
+	// maxWhisperResponseBytes bounds the verbose_json response read back from the server
+	maxWhisperResponseBytes = 64 << 20
+	// maxWhisperPromptLength keeps the prompt within the 224 tokens Whisper looks at
+	maxWhisperPromptLength = 800
+)
+
+// WhisperTranscription transcribes with a server implementing the OpenAI audio transcription API, either
+// OpenAI itself or a local whisper.cpp server. The API answers synchronously, so jobs run in the background and
+// write the response to the result location. Job statuses are only kept in memory, jobs that were running when
+// the service stopped are reported as failed.
+type WhisperTranscription struct {
+	client    *http.Client
+	baseURL   string
+	apiKey    string
+	model     string
+	fileStore fileStore
+	bucket    string
+	slots     chan struct{}
+
+	mu   sync.Mutex
+	jobs map[string]models.TranscriptionJobStatus
+}
+
+func NewWhisperTranscription(baseURL, apiKey, model string, fileStore fileStore, bucket string) *WhisperTranscription {
+	return &WhisperTranscription{
+		client:    &http.Client{Timeout: whisperJobTimeout},
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		apiKey:    apiKey,
+		model:     model,
+		fileStore: fileStore,
+		bucket:    bucket,
+		slots:     make(chan struct{}, whisperConcurrency),
+		jobs:      map[string]models.TranscriptionJobStatus{},
+	}
+}
+
+// StartTranscriptionJob queues the audio to be sent to the server, a job that is already known is left alone.
+// Whisper doesn't diarize so the speaker options are ignored, it identifies the language itself unless one is
+// given. The vocabulary is sent as the prompt, which Whisper follows for the spelling of names.
+func (t *WhisperTranscription) StartTranscriptionJob(jobName, audioLocation, resultLocation string, audioFormat models.AudioFormat, options models.TranscriptionOptions) error {
+	extension, err := audioFormatToMediaString(audioFormat)
+	if err != nil {
+		return err
+	}
+	if !t.queueJob(jobName) {
+		return nil
+	}
+	go func() {
+		t.slots <- struct{}{}
+		defer func() { <-t.slots }()
+		t.setStatus(jobName, models.TranscriptionJobInProgress)
+
+		ctx, cancel := context.WithTimeout(context.Background(), whisperJobTimeout)
+		defer cancel()
+		if err := t.transcribe(ctx, audioLocation, resultLocation, extension, options); err != nil {
+			log.Printf("whisper transcription job %s failed: %s", jobName, err)
+			t.setStatus(jobName, models.TranscriptionJobFailed)
+			return
+		}
+		t.setStatus(jobName, models.TranscriptionJobCompleted)
+	}()
+	return nil
+}
+
+func (t *WhisperTranscription) GetTranscriptionJobStatus(jobName string) (models.TranscriptionJobStatus, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status, ok := t.jobs[jobName]
+	if !ok {
+		log.Printf("whisper transcription job %s is unknown, it was lost when the service restarted", jobName)
+		return models.TranscriptionJobFailed, nil
+	}
+	return status, nil
+}
+
+// ParseTranscript converts the verbose_json response written to the result location into a models.TranscriptDocument
+func (t *WhisperTranscription) ParseTranscript(data []byte) (*models.TranscriptDocument, error) {
+	return ParseWhisperTranscript(data)
+}
+
+// queueJob records a job as queued, it reports false when the job is already known
+func (t *WhisperTranscription) queueJob(jobName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.jobs[jobName]; ok {
+		return false
+	}
+	t.jobs[jobName] = models.TranscriptionJobQueued
+	return true
+}
+
+func (t *WhisperTranscription) setStatus(jobName string, status models.TranscriptionJobStatus) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.jobs[jobName] = status
+}
+
+// transcribe sends the audio to the server and stores its response at the result location
+func (t *WhisperTranscription) transcribe(ctx context.Context, audioLocation, resultLocation, extension string, options models.TranscriptionOptions) error {
+	// the audio is buffered to a file since recordings of a whole session don't fit comfortably in memory
+	audio, err := os.CreateTemp("", "whisper-audio-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(audio.Name())
+	defer audio.Close()
+	if _, err = t.fileStore.DownloadData(t.bucket, audioLocation, audio); err != nil {
+		return err
+	}
+	if _, err = audio.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	body, contentType := t.requestBody(audio, "audio."+extension, options)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/audio/transcriptions", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if t.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	result, err := io.ReadAll(io.LimitReader(resp.Body, maxWhisperResponseBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("transcription server returned %d: %s", resp.StatusCode, bytes.TrimSpace(result))
+	}
+	if _, err = ParseWhisperTranscript(result); err != nil {
+		return err
+	}
+	return t.fileStore.UploadData(t.bucket, resultLocation, bytes.NewReader(result))
+}
+
+// requestBody streams the multipart form the transcription API expects, with the audio as its file part. The
+// client closes the body when the request ends, which stops the stream if the server answered early.
+func (t *WhisperTranscription) requestBody(audio io.Reader, fileName string, options models.TranscriptionOptions) (io.ReadCloser, string) {
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		fields := [][2]string{
+			{"model", t.model},
+			{"response_format", "verbose_json"},
+			{"timestamp_granularities[]", "segment"},
+		}
+		if options.Language != "" && options.Language != models.AutoLanguage {
+			// the API takes ISO 639-1 codes without a region
+			code, _, _ := strings.Cut(options.Language, "-")
+			fields = append(fields, [2]string{"language", strings.ToLower(code)})
+		}
+		if prompt := whisperPrompt(options.Vocabulary); prompt != "" {
+			fields = append(fields, [2]string{"prompt", prompt})
+		}
+		for _, field := range fields {
+			if err := form.WriteField(field[0], field[1]); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
+		part, err := form.CreateFormFile("file", fileName)
+		if err == nil {
+			_, err = io.Copy(part, audio)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+	return reader, form.FormDataContentType()
+}
+
+// whisperPrompt lists the phrases of the vocabulary, leaving out the ones that don't fit the prompt
+func whisperPrompt(vocabulary []models.VocabularyTerm) string {
+	prompt := ""
+	for _, term := range vocabulary {
+		next := term.Phrase
+		if prompt != "" {
+			next = prompt + ", " + term.Phrase
+		}
+		if len(next) > maxWhisperPromptLength {
+			break
+		}
+		prompt = next
+	}
+	return prompt
+}
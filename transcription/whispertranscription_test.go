@@ -0,0 +1,223 @@
+package transcription
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryFileStore struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newMemoryFileStore() *memoryFileStore {
+	return &memoryFileStore{files: map[string][]byte{}}
+}
+
+func (m *memoryFileStore) UploadData(bucket, fileKey string, body io.Reader) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[bucket+"/"+fileKey] = data
+	return nil
+}
+
+func (m *memoryFileStore) DownloadData(bucket, fileKey string, w io.WriterAt) (int64, error) {
+	m.mu.Lock()
+	data, ok := m.files[bucket+"/"+fileKey]
+	m.mu.Unlock()
+	if !ok {
+		return 0, models.EntityNotFound
+	}
+	n, err := w.WriteAt(data, 0)
+	return int64(n), err
+}
+
+const testWhisperResponse = `{"text": "Roll initiative.", "segments": [{"start": 0, "end": 1.5, "text": "Roll initiative.", "avg_logprob": 0}]}`
+
+func TestWhisperTranscription(t *testing.T) {
+	cases := []struct {
+		description        string
+		language           string
+		vocabulary         []models.VocabularyTerm
+		expectedLanguage   string
+		expectedPrompt     string
+		storeAudio         bool
+		serverStatus       int
+		serverResponse     string
+		expectedStatus     models.TranscriptionJobStatus
+		expectedTranscript string
+	}{
+		{
+			description:        "audio transcribed, response stored and job completed",
+			storeAudio:         true,
+			serverStatus:       http.StatusOK,
+			serverResponse:     testWhisperResponse,
+			expectedStatus:     models.TranscriptionJobCompleted,
+			expectedTranscript: testWhisperResponse,
+		},
+		{
+			description:        "language is given, its code is sent without the region",
+			language:           "pt-BR",
+			expectedLanguage:   "pt",
+			storeAudio:         true,
+			serverStatus:       http.StatusOK,
+			serverResponse:     testWhisperResponse,
+			expectedStatus:     models.TranscriptionJobCompleted,
+			expectedTranscript: testWhisperResponse,
+		},
+		{
+			description:        "vocabulary is sent as the prompt",
+			vocabulary:         []models.VocabularyTerm{{Phrase: "Tiamat", SoundsLike: []string{"tee-ah-mat"}}, {Phrase: "Neverwinter"}},
+			expectedPrompt:     "Tiamat, Neverwinter",
+			storeAudio:         true,
+			serverStatus:       http.StatusOK,
+			serverResponse:     testWhisperResponse,
+			expectedStatus:     models.TranscriptionJobCompleted,
+			expectedTranscript: testWhisperResponse,
+		},
+		{
+			description:        "language identification requested, no language is sent",
+			language:           models.AutoLanguage,
+			storeAudio:         true,
+			serverStatus:       http.StatusOK,
+			serverResponse:     testWhisperResponse,
+			expectedStatus:     models.TranscriptionJobCompleted,
+			expectedTranscript: testWhisperResponse,
+		},
+		{
+			description:    "server returns an error, job failed",
+			storeAudio:     true,
+			serverStatus:   http.StatusInternalServerError,
+			serverResponse: `{"error": "model not loaded"}`,
+			expectedStatus: models.TranscriptionJobFailed,
+		},
+		{
+			description:    "server returns something other than a transcript, job failed",
+			storeAudio:     true,
+			serverStatus:   http.StatusOK,
+			serverResponse: `not json`,
+			expectedStatus: models.TranscriptionJobFailed,
+		},
+		{
+			description:    "audio is missing, job failed",
+			serverStatus:   http.StatusOK,
+			serverResponse: testWhisperResponse,
+			expectedStatus: models.TranscriptionJobFailed,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			var receivedFields map[string]string
+			var receivedAudio string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v1/audio/transcriptions", r.URL.Path)
+				assert.Equal(t, "Bearer testKey", r.Header.Get("Authorization"))
+				if err := r.ParseMultipartForm(1 << 20); err == nil {
+					receivedFields = map[string]string{}
+					for name, values := range r.MultipartForm.Value {
+						receivedFields[name] = values[0]
+					}
+					if file, header, err := r.FormFile("file"); err == nil {
+						audio, _ := io.ReadAll(file)
+						receivedAudio = header.Filename + ":" + string(audio)
+					}
+				}
+				w.WriteHeader(c.serverStatus)
+				fmt.Fprint(w, c.serverResponse)
+			}))
+			defer server.Close()
+			fileStore := newMemoryFileStore()
+			if c.storeAudio {
+				fileStore.files["testBucket/audio-1"] = []byte("ID3audio")
+			}
+			provider := NewWhisperTranscription(server.URL+"/v1/", "testKey", "whisper-1", fileStore, "testBucket")
+
+			err := provider.StartTranscriptionJob("job-1", "audio-1", "transcript-1", models.MP3, models.TranscriptionOptions{Language: c.language, Vocabulary: c.vocabulary})
+			assert.NoError(t, err)
+			status := waitForJob(t, provider, "job-1")
+
+			assert.Equal(t, c.expectedStatus, status)
+			if c.storeAudio {
+				expectedFields := map[string]string{"model": "whisper-1", "response_format": "verbose_json", "timestamp_granularities[]": "segment"}
+				if c.expectedLanguage != "" {
+					expectedFields["language"] = c.expectedLanguage
+				}
+				if c.expectedPrompt != "" {
+					expectedFields["prompt"] = c.expectedPrompt
+				}
+				assert.Equal(t, expectedFields, receivedFields)
+				assert.Equal(t, "audio.mp3:ID3audio", receivedAudio)
+			}
+			transcript, ok := fileStore.files["testBucket/transcript-1"]
+			if c.expectedTranscript == "" {
+				assert.False(t, ok, "nothing should be stored for a failed job")
+				return
+			}
+			assert.Equal(t, c.expectedTranscript, string(transcript))
+		})
+	}
+}
+
+func TestWhisperTranscriptionUnknownJob(t *testing.T) {
+	provider := NewWhisperTranscription("http://whisper.test", "", "whisper-1", newMemoryFileStore(), "testBucket")
+
+	status, err := provider.GetTranscriptionJobStatus("lost-job")
+	assert.NoError(t, err)
+	assert.Equal(t, models.TranscriptionJobFailed, status)
+}
+
+func TestWhisperTranscriptionStartedTwice(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, testWhisperResponse)
+	}))
+	defer server.Close()
+	fileStore := newMemoryFileStore()
+	fileStore.files["testBucket/audio-1"] = []byte("ID3audio")
+	provider := NewWhisperTranscription(server.URL+"/v1/", "testKey", "whisper-1", fileStore, "testBucket")
+
+	assert.NoError(t, provider.StartTranscriptionJob("job-1", "audio-1", "transcript-1", models.MP3, models.TranscriptionOptions{}))
+	assert.Equal(t, models.TranscriptionJobCompleted, waitForJob(t, provider, "job-1"))
+	assert.NoError(t, provider.StartTranscriptionJob("job-1", "audio-1", "transcript-1", models.MP3, models.TranscriptionOptions{}))
+
+	status, err := provider.GetTranscriptionJobStatus("job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.TranscriptionJobCompleted, status, "a job started again should be left alone")
+	assert.Equal(t, 1, requests)
+}
+
+func waitForJob(t *testing.T, provider *WhisperTranscription, jobName string) models.TranscriptionJobStatus {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		status, err := provider.GetTranscriptionJobStatus(jobName)
+		assert.NoError(t, err)
+		if status == models.TranscriptionJobCompleted || status == models.TranscriptionJobFailed {
+			return status
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", jobName)
+	return models.TranscriptionJobFailed
+}
+
+func TestWhisperPrompt(t *testing.T) {
+	vocabulary := []models.VocabularyTerm{{Phrase: "Tiamat"}, {Phrase: strings.Repeat("a", maxWhisperPromptLength)}, {Phrase: "Neverwinter"}}
+
+	assert.Equal(t, "", whisperPrompt(nil))
+	assert.Equal(t, "Tiamat", whisperPrompt(vocabulary), "phrases past the prompt length are left out")
+}
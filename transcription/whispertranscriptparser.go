@@ -0,0 +1,88 @@
+package transcription
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+)
+
+type whisperSegment struct {
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Text       string  `json:"text"`
+	AvgLogprob float64 `json:"avg_logprob"`
+}
+
+// whisperLanguages maps the language names Whisper reports to their ISO 639-1 codes
+var whisperLanguages = map[string]string{
+	"chinese":    "zh",
+	"danish":     "da",
+	"dutch":      "nl",
+	"english":    "en",
+	"french":     "fr",
+	"german":     "de",
+	"italian":    "it",
+	"japanese":   "ja",
+	"korean":     "ko",
+	"polish":     "pl",
+	"portuguese": "pt",
+	"russian":    "ru",
+	"spanish":    "es",
+	"swedish":    "sv",
+}
+
+// whisperTranscriptDocument is the verbose_json response of the OpenAI audio transcription API
+type whisperTranscriptDocument struct {
+	Text     string           `json:"text"`
+	Language string           `json:"language"`
+	Segments []whisperSegment `json:"segments"`
+}
+
+// ParseWhisperTranscript converts the verbose_json response of an OpenAI compatible transcription API into a
+// models.TranscriptDocument. Whisper doesn't diarize, so segments carry no speaker.
+func ParseWhisperTranscript(data []byte) (*models.TranscriptDocument, error) {
+	doc := whisperTranscriptDocument{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	segments := []models.TranscriptSegment{}
+	for _, segment := range doc.Segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+		segments = append(segments, models.TranscriptSegment{
+			StartTime: secondsToDuration(segment.Start),
+			EndTime:   secondsToDuration(segment.End),
+			Text:      text,
+			// the average log probability of the tokens, as a probability it reads like a confidence
+			Confidence: math.Min(1, math.Exp(segment.AvgLogprob)),
+		})
+	}
+	if len(doc.Segments) == 0 && strings.TrimSpace(doc.Text) != "" {
+		// responses without segments only carry the full text
+		segments = append(segments, models.TranscriptSegment{Text: strings.TrimSpace(doc.Text)})
+	}
+	return &models.TranscriptDocument{Segments: segments, Language: whisperLanguage(doc.Language)}, nil
+}
+
+// whisperLanguage converts the language Whisper reports to a language code. OpenAI reports the language's
+// name while some compatible servers report its code, unknown names are dropped.
+func whisperLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if code, ok := whisperLanguages[language]; ok {
+		return code
+	}
+	if len(language) == 2 {
+		return language
+	}
+	return ""
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Round(seconds * float64(time.Second)))
+}
@@ -0,0 +1,77 @@
+package transcription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EdgarH78/dragonspeak-service/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWhisperTranscript(t *testing.T) {
+	cases := []struct {
+		description      string
+		transcript       string
+		expectedDocument *models.TranscriptDocument
+		expectError      bool
+	}{
+		{
+			description: "segments are converted with their timings",
+			transcript: `{"task": "transcribe", "language": "spanish", "duration": 3.0, "text": "Tirad iniciativa. ¡Vamos!",
+				"segments": [
+					{"id": 0, "start": 0.5, "end": 1.8, "text": " Tirad iniciativa.", "avg_logprob": 0},
+					{"id": 1, "start": 2.0, "end": 3.0, "text": " ¡Vamos!", "avg_logprob": -0.6931471805599453}]}`,
+			expectedDocument: &models.TranscriptDocument{
+				Segments: []models.TranscriptSegment{
+					{StartTime: 500 * time.Millisecond, EndTime: 1800 * time.Millisecond, Text: "Tirad iniciativa.", Confidence: 1},
+					{StartTime: 2 * time.Second, EndTime: 3 * time.Second, Text: "¡Vamos!", Confidence: 0.5},
+				},
+				Language: "es",
+			},
+		},
+		{
+			description: "blank segments are dropped",
+			transcript: `{"text": "Hello.", "language": "en", "segments": [
+					{"start": 0, "end": 1, "text": " ", "avg_logprob": -0.1},
+					{"start": 1, "end": 2, "text": "Hello.", "avg_logprob": 0}]}`,
+			expectedDocument: &models.TranscriptDocument{
+				Segments: []models.TranscriptSegment{
+					{StartTime: time.Second, EndTime: 2 * time.Second, Text: "Hello.", Confidence: 1},
+				},
+				Language: "en",
+			},
+		},
+		{
+			description: "response without segments keeps the full text",
+			transcript:  `{"text": " Roll for initiative. "}`,
+			expectedDocument: &models.TranscriptDocument{
+				Segments: []models.TranscriptSegment{{Text: "Roll for initiative."}},
+			},
+		},
+		{
+			description: "invalid json, error returned",
+			transcript:  `{"segments": [`,
+			expectError: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			document, err := ParseWhisperTranscript([]byte(c.transcript))
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedDocument.Language, document.Language)
+			assert.Equal(t, len(c.expectedDocument.Segments), len(document.Segments))
+			for i, expected := range c.expectedDocument.Segments {
+				actual := document.Segments[i]
+				assert.Equal(t, expected.StartTime, actual.StartTime)
+				assert.Equal(t, expected.EndTime, actual.EndTime)
+				assert.Equal(t, expected.Text, actual.Text)
+				assert.InDelta(t, expected.Confidence, actual.Confidence, 0.0001)
+			}
+		})
+	}
+}